@@ -18,6 +18,9 @@ package v1
 
 import (
 	"fmt"
+	"slices"
+	"strings"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -25,6 +28,18 @@ import (
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 )
 
+// maxEstablishingCRDs is the maximum number of CRDs named in an
+// EstablishingCRDs condition's message.
+const maxEstablishingCRDs = 5
+
+// maxUnhealthyFamilyMembers is the maximum number of packages named in a
+// PartiallyHealthy condition's message.
+const maxUnhealthyFamilyMembers = 5
+
+// maxDeprecatedAPIs is the maximum number of APIs named in a DeprecatedAPIs
+// condition's message.
+const maxDeprecatedAPIs = 5
+
 // Condition types.
 const (
 	// A TypeInstalled indicates whether a package has been installed.
@@ -42,6 +57,45 @@ const (
 	// A TypeVerified indicates whether a package's signature is verified.
 	// It could be either successful or skipped to be marked as complete.
 	TypeVerified xpv1.ConditionType = "Verified"
+
+	// A TypeRevisionGarbageCollection indicates whether a package's revisions
+	// in excess of its revision history limit can be garbage collected.
+	TypeRevisionGarbageCollection xpv1.ConditionType = "RevisionGarbageCollection"
+
+	// A TypeRevisionUpdated indicates whether a package's current revision
+	// could be updated to match the package.
+	TypeRevisionUpdated xpv1.ConditionType = "RevisionUpdated"
+
+	// A TypeRevisionOwnership indicates whether all of a package's revisions
+	// are controlled by the package.
+	TypeRevisionOwnership xpv1.ConditionType = "RevisionOwnership"
+
+	// A TypeHeld indicates whether a package's revision is held active.
+	TypeHeld xpv1.ConditionType = "Held"
+
+	// A TypeGlobalFreeze indicates whether a cluster-wide maintenance
+	// freeze stops the package manager changing a package's revisions.
+	TypeGlobalFreeze xpv1.ConditionType = "GlobalFreeze"
+
+	// A TypeStuck indicates whether a package has been unpacking for longer
+	// than the package manager expects.
+	TypeStuck xpv1.ConditionType = "Stuck"
+
+	// A TypeUpgradeAvailable indicates whether a package's source resolves
+	// to newer content than its current revision.
+	TypeUpgradeAvailable xpv1.ConditionType = "UpgradeAvailable"
+
+	// A TypeDeprecatedAPIs indicates whether a package's contents use or
+	// define deprecated APIs.
+	TypeDeprecatedAPIs xpv1.ConditionType = "DeprecatedAPIs"
+
+	// A TypeSourceUnavailable indicates whether a package's source no
+	// longer exists, while its current revision remains usable.
+	TypeSourceUnavailable xpv1.ConditionType = "SourceUnavailable"
+
+	// A TypeRevisionGarbageCollectionMode indicates how a package's revisions
+	// are garbage collected. It's informational.
+	TypeRevisionGarbageCollectionMode xpv1.ConditionType = "RevisionGarbageCollectionMode"
 )
 
 // Reasons a package is or is not installed.
@@ -52,7 +106,109 @@ const (
 	ReasonActive               xpv1.ConditionReason = "ActivePackageRevision"
 	ReasonUnhealthy            xpv1.ConditionReason = "UnhealthyPackageRevision"
 	ReasonHealthy              xpv1.ConditionReason = "HealthyPackageRevision"
+	ReasonNoCachedRevision     xpv1.ConditionReason = "NoCachedRevision"
 	ReasonUnknownHealth        xpv1.ConditionReason = "UnknownPackageRevisionHealth"
+	ReasonEstablishingCRDs     xpv1.ConditionReason = "EstablishingCRDs"
+	ReasonStabilizing          xpv1.ConditionReason = "StabilizingPackageRevision"
+	ReasonPartiallyHealthy     xpv1.ConditionReason = "PartiallyHealthy"
+	ReasonPullSecretPending    xpv1.ConditionReason = "PullSecretPending"
+	ReasonAuthenticationFailed xpv1.ConditionReason = "AuthenticationFailed"
+)
+
+// Reasons a package's image couldn't be fetched from its registry.
+const (
+	ReasonRateLimited     xpv1.ConditionReason = "RateLimited"
+	ReasonPackageNotFound xpv1.ConditionReason = "PackageNotFound"
+)
+
+// Reasons a package's source can't be resolved.
+const (
+	ReasonAliasNotFound xpv1.ConditionReason = "PackageSourceAliasNotFound"
+)
+
+// Reasons a package can't be rolled back to its desired revision.
+const (
+	ReasonDesiredRevisionNotFound xpv1.ConditionReason = "DesiredRevisionNotFound"
+)
+
+// Reasons a package's contents can't be installed.
+const (
+	ReasonContentPolicyViolation xpv1.ConditionReason = "ContentPolicyViolation"
+	ReasonDigestMismatch         xpv1.ConditionReason = "DigestMismatch"
+	ReasonProvenanceMissing      xpv1.ConditionReason = "ProvenanceMissing"
+	ReasonPackageTypeMismatch    xpv1.ConditionReason = "PackageTypeMismatch"
+)
+
+// Reasons a package's current revision can't be activated.
+const (
+	ReasonQuotaExceeded        xpv1.ConditionReason = "QuotaExceeded"
+	ReasonRuntimeConfigMissing xpv1.ConditionReason = "RuntimeConfigMissing"
+
+	ReasonDependencyGarbageCollecting xpv1.ConditionReason = "DependencyGarbageCollecting"
+)
+
+// Reasons a package's revisions can or can't be garbage collected.
+const (
+	ReasonGarbageCollectionBlocked   xpv1.ConditionReason = "GarbageCollectionBlocked"
+	ReasonGarbageCollectionUnblocked xpv1.ConditionReason = "GarbageCollectionUnblocked"
+	ReasonGarbageCollecting          xpv1.ConditionReason = "GarbageCollecting"
+)
+
+// Reasons a package's revisions are or aren't garbage collected.
+const (
+	ReasonDefaultRevisionHistoryLimit  xpv1.ConditionReason = "DefaultRevisionHistoryLimit"
+	ReasonExplicitRevisionHistoryLimit xpv1.ConditionReason = "ExplicitRevisionHistoryLimit"
+	ReasonGarbageCollectionDisabled    xpv1.ConditionReason = "GarbageCollectionDisabled"
+)
+
+// Reasons a package's revisions are or aren't controlled by the package.
+const (
+	ReasonOwnershipDrift  xpv1.ConditionReason = "OwnershipDrift"
+	ReasonOwnershipIntact xpv1.ConditionReason = "OwnershipIntact"
+)
+
+// Reasons a package's revision is or isn't held active.
+const (
+	ReasonHeld                 xpv1.ConditionReason = "RevisionHeld"
+	ReasonHeldRevisionNotFound xpv1.ConditionReason = "HeldRevisionNotFound"
+	ReasonHoldReleased         xpv1.ConditionReason = "HoldReleased"
+)
+
+// Reasons a package's revisions are or aren't frozen.
+const (
+	ReasonFrozen       xpv1.ConditionReason = "Frozen"
+	ReasonFreezeLifted xpv1.ConditionReason = "FreezeLifted"
+)
+
+// Reasons a package is or isn't stuck.
+const (
+	ReasonStuckUnpacking xpv1.ConditionReason = "StuckUnpacking"
+	ReasonUnpacked       xpv1.ConditionReason = "Unpacked"
+)
+
+// Reasons a package does or doesn't have an upgrade available.
+const (
+	ReasonNewerDigestAvailable xpv1.ConditionReason = "NewerDigestAvailable"
+	ReasonUpToDate             xpv1.ConditionReason = "UpToDate"
+)
+
+// Reasons a package's contents do or don't use deprecated APIs.
+const (
+	ReasonDeprecatedAPIs   xpv1.ConditionReason = "DeprecatedAPIs"
+	ReasonNoDeprecatedAPIs xpv1.ConditionReason = "NoDeprecatedAPIs"
+)
+
+// Reasons a package's source is or isn't unavailable.
+const (
+	ReasonManifestNotFound xpv1.ConditionReason = "ManifestNotFound"
+	ReasonSourceAvailable  xpv1.ConditionReason = "SourceAvailable"
+)
+
+// Reasons a package's current revision could or couldn't be updated.
+const (
+	ReasonImmutableRevisionField xpv1.ConditionReason = "ImmutableRevisionField"
+	ReasonRevisionUpdated        xpv1.ConditionReason = "RevisionUpdated"
+	ReasonRevisionLimitExceeded  xpv1.ConditionReason = "RevisionLimitExceeded"
 )
 
 // Reasons a package's signature is or is not verified.
@@ -83,6 +239,411 @@ func Unpacking() xpv1.Condition {
 	}
 }
 
+// NoCachedRevision indicates that the package manager can't install a package
+// because it has no revisions, and the package manager won't fetch the
+// package from its registry to create one.
+func NoCachedRevision() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeInstalled,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonNoCachedRevision,
+		Message:            "Package has no revisions and its pull policy is IfNotPresent. The package manager is running in strict offline mode, so it won't fetch the package from its registry.",
+	}
+}
+
+// AuthenticationFailed indicates that the package manager can't install a
+// package because the package's registry rejected its credentials.
+func AuthenticationFailed() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeInstalled,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonAuthenticationFailed,
+	}
+}
+
+// ContentPolicyViolation indicates that the package manager won't create a
+// revision of a package because the package's contents violate policy.
+func ContentPolicyViolation(msg string) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeInstalled,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonContentPolicyViolation,
+		Message:            msg,
+	}
+}
+
+// DigestMismatch indicates that the package manager won't create a revision of
+// a package because the package resolved to a different digest than expected.
+// The resolved digest may be unknown.
+func DigestMismatch(expected, resolved string) xpv1.Condition {
+	if resolved == "" {
+		resolved = "an unknown digest"
+	}
+	return xpv1.Condition{
+		Type:               TypeInstalled,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonDigestMismatch,
+		Message:            fmt.Sprintf("Package resolved to %s, but is expected to resolve to %s", resolved, expected),
+	}
+}
+
+// ProvenanceMissing indicates that the package manager won't create a revision
+// of a package because the package image lacks a required provenance
+// attestation.
+func ProvenanceMissing(msg string) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeInstalled,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonProvenanceMissing,
+		Message:            msg,
+	}
+}
+
+// PackageTypeMismatch indicates that the package manager won't create a
+// revision of a package because the package's metadata declares it to be a
+// different kind of package, for example a Configuration whose image contains
+// a Provider.
+func PackageTypeMismatch(kind, declared string) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeInstalled,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonPackageTypeMismatch,
+		Message:            fmt.Sprintf("Package is a %s, but its image contains a %s", kind, declared),
+	}
+}
+
+// PullSecretPending indicates that the package manager can't install a package
+// yet because the pull secret selected by the supplied image config doesn't
+// exist. The secret may not exist yet because it's synced from an external
+// secret store.
+func PullSecretPending(secret, imageConfig string) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeInstalled,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonPullSecretPending,
+		Message:            fmt.Sprintf("Waiting for pull secret %q selected by ImageConfig named %q to exist", secret, imageConfig),
+	}
+}
+
+// ServiceAccountPending indicates that the package manager can't install a
+// package yet because the ServiceAccount whose image pull secrets it uses
+// doesn't exist.
+func ServiceAccountPending(serviceAccount string) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeInstalled,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonPullSecretPending,
+		Message:            fmt.Sprintf("Waiting for ServiceAccount %q whose image pull secrets the package uses to exist", serviceAccount),
+	}
+}
+
+// GarbageCollectionBlocked indicates that a package has more revisions than its
+// revision history limit allows, but none of them can be garbage collected
+// because they're all protected. Each supplied protection names a revision and
+// why it's protected, e.g. "provider-1234567 (current revision)".
+func GarbageCollectionBlocked(revisions int, limit int64, protections ...string) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeRevisionGarbageCollection,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonGarbageCollectionBlocked,
+		Message:            fmt.Sprintf("Package has %d revisions, exceeding its revision history limit of %d, but no revision can be garbage collected because each is protected: %s", revisions, limit, strings.Join(protections, ", ")),
+	}
+}
+
+// GarbageCollectionUnblocked indicates that a package's revisions in excess of
+// its revision history limit can be garbage collected.
+func GarbageCollectionUnblocked() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeRevisionGarbageCollection,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonGarbageCollectionUnblocked,
+	}
+}
+
+// GarbageCollecting indicates that the package manager is garbage collecting
+// the supplied revisions of a package, which may still be being deleted.
+func GarbageCollecting(revisions ...string) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeRevisionGarbageCollection,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonGarbageCollecting,
+		Message:            fmt.Sprintf("Garbage collecting package revisions %s", strings.Join(revisions, ", ")),
+	}
+}
+
+// GarbageCollectionDefaultLimit indicates that a package doesn't specify a
+// revision history limit, so its revisions in excess of the package manager's
+// default limit are garbage collected.
+func GarbageCollectionDefaultLimit(limit int64) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeRevisionGarbageCollectionMode,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonDefaultRevisionHistoryLimit,
+		Message:            fmt.Sprintf("Package doesn't specify a revision history limit, so revisions in excess of the default limit of %d are garbage collected", limit),
+	}
+}
+
+// GarbageCollectionExplicitLimit indicates that a package's revisions in excess
+// of the revision history limit it specifies are garbage collected.
+func GarbageCollectionExplicitLimit(limit int64) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeRevisionGarbageCollectionMode,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonExplicitRevisionHistoryLimit,
+		Message:            fmt.Sprintf("Revisions in excess of the package's revision history limit of %d are garbage collected", limit),
+	}
+}
+
+// GarbageCollectionDisabled indicates that a package's revisions are never
+// garbage collected, because it has no revision history limit.
+func GarbageCollectionDisabled() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeRevisionGarbageCollectionMode,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonGarbageCollectionDisabled,
+		Message:            "Revisions are never garbage collected, because the package has no revision history limit",
+	}
+}
+
+// OwnershipDrift indicates that some of a package's revisions aren't
+// controlled by the package, for example because their owner references were
+// removed out-of-band.
+func OwnershipDrift(revisions ...string) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeRevisionOwnership,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonOwnershipDrift,
+		Message:            fmt.Sprintf("Package revisions %s are labelled as belonging to the package, but are not controlled by it", strings.Join(revisions, ", ")),
+	}
+}
+
+// OwnershipIntact indicates that all of a package's revisions are controlled
+// by the package.
+func OwnershipIntact() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeRevisionOwnership,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonOwnershipIntact,
+	}
+}
+
+// Held indicates that the named revision of a package is held active.
+func Held(revision string) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeHeld,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonHeld,
+		Message:            fmt.Sprintf("Package revision %q is held active. Remove the %s annotation to release it", revision, AnnotationHold),
+	}
+}
+
+// HeldRevisionNotFound indicates that a package can't hold the named revision
+// active, because it has no such revision.
+func HeldRevisionNotFound(revision string) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeHeld,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonHeldRevisionNotFound,
+		Message:            fmt.Sprintf("Package has no revision named %q to hold", revision),
+	}
+}
+
+// HoldReleased indicates that a package that held a revision active no longer
+// does.
+func HoldReleased() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeHeld,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonHoldReleased,
+	}
+}
+
+// GlobalFreeze indicates that the package manager won't create, activate, or
+// garbage collect a package's revisions, because a cluster-wide maintenance
+// freeze is enabled for the supplied reason. The reason may be empty.
+func GlobalFreeze(reason string) xpv1.Condition {
+	msg := "Package revisions are frozen by a cluster-wide maintenance freeze"
+	if reason != "" {
+		msg += ": " + reason
+	}
+	return xpv1.Condition{
+		Type:               TypeGlobalFreeze,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonFrozen,
+		Message:            msg,
+	}
+}
+
+// GlobalFreezeLifted indicates that a package whose revisions were frozen by a
+// cluster-wide maintenance freeze no longer is.
+func GlobalFreezeLifted() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeGlobalFreeze,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonFreezeLifted,
+	}
+}
+
+// StuckUnpacking indicates that a package has been unpacking for the supplied
+// duration, which is longer than the package manager expects.
+func StuckUnpacking(d time.Duration) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeStuck,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonStuckUnpacking,
+		Message:            fmt.Sprintf("Package has been unpacking for %s", d),
+	}
+}
+
+// Unstuck indicates that a package that was stuck unpacking has since been
+// unpacked.
+func Unstuck() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeStuck,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonUnpacked,
+	}
+}
+
+// SourceUnavailable indicates that a package's source no longer exists, for
+// example because its tag was deleted, but that its current healthy revision
+// remains active.
+func SourceUnavailable(revision string, err error) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeSourceUnavailable,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonManifestNotFound,
+		Message:            fmt.Sprintf("Package source no longer exists, so its current healthy revision %q remains active: %s", revision, err),
+	}
+}
+
+// SourceAvailable indicates that a package's source that no longer existed
+// exists again.
+func SourceAvailable() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeSourceUnavailable,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonSourceAvailable,
+	}
+}
+
+// UpgradeAvailable indicates that a package's source now resolves to
+// different content than its current revision was created from. The digest
+// of the new content may be unknown.
+func UpgradeAvailable(revision, digest string) xpv1.Condition {
+	msg := fmt.Sprintf("Package source now resolves to new content, which would create revision %q", revision)
+	if digest != "" {
+		msg = fmt.Sprintf("Package source now resolves to digest %s, which would create revision %q", digest, revision)
+	}
+	return xpv1.Condition{
+		Type:               TypeUpgradeAvailable,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonNewerDigestAvailable,
+		Message:            msg + ". Set the package's pull policy to Always to upgrade",
+	}
+}
+
+// UpToDate indicates that a package that had an upgrade available no longer
+// does.
+func UpToDate() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeUpgradeAvailable,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonUpToDate,
+	}
+}
+
+// UsesDeprecatedAPIs indicates that a package's contents use or define the
+// supplied deprecated APIs. It's informational - it doesn't stop the package
+// from becoming active.
+func UsesDeprecatedAPIs(apis ...string) xpv1.Condition {
+	names := apis
+	if len(names) > maxDeprecatedAPIs {
+		names = append(slices.Clone(names[:maxDeprecatedAPIs]), fmt.Sprintf("and %d more", len(apis)-maxDeprecatedAPIs))
+	}
+	return xpv1.Condition{
+		Type:               TypeDeprecatedAPIs,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonDeprecatedAPIs,
+		Message:            fmt.Sprintf("Package uses or defines deprecated APIs: %s", strings.Join(names, ", ")),
+	}
+}
+
+// NoDeprecatedAPIs indicates that a package whose contents used deprecated
+// APIs no longer does.
+func NoDeprecatedAPIs() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeDeprecatedAPIs,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonNoDeprecatedAPIs,
+	}
+}
+
+// RevisionUpdateSkipped indicates that the package manager skipped updating a
+// package's current revision because the update would change immutable fields.
+func RevisionUpdateSkipped(err error) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeRevisionUpdated,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonImmutableRevisionField,
+		Message:            err.Error(),
+	}
+}
+
+// RevisionLimitExceeded indicates that the package manager won't create a new
+// revision for a package because the package already has the maximum number
+// of revisions.
+func RevisionLimitExceeded(revisions, limit int) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeRevisionUpdated,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonRevisionLimitExceeded,
+		Message:            fmt.Sprintf("Package has %d revisions, which is at or above the maximum of %d. No new revisions will be created until some are deleted.", revisions, limit),
+	}
+}
+
+// RevisionUpdated indicates that the package manager updated a package's
+// current revision to match the package.
+func RevisionUpdated() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeRevisionUpdated,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonRevisionUpdated,
+	}
+}
+
 // Inactive indicates that the package manager is waiting for a package
 // revision to be transitioned to an active state.
 func Inactive() xpv1.Condition {
@@ -94,6 +655,70 @@ func Inactive() xpv1.Condition {
 	}
 }
 
+// DesiredRevisionNotFound indicates that the package manager can't roll a
+// package back to its desired revision number, because the package has no
+// revision with that number.
+func DesiredRevisionNotFound(revision int64) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeInstalled,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonDesiredRevisionNotFound,
+		Message:            fmt.Sprintf("Package has no revision with revision number %d", revision),
+	}
+}
+
+// AliasNotFound indicates that a package's source refers to a
+// PackageSourceAlias that doesn't exist.
+func AliasNotFound(alias string) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeInstalled,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonAliasNotFound,
+		Message:            fmt.Sprintf("Package source refers to PackageSourceAlias %q, which doesn't exist", alias),
+	}
+}
+
+// QuotaExceeded indicates that the package manager won't activate a package's
+// current revision, because doing so would give the package's tenant more
+// active revisions than its quota allows.
+func QuotaExceeded(tenant string, quota int) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeInstalled,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonQuotaExceeded,
+		Message:            fmt.Sprintf("Package is inactive because tenant %q already has its quota of %d active package revisions", tenant, quota),
+	}
+}
+
+// RuntimeConfigMissing indicates that the package manager won't activate a
+// package's current revision, because the runtime config the package
+// references doesn't exist.
+func RuntimeConfigMissing(name string) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeInstalled,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonRuntimeConfigMissing,
+		Message:            fmt.Sprintf("Package is inactive because its runtime config %q doesn't exist", name),
+	}
+}
+
+// DependencyGarbageCollecting indicates that the package manager won't
+// activate a package's current revision yet, because the supplied dependencies
+// are garbage collecting their old revisions.
+func DependencyGarbageCollecting(dependencies ...string) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeInstalled,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonDependencyGarbageCollecting,
+		Message:            fmt.Sprintf("Package is inactive until its dependencies %s finish garbage collecting their old revisions", strings.Join(dependencies, ", ")),
+	}
+}
+
 // Active indicates that the package manager has installed and activated
 // a package revision.
 func Active() xpv1.Condition {
@@ -135,6 +760,51 @@ func UnknownHealth() xpv1.Condition {
 	}
 }
 
+// EstablishingCRDs indicates that the current revision is healthy, but is
+// waiting for the supplied CRDs it installed to become established.
+func EstablishingCRDs(crds ...string) xpv1.Condition {
+	names := crds
+	if len(names) > maxEstablishingCRDs {
+		names = append(slices.Clone(names[:maxEstablishingCRDs]), fmt.Sprintf("and %d more", len(crds)-maxEstablishingCRDs))
+	}
+	return xpv1.Condition{
+		Type:               TypeHealthy,
+		Status:             corev1.ConditionUnknown,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonEstablishingCRDs,
+		Message:            fmt.Sprintf("Waiting for CRDs to become established: %s", strings.Join(names, ", ")),
+	}
+}
+
+// Stabilizing indicates that the current revision is healthy, but must remain
+// healthy for the supplied duration before the package is reported healthy.
+func Stabilizing(remaining time.Duration) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeHealthy,
+		Status:             corev1.ConditionUnknown,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonStabilizing,
+		Message:            fmt.Sprintf("Package revision is healthy, but must remain healthy for another %s", remaining.Round(time.Second)),
+	}
+}
+
+// PartiallyHealthy indicates that the current revision is healthy, but the
+// active revisions of the supplied packages in the same provider family
+// aren't.
+func PartiallyHealthy(members ...string) xpv1.Condition {
+	names := members
+	if len(names) > maxUnhealthyFamilyMembers {
+		names = append(slices.Clone(names[:maxUnhealthyFamilyMembers]), fmt.Sprintf("and %d more", len(members)-maxUnhealthyFamilyMembers))
+	}
+	return xpv1.Condition{
+		Type:               TypeHealthy,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonPartiallyHealthy,
+		Message:            fmt.Sprintf("Package revision is healthy, but these members of its provider family are unhealthy: %s", strings.Join(names, ", ")),
+	}
+}
+
 // AwaitingVerification indicates that the package revision reconciler is
 // waiting for a package's signature to be verified.
 func AwaitingVerification() xpv1.Condition {
@@ -20,6 +20,7 @@ import (
 	"slices"
 
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
@@ -43,6 +44,91 @@ const (
 	// revisions, and can be used to select all provider revisions that belong
 	// to a particular family. It is not added to providers, only revisions.
 	LabelProviderFamily = "pkg.crossplane.io/provider-family"
+
+	// AnnotationActivationPolicyOverride can be set on a package to override
+	// its spec.revisionActivationPolicy. This allows the same package manifest
+	// to be applied to environments that activate revisions differently. The
+	// override is ignored unless it's a valid revision activation policy.
+	AnnotationActivationPolicyOverride = "pkg.crossplane.io/activation-policy-override"
+
+	// AnnotationExternallyManaged can be set to "true" on a package revision
+	// that was created by something other than the package manager and
+	// adopted by a package. The package manager never garbage collects an
+	// externally managed revision, or adds owner references to it.
+	AnnotationExternallyManaged = "pkg.crossplane.io/externally-managed"
+
+	// AnnotationSBOMReferences may be set by the package manager on a package
+	// to a comma separated list of references to the software bill of
+	// materials (SBOM) artifacts that refer to its current revision's image.
+	// The list is truncated if the image has many SBOMs.
+	AnnotationSBOMReferences = "pkg.crossplane.io/sbom-references"
+
+	// AnnotationDebug can be set to "true" on a package to make the package
+	// manager log the details of how it reconciles that package, without
+	// increasing the log verbosity for every package.
+	AnnotationDebug = "pkg.crossplane.io/debug"
+
+	// AnnotationResolvedDigest is set by the package manager on a package to
+	// the digest of the image its current revision resolved to, so that
+	// tooling can read it without parsing the package's status. It may also
+	// be set on the package's active revision.
+	AnnotationResolvedDigest = "pkg.crossplane.io/resolved-digest"
+
+	// AnnotationReconciledBy may be set by the package manager on a package
+	// to the id of the package manager instance that last reconciled it
+	// successfully. It helps to diagnose which replica or shard acted on a
+	// package.
+	AnnotationReconciledBy = "pkg.crossplane.io/reconciled-by"
+
+	// AnnotationPackageGeneration is set by the package manager on each
+	// package revision it creates to the metadata.generation of the package
+	// that produced it.
+	AnnotationPackageGeneration = "pkg.crossplane.io/package-generation"
+
+	// AnnotationSourceTag is set by the package manager on each package
+	// revision it creates to the tag its package's source specified, if any.
+	// It records which tag a revision came from even though the revision's
+	// package is resolved to a digest.
+	AnnotationSourceTag = "pkg.crossplane.io/source-tag"
+
+	// AnnotationCreationReason can be set by the package manager on each
+	// package revision it creates to why it created the revision, for example
+	// because the tag its package's source specified moved to a new image.
+	AnnotationCreationReason = "pkg.crossplane.io/creation-reason"
+
+	// AnnotationHold can be set on a package to the name of one of its
+	// revisions to hold that revision active. The package manager doesn't
+	// resolve, create, or activate other revisions of a held package, even
+	// if its source changes. Removing the annotation releases the hold.
+	AnnotationHold = "pkg.crossplane.io/hold"
+
+	// AnnotationPausedUntil can be set on a package to an RFC3339 timestamp
+	// to pause reconciling the package until that time. The package manager
+	// removes the annotation and resumes reconciling the package once the
+	// time passes.
+	AnnotationPausedUntil = "pkg.crossplane.io/paused-until"
+
+	// AnnotationLastAction may be set by the package manager on a package to
+	// a JSON object recording the last action it took on the package's
+	// revisions - the action, the revision it was taken on, and why. It's
+	// only updated when the package manager takes an action, so it doesn't
+	// change every time the package is reconciled.
+	AnnotationLastAction = "pkg.crossplane.io/last-action"
+)
+
+// Reasons the package manager creates a package revision, recorded using the
+// AnnotationCreationReason annotation.
+const (
+	// CreationReasonInitial indicates the package had no revisions.
+	CreationReasonInitial = "initial"
+
+	// CreationReasonSourceChanged indicates the package's source changed,
+	// for example to a new version.
+	CreationReasonSourceChanged = "source-changed"
+
+	// CreationReasonTagMoved indicates the package's source didn't change,
+	// but now resolves to a different image.
+	CreationReasonTagMoved = "tag-moved"
 )
 
 var (
@@ -52,6 +138,10 @@ var (
 	// ManualActivation indicates that a user will manually activate package
 	// revisions.
 	ManualActivation RevisionActivationPolicy = "Manual"
+	// HighestHealthyActivation indicates that package should automatically
+	// activate package revisions, but keep its highest numbered healthy
+	// revision active while its newest revision is unhealthy.
+	HighestHealthyActivation RevisionActivationPolicy = "HighestHealthy"
 )
 
 // RefNames converts a slice of LocalObjectReferences to a slice of strings.
@@ -118,6 +208,7 @@ type Package interface { //nolint:interfacebloat // TODO(negz): Could we break t
 	resource.Object
 	resource.Conditioned
 
+	GetConditions() []xpv1.Condition
 	CleanConditions()
 
 	GetSource() string
@@ -129,6 +220,12 @@ type Package interface { //nolint:interfacebloat // TODO(negz): Could we break t
 	GetPackagePullSecrets() []corev1.LocalObjectReference
 	SetPackagePullSecrets(s []corev1.LocalObjectReference)
 
+	GetServiceAccountName() string
+	SetServiceAccountName(n string)
+
+	GetExpectedDigest() string
+	SetExpectedDigest(d string)
+
 	GetPackagePullPolicy() *corev1.PullPolicy
 	SetPackagePullPolicy(i *corev1.PullPolicy)
 
@@ -156,6 +253,45 @@ type Package interface { //nolint:interfacebloat // TODO(negz): Could we break t
 
 	GetResolvedSource() string
 	SetResolvedSource(s string)
+
+	GetResolvedAlias() string
+	SetResolvedAlias(name string)
+
+	GetEffectiveRevisionHistoryLimit() *int64
+	SetEffectiveRevisionHistoryLimit(l *int64)
+
+	GetTotalRevisionsCreated() int64
+	SetTotalRevisionsCreated(n int64)
+
+	GetRevisionDiff() *RevisionDiff
+	SetRevisionDiff(d *RevisionDiff)
+
+	GetRevisionSummaries() []RevisionSummary
+	SetRevisionSummaries(s []RevisionSummary)
+
+	GetActivationDelay() *metav1.Duration
+	SetActivationDelay(d *metav1.Duration)
+
+	GetNextPollTime() *metav1.Time
+	SetNextPollTime(t *metav1.Time)
+
+	GetLastResolverError() string
+	SetLastResolverError(err string)
+
+	GetDesiredRevisionNumber() *int64
+	SetDesiredRevisionNumber(n *int64)
+
+	GetDeletionPolicy() xpv1.DeletionPolicy
+	SetDeletionPolicy(d xpv1.DeletionPolicy)
+
+	GetResolvedDependencies() []ResolvedDependency
+	SetResolvedDependencies(d []ResolvedDependency)
+
+	GetHealthSourceRevision() *string
+	SetHealthSourceRevision(r *string)
+
+	GetGarbageCollection() *GarbageCollectionResult
+	SetGarbageCollection(r *GarbageCollectionResult)
 }
 
 // GetCondition of this Provider.
@@ -168,6 +304,11 @@ func (p *Provider) SetConditions(c ...xpv1.Condition) {
 	p.Status.SetConditions(c...)
 }
 
+// GetConditions of this Provider.
+func (p *Provider) GetConditions() []xpv1.Condition {
+	return p.Status.Conditions
+}
+
 // CleanConditions removes all conditions.
 func (p *Provider) CleanConditions() {
 	p.Status.Conditions = []xpv1.Condition{}
@@ -203,6 +344,26 @@ func (p *Provider) SetPackagePullSecrets(s []corev1.LocalObjectReference) {
 	p.Spec.PackagePullSecrets = s
 }
 
+// GetServiceAccountName of this Provider.
+func (p *Provider) GetServiceAccountName() string {
+	return p.Spec.ServiceAccountName
+}
+
+// SetServiceAccountName of this Provider.
+func (p *Provider) SetServiceAccountName(n string) {
+	p.Spec.ServiceAccountName = n
+}
+
+// GetExpectedDigest of this Provider.
+func (p *Provider) GetExpectedDigest() string {
+	return p.Spec.ExpectedDigest
+}
+
+// SetExpectedDigest of this Provider.
+func (p *Provider) SetExpectedDigest(d string) {
+	p.Spec.ExpectedDigest = d
+}
+
 // GetPackagePullPolicy of this Provider.
 func (p *Provider) GetPackagePullPolicy() *corev1.PullPolicy {
 	return p.Spec.PackagePullPolicy
@@ -318,6 +479,136 @@ func (p *Provider) SetResolvedSource(s string) {
 	p.Status.ResolvedPackage = s
 }
 
+// GetResolvedAlias of this Provider.
+func (p *Provider) GetResolvedAlias() string {
+	return p.Status.ResolvedAlias
+}
+
+// SetResolvedAlias of this Provider.
+func (p *Provider) SetResolvedAlias(name string) {
+	p.Status.ResolvedAlias = name
+}
+
+// GetEffectiveRevisionHistoryLimit of this Provider.
+func (p *Provider) GetEffectiveRevisionHistoryLimit() *int64 {
+	return p.Status.EffectiveRevisionHistoryLimit
+}
+
+// SetEffectiveRevisionHistoryLimit of this Provider.
+func (p *Provider) SetEffectiveRevisionHistoryLimit(l *int64) {
+	p.Status.EffectiveRevisionHistoryLimit = l
+}
+
+// GetTotalRevisionsCreated of this Provider.
+func (p *Provider) GetTotalRevisionsCreated() int64 {
+	return p.Status.TotalRevisionsCreated
+}
+
+// SetTotalRevisionsCreated of this Provider.
+func (p *Provider) SetTotalRevisionsCreated(n int64) {
+	p.Status.TotalRevisionsCreated = n
+}
+
+// GetRevisionDiff of this Provider.
+func (p *Provider) GetRevisionDiff() *RevisionDiff {
+	return p.Status.RevisionDiff
+}
+
+// SetRevisionDiff of this Provider.
+func (p *Provider) SetRevisionDiff(d *RevisionDiff) {
+	p.Status.RevisionDiff = d
+}
+
+// GetRevisionSummaries of this Provider.
+func (p *Provider) GetRevisionSummaries() []RevisionSummary {
+	return p.Status.Revisions
+}
+
+// SetRevisionSummaries of this Provider.
+func (p *Provider) SetRevisionSummaries(s []RevisionSummary) {
+	p.Status.Revisions = s
+}
+
+// GetActivationDelay of this Provider.
+func (p *Provider) GetActivationDelay() *metav1.Duration {
+	return p.Spec.ActivationDelay
+}
+
+// SetActivationDelay of this Provider.
+func (p *Provider) SetActivationDelay(d *metav1.Duration) {
+	p.Spec.ActivationDelay = d
+}
+
+// GetNextPollTime of this Provider.
+func (p *Provider) GetNextPollTime() *metav1.Time {
+	return p.Status.NextPollTime
+}
+
+// SetNextPollTime of this Provider.
+func (p *Provider) SetNextPollTime(t *metav1.Time) {
+	p.Status.NextPollTime = t
+}
+
+// GetLastResolverError of this Provider.
+func (p *Provider) GetLastResolverError() string {
+	return p.Status.LastResolverError
+}
+
+// SetLastResolverError of this Provider.
+func (p *Provider) SetLastResolverError(err string) {
+	p.Status.LastResolverError = err
+}
+
+// GetDesiredRevisionNumber of this Provider.
+func (p *Provider) GetDesiredRevisionNumber() *int64 {
+	return p.Spec.DesiredRevisionNumber
+}
+
+// SetDesiredRevisionNumber of this Provider.
+func (p *Provider) SetDesiredRevisionNumber(n *int64) {
+	p.Spec.DesiredRevisionNumber = n
+}
+
+// GetDeletionPolicy of this Provider.
+func (p *Provider) GetDeletionPolicy() xpv1.DeletionPolicy {
+	return p.Spec.DeletionPolicy
+}
+
+// SetDeletionPolicy of this Provider.
+func (p *Provider) SetDeletionPolicy(d xpv1.DeletionPolicy) {
+	p.Spec.DeletionPolicy = d
+}
+
+// GetResolvedDependencies of this Provider.
+func (p *Provider) GetResolvedDependencies() []ResolvedDependency {
+	return p.Status.ResolvedDependencies
+}
+
+// SetResolvedDependencies of this Provider.
+func (p *Provider) SetResolvedDependencies(d []ResolvedDependency) {
+	p.Status.ResolvedDependencies = d
+}
+
+// GetHealthSourceRevision of this Provider.
+func (p *Provider) GetHealthSourceRevision() *string {
+	return p.Spec.HealthSourceRevision
+}
+
+// SetHealthSourceRevision of this Provider.
+func (p *Provider) SetHealthSourceRevision(r *string) {
+	p.Spec.HealthSourceRevision = r
+}
+
+// GetGarbageCollection of this Provider.
+func (p *Provider) GetGarbageCollection() *GarbageCollectionResult {
+	return p.Status.GarbageCollection
+}
+
+// SetGarbageCollection of this Provider.
+func (p *Provider) SetGarbageCollection(r *GarbageCollectionResult) {
+	p.Status.GarbageCollection = r
+}
+
 // GetCondition of this Configuration.
 func (p *Configuration) GetCondition(ct xpv1.ConditionType) xpv1.Condition {
 	return p.Status.GetCondition(ct)
@@ -328,6 +619,11 @@ func (p *Configuration) SetConditions(c ...xpv1.Condition) {
 	p.Status.SetConditions(c...)
 }
 
+// GetConditions of this Configuration.
+func (p *Configuration) GetConditions() []xpv1.Condition {
+	return p.Status.Conditions
+}
+
 // CleanConditions removes all conditions.
 func (p *Configuration) CleanConditions() {
 	p.Status.Conditions = []xpv1.Condition{}
@@ -363,6 +659,26 @@ func (p *Configuration) SetPackagePullSecrets(s []corev1.LocalObjectReference) {
 	p.Spec.PackagePullSecrets = s
 }
 
+// GetServiceAccountName of this Configuration.
+func (p *Configuration) GetServiceAccountName() string {
+	return p.Spec.ServiceAccountName
+}
+
+// SetServiceAccountName of this Configuration.
+func (p *Configuration) SetServiceAccountName(n string) {
+	p.Spec.ServiceAccountName = n
+}
+
+// GetExpectedDigest of this Configuration.
+func (p *Configuration) GetExpectedDigest() string {
+	return p.Spec.ExpectedDigest
+}
+
+// SetExpectedDigest of this Configuration.
+func (p *Configuration) SetExpectedDigest(d string) {
+	p.Spec.ExpectedDigest = d
+}
+
 // GetPackagePullPolicy of this Configuration.
 func (p *Configuration) GetPackagePullPolicy() *corev1.PullPolicy {
 	return p.Spec.PackagePullPolicy
@@ -458,6 +774,136 @@ func (p *Configuration) SetResolvedSource(s string) {
 	p.Status.ResolvedPackage = s
 }
 
+// GetResolvedAlias of this Configuration.
+func (p *Configuration) GetResolvedAlias() string {
+	return p.Status.ResolvedAlias
+}
+
+// SetResolvedAlias of this Configuration.
+func (p *Configuration) SetResolvedAlias(name string) {
+	p.Status.ResolvedAlias = name
+}
+
+// GetEffectiveRevisionHistoryLimit of this Configuration.
+func (p *Configuration) GetEffectiveRevisionHistoryLimit() *int64 {
+	return p.Status.EffectiveRevisionHistoryLimit
+}
+
+// SetEffectiveRevisionHistoryLimit of this Configuration.
+func (p *Configuration) SetEffectiveRevisionHistoryLimit(l *int64) {
+	p.Status.EffectiveRevisionHistoryLimit = l
+}
+
+// GetTotalRevisionsCreated of this Configuration.
+func (p *Configuration) GetTotalRevisionsCreated() int64 {
+	return p.Status.TotalRevisionsCreated
+}
+
+// SetTotalRevisionsCreated of this Configuration.
+func (p *Configuration) SetTotalRevisionsCreated(n int64) {
+	p.Status.TotalRevisionsCreated = n
+}
+
+// GetRevisionDiff of this Configuration.
+func (p *Configuration) GetRevisionDiff() *RevisionDiff {
+	return p.Status.RevisionDiff
+}
+
+// SetRevisionDiff of this Configuration.
+func (p *Configuration) SetRevisionDiff(d *RevisionDiff) {
+	p.Status.RevisionDiff = d
+}
+
+// GetRevisionSummaries of this Configuration.
+func (p *Configuration) GetRevisionSummaries() []RevisionSummary {
+	return p.Status.Revisions
+}
+
+// SetRevisionSummaries of this Configuration.
+func (p *Configuration) SetRevisionSummaries(s []RevisionSummary) {
+	p.Status.Revisions = s
+}
+
+// GetActivationDelay of this Configuration.
+func (p *Configuration) GetActivationDelay() *metav1.Duration {
+	return p.Spec.ActivationDelay
+}
+
+// SetActivationDelay of this Configuration.
+func (p *Configuration) SetActivationDelay(d *metav1.Duration) {
+	p.Spec.ActivationDelay = d
+}
+
+// GetNextPollTime of this Configuration.
+func (p *Configuration) GetNextPollTime() *metav1.Time {
+	return p.Status.NextPollTime
+}
+
+// SetNextPollTime of this Configuration.
+func (p *Configuration) SetNextPollTime(t *metav1.Time) {
+	p.Status.NextPollTime = t
+}
+
+// GetLastResolverError of this Configuration.
+func (p *Configuration) GetLastResolverError() string {
+	return p.Status.LastResolverError
+}
+
+// SetLastResolverError of this Configuration.
+func (p *Configuration) SetLastResolverError(err string) {
+	p.Status.LastResolverError = err
+}
+
+// GetDesiredRevisionNumber of this Configuration.
+func (p *Configuration) GetDesiredRevisionNumber() *int64 {
+	return p.Spec.DesiredRevisionNumber
+}
+
+// SetDesiredRevisionNumber of this Configuration.
+func (p *Configuration) SetDesiredRevisionNumber(n *int64) {
+	p.Spec.DesiredRevisionNumber = n
+}
+
+// GetDeletionPolicy of this Configuration.
+func (p *Configuration) GetDeletionPolicy() xpv1.DeletionPolicy {
+	return p.Spec.DeletionPolicy
+}
+
+// SetDeletionPolicy of this Configuration.
+func (p *Configuration) SetDeletionPolicy(d xpv1.DeletionPolicy) {
+	p.Spec.DeletionPolicy = d
+}
+
+// GetResolvedDependencies of this Configuration.
+func (p *Configuration) GetResolvedDependencies() []ResolvedDependency {
+	return p.Status.ResolvedDependencies
+}
+
+// SetResolvedDependencies of this Configuration.
+func (p *Configuration) SetResolvedDependencies(d []ResolvedDependency) {
+	p.Status.ResolvedDependencies = d
+}
+
+// GetHealthSourceRevision of this Configuration.
+func (p *Configuration) GetHealthSourceRevision() *string {
+	return p.Spec.HealthSourceRevision
+}
+
+// SetHealthSourceRevision of this Configuration.
+func (p *Configuration) SetHealthSourceRevision(r *string) {
+	p.Spec.HealthSourceRevision = r
+}
+
+// GetGarbageCollection of this Configuration.
+func (p *Configuration) GetGarbageCollection() *GarbageCollectionResult {
+	return p.Status.GarbageCollection
+}
+
+// SetGarbageCollection of this Configuration.
+func (p *Configuration) SetGarbageCollection(r *GarbageCollectionResult) {
+	p.Status.GarbageCollection = r
+}
+
 // PackageRevisionWithRuntime is the interface satisfied by revision of packages
 // with runtime types.
 // +k8s:deepcopy-gen=false
@@ -549,6 +995,20 @@ type PackageRevision interface { //nolint:interfacebloat // TODO(negz): Could we
 
 	GetResolvedSource() string
 	SetResolvedSource(s string)
+
+	GetResolvedPlatform() string
+	SetResolvedPlatform(platform string)
+
+	GetResolvedImageSize() *int64
+	SetResolvedImageSize(s *int64)
+
+	GetResolvedDigest() string
+	SetResolvedDigest(d string)
+
+	GetProvenance() []ProvenanceStep
+	SetProvenance(steps []ProvenanceStep)
+	GetSBOMReferences() []string
+	SetSBOMReferences(refs []string)
 }
 
 // GetCondition of this ProviderRevision.
@@ -723,6 +1183,56 @@ func (p *ProviderRevision) SetResolvedSource(s string) {
 	p.Status.ResolvedPackage = s
 }
 
+// GetResolvedPlatform of this ProviderRevision.
+func (p *ProviderRevision) GetResolvedPlatform() string {
+	return p.Status.ResolvedPlatform
+}
+
+// SetResolvedPlatform of this ProviderRevision.
+func (p *ProviderRevision) SetResolvedPlatform(platform string) {
+	p.Status.ResolvedPlatform = platform
+}
+
+// GetResolvedImageSize of this ProviderRevision.
+func (p *ProviderRevision) GetResolvedImageSize() *int64 {
+	return p.Status.ResolvedImageSize
+}
+
+// SetResolvedImageSize of this ProviderRevision.
+func (p *ProviderRevision) SetResolvedImageSize(s *int64) {
+	p.Status.ResolvedImageSize = s
+}
+
+// GetResolvedDigest of this ProviderRevision.
+func (p *ProviderRevision) GetResolvedDigest() string {
+	return p.Status.ResolvedDigest
+}
+
+// SetResolvedDigest of this ProviderRevision.
+func (p *ProviderRevision) SetResolvedDigest(d string) {
+	p.Status.ResolvedDigest = d
+}
+
+// GetProvenance of this ProviderRevision.
+func (p *ProviderRevision) GetProvenance() []ProvenanceStep {
+	return p.Status.Provenance
+}
+
+// SetProvenance of this ProviderRevision.
+func (p *ProviderRevision) SetProvenance(steps []ProvenanceStep) {
+	p.Status.Provenance = steps
+}
+
+// GetSBOMReferences of this ProviderRevision.
+func (p *ProviderRevision) GetSBOMReferences() []string {
+	return p.Status.SBOMReferences
+}
+
+// SetSBOMReferences of this ProviderRevision.
+func (p *ProviderRevision) SetSBOMReferences(refs []string) {
+	p.Status.SBOMReferences = refs
+}
+
 // GetCondition of this ConfigurationRevision.
 func (p *ConfigurationRevision) GetCondition(ct xpv1.ConditionType) xpv1.Condition {
 	return p.Status.GetCondition(ct)
@@ -865,6 +1375,56 @@ func (p *ConfigurationRevision) SetResolvedSource(s string) {
 	p.Status.ResolvedPackage = s
 }
 
+// GetResolvedPlatform of this ConfigurationRevision.
+func (p *ConfigurationRevision) GetResolvedPlatform() string {
+	return p.Status.ResolvedPlatform
+}
+
+// SetResolvedPlatform of this ConfigurationRevision.
+func (p *ConfigurationRevision) SetResolvedPlatform(platform string) {
+	p.Status.ResolvedPlatform = platform
+}
+
+// GetResolvedImageSize of this ConfigurationRevision.
+func (p *ConfigurationRevision) GetResolvedImageSize() *int64 {
+	return p.Status.ResolvedImageSize
+}
+
+// SetResolvedImageSize of this ConfigurationRevision.
+func (p *ConfigurationRevision) SetResolvedImageSize(s *int64) {
+	p.Status.ResolvedImageSize = s
+}
+
+// GetResolvedDigest of this ConfigurationRevision.
+func (p *ConfigurationRevision) GetResolvedDigest() string {
+	return p.Status.ResolvedDigest
+}
+
+// SetResolvedDigest of this ConfigurationRevision.
+func (p *ConfigurationRevision) SetResolvedDigest(d string) {
+	p.Status.ResolvedDigest = d
+}
+
+// GetProvenance of this ConfigurationRevision.
+func (p *ConfigurationRevision) GetProvenance() []ProvenanceStep {
+	return p.Status.Provenance
+}
+
+// SetProvenance of this ConfigurationRevision.
+func (p *ConfigurationRevision) SetProvenance(steps []ProvenanceStep) {
+	p.Status.Provenance = steps
+}
+
+// GetSBOMReferences of this ConfigurationRevision.
+func (p *ConfigurationRevision) GetSBOMReferences() []string {
+	return p.Status.SBOMReferences
+}
+
+// SetSBOMReferences of this ConfigurationRevision.
+func (p *ConfigurationRevision) SetSBOMReferences(refs []string) {
+	p.Status.SBOMReferences = refs
+}
+
 // PackageRevisionList is the interface satisfied by package revision list
 // types.
 // +k8s:deepcopy-gen=false
@@ -928,6 +1488,11 @@ func (f *Function) SetConditions(c ...xpv1.Condition) {
 	f.Status.SetConditions(c...)
 }
 
+// GetConditions of this Function.
+func (f *Function) GetConditions() []xpv1.Condition {
+	return f.Status.Conditions
+}
+
 // CleanConditions removes all conditions.
 func (f *Function) CleanConditions() {
 	f.Status.Conditions = []xpv1.Condition{}
@@ -963,6 +1528,26 @@ func (f *Function) SetPackagePullSecrets(s []corev1.LocalObjectReference) {
 	f.Spec.PackagePullSecrets = s
 }
 
+// GetServiceAccountName of this Function.
+func (f *Function) GetServiceAccountName() string {
+	return f.Spec.ServiceAccountName
+}
+
+// SetServiceAccountName of this Function.
+func (f *Function) SetServiceAccountName(n string) {
+	f.Spec.ServiceAccountName = n
+}
+
+// GetExpectedDigest of this Function.
+func (f *Function) GetExpectedDigest() string {
+	return f.Spec.ExpectedDigest
+}
+
+// SetExpectedDigest of this Function.
+func (f *Function) SetExpectedDigest(d string) {
+	f.Spec.ExpectedDigest = d
+}
+
 // GetPackagePullPolicy of this Function.
 func (f *Function) GetPackagePullPolicy() *corev1.PullPolicy {
 	return f.Spec.PackagePullPolicy
@@ -1078,6 +1663,136 @@ func (f *Function) SetResolvedSource(s string) {
 	f.Status.ResolvedPackage = s
 }
 
+// GetResolvedAlias of this Function.
+func (f *Function) GetResolvedAlias() string {
+	return f.Status.ResolvedAlias
+}
+
+// SetResolvedAlias of this Function.
+func (f *Function) SetResolvedAlias(name string) {
+	f.Status.ResolvedAlias = name
+}
+
+// GetEffectiveRevisionHistoryLimit of this Function.
+func (f *Function) GetEffectiveRevisionHistoryLimit() *int64 {
+	return f.Status.EffectiveRevisionHistoryLimit
+}
+
+// SetEffectiveRevisionHistoryLimit of this Function.
+func (f *Function) SetEffectiveRevisionHistoryLimit(l *int64) {
+	f.Status.EffectiveRevisionHistoryLimit = l
+}
+
+// GetTotalRevisionsCreated of this Function.
+func (f *Function) GetTotalRevisionsCreated() int64 {
+	return f.Status.TotalRevisionsCreated
+}
+
+// SetTotalRevisionsCreated of this Function.
+func (f *Function) SetTotalRevisionsCreated(n int64) {
+	f.Status.TotalRevisionsCreated = n
+}
+
+// GetRevisionDiff of this Function.
+func (f *Function) GetRevisionDiff() *RevisionDiff {
+	return f.Status.RevisionDiff
+}
+
+// SetRevisionDiff of this Function.
+func (f *Function) SetRevisionDiff(d *RevisionDiff) {
+	f.Status.RevisionDiff = d
+}
+
+// GetRevisionSummaries of this Function.
+func (f *Function) GetRevisionSummaries() []RevisionSummary {
+	return f.Status.Revisions
+}
+
+// SetRevisionSummaries of this Function.
+func (f *Function) SetRevisionSummaries(s []RevisionSummary) {
+	f.Status.Revisions = s
+}
+
+// GetActivationDelay of this Function.
+func (f *Function) GetActivationDelay() *metav1.Duration {
+	return f.Spec.ActivationDelay
+}
+
+// SetActivationDelay of this Function.
+func (f *Function) SetActivationDelay(d *metav1.Duration) {
+	f.Spec.ActivationDelay = d
+}
+
+// GetNextPollTime of this Function.
+func (f *Function) GetNextPollTime() *metav1.Time {
+	return f.Status.NextPollTime
+}
+
+// SetNextPollTime of this Function.
+func (f *Function) SetNextPollTime(t *metav1.Time) {
+	f.Status.NextPollTime = t
+}
+
+// GetLastResolverError of this Function.
+func (f *Function) GetLastResolverError() string {
+	return f.Status.LastResolverError
+}
+
+// SetLastResolverError of this Function.
+func (f *Function) SetLastResolverError(err string) {
+	f.Status.LastResolverError = err
+}
+
+// GetDesiredRevisionNumber of this Function.
+func (f *Function) GetDesiredRevisionNumber() *int64 {
+	return f.Spec.DesiredRevisionNumber
+}
+
+// SetDesiredRevisionNumber of this Function.
+func (f *Function) SetDesiredRevisionNumber(n *int64) {
+	f.Spec.DesiredRevisionNumber = n
+}
+
+// GetDeletionPolicy of this Function.
+func (f *Function) GetDeletionPolicy() xpv1.DeletionPolicy {
+	return f.Spec.DeletionPolicy
+}
+
+// SetDeletionPolicy of this Function.
+func (f *Function) SetDeletionPolicy(d xpv1.DeletionPolicy) {
+	f.Spec.DeletionPolicy = d
+}
+
+// GetResolvedDependencies of this Function.
+func (f *Function) GetResolvedDependencies() []ResolvedDependency {
+	return f.Status.ResolvedDependencies
+}
+
+// SetResolvedDependencies of this Function.
+func (f *Function) SetResolvedDependencies(d []ResolvedDependency) {
+	f.Status.ResolvedDependencies = d
+}
+
+// GetHealthSourceRevision of this Function.
+func (f *Function) GetHealthSourceRevision() *string {
+	return f.Spec.HealthSourceRevision
+}
+
+// SetHealthSourceRevision of this Function.
+func (f *Function) SetHealthSourceRevision(r *string) {
+	f.Spec.HealthSourceRevision = r
+}
+
+// GetGarbageCollection of this Function.
+func (f *Function) GetGarbageCollection() *GarbageCollectionResult {
+	return f.Status.GarbageCollection
+}
+
+// SetGarbageCollection of this Function.
+func (f *Function) SetGarbageCollection(r *GarbageCollectionResult) {
+	f.Status.GarbageCollection = r
+}
+
 // GetCondition of this FunctionRevision.
 func (r *FunctionRevision) GetCondition(ct xpv1.ConditionType) xpv1.Condition {
 	return r.Status.GetCondition(ct)
@@ -1250,6 +1965,56 @@ func (r *FunctionRevision) SetResolvedSource(s string) {
 	r.Status.ResolvedPackage = s
 }
 
+// GetResolvedPlatform of this FunctionRevision.
+func (r *FunctionRevision) GetResolvedPlatform() string {
+	return r.Status.ResolvedPlatform
+}
+
+// SetResolvedPlatform of this FunctionRevision.
+func (r *FunctionRevision) SetResolvedPlatform(platform string) {
+	r.Status.ResolvedPlatform = platform
+}
+
+// GetResolvedImageSize of this FunctionRevision.
+func (r *FunctionRevision) GetResolvedImageSize() *int64 {
+	return r.Status.ResolvedImageSize
+}
+
+// SetResolvedImageSize of this FunctionRevision.
+func (r *FunctionRevision) SetResolvedImageSize(s *int64) {
+	r.Status.ResolvedImageSize = s
+}
+
+// GetResolvedDigest of this FunctionRevision.
+func (r *FunctionRevision) GetResolvedDigest() string {
+	return r.Status.ResolvedDigest
+}
+
+// SetResolvedDigest of this FunctionRevision.
+func (r *FunctionRevision) SetResolvedDigest(d string) {
+	r.Status.ResolvedDigest = d
+}
+
+// GetProvenance of this FunctionRevision.
+func (r *FunctionRevision) GetProvenance() []ProvenanceStep {
+	return r.Status.Provenance
+}
+
+// SetProvenance of this FunctionRevision.
+func (r *FunctionRevision) SetProvenance(steps []ProvenanceStep) {
+	r.Status.Provenance = steps
+}
+
+// GetSBOMReferences of this FunctionRevision.
+func (r *FunctionRevision) GetSBOMReferences() []string {
+	return r.Status.SBOMReferences
+}
+
+// SetSBOMReferences of this FunctionRevision.
+func (r *FunctionRevision) SetSBOMReferences(refs []string) {
+	r.Status.SBOMReferences = refs
+}
+
 // GetRevisions of this ConfigurationRevisionList.
 func (p *FunctionRevisionList) GetRevisions() []PackageRevision {
 	prs := make([]PackageRevision, len(p.Items))
@@ -16,7 +16,12 @@ limitations under the License.
 
 package v1
 
-import corev1 "k8s.io/api/core/v1"
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+)
 
 // RevisionActivationPolicy indicates how a package should activate its
 // revisions.
@@ -27,25 +32,74 @@ type PackageSpec struct {
 	// Package is the name of the package that is being requested.
 	Package string `json:"package"`
 
+	// ExpectedDigest is the digest, for example sha256:..., that the package
+	// is expected to resolve to. The package controller won't create a new
+	// revision of a package that resolves to a different digest, which
+	// guards against a tag being repointed to different content.
+	// +optional
+	ExpectedDigest string `json:"expectedDigest,omitempty"`
+
 	// RevisionActivationPolicy specifies how the package controller should
-	// update from one revision to the next. Options are Automatic or Manual.
-	// Default is Automatic.
+	// update from one revision to the next. Options are Automatic, Manual, or
+	// HighestHealthy. HighestHealthy activates new revisions like Automatic,
+	// but keeps the highest numbered healthy revision active while the newest
+	// revision is unhealthy. Defaults to the package manager's default
+	// activation policy, which is Automatic unless configured otherwise.
 	// +optional
-	// +kubebuilder:default=Automatic
 	RevisionActivationPolicy *RevisionActivationPolicy `json:"revisionActivationPolicy,omitempty"`
 
 	// RevisionHistoryLimit dictates how the package controller cleans up old
 	// inactive package revisions.
-	// Defaults to 1. Can be disabled by explicitly setting to 0.
+	// Defaults to the package manager's default revision history limit for
+	// the package's kind, which is 1 unless configured otherwise. Can be
+	// disabled by explicitly setting to 0.
 	// +optional
-	// +kubebuilder:default=1
 	RevisionHistoryLimit *int64 `json:"revisionHistoryLimit,omitempty"`
 
+	// ActivationDelay is how long the package controller waits after creating
+	// a new package revision before activating it, when the revision
+	// activation policy is Automatic. A delay gives controllers that depend on
+	// the package time to catch up before the new revision takes over.
+	// Default is no delay.
+	// +optional
+	ActivationDelay *metav1.Duration `json:"activationDelay,omitempty"`
+
+	// DesiredRevisionNumber rolls the package back to the revision with the
+	// supplied revision number. The package controller activates that
+	// revision and deactivates all others. It doesn't create or garbage
+	// collect revisions while the package is rolled back. Unset this field to
+	// resume installing the package's current revision.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	DesiredRevisionNumber *int64 `json:"desiredRevisionNumber,omitempty"`
+
+	// DeletionPolicy specifies what happens to the package's revisions when
+	// the package is deleted. Delete deletes them, along with the objects
+	// they install. Orphan leaves them, and the objects they install, in
+	// place by removing their owner references to the package.
+	// Default is Delete.
+	// +optional
+	// +kubebuilder:default=Delete
+	DeletionPolicy xpv1.DeletionPolicy `json:"deletionPolicy,omitempty"`
+
+	// HealthSourceRevision is the name of the package revision the package
+	// controller derives the package's health from, for example when
+	// running two active revisions during a canary. The revision must belong
+	// to the package. Default is the package's current revision.
+	// +optional
+	HealthSourceRevision *string `json:"healthSourceRevision,omitempty"`
+
 	// PackagePullSecrets are named secrets in the same namespace that can be used
 	// to fetch packages from private registries.
 	// +optional
 	PackagePullSecrets []corev1.LocalObjectReference `json:"packagePullSecrets,omitempty"`
 
+	// ServiceAccountName is the name of a ServiceAccount in the same namespace
+	// whose image pull secrets can be used, in addition to PackagePullSecrets,
+	// to fetch packages from private registries.
+	// +optional
+	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+
 	// PackagePullPolicy defines the pull policy for the package.
 	// Default is IfNotPresent.
 	// +optional
@@ -98,6 +152,141 @@ type PackageStatus struct {
 	// resolution. It may be different from spec.package if the package path was
 	// rewritten using an image config.
 	ResolvedPackage string `json:"resolvedPackage,omitempty"`
+
+	// ResolvedAlias is the name of the PackageSourceAlias the package's
+	// source refers to, if any.
+	// +optional
+	ResolvedAlias string `json:"resolvedAlias,omitempty"`
+
+	// RevisionDiff summarizes how the objects installed by the current
+	// revision differ from those installed by the revision that was active
+	// when it became current. It is only set while the current revision is
+	// inactive, for example when the revision activation policy is Manual, to
+	// help decide whether to activate it.
+	// +optional
+	RevisionDiff *RevisionDiff `json:"revisionDiff,omitempty"`
+
+	// Revisions summarizes the package's most recent revisions, most recent
+	// first.
+	// +optional
+	// +kubebuilder:validation:MaxItems=10
+	Revisions []RevisionSummary `json:"revisions,omitempty"`
+
+	// ResolvedDependencies lists the package's direct dependencies, and the
+	// version of each that the package manager resolved.
+	// +optional
+	// +kubebuilder:validation:MaxItems=50
+	ResolvedDependencies []ResolvedDependency `json:"resolvedDependencies,omitempty"`
+
+	// GarbageCollection records the outcome of the most recent garbage
+	// collection of the package's revisions.
+	// +optional
+	GarbageCollection *GarbageCollectionResult `json:"garbageCollection,omitempty"`
+
+	// EffectiveRevisionHistoryLimit is the revision history limit in effect
+	// for the package - its spec.revisionHistoryLimit if set, otherwise the
+	// package manager's default for its kind. It's unset if no limit is in
+	// effect, in which case revisions are never garbage collected.
+	// +optional
+	EffectiveRevisionHistoryLimit *int64 `json:"effectiveRevisionHistoryLimit,omitempty"`
+
+	// LastResolverError is the full error the package manager most recently
+	// encountered resolving the package's revision, which may be truncated
+	// in the package's conditions. It's unset once resolution succeeds.
+	// +optional
+	LastResolverError string `json:"lastResolverError,omitempty"`
+
+	// NextPollTime is when the package manager will next reconcile the
+	// package, for example to check for new content when its package pull
+	// policy is Always. It is unset when no reconcile is scheduled.
+	// +optional
+	NextPollTime *metav1.Time `json:"nextPollTime,omitempty"`
+
+	// TotalRevisionsCreated is the number of revisions the package manager
+	// has created for the package. It only ever increases, so revisions that
+	// were garbage collected are still counted.
+	// +optional
+	TotalRevisionsCreated int64 `json:"totalRevisionsCreated,omitempty"`
+}
+
+// A RevisionSummary summarizes the state of a package revision.
+type RevisionSummary struct {
+	// Name of the revision.
+	Name string `json:"name"`
+
+	// Revision number of the revision.
+	Revision int64 `json:"revision"`
+
+	// DesiredState of the revision.
+	DesiredState PackageRevisionDesiredState `json:"desiredState"`
+
+	// Healthy indicates whether the revision is healthy. It may be True,
+	// False, or Unknown.
+	Healthy corev1.ConditionStatus `json:"healthy"`
+}
+
+// A ResolvedDependency is a dependency of a package, as resolved by the package
+// manager.
+type ResolvedDependency struct {
+	// Package is the OCI image name of the dependency, without a tag or
+	// digest.
+	Package string `json:"package"`
+
+	// Version is the tag or digest of the dependency that was resolved. It is
+	// unset if the dependency hasn't been resolved yet.
+	// +optional
+	Version string `json:"version,omitempty"`
+
+	// Healthy indicates whether the dependency is healthy. It may be True,
+	// False, or Unknown.
+	Healthy corev1.ConditionStatus `json:"healthy"`
+}
+
+// A GarbageCollectionResult records which revisions the package manager
+// collected, and which it failed to collect, the last time it garbage
+// collected a package's revisions.
+type GarbageCollectionResult struct {
+	// Collected lists the revisions that were garbage collected.
+	// +optional
+	Collected []string `json:"collected,omitempty"`
+
+	// Failed lists the revisions that couldn't be garbage collected. The
+	// package manager will try to collect them again.
+	// +optional
+	Failed []string `json:"failed,omitempty"`
+
+	// CollectedCount is the number of revisions that were garbage collected.
+	// +optional
+	CollectedCount int64 `json:"collectedCount,omitempty"`
+
+	// Time is when the package manager garbage collected the package's
+	// revisions.
+	// +optional
+	Time *metav1.Time `json:"time,omitempty"`
+}
+
+// A RevisionDiff summarizes the differences between the objects installed by
+// two package revisions.
+type RevisionDiff struct {
+	// PreviousRevision is the name of the revision the current revision was
+	// compared to - the revision that was active when it became current.
+	PreviousRevision string `json:"previousRevision"`
+
+	// AddedObjects are installed by the current revision, but not by the
+	// previous revision.
+	// +optional
+	AddedObjects []xpv1.TypedReference `json:"addedObjects,omitempty"`
+
+	// RemovedObjects are installed by the previous revision, but not by the
+	// current revision.
+	// +optional
+	RemovedObjects []xpv1.TypedReference `json:"removedObjects,omitempty"`
+
+	// ChangedObjects are installed by both revisions, but differ between
+	// them. They're only reported when the package manager is configured to
+	// parse package contents.
+	// +optional
+	ChangedObjects []xpv1.TypedReference `json:"changedObjects,omitempty"`
 }
 
 // ImageConfigRef is a reference to an image config that indicates how the
@@ -102,6 +102,31 @@ type PackageRevisionStatus struct {
 	// different from spec.image if the package path was rewritten using an
 	// image config.
 	ResolvedPackage string `json:"resolvedImage,omitempty"`
+
+	// ResolvedDigest is the digest of the package image the revision was
+	// resolved to. When the package is an OCI index of platform-specific
+	// packages it's the digest of the package selected for the platform.
+	ResolvedDigest string `json:"resolvedDigest,omitempty"`
+
+	// ResolvedPlatform is the platform of the package image the revision
+	// was resolved to, for example linux/amd64.
+	ResolvedPlatform string `json:"resolvedPlatform,omitempty"`
+
+	// ResolvedImageSize is the total size in bytes of the package image the
+	// revision was resolved to, including its config and layers. It's unset
+	// if the size of the image is unknown.
+	ResolvedImageSize *int64 `json:"resolvedImageSize,omitempty"`
+
+	// Provenance records, in order, how the package manager resolved the
+	// package's requested source to the image the revision was created
+	// from. It's unset if the package manager didn't inspect the image.
+	Provenance []ProvenanceStep `json:"provenance,omitempty"`
+
+	// SBOMReferences are references to the software bill of materials (SBOM)
+	// artifacts that refer to the package image the revision was resolved
+	// to. It's unset if the package manager doesn't discover SBOMs, or if it
+	// found none.
+	SBOMReferences []string `json:"sbomReferences,omitempty"`
 }
 
 // A ControllerReference references the controller (e.g. Deployment), if any,
@@ -110,3 +135,40 @@ type ControllerReference struct {
 	// Name of the controller.
 	Name string `json:"name"`
 }
+
+// A ProvenanceStepType is a step in resolving a package's source.
+type ProvenanceStepType string
+
+// Steps in resolving a package's source.
+const (
+	// ProvenanceStepRequested records the source the package requested.
+	ProvenanceStepRequested ProvenanceStepType = "Requested"
+
+	// ProvenanceStepAliasExpanded records the source a PackageSourceAlias
+	// expanded the requested source to.
+	ProvenanceStepAliasExpanded ProvenanceStepType = "AliasExpanded"
+
+	// ProvenanceStepRewritten records the source an ImageConfig rewrote the
+	// package's source to.
+	ProvenanceStepRewritten ProvenanceStepType = "Rewritten"
+
+	// ProvenanceStepDigestResolved records the digest the package's source
+	// resolved to.
+	ProvenanceStepDigestResolved ProvenanceStepType = "DigestResolved"
+)
+
+// A ProvenanceStep is one step in resolving a package's requested source to
+// the image a revision was created from.
+type ProvenanceStep struct {
+	// Type of the step.
+	Type ProvenanceStepType `json:"type"`
+
+	// Reference is the package source after the step. For the
+	// DigestResolved step it's the digest the source resolved to.
+	Reference string `json:"reference"`
+
+	// Via is the name of the PackageSourceAlias or ImageConfig responsible
+	// for the step, if any.
+	// +optional
+	Via string `json:"via,omitempty"`
+}
@@ -23,6 +23,7 @@ package v1
 import (
 	commonv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -377,6 +378,35 @@ func (in *FunctionStatus) DeepCopy() *FunctionStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GarbageCollectionResult) DeepCopyInto(out *GarbageCollectionResult) {
+	*out = *in
+	if in.Collected != nil {
+		in, out := &in.Collected, &out.Collected
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Failed != nil {
+		in, out := &in.Failed, &out.Failed
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Time != nil {
+		in, out := &in.Time, &out.Time
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GarbageCollectionResult.
+func (in *GarbageCollectionResult) DeepCopy() *GarbageCollectionResult {
+	if in == nil {
+		return nil
+	}
+	out := new(GarbageCollectionResult)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ImageConfigRef) DeepCopyInto(out *ImageConfigRef) {
 	*out = *in
@@ -474,6 +504,21 @@ func (in *PackageRevisionStatus) DeepCopyInto(out *PackageRevisionStatus) {
 		*out = make([]ImageConfigRef, len(*in))
 		copy(*out, *in)
 	}
+	if in.ResolvedImageSize != nil {
+		in, out := &in.ResolvedImageSize, &out.ResolvedImageSize
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Provenance != nil {
+		in, out := &in.Provenance, &out.Provenance
+		*out = make([]ProvenanceStep, len(*in))
+		copy(*out, *in)
+	}
+	if in.SBOMReferences != nil {
+		in, out := &in.SBOMReferences, &out.SBOMReferences
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PackageRevisionStatus.
@@ -519,6 +564,21 @@ func (in *PackageSpec) DeepCopyInto(out *PackageSpec) {
 		*out = new(int64)
 		**out = **in
 	}
+	if in.ActivationDelay != nil {
+		in, out := &in.ActivationDelay, &out.ActivationDelay
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.DesiredRevisionNumber != nil {
+		in, out := &in.DesiredRevisionNumber, &out.DesiredRevisionNumber
+		*out = new(int64)
+		**out = **in
+	}
+	if in.HealthSourceRevision != nil {
+		in, out := &in.HealthSourceRevision, &out.HealthSourceRevision
+		*out = new(string)
+		**out = **in
+	}
 	if in.PackagePullSecrets != nil {
 		in, out := &in.PackagePullSecrets, &out.PackagePullSecrets
 		*out = make([]corev1.LocalObjectReference, len(*in))
@@ -566,6 +626,35 @@ func (in *PackageStatus) DeepCopyInto(out *PackageStatus) {
 		*out = make([]ImageConfigRef, len(*in))
 		copy(*out, *in)
 	}
+	if in.RevisionDiff != nil {
+		in, out := &in.RevisionDiff, &out.RevisionDiff
+		*out = new(RevisionDiff)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Revisions != nil {
+		in, out := &in.Revisions, &out.Revisions
+		*out = make([]RevisionSummary, len(*in))
+		copy(*out, *in)
+	}
+	if in.ResolvedDependencies != nil {
+		in, out := &in.ResolvedDependencies, &out.ResolvedDependencies
+		*out = make([]ResolvedDependency, len(*in))
+		copy(*out, *in)
+	}
+	if in.GarbageCollection != nil {
+		in, out := &in.GarbageCollection, &out.GarbageCollection
+		*out = new(GarbageCollectionResult)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.EffectiveRevisionHistoryLimit != nil {
+		in, out := &in.EffectiveRevisionHistoryLimit, &out.EffectiveRevisionHistoryLimit
+		*out = new(int64)
+		**out = **in
+	}
+	if in.NextPollTime != nil {
+		in, out := &in.NextPollTime, &out.NextPollTime
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PackageStatus.
@@ -578,6 +667,21 @@ func (in *PackageStatus) DeepCopy() *PackageStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProvenanceStep) DeepCopyInto(out *ProvenanceStep) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProvenanceStep.
+func (in *ProvenanceStep) DeepCopy() *ProvenanceStep {
+	if in == nil {
+		return nil
+	}
+	out := new(ProvenanceStep)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Provider) DeepCopyInto(out *Provider) {
 	*out = *in
@@ -747,6 +851,66 @@ func (in *ProviderStatus) DeepCopy() *ProviderStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResolvedDependency) DeepCopyInto(out *ResolvedDependency) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResolvedDependency.
+func (in *ResolvedDependency) DeepCopy() *ResolvedDependency {
+	if in == nil {
+		return nil
+	}
+	out := new(ResolvedDependency)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RevisionDiff) DeepCopyInto(out *RevisionDiff) {
+	*out = *in
+	if in.AddedObjects != nil {
+		in, out := &in.AddedObjects, &out.AddedObjects
+		*out = make([]commonv1.TypedReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.RemovedObjects != nil {
+		in, out := &in.RemovedObjects, &out.RemovedObjects
+		*out = make([]commonv1.TypedReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.ChangedObjects != nil {
+		in, out := &in.ChangedObjects, &out.ChangedObjects
+		*out = make([]commonv1.TypedReference, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RevisionDiff.
+func (in *RevisionDiff) DeepCopy() *RevisionDiff {
+	if in == nil {
+		return nil
+	}
+	out := new(RevisionDiff)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RevisionSummary) DeepCopyInto(out *RevisionSummary) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RevisionSummary.
+func (in *RevisionSummary) DeepCopy() *RevisionSummary {
+	if in == nil {
+		return nil
+	}
+	out := new(RevisionSummary)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RuntimeConfigReference) DeepCopyInto(out *RuntimeConfigReference) {
 	*out = *in
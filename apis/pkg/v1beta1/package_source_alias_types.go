@@ -0,0 +1,67 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PackageSourceAliasPrefix prefixes package sources that refer to a
+// PackageSourceAlias, for example alias://provider-aws:v1.2.0.
+const PackageSourceAliasPrefix = "alias://"
+
+// +kubebuilder:object:root=true
+// +genclient
+// +genclient:nonNamespaced
+
+// A PackageSourceAlias maps a logical package name to a concrete OCI
+// repository. A package refers to an alias using a source of the form
+// alias://<alias-name>:<tag> or alias://<alias-name>@<digest>.
+//
+// +kubebuilder:printcolumn:name="PACKAGE",type="string",JSONPath=".spec.package"
+// +kubebuilder:printcolumn:name="CONSTRAINTS",type="string",JSONPath=".spec.constraints"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:resource:scope=Cluster,categories={crossplane}
+type PackageSourceAlias struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec PackageSourceAliasSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// PackageSourceAliasList contains a list of PackageSourceAlias.
+type PackageSourceAliasList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []PackageSourceAlias `json:"items"`
+}
+
+// PackageSourceAliasSpec specifies what a PackageSourceAlias refers to.
+type PackageSourceAliasSpec struct {
+	// Package is the OCI repository the alias refers to, without a tag or
+	// digest. For example xpkg.crossplane.io/crossplane-contrib/provider-aws.
+	// +kubebuilder:validation:MinLength=1
+	Package string `json:"package"`
+
+	// Constraints is a semantic version constraint, for example
+	// ">=1.2.0, <2.0.0". When set, packages may only refer to the alias
+	// using a tag that satisfies it.
+	// +optional
+	Constraints string `json:"constraints,omitempty"`
+}
@@ -80,10 +80,19 @@ var (
 	ImageConfigGroupVersionKind = SchemeGroupVersion.WithKind(ImageConfigKind)
 )
 
+// PackageSourceAlias type metadata.
+var (
+	PackageSourceAliasKind             = reflect.TypeOf(PackageSourceAlias{}).Name()
+	PackageSourceAliasGroupKind        = schema.GroupKind{Group: Group, Kind: PackageSourceAliasKind}.String()
+	PackageSourceAliasKindAPIVersion   = PackageSourceAliasKind + "." + SchemeGroupVersion.String()
+	PackageSourceAliasGroupVersionKind = SchemeGroupVersion.WithKind(PackageSourceAliasKind)
+)
+
 func init() {
 	SchemeBuilder.Register(&Lock{}, &LockList{})
 	SchemeBuilder.Register(&Function{}, &FunctionList{})
 	SchemeBuilder.Register(&FunctionRevision{}, &FunctionRevisionList{})
 	SchemeBuilder.Register(&DeploymentRuntimeConfig{}, &DeploymentRuntimeConfigList{})
 	SchemeBuilder.Register(&ImageConfig{}, &ImageConfigList{})
+	SchemeBuilder.Register(&PackageSourceAlias{}, &PackageSourceAliasList{})
 }
@@ -24,6 +24,7 @@ import (
 	commonv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -442,6 +443,35 @@ func (in *FunctionStatus) DeepCopy() *FunctionStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GarbageCollectionResult) DeepCopyInto(out *GarbageCollectionResult) {
+	*out = *in
+	if in.Collected != nil {
+		in, out := &in.Collected, &out.Collected
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Failed != nil {
+		in, out := &in.Failed, &out.Failed
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Time != nil {
+		in, out := &in.Time, &out.Time
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GarbageCollectionResult.
+func (in *GarbageCollectionResult) DeepCopy() *GarbageCollectionResult {
+	if in == nil {
+		return nil
+	}
+	out := new(GarbageCollectionResult)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Identity) DeepCopyInto(out *Identity) {
 	*out = *in
@@ -911,6 +941,21 @@ func (in *PackageRevisionStatus) DeepCopyInto(out *PackageRevisionStatus) {
 		*out = make([]ImageConfigRef, len(*in))
 		copy(*out, *in)
 	}
+	if in.ResolvedImageSize != nil {
+		in, out := &in.ResolvedImageSize, &out.ResolvedImageSize
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Provenance != nil {
+		in, out := &in.Provenance, &out.Provenance
+		*out = make([]ProvenanceStep, len(*in))
+		copy(*out, *in)
+	}
+	if in.SBOMReferences != nil {
+		in, out := &in.SBOMReferences, &out.SBOMReferences
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PackageRevisionStatus.
@@ -943,6 +988,79 @@ func (in *PackageRuntimeSpec) DeepCopy() *PackageRuntimeSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PackageSourceAlias) DeepCopyInto(out *PackageSourceAlias) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PackageSourceAlias.
+func (in *PackageSourceAlias) DeepCopy() *PackageSourceAlias {
+	if in == nil {
+		return nil
+	}
+	out := new(PackageSourceAlias)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PackageSourceAlias) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PackageSourceAliasList) DeepCopyInto(out *PackageSourceAliasList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]PackageSourceAlias, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PackageSourceAliasList.
+func (in *PackageSourceAliasList) DeepCopy() *PackageSourceAliasList {
+	if in == nil {
+		return nil
+	}
+	out := new(PackageSourceAliasList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PackageSourceAliasList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PackageSourceAliasSpec) DeepCopyInto(out *PackageSourceAliasSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PackageSourceAliasSpec.
+func (in *PackageSourceAliasSpec) DeepCopy() *PackageSourceAliasSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PackageSourceAliasSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PackageSpec) DeepCopyInto(out *PackageSpec) {
 	*out = *in
@@ -956,6 +1074,21 @@ func (in *PackageSpec) DeepCopyInto(out *PackageSpec) {
 		*out = new(int64)
 		**out = **in
 	}
+	if in.ActivationDelay != nil {
+		in, out := &in.ActivationDelay, &out.ActivationDelay
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.DesiredRevisionNumber != nil {
+		in, out := &in.DesiredRevisionNumber, &out.DesiredRevisionNumber
+		*out = new(int64)
+		**out = **in
+	}
+	if in.HealthSourceRevision != nil {
+		in, out := &in.HealthSourceRevision, &out.HealthSourceRevision
+		*out = new(string)
+		**out = **in
+	}
 	if in.PackagePullSecrets != nil {
 		in, out := &in.PackagePullSecrets, &out.PackagePullSecrets
 		*out = make([]corev1.LocalObjectReference, len(*in))
@@ -1003,6 +1136,35 @@ func (in *PackageStatus) DeepCopyInto(out *PackageStatus) {
 		*out = make([]ImageConfigRef, len(*in))
 		copy(*out, *in)
 	}
+	if in.RevisionDiff != nil {
+		in, out := &in.RevisionDiff, &out.RevisionDiff
+		*out = new(RevisionDiff)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Revisions != nil {
+		in, out := &in.Revisions, &out.Revisions
+		*out = make([]RevisionSummary, len(*in))
+		copy(*out, *in)
+	}
+	if in.ResolvedDependencies != nil {
+		in, out := &in.ResolvedDependencies, &out.ResolvedDependencies
+		*out = make([]ResolvedDependency, len(*in))
+		copy(*out, *in)
+	}
+	if in.GarbageCollection != nil {
+		in, out := &in.GarbageCollection, &out.GarbageCollection
+		*out = new(GarbageCollectionResult)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.EffectiveRevisionHistoryLimit != nil {
+		in, out := &in.EffectiveRevisionHistoryLimit, &out.EffectiveRevisionHistoryLimit
+		*out = new(int64)
+		**out = **in
+	}
+	if in.NextPollTime != nil {
+		in, out := &in.NextPollTime, &out.NextPollTime
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PackageStatus.
@@ -1015,6 +1177,21 @@ func (in *PackageStatus) DeepCopy() *PackageStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProvenanceStep) DeepCopyInto(out *ProvenanceStep) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProvenanceStep.
+func (in *ProvenanceStep) DeepCopy() *ProvenanceStep {
+	if in == nil {
+		return nil
+	}
+	out := new(ProvenanceStep)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RegistryAuthentication) DeepCopyInto(out *RegistryAuthentication) {
 	*out = *in
@@ -1051,6 +1228,66 @@ func (in *RegistryConfig) DeepCopy() *RegistryConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResolvedDependency) DeepCopyInto(out *ResolvedDependency) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResolvedDependency.
+func (in *ResolvedDependency) DeepCopy() *ResolvedDependency {
+	if in == nil {
+		return nil
+	}
+	out := new(ResolvedDependency)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RevisionDiff) DeepCopyInto(out *RevisionDiff) {
+	*out = *in
+	if in.AddedObjects != nil {
+		in, out := &in.AddedObjects, &out.AddedObjects
+		*out = make([]commonv1.TypedReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.RemovedObjects != nil {
+		in, out := &in.RemovedObjects, &out.RemovedObjects
+		*out = make([]commonv1.TypedReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.ChangedObjects != nil {
+		in, out := &in.ChangedObjects, &out.ChangedObjects
+		*out = make([]commonv1.TypedReference, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RevisionDiff.
+func (in *RevisionDiff) DeepCopy() *RevisionDiff {
+	if in == nil {
+		return nil
+	}
+	out := new(RevisionDiff)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RevisionSummary) DeepCopyInto(out *RevisionSummary) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RevisionSummary.
+func (in *RevisionSummary) DeepCopy() *RevisionSummary {
+	if in == nil {
+		return nil
+	}
+	out := new(RevisionSummary)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RuntimeConfigReference) DeepCopyInto(out *RuntimeConfigReference) {
 	*out = *in
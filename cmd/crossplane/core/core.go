@@ -27,7 +27,9 @@ import (
 	"time"
 
 	"github.com/alecthomas/kong"
+	conregv1 "github.com/google/go-containerregistry/pkg/v1"
 	"github.com/spf13/afero"
+	"golang.org/x/sync/semaphore"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/rest"
@@ -41,6 +43,7 @@ import (
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/certificates"
 	"github.com/crossplane/crossplane-runtime/pkg/controller"
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
@@ -48,6 +51,7 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
 	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
 
+	pkgv1 "github.com/crossplane/crossplane/apis/pkg/v1"
 	"github.com/crossplane/crossplane/internal/controller/apiextensions"
 	apiextensionscontroller "github.com/crossplane/crossplane/internal/controller/apiextensions/controller"
 	"github.com/crossplane/crossplane/internal/controller/pkg"
@@ -96,14 +100,82 @@ type startCommand struct {
 	CABundlePath   string `env:"CA_BUNDLE_PATH"            help:"Additional CA bundle to use when fetching packages from registry."`
 	UserAgent      string `default:"${default_user_agent}" env:"USER_AGENT"                                                         help:"The User-Agent header that will be set on all package requests."`
 
+	PackageProxy   string   `env:"PACKAGE_PROXY"    help:"HTTP(S) proxy URL used when fetching packages from registry. Overrides the proxy environment variables."`
+	PackageNoProxy []string `env:"PACKAGE_NO_PROXY" help:"Hosts that should be fetched from directly, rather than through the package proxy."`
+
 	XpkgCacheDir string `aliases:"cache-dir" default:"/cache/xpkg" env:"XPKG_CACHE_DIR,CACHE_DIR" help:"Directory used for caching package images." short:"c"`
 
 	PackageRuntime string `default:"Deployment" env:"PACKAGE_RUNTIME" help:"The package runtime to use for packages with a runtime (e.g. Providers and Functions)"`
 
+	PackageSBOMArtifactTypes []string `help:"Record the SBOMs of these artifact types that refer to each package image, e.g. application/spdx+json, in its revision's status and as an annotation on the package. Uses the OCI referrers API."`
+
+	PackagePlatform string `env:"PACKAGE_PLATFORM" help:"The platform (e.g. linux/arm64) to select when a package is an OCI index of platform-specific packages. If unset, an index is treated like any other package image."`
+
+	PackageLocalDir string `env:"PACKAGE_LOCAL_DIR" help:"Allow installing packages from image tarballs in this directory, using a file:// package source. If unset, packages with a file:// source can't be installed."`
+
 	SyncInterval                     time.Duration `default:"1h"  help:"How often all resources will be double-checked for drift from the desired state."                      short:"s"`
 	PollInterval                     time.Duration `default:"1m"  help:"How often individual resources will be checked for drift from the desired state."`
 	MaxReconcileRate                 int           `default:"100" help:"The global maximum rate per second at which resources may checked for drift from the desired state."`
 	MaxConcurrentPackageEstablishers int           `default:"10"  help:"The the maximum number of goroutines to use for establishing Providers, Configurations and Functions."`
+	MaxConcurrentRevisionDeletes     int           `default:"5"   help:"The maximum number of package revisions to garbage collect concurrently for each package."`
+	MaxRevisionsPerPackage           int           `default:"0"   help:"The maximum number of revisions to create for each package, regardless of its revision history limit. Set to 0 for no maximum."`
+	MaxConcurrentPackageResolutions  int           `default:"0"   help:"The maximum number of packages to resolve concurrently across Providers, Configurations and Functions. Packages that can't be resolved are requeued. Set to 0 for no maximum."`
+	PackageHealthProbeInterval       time.Duration `default:"0s"  help:"How often to check the health of a package whose current revision's health is unknown or recently changed. Set to 0 to disable."`
+	PackageResyncInterval            time.Duration `default:"0s"  help:"How often to reconcile every package, regardless of watch events. A safety net for unreliable watches. Set to 0 to disable."`
+	PackageSlowReconcileThreshold    float64       `default:"0.8" help:"Warn when a package reconcile takes more than this fraction of its deadline. Set to 0 to disable."`
+	PackageStuckThreshold            time.Duration `default:"0s"  help:"Mark a package Stuck when it has been unpacking for longer than this. Set to 0 to disable."`
+
+	PackagePropagatedRevisionConditions      []string `help:"Types of additional conditions to mirror from a package's current revision to the package."`
+	PackagePropagatedRevisionConditionPrefix string   `default:"Revision" help:"Prefix added to the type of each condition mirrored from a package's current revision to the package."`
+
+	PackagePropagatedLabels      []string `help:"Keys of labels to propagate from each package to its revisions."`
+	PackagePropagatedAnnotations []string `help:"Keys of annotations to propagate from each package to its revisions."`
+
+	PackageRevisionFieldManager string `help:"Apply package revisions using server-side apply as this field manager, so that other controllers can edit them without conflict. If unset, package revisions are created or patched."`
+
+	PackageProvenanceArtifactTypes []string `help:"Only create a package revision if its image is referred to by an attestation of one of these artifact types, e.g. application/vnd.in-toto+json. Uses the OCI referrers API. Packages without one report a ProvenanceMissing condition."`
+
+	PackageTenantLabel                  string `help:"The label that identifies the tenant a package belongs to. Used to enforce active package revision quotas."`
+	PackageActiveRevisionQuotaConfigMap string `help:"The name of a ConfigMap in Crossplane's namespace that maps each tenant to the maximum number of active revisions its packages may have."`
+
+	PackageErrorConditionConfigMap string `help:"The name of a ConfigMap in Crossplane's namespace whose rules map errors encountered while fetching packages to package conditions."`
+	PackageFreezeConfigMap         string `help:"The name of a ConfigMap in Crossplane's namespace that enables a cluster-wide maintenance freeze while its frozen key is true. During a freeze the package manager doesn't create, activate, or garbage collect package revisions, but keeps package status up to date. The optional reason key explains the freeze."`
+	PackageRevisionLegacyLabel     string `help:"A label key that package revisions previously used to identify their parent package. Revisions that carry it are relabeled to use the current key."`
+	PackageFinalizer               string `help:"The finalizer added to packages whose revisions must be orphaned when they're deleted. Defaults to package.pkg.crossplane.io. Use a distinct finalizer when several package managers reconcile the same packages."`
+	PackageAnnotateRevisionDigest  bool   `help:"Annotate each package's active revision, as well as the package, with the digest of the image it resolved to."`
+	PackageAnnotateLastAction      bool   `help:"Annotate each package with a JSON record of the last action the package manager took on its revisions, for audit."`
+	PackageAnnotateCreationReason  bool   `help:"Annotate each package revision with why the package manager created it, for example because its package was first installed or because the tag its package's source specified moved."`
+	PackageReconcileRevisionSpec   bool   `help:"Reconcile the spec of each package's existing revision, like its pull secrets and runtime config, toward the package's. By default an existing revision is reused as-is."`
+	PackageCollectUnknownHealth    bool   `help:"Garbage collect package revisions whose health is unknown. By default they're retained because they may still be converging."`
+	PackageDeferGCUntilHealthy     bool   `help:"Only garbage collect a package's old revisions once its current revision is healthy."`
+	PackageGCBeforeActivation      bool   `help:"Garbage collect a package's old revisions before activating its current revision, to free their resources. By default they're garbage collected after. Garbage collection deferred until the current revision is healthy remains deferred."`
+	PackageAwaitDependencyGC       bool   `help:"Don't activate a package's new revision while any of its dependencies are garbage collecting their old revisions. Activation is retried shortly."`
+	PackageCountRevisionsCreated   bool   `help:"Count the revisions created for each package in the package's status.totalRevisionsCreated, to help spot packages that churn through revisions."`
+	PackageReportGCMode            bool   `help:"Report whether each package's revisions are garbage collected using a default revision history limit, the limit the package specifies, or not at all, using the package's RevisionGarbageCollectionMode condition."`
+	PackageProviderFamilyHealth    bool   `help:"Only report a member of a provider family healthy once the active revisions of every member of the family are healthy."`
+	PackageStrictOffline           bool   `help:"Never fetch a package with pull policy IfNotPresent from its registry to create its first revision. Such packages report a NoCachedRevision condition instead."`
+	PackageCheckDeprecatedAPIs     bool   `help:"Parse the contents of each new package revision and report any deprecated APIs they use or define using the package's DeprecatedAPIs condition. Requires fetching each new package image in full."`
+	PackageDiffRevisionContents    bool   `help:"Parse the contents of a package's inactive current revision, and of the revision that was active when it became current, to report the objects that changed between them in the package's status.revisionDiff. Requires fetching both package images in full."`
+
+	PackageDefaultActivationPolicy string `default:"Automatic" enum:"Automatic,Manual,HighestHealthy" help:"The revision activation policy used for packages that don't specify one."`
+	PackageOwnershipDriftPolicy    string `default:"Ignore"    enum:"Ignore,Adopt,Report"             help:"How to handle package revisions that are labelled as belonging to a package, but aren't controlled by it. Adopt restores the package's controller reference. Report sets the package's RevisionOwnership condition."`
+	PackageInstanceID              string `help:"An id for this Crossplane instance, such as its pod name. If set, each package is annotated with the id of the instance that last reconciled it."`
+
+	PackageHistoryLimits map[string]int64 `help:"The revision history limit used for each kind of package that doesn't specify one, for example Provider=1;Configuration=3. Kinds that aren't listed use a limit of 1."`
+
+	PackageMinHealthyDuration time.Duration `default:"0s" help:"Only report a package healthy once its revision has been continuously healthy for this long, to damp flapping health. Set to 0 to disable."`
+	PackageWarmUp             time.Duration `default:"0s" help:"For this long after starting, don't re-resolve the revisions of healthy packages that aren't due to be polled. Spreads registry load after a leader election. Set to 0 to disable."`
+
+	PackageFetchRetryAttempts int           `default:"1"     help:"The maximum number of times to attempt each registry request made while fetching a package, retrying requests that fail transiently. Set to 1 to disable retries."`
+	PackageFetchRetryDelay    time.Duration `default:"500ms" help:"How long to wait before retrying a registry request that failed transiently for the first time."`
+	PackageFetchRetryBackoff  float64       `default:"2"     help:"The factor by which the delay between retries of a registry request grows after each retry."`
+
+	PackageUpgradeCheckInterval time.Duration `default:"0s" help:"How often to check whether the source of a package with pull policy IfNotPresent resolves to newer content, and report it using the package's UpgradeAvailable condition. Never changes the package's revision. Set to 0 to disable."`
+
+	PackageMaxConditionMessageLength  int `default:"0" help:"Truncate the messages of package conditions longer than this many bytes. The full error encountered resolving a package's revision is kept in its status.lastResolverError. Set to 0 to disable."`
+	PackageMaxInformationalConditions int `default:"0" help:"The maximum number of informational conditions, like UpgradeAvailable or DeprecatedAPIs, to keep on each package. Those that transitioned least recently are dropped first. The Installed and Healthy conditions are never dropped. Set to 0 for no maximum."`
+
+	PackageActivationWebhookURL string `help:"A URL to POST a JSON notification to when a package activates a revision. The notification includes the package, its previously active and newly active revisions, and the activated revision's image digest. Notifications are sent asynchronously, and dropped if too many are waiting to be sent. Failures to notify are logged but otherwise ignored."`
 
 	EnableWebhooks bool `aliases:"webhook-enabled" default:"true" env:"ENABLE_WEBHOOKS,WEBHOOK_ENABLED" help:"Enable webhook configuration."`
 
@@ -414,6 +486,32 @@ func (c *startCommand) Run(s *runtime.Scheme, log logging.Logger) error { //noli
 			c.PackageRuntime, pkgcontroller.PackageRuntimeDeployment, pkgcontroller.PackageRuntimeExternal)
 	}
 
+	var platform *conregv1.Platform
+	if c.PackagePlatform != "" {
+		p, err := conregv1.ParsePlatform(c.PackagePlatform)
+		if err != nil {
+			return errors.Wrap(err, "cannot parse package platform")
+		}
+		platform = p
+	}
+
+	pmm := pkgcontroller.NewPrometheusMetrics()
+	metrics.Registry.MustRegister(pmm)
+
+	propagated := make([]xpv1.ConditionType, len(c.PackagePropagatedRevisionConditions))
+	for i, t := range c.PackagePropagatedRevisionConditions {
+		propagated[i] = xpv1.ConditionType(t)
+	}
+
+	for kind, limit := range c.PackageHistoryLimits {
+		if kind != pkgv1.ProviderKind && kind != pkgv1.ConfigurationKind && kind != pkgv1.FunctionKind {
+			return errors.Errorf("invalid --package-history-limits kind %q: must be %s, %s, or %s", kind, pkgv1.ProviderKind, pkgv1.ConfigurationKind, pkgv1.FunctionKind)
+		}
+		if limit < 0 {
+			return errors.Errorf("invalid --package-history-limits limit %d for %s: must not be negative", limit, kind)
+		}
+	}
+
 	po := pkgcontroller.Options{
 		Options:                          o,
 		Cache:                            xpkg.NewFsPackageCache(c.XpkgCacheDir, afero.NewOsFs()),
@@ -422,7 +520,58 @@ func (c *startCommand) Run(s *runtime.Scheme, log logging.Logger) error { //noli
 		DefaultRegistry:                  c.Registry,
 		FetcherOptions:                   []xpkg.FetcherOpt{xpkg.WithUserAgent(c.UserAgent)},
 		PackageRuntime:                   pr,
+		Platform:                         platform,
+		LocalPackageDir:                  c.PackageLocalDir,
 		MaxConcurrentPackageEstablishers: c.MaxConcurrentPackageEstablishers,
+		MaxConcurrentRevisionDeletes:     c.MaxConcurrentRevisionDeletes,
+		MaxRevisionsPerPackage:           c.MaxRevisionsPerPackage,
+		SBOMArtifactTypes:                c.PackageSBOMArtifactTypes,
+		HealthProbeInterval:              c.PackageHealthProbeInterval,
+		ResyncInterval:                   c.PackageResyncInterval,
+		SlowReconcileThreshold:           c.PackageSlowReconcileThreshold,
+		StuckUnpackingThreshold:          c.PackageStuckThreshold,
+		MinHealthyDuration:               c.PackageMinHealthyDuration,
+		WarmUp:                           c.PackageWarmUp,
+		UpgradeCheckInterval:             c.PackageUpgradeCheckInterval,
+		PropagatedRevisionConditions:     propagated,
+		PropagatedConditionPrefix:        c.PackagePropagatedRevisionConditionPrefix,
+		PropagatedLabels:                 c.PackagePropagatedLabels,
+		PropagatedAnnotations:            c.PackagePropagatedAnnotations,
+		ProvenanceArtifactTypes:          c.PackageProvenanceArtifactTypes,
+		RevisionFieldManager:             c.PackageRevisionFieldManager,
+		TenantLabel:                      c.PackageTenantLabel,
+		ActiveRevisionQuotaConfigMap:     c.PackageActiveRevisionQuotaConfigMap,
+		ErrorConditionConfigMap:          c.PackageErrorConditionConfigMap,
+		FreezeConfigMap:                  c.PackageFreezeConfigMap,
+		LegacyRevisionLabel:              c.PackageRevisionLegacyLabel,
+		AnnotateRevisionDigest:           c.PackageAnnotateRevisionDigest,
+		AnnotateLastAction:               c.PackageAnnotateLastAction,
+		AnnotateCreationReason:           c.PackageAnnotateCreationReason,
+		ReconcileRevisionSpec:            c.PackageReconcileRevisionSpec,
+		CollectUnknownHealthRevisions:    c.PackageCollectUnknownHealth,
+		DeferGCUntilHealthy:              c.PackageDeferGCUntilHealthy,
+		GCBeforeActivation:               c.PackageGCBeforeActivation,
+		ReportGCMode:                     c.PackageReportGCMode,
+		AwaitDependencyGC:                c.PackageAwaitDependencyGC,
+		CountRevisionsCreated:            c.PackageCountRevisionsCreated,
+		ProviderFamilyHealth:             c.PackageProviderFamilyHealth,
+		CheckDeprecatedAPIs:              c.PackageCheckDeprecatedAPIs,
+		DiffRevisionContents:             c.PackageDiffRevisionContents,
+		StrictOffline:                    c.PackageStrictOffline,
+		MaxConditionMessageLength:        c.PackageMaxConditionMessageLength,
+		MaxInformationalConditions:       c.PackageMaxInformationalConditions,
+		ActivationWebhookURL:             c.PackageActivationWebhookURL,
+		FinalizerName:                    c.PackageFinalizer,
+		DefaultRevisionHistoryLimits:     c.PackageHistoryLimits,
+		DefaultActivationPolicy:          pkgv1.RevisionActivationPolicy(c.PackageDefaultActivationPolicy),
+		OwnershipDriftPolicy:             c.PackageOwnershipDriftPolicy,
+		InstanceID:                       c.PackageInstanceID,
+		Metrics:                          pmm,
+	}
+	if c.MaxConcurrentPackageResolutions > 0 {
+		// Share one semaphore between every package manager controller, so
+		// that the limit applies across all kinds of package.
+		po.PackageResolutions = semaphore.NewWeighted(int64(c.MaxConcurrentPackageResolutions))
 	}
 
 	// We need to set the TUF_ROOT environment variable so that the TUF client
@@ -445,6 +594,18 @@ func (c *startCommand) Run(s *runtime.Scheme, log logging.Logger) error { //noli
 		po.FetcherOptions = append(po.FetcherOptions, xpkg.WithCustomCA(rootCAs))
 	}
 
+	if c.PackageFetchRetryAttempts > 1 {
+		po.FetcherOptions = append(po.FetcherOptions, xpkg.WithRetryPolicy(xpkg.RetryPolicy{
+			MaxAttempts: c.PackageFetchRetryAttempts,
+			BaseDelay:   c.PackageFetchRetryDelay,
+			Factor:      c.PackageFetchRetryBackoff,
+		}))
+	}
+
+	if c.PackageProxy != "" {
+		po.FetcherOptions = append(po.FetcherOptions, xpkg.WithProxy(c.PackageProxy, c.PackageNoProxy...))
+	}
+
 	if err := pkg.Setup(mgr, po); err != nil {
 		return errors.Wrap(err, "cannot add package manager controllers to manager")
 	}
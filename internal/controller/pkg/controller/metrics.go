@@ -0,0 +1,81 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+
+	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+)
+
+// Metrics records package manager metrics.
+type Metrics interface {
+	// RecordFirstTimeHealthy records the time it took the supplied package to
+	// become healthy after it was created. It should be called when the
+	// package's Healthy condition transitions to true.
+	RecordFirstTimeHealthy(p v1.Package)
+}
+
+// NopMetrics does nothing.
+type NopMetrics struct{}
+
+// RecordFirstTimeHealthy does nothing.
+func (m *NopMetrics) RecordFirstTimeHealthy(_ v1.Package) {}
+
+// PrometheusMetrics for the package manager.
+type PrometheusMetrics struct {
+	timeToHealthy *prometheus.HistogramVec
+}
+
+// NewPrometheusMetrics exposes package manager metrics via Prometheus.
+func NewPrometheusMetrics() *PrometheusMetrics {
+	return &PrometheusMetrics{
+		timeToHealthy: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Subsystem: "package",
+			Name:      "first_time_to_healthy_seconds",
+			Help:      "Histogram of the time it took a package to become healthy for the first time after creation (seconds).",
+			Buckets:   []float64{1, 5, 10, 15, 30, 60, 120, 300, 600, 1800, 3600},
+		}, []string{"kind"}),
+	}
+}
+
+// RecordFirstTimeHealthy records the time between when the supplied package
+// was created and when its Healthy condition last transitioned to true. It
+// does nothing if the package isn't healthy.
+func (m *PrometheusMetrics) RecordFirstTimeHealthy(p v1.Package) {
+	healthy := p.GetCondition(v1.TypeHealthy)
+	if healthy.Status != corev1.ConditionTrue {
+		return
+	}
+	l := prometheus.Labels{"kind": p.GetObjectKind().GroupVersionKind().Kind}
+	m.timeToHealthy.With(l).Observe(healthy.LastTransitionTime.Sub(p.GetCreationTimestamp().Time).Seconds())
+}
+
+// Describe sends the super-set of all possible descriptors of metrics
+// collected by this Collector to the provided channel and returns once
+// the last descriptor has been sent.
+func (m *PrometheusMetrics) Describe(ch chan<- *prometheus.Desc) {
+	m.timeToHealthy.Describe(ch)
+}
+
+// Collect is called by the Prometheus registry when collecting
+// metrics. The implementation sends each collected metric via the
+// provided channel and returns once the last metric has been sent.
+func (m *PrometheusMetrics) Collect(ch chan<- prometheus.Metric) {
+	m.timeToHealthy.Collect(ch)
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+
+	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+)
+
+func TestRecordFirstTimeHealthy(t *testing.T) {
+	created := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	type want struct {
+		count uint64
+		sum   float64
+	}
+	cases := map[string]struct {
+		reason string
+		health xpv1.Condition
+		want   want
+	}{
+		"Healthy": {
+			reason: "We should observe how long after it was created a package's Healthy condition transitioned to true.",
+			health: func() xpv1.Condition {
+				c := v1.Healthy()
+				c.LastTransitionTime = metav1.NewTime(created.Add(90 * time.Second))
+				return c
+			}(),
+			want: want{
+				count: 1,
+				sum:   90,
+			},
+		},
+		"Unhealthy": {
+			reason: "We shouldn't observe a package that isn't healthy.",
+			health: v1.Unhealthy(),
+			want: want{
+				count: 0,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			m := NewPrometheusMetrics()
+
+			p := &v1.Provider{}
+			p.SetGroupVersionKind(v1.ProviderGroupVersionKind)
+			p.SetCreationTimestamp(metav1.NewTime(created))
+			p.SetConditions(tc.health)
+
+			m.RecordFirstTimeHealthy(p)
+
+			got := &dto.Metric{}
+			if err := m.timeToHealthy.With(prometheus.Labels{"kind": v1.ProviderKind}).(prometheus.Histogram).Write(got); err != nil {
+				t.Fatalf("Write(...): %v", err)
+			}
+			if got.GetHistogram().GetSampleCount() != tc.want.count {
+				t.Errorf("\n%s\nRecordFirstTimeHealthy(...): want %d observations, got %d", tc.reason, tc.want.count, got.GetHistogram().GetSampleCount())
+			}
+			if got.GetHistogram().GetSampleSum() != tc.want.sum {
+				t.Errorf("\n%s\nRecordFirstTimeHealthy(...): want observations summing to %vs, got %vs", tc.reason, tc.want.sum, got.GetHistogram().GetSampleSum())
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+	"github.com/crossplane/crossplane/internal/xpkg"
+)
+
+// A RevisionNamer derives the name of a package's revision.
+type RevisionNamer interface {
+	// RevisionName returns the name of the supplied package's revision
+	// with the supplied hash - typically the digest of the package image the
+	// revision was resolved to. It must return the same name each time it's
+	// called with the same package and hash, and a valid Kubernetes object
+	// name.
+	RevisionName(p v1.Package, hash string) string
+}
+
+// A RevisionNamerFn derives the name of a package's revision.
+type RevisionNamerFn func(p v1.Package, hash string) string
+
+// RevisionName calls the function with the supplied package and hash.
+func (fn RevisionNamerFn) RevisionName(p v1.Package, hash string) string {
+	return fn(p, hash)
+}
+
+// FriendlyRevisionNamer names a package's revisions after the package and a
+// truncated hash, for example provider-aws-a1b2c3d4e5f6.
+type FriendlyRevisionNamer struct{}
+
+// RevisionName returns a friendly name for the supplied package's revision.
+func (n *FriendlyRevisionNamer) RevisionName(p v1.Package, hash string) string {
+	return xpkg.FriendlyID(p.GetName(), hash)
+}
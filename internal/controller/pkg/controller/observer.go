@@ -0,0 +1,79 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// A ReconcileReport describes what the package manager decided while
+// reconciling a package, and why.
+type ReconcileReport struct {
+	// Request that was reconciled.
+	Request reconcile.Request
+
+	// Source of the package, as specified by the package.
+	Source string
+
+	// ResolvedSource is the source the package manager resolved the
+	// package's source to, after expanding aliases and rewriting it.
+	ResolvedSource string
+
+	// Revision is the name of the package's current revision. It's empty if
+	// the package manager didn't determine the current revision.
+	Revision string
+
+	// Created is true if the package manager created the current revision.
+	Created bool
+
+	// Activated is true if the package manager activated the current
+	// revision.
+	Activated bool
+
+	// GarbageCollected are the names of the revisions the package manager
+	// garbage collected.
+	GarbageCollected []string
+
+	// Result of the reconcile.
+	Result reconcile.Result
+
+	// Err returned by the reconcile, if any.
+	Err error
+}
+
+// A ReconcileObserver observes each reconcile of a package.
+type ReconcileObserver interface {
+	// ObserveReconcile is called at the end of each reconcile with a report
+	// describing it.
+	ObserveReconcile(ctx context.Context, r ReconcileReport)
+}
+
+// A ReconcileObserverFn observes each reconcile of a package.
+type ReconcileObserverFn func(ctx context.Context, r ReconcileReport)
+
+// ObserveReconcile calls the function with the supplied report.
+func (fn ReconcileObserverFn) ObserveReconcile(ctx context.Context, r ReconcileReport) {
+	fn(ctx, r)
+}
+
+// NopReconcileObserver does nothing.
+type NopReconcileObserver struct{}
+
+// ObserveReconcile does nothing.
+func (o *NopReconcileObserver) ObserveReconcile(_ context.Context, _ ReconcileReport) {}
@@ -18,8 +18,15 @@ limitations under the License.
 package controller
 
 import (
+	"time"
+
+	conregv1 "github.com/google/go-containerregistry/pkg/v1"
+	"golang.org/x/sync/semaphore"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/controller"
 
+	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
 	"github.com/crossplane/crossplane/internal/xpkg"
 )
 
@@ -46,7 +53,251 @@ type Options struct {
 	// PackageRuntime specifies the runtime to use for package runtime.
 	PackageRuntime PackageRuntime
 
+	// Platform selected when a package is an OCI index of platform-specific
+	// packages. If nil, an index is treated like any other package image.
+	Platform *conregv1.Platform
+
+	// LocalPackageDir is the directory the package manager loads local
+	// packages - those with a file:// source - from. If empty, the package
+	// manager doesn't install local packages.
+	LocalPackageDir string
+
 	// MaxConcurrentPackageEstablishers is the maximum number of goroutines to use
 	// for establishing Providers, Configurations and Functions.
 	MaxConcurrentPackageEstablishers int
+
+	// MaxConcurrentRevisionDeletes is the maximum number of package revisions
+	// to garbage collect concurrently for each package.
+	MaxConcurrentRevisionDeletes int
+
+	// PackageResolutions limits how many packages the package manager
+	// resolves concurrently, across every kind of package. Each resolution
+	// acquires a weight of one. If nil, resolutions aren't limited.
+	PackageResolutions *semaphore.Weighted
+
+	// MaxRevisionsPerPackage is the maximum number of revisions the package
+	// manager will create for each package, regardless of its revision
+	// history limit. Zero means there is no maximum.
+	MaxRevisionsPerPackage int
+
+	// SBOMArtifactTypes are the artifact types of software bill of materials
+	// (SBOM) artifacts the package manager discovers for each package image.
+	// If empty, the package manager doesn't discover SBOMs.
+	SBOMArtifactTypes []string
+
+	// HealthProbeInterval is how often the package manager checks the health
+	// of a package whose current revision's health is unknown or recently
+	// changed. Zero means the package manager doesn't probe package health.
+	HealthProbeInterval time.Duration
+
+	// ResyncInterval is how often the package manager reconciles every
+	// package, regardless of watch events. Set to 0 to disable.
+	ResyncInterval time.Duration
+
+	// SlowReconcileThreshold is the fraction of the reconcile deadline after
+	// which the package manager warns that a reconcile is slow. Zero means
+	// the package manager doesn't warn about slow reconciles.
+	SlowReconcileThreshold float64
+
+	// WarmUp is how long after it starts the package manager skips
+	// resolving the revisions of healthy packages that aren't due to be
+	// polled. Set to 0 to disable.
+	WarmUp time.Duration
+
+	// MinHealthyDuration is how long a package's revision must be
+	// continuously healthy before the package is reported healthy. Set to 0
+	// to report packages healthy as soon as their revision is.
+	MinHealthyDuration time.Duration
+
+	// UpgradeCheckInterval is how often the package manager checks whether
+	// the source of a package with pull policy IfNotPresent resolves to newer
+	// content than its current revision. Set to 0 to disable.
+	UpgradeCheckInterval time.Duration
+
+	// StuckUnpackingThreshold is how long a package may be unpacking before
+	// the package manager marks it Stuck. Set to 0 to disable.
+	StuckUnpackingThreshold time.Duration
+
+	// PropagatedRevisionConditions are the types of additional conditions
+	// mirrored from a package's current revision to the package.
+	PropagatedRevisionConditions []xpv1.ConditionType
+
+	// PropagatedConditionPrefix is prefixed to the type of each
+	// condition mirrored from a package's current revision to the package.
+	PropagatedConditionPrefix string
+
+	// PropagatedLabels are the keys of labels propagated from each package
+	// to the revisions it controls.
+	PropagatedLabels []string
+
+	// PropagatedAnnotations are the keys of annotations propagated from each
+	// package to the revisions it controls.
+	PropagatedAnnotations []string
+
+	// RevisionFieldManager is the field manager the package manager uses to
+	// apply package revisions using server-side apply. If empty, the package
+	// manager creates or patches package revisions instead.
+	RevisionFieldManager string
+
+	// ProvenanceArtifactTypes are the artifact types of provenance
+	// attestations, one of which must refer to a package image before the
+	// package manager creates a new revision of it. If empty, the package
+	// manager doesn't require a provenance attestation.
+	ProvenanceArtifactTypes []string
+
+	// TenantLabel is the label that identifies the tenant a package belongs
+	// to. The package manager enforces active revision quotas per tenant.
+	TenantLabel string
+
+	// ActiveRevisionQuotaConfigMap is the name of a ConfigMap in Namespace
+	// that maps each tenant to the maximum number of active revisions its
+	// packages may have. Tenants without an entry have no quota.
+	ActiveRevisionQuotaConfigMap string
+
+	// FreezeConfigMap is the name of a ConfigMap in Namespace that enables
+	// a cluster-wide maintenance freeze. While the freeze is enabled the
+	// package manager doesn't create, activate, or garbage collect package
+	// revisions.
+	FreezeConfigMap string
+
+	// DefaultRevisionHistoryLimits are the revision history limits the
+	// package manager uses for packages that don't specify one, keyed by
+	// package kind (e.g. Provider). Kinds without an entry have no default.
+	DefaultRevisionHistoryLimits map[string]int64
+
+	// DefaultActivationPolicy is the revision activation policy the package
+	// manager uses for packages that don't specify one. Empty means the
+	// policy is implicitly Automatic.
+	DefaultActivationPolicy v1.RevisionActivationPolicy
+
+	// OwnershipDriftPolicy is how the package manager handles package
+	// revisions that are labelled as belonging to a package, but aren't
+	// controlled by it - Ignore, Adopt, or Report. If empty, the package
+	// manager ignores them.
+	OwnershipDriftPolicy string
+
+	// ErrorConditionConfigMap is the name of a ConfigMap in Namespace that
+	// contains rules mapping errors encountered while fetching packages to
+	// package conditions. The rules are evaluated before the default rules.
+	ErrorConditionConfigMap string
+
+	// LegacyRevisionLabel is a label key that package revisions previously
+	// used to identify their parent package. The package manager relabels
+	// revisions that carry it to use the current key.
+	LegacyRevisionLabel string
+
+	// FinalizerName is the name of the finalizer the package manager adds
+	// to packages whose revisions must be orphaned when they're deleted. The
+	// package manager uses its default finalizer if it's empty.
+	FinalizerName string
+
+	// AnnotateRevisionDigest specifies whether the package manager annotates
+	// each package's active revision with the digest of the image it
+	// resolved to, as well as the package.
+	AnnotateRevisionDigest bool
+
+	// AnnotateLastAction specifies whether the package manager annotates
+	// each package with the last action it took on the package's revisions.
+	AnnotateLastAction bool
+
+	// AnnotateCreationReason specifies whether the package manager annotates
+	// each package revision it creates with why it created the revision.
+	AnnotateCreationReason bool
+
+	// ReconcileRevisionSpec specifies whether the package manager reconciles
+	// the spec of a package's existing revision, like its pull secrets and
+	// runtime config, toward the package's. By default it reuses an existing
+	// revision's spec as-is.
+	ReconcileRevisionSpec bool
+
+	// CollectUnknownHealthRevisions specifies whether the package manager
+	// garbage collects package revisions whose health is unknown. By default
+	// it retains them, because they may still be converging.
+	CollectUnknownHealthRevisions bool
+
+	// InstanceID identifies this package manager instance. If set, the
+	// package manager annotates each package it successfully reconciles with
+	// it.
+	InstanceID string
+
+	// DeferGCUntilHealthy specifies whether the package manager waits for a
+	// package's current revision to become healthy before it garbage
+	// collects the package's old revisions.
+	DeferGCUntilHealthy bool
+
+	// GCBeforeActivation specifies whether the package manager garbage
+	// collects a package's old revisions before it activates the package's
+	// current revision, rather than after.
+	GCBeforeActivation bool
+
+	// ReportGCMode specifies whether the package manager reports how each
+	// package's revisions are garbage collected using the package's
+	// RevisionGarbageCollectionMode condition.
+	ReportGCMode bool
+
+	// AwaitDependencyGC specifies whether the package manager defers
+	// activating a package's current revision while any of its dependencies
+	// are garbage collecting their old revisions.
+	AwaitDependencyGC bool
+
+	// CountRevisionsCreated specifies whether the package manager counts the
+	// revisions it creates for each package using the package's
+	// status.totalRevisionsCreated.
+	CountRevisionsCreated bool
+
+	// ProviderFamilyHealth specifies whether the package manager only
+	// reports a member of a provider family healthy once every member of the
+	// family is healthy.
+	ProviderFamilyHealth bool
+
+	// CheckDeprecatedAPIs specifies whether the package manager parses the
+	// contents of each new package revision and reports any deprecated APIs
+	// they use or define using the package's DeprecatedAPIs condition.
+	CheckDeprecatedAPIs bool
+
+	// DiffRevisionContents specifies whether the package manager parses the
+	// contents of an inactive current revision and of the revision that was
+	// active when it became current, to report the objects that changed
+	// between them in the package's revision diff.
+	DiffRevisionContents bool
+
+	// MaxConditionMessageLength is the maximum length in bytes of the
+	// messages of the conditions the package manager sets on packages.
+	// Longer messages are truncated. Set to 0 to disable.
+	MaxConditionMessageLength int
+
+	// MaxInformationalConditions is the maximum number of informational
+	// conditions, like UpgradeAvailable, the package manager keeps on each
+	// package. It drops those that transitioned least recently, but never
+	// the Installed or Healthy conditions. Set to 0 to disable.
+	MaxInformationalConditions int
+
+	// ActivationWebhookURL is the URL of a webhook the package manager
+	// notifies, with a JSON payload, when a package activates a revision.
+	// Notifications are sent asynchronously, and failures to notify are
+	// logged. Set to "" to disable.
+	ActivationWebhookURL string
+
+	// StrictOffline specifies whether the package manager refuses to fetch
+	// a package with pull policy IfNotPresent from its registry to create
+	// its first revision.
+	StrictOffline bool
+
+	// Metrics used to record package manager metrics.
+	Metrics Metrics
+
+	// ReconcileObserver is called at the end of each package reconcile with
+	// a report describing it. It's intended for systems that embed the
+	// package manager. If nil, reconciles aren't observed.
+	ReconcileObserver ReconcileObserver
+
+	// RevisionNamer derives the names of package revisions. It's intended
+	// for systems that need revision names to follow their own convention.
+	// If nil, revisions are named after their package and a truncated hash.
+	RevisionNamer RevisionNamer
+
+	// RevisionSpecTemplate specifies defaults for the spec of every package
+	// revision created by the package manager. Fields set on a package take
+	// precedence over those set in the template.
+	RevisionSpecTemplate v1.PackageRevisionSpec
 }
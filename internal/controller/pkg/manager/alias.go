@@ -0,0 +1,162 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Masterminds/semver"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+
+	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+	"github.com/crossplane/crossplane/apis/pkg/v1beta1"
+)
+
+const (
+	errFmtInvalidAliasSource = "package source %q must be of the form %s<alias>:<tag> or %s<alias>@<digest>"
+	errFmtGetAlias           = "cannot get PackageSourceAlias %q"
+	errFmtParseConstraints   = "cannot parse version constraints %q of PackageSourceAlias %q"
+	errFmtParseAliasVersion  = "cannot parse tag %q as a semantic version to check it against the constraints of PackageSourceAlias %q"
+	errFmtAliasDigest        = "cannot check digest %q against the constraints of PackageSourceAlias %q"
+	errFmtAliasConstraint    = "tag %q doesn't satisfy the constraints %q of PackageSourceAlias %q"
+)
+
+// An AliasResolver resolves package sources that refer to a
+// PackageSourceAlias.
+type AliasResolver interface {
+	// ResolveAlias returns the name of the alias the supplied source refers
+	// to, and the concrete source the alias resolves it to. It returns the
+	// name of the alias it tried to resolve, if any, even if it returns an
+	// error.
+	ResolveAlias(ctx context.Context, source string) (alias, resolved string, err error)
+}
+
+// An AliasResolverFn resolves package sources that refer to a
+// PackageSourceAlias.
+type AliasResolverFn func(ctx context.Context, source string) (string, string, error)
+
+// ResolveAlias resolves the supplied source.
+func (fn AliasResolverFn) ResolveAlias(ctx context.Context, source string) (string, string, error) {
+	return fn(ctx, source)
+}
+
+// An APIAliasResolver resolves package sources using the PackageSourceAliases
+// read from the API server.
+type APIAliasResolver struct {
+	client client.Reader
+}
+
+// NewAPIAliasResolver returns an AliasResolver that resolves package sources
+// using the PackageSourceAliases read from the API server.
+func NewAPIAliasResolver(c client.Reader) *APIAliasResolver {
+	return &APIAliasResolver{client: c}
+}
+
+// ResolveAlias returns the concrete source the supplied source refers to. A
+// source that doesn't refer to an alias resolves to itself.
+func (r *APIAliasResolver) ResolveAlias(ctx context.Context, source string) (string, string, error) {
+	ref, ok := strings.CutPrefix(source, v1beta1.PackageSourceAliasPrefix)
+	if !ok {
+		return "", source, nil
+	}
+	i := strings.IndexAny(ref, ":@")
+	if i <= 0 || i == len(ref)-1 {
+		return "", "", errors.Errorf(errFmtInvalidAliasSource, source, v1beta1.PackageSourceAliasPrefix, v1beta1.PackageSourceAliasPrefix)
+	}
+	name, sep, version := ref[:i], ref[i:i+1], ref[i+1:]
+
+	a := &v1beta1.PackageSourceAlias{}
+	if err := r.client.Get(ctx, types.NamespacedName{Name: name}, a); err != nil {
+		return name, "", errors.Wrapf(err, errFmtGetAlias, name)
+	}
+	if a.Spec.Constraints == "" {
+		return name, a.Spec.Package + sep + version, nil
+	}
+
+	c, err := semver.NewConstraint(a.Spec.Constraints)
+	if err != nil {
+		return name, "", errors.Wrapf(err, errFmtParseConstraints, a.Spec.Constraints, name)
+	}
+	if sep == "@" {
+		return name, "", errors.Errorf(errFmtAliasDigest, version, name)
+	}
+	v, err := semver.NewVersion(version)
+	if err != nil {
+		return name, "", errors.Wrapf(err, errFmtParseAliasVersion, version, name)
+	}
+	if !c.Check(v) {
+		return name, "", errors.Errorf(errFmtAliasConstraint, version, a.Spec.Constraints, name)
+	}
+	return name, a.Spec.Package + sep + version, nil
+}
+
+// aliasName returns the name of the PackageSourceAlias the supplied source
+// refers to, or an empty string if it doesn't refer to one.
+func aliasName(source string) string {
+	ref, ok := strings.CutPrefix(source, v1beta1.PackageSourceAliasPrefix)
+	if !ok {
+		return ""
+	}
+	if i := strings.IndexAny(ref, ":@"); i >= 0 {
+		return ref[:i]
+	}
+	return ref
+}
+
+// enqueuePackagesForAlias returns an event handler that enqueues packages of
+// the supplied kind whose source refers to a PackageSourceAlias when it
+// changes.
+func enqueuePackagesForAlias(kube client.Client, k PackageKind, log logging.Logger) handler.EventHandler {
+	return handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, o client.Object) []reconcile.Request {
+		a, ok := o.(*v1beta1.PackageSourceAlias)
+		if !ok {
+			return nil
+		}
+		l := k.NewPackageList()
+		if err := kube.List(ctx, l); err != nil {
+			// Nothing we can do, except logging, if we can't list packages.
+			log.Debug("Cannot list packages while attempting to enqueue from PackageSourceAlias", "error", err)
+			return nil
+		}
+		items, err := apimeta.ExtractList(l)
+		if err != nil {
+			log.Debug("Cannot extract packages while attempting to enqueue from PackageSourceAlias", "error", err)
+			return nil
+		}
+
+		var matches []reconcile.Request
+		for _, i := range items {
+			p, ok := i.(v1.Package)
+			if !ok {
+				continue
+			}
+			if aliasName(p.GetSource()) == a.GetName() || p.GetResolvedAlias() == a.GetName() {
+				log.Debug("Enqueuing package for package source alias", "package", p.GetName(), "alias", a.GetName())
+				matches = append(matches, reconcile.Request{NamespacedName: types.NamespacedName{Name: p.GetName()}})
+			}
+		}
+		return matches
+	})
+}
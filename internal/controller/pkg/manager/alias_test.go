@@ -0,0 +1,143 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/crossplane/apis/pkg/v1beta1"
+)
+
+func TestAPIAliasResolver(t *testing.T) {
+	errNotFound := kerrors.NewNotFound(schema.GroupResource{Group: v1beta1.Group, Resource: "packagesourcealiases"}, "provider-aws")
+
+	alias := func(constraints string) test.MockGetFn {
+		return test.NewMockGetFn(nil, func(o client.Object) error {
+			a := o.(*v1beta1.PackageSourceAlias)
+			a.Spec.Package = "xpkg.crossplane.io/crossplane-contrib/provider-aws"
+			a.Spec.Constraints = constraints
+			return nil
+		})
+	}
+
+	type want struct {
+		alias    string
+		resolved string
+		err      error
+	}
+
+	cases := map[string]struct {
+		reason string
+		source string
+		get    test.MockGetFn
+		want   want
+	}{
+		"NotAnAlias": {
+			reason: "A source that doesn't refer to an alias should resolve to itself.",
+			source: "xpkg.crossplane.io/crossplane-contrib/provider-aws:v1.2.0",
+			want: want{
+				resolved: "xpkg.crossplane.io/crossplane-contrib/provider-aws:v1.2.0",
+			},
+		},
+		"NoVersion": {
+			reason: "We should return an error if an alias source has no tag or digest.",
+			source: "alias://provider-aws",
+			want: want{
+				err: errors.Errorf(errFmtInvalidAliasSource, "alias://provider-aws", v1beta1.PackageSourceAliasPrefix, v1beta1.PackageSourceAliasPrefix),
+			},
+		},
+		"NotFound": {
+			reason: "We should return the alias we tried to resolve, and an error, if the alias doesn't exist.",
+			source: "alias://provider-aws:v1.2.0",
+			get:    test.NewMockGetFn(errNotFound),
+			want: want{
+				alias: "provider-aws",
+				err:   errors.Wrapf(errNotFound, errFmtGetAlias, "provider-aws"),
+			},
+		},
+		"Tag": {
+			reason: "We should resolve a tagged alias source to the alias's package with the same tag.",
+			source: "alias://provider-aws:v1.2.0",
+			get:    alias(""),
+			want: want{
+				alias:    "provider-aws",
+				resolved: "xpkg.crossplane.io/crossplane-contrib/provider-aws:v1.2.0",
+			},
+		},
+		"Digest": {
+			reason: "We should resolve an alias source with a digest to the alias's package with the same digest.",
+			source: "alias://provider-aws@sha256:ecc25c121431dfc7058754427f97c034ecde26d4aafa0da16d4fc1c6d1c2d07a",
+			get:    alias(""),
+			want: want{
+				alias:    "provider-aws",
+				resolved: "xpkg.crossplane.io/crossplane-contrib/provider-aws@sha256:ecc25c121431dfc7058754427f97c034ecde26d4aafa0da16d4fc1c6d1c2d07a",
+			},
+		},
+		"ConstraintsSatisfied": {
+			reason: "We should resolve an alias source whose tag satisfies the alias's constraints.",
+			source: "alias://provider-aws:v1.2.0",
+			get:    alias(">=1.0.0, <2.0.0"),
+			want: want{
+				alias:    "provider-aws",
+				resolved: "xpkg.crossplane.io/crossplane-contrib/provider-aws:v1.2.0",
+			},
+		},
+		"ConstraintsViolated": {
+			reason: "We should return an error if an alias source's tag doesn't satisfy the alias's constraints.",
+			source: "alias://provider-aws:v2.0.0",
+			get:    alias(">=1.0.0, <2.0.0"),
+			want: want{
+				alias: "provider-aws",
+				err:   errors.Errorf(errFmtAliasConstraint, "v2.0.0", ">=1.0.0, <2.0.0", "provider-aws"),
+			},
+		},
+		"ConstrainedDigest": {
+			reason: "We should return an error if an alias source with a digest refers to an alias with constraints.",
+			source: "alias://provider-aws@sha256:ecc25c121431dfc7058754427f97c034ecde26d4aafa0da16d4fc1c6d1c2d07a",
+			get:    alias(">=1.0.0"),
+			want: want{
+				alias: "provider-aws",
+				err:   errors.Errorf(errFmtAliasDigest, "sha256:ecc25c121431dfc7058754427f97c034ecde26d4aafa0da16d4fc1c6d1c2d07a", "provider-aws"),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := NewAPIAliasResolver(&test.MockClient{MockGet: tc.get})
+			alias, resolved, err := r.ResolveAlias(context.Background(), tc.source)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nr.ResolveAlias(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.alias, alias); diff != "" {
+				t.Errorf("\n%s\nr.ResolveAlias(...): -want alias, +got alias:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.resolved, resolved); diff != "" {
+				t.Errorf("\n%s\nr.ResolveAlias(...): -want resolved source, +got resolved source:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
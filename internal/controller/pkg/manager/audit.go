@@ -0,0 +1,117 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"context"
+	"encoding/json"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+
+	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+)
+
+// An AuditAction is an action the package manager took on a package revision.
+type AuditAction string
+
+// Audited package revision actions.
+const (
+	// AuditActionCreate indicates a package revision was created.
+	AuditActionCreate AuditAction = "Create"
+
+	// AuditActionActivate indicates a package revision was activated.
+	AuditActionActivate AuditAction = "Activate"
+
+	// AuditActionGarbageCollect indicates a package revision was garbage
+	// collected.
+	AuditActionGarbageCollect AuditAction = "GarbageCollect"
+)
+
+// An AuditEvent records an action the package manager took on a package
+// revision.
+type AuditEvent struct {
+	// Package that owns the revision.
+	Package xpv1.TypedReference
+
+	// Revision the action was taken on.
+	Revision string
+
+	// Action that was taken.
+	Action AuditAction
+
+	// Actor that took the action, i.e. the name of the controller.
+	Actor string
+}
+
+// Reasons the package manager took an action on a package revision.
+const (
+	LastActionReasonNewRevision          = "NewRevision"
+	LastActionReasonRevisionHistoryLimit = "RevisionHistoryLimit"
+	LastActionReasonRollback             = "DesiredRevisionNumber"
+	LastActionReasonHold                 = "HoldAnnotation"
+)
+
+// A LastAction records the last action the package manager took on a
+// package's revisions. It's serialized to the package's last action
+// annotation, so it's intentionally small.
+type LastAction struct {
+	// Action that was taken.
+	Action AuditAction `json:"action"`
+
+	// Revision the action was taken on.
+	Revision string `json:"revision"`
+
+	// Reason the action was taken.
+	Reason string `json:"reason"`
+}
+
+// String returns the JSON representation of the action.
+func (a LastAction) String() string {
+	b, _ := json.Marshal(a) //nolint:errchkjson // Marshalling a struct of strings can't fail.
+	return string(b)
+}
+
+// activationReason returns the reason a revision was activated under the
+// supplied activation policy.
+func activationReason(ap v1.RevisionActivationPolicy) string {
+	return string(ap) + "ActivationPolicy"
+}
+
+// An AuditSink records package manager actions to an audit system.
+type AuditSink interface {
+	// Record the supplied audit event.
+	Record(ctx context.Context, e AuditEvent)
+}
+
+// An AuditSinkFn records package manager actions to an audit system.
+type AuditSinkFn func(ctx context.Context, e AuditEvent)
+
+// Record the supplied audit event.
+func (fn AuditSinkFn) Record(ctx context.Context, e AuditEvent) {
+	fn(ctx, e)
+}
+
+// A NopAuditSink does nothing.
+type NopAuditSink struct{}
+
+// NewNopAuditSink returns an AuditSink that does nothing.
+func NewNopAuditSink() *NopAuditSink {
+	return &NopAuditSink{}
+}
+
+// Record does nothing.
+func (s *NopAuditSink) Record(_ context.Context, _ AuditEvent) {}
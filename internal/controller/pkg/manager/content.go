@@ -0,0 +1,192 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	extv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/ptr"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/parser"
+
+	apiextensionsv1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	"github.com/crossplane/crossplane/apis/apiextensions/v2alpha1"
+	pkgmetav1 "github.com/crossplane/crossplane/apis/pkg/meta/v1"
+	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+	"github.com/crossplane/crossplane/internal/xpkg"
+)
+
+// A ContentValidator validates the contents of a package before the package
+// manager creates a new revision of it, for example to enforce that the CRDs
+// and XRDs it contains satisfy organizational policy.
+type ContentValidator interface {
+	// Validate returns an error if the supplied contents of the supplied
+	// package violate policy. Contents are nil if the Revisioner didn't
+	// parse the package.
+	Validate(ctx context.Context, p v1.Package, contents *parser.Package) error
+}
+
+// A ContentValidatorFn validates the contents of a package.
+type ContentValidatorFn func(ctx context.Context, p v1.Package, contents *parser.Package) error
+
+// Validate the supplied contents of the supplied package.
+func (fn ContentValidatorFn) Validate(ctx context.Context, p v1.Package, contents *parser.Package) error {
+	return fn(ctx, p, contents)
+}
+
+// A NopContentValidator accepts all package contents.
+type NopContentValidator struct{}
+
+// NewNopContentValidator returns a ContentValidator that accepts all package
+// contents.
+func NewNopContentValidator() *NopContentValidator {
+	return &NopContentValidator{}
+}
+
+// Validate always returns nil.
+func (v *NopContentValidator) Validate(_ context.Context, _ v1.Package, _ *parser.Package) error {
+	return nil
+}
+
+// deprecatedAPIs returns the deprecated APIs the supplied package contents use
+// or define, sorted. It returns nil if contents are nil.
+func deprecatedAPIs(contents *parser.Package) []string {
+	if contents == nil {
+		return nil
+	}
+	var apis []string
+	for _, o := range contents.GetObjects() {
+		switch o := o.(type) {
+		case *extv1beta1.CustomResourceDefinition:
+			// Kubernetes stopped serving v1beta1 CRDs in v1.22.
+			apis = append(apis, fmt.Sprintf("%s (CustomResourceDefinition %s)", extv1beta1.SchemeGroupVersion, o.GetName()))
+		case *extv1.CustomResourceDefinition:
+			for _, v := range o.Spec.Versions {
+				if v.Deprecated {
+					apis = append(apis, schema.GroupVersionKind{Group: o.Spec.Group, Version: v.Name, Kind: o.Spec.Names.Kind}.String())
+				}
+			}
+		case *apiextensionsv1.CompositeResourceDefinition:
+			for _, v := range o.Spec.Versions {
+				if ptr.Deref(v.Deprecated, false) {
+					apis = append(apis, schema.GroupVersionKind{Group: o.Spec.Group, Version: v.Name, Kind: o.Spec.Names.Kind}.String())
+				}
+			}
+		case *v2alpha1.CompositeResourceDefinition:
+			for _, v := range o.Spec.Versions {
+				if ptr.Deref(v.Deprecated, false) {
+					apis = append(apis, schema.GroupVersionKind{Group: o.Spec.Group, Version: v.Name, Kind: o.Spec.Names.Kind}.String())
+				}
+			}
+		}
+	}
+	slices.Sort(apis)
+	return slices.Compact(apis)
+}
+
+// changedObjects returns the objects the supplied current package contents
+// share with the supplied previous package contents, but that differ between
+// them. Objects are identified by their group, kind, and name. It returns nil
+// if either contents are nil.
+func changedObjects(previous, current *parser.Package) []xpv1.TypedReference {
+	if previous == nil || current == nil {
+		return nil
+	}
+	prev := make(map[string][]byte, len(previous.GetObjects()))
+	for _, o := range previous.GetObjects() {
+		if ref, ok := objectReference(o); ok {
+			prev[objectID(ref)], _ = json.Marshal(o)
+		}
+	}
+
+	var changed []xpv1.TypedReference
+	for _, o := range current.GetObjects() {
+		ref, ok := objectReference(o)
+		if !ok {
+			continue
+		}
+		p, ok := prev[objectID(ref)]
+		if !ok {
+			continue
+		}
+		if c, _ := json.Marshal(o); !bytes.Equal(p, c) {
+			changed = append(changed, ref)
+		}
+	}
+	return changed
+}
+
+// objectReference returns a reference to the supplied package object, or
+// false if the object has no name.
+func objectReference(o runtime.Object) (xpv1.TypedReference, bool) {
+	m, err := meta.Accessor(o)
+	if err != nil || m.GetName() == "" {
+		return xpv1.TypedReference{}, false
+	}
+	apiVersion, kind := o.GetObjectKind().GroupVersionKind().ToAPIVersionAndKind()
+	return xpv1.TypedReference{APIVersion: apiVersion, Kind: kind, Name: m.GetName()}, true
+}
+
+// objectID identifies the referenced object by its group, kind, and name, so
+// that different versions of the same object share an identity.
+func objectID(ref xpv1.TypedReference) string {
+	return ref.GroupVersionKind().GroupKind().String() + "/" + ref.Name
+}
+
+// declaredKind returns the kind of package the supplied package contents'
+// metadata declare, e.g. Provider. It returns an empty string if contents are
+// nil, or don't contain exactly one recognized metadata object.
+func declaredKind(contents *parser.Package) string {
+	if contents == nil || len(contents.GetMeta()) != 1 {
+		return ""
+	}
+	m, _ := xpkg.TryConvertToPkg(contents.GetMeta()[0], &pkgmetav1.Provider{}, &pkgmetav1.Configuration{}, &pkgmetav1.Function{})
+	switch m.(type) {
+	case *pkgmetav1.Provider:
+		return pkgmetav1.ProviderKind
+	case *pkgmetav1.Configuration:
+		return pkgmetav1.ConfigurationKind
+	case *pkgmetav1.Function:
+		return pkgmetav1.FunctionKind
+	}
+	return ""
+}
+
+// packageKind returns the kind of the supplied package, e.g. Provider. It
+// returns an empty string if the package isn't a kind the package manager
+// knows about.
+func packageKind(p v1.Package) string {
+	switch p.(type) {
+	case *v1.Provider:
+		return v1.ProviderKind
+	case *v1.Configuration:
+		return v1.ConfigurationKind
+	case *v1.Function:
+		return v1.FunctionKind
+	}
+	return ""
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/parser"
+
+	"github.com/crossplane/crossplane/internal/xpkg/parser/yaml"
+)
+
+func TestChangedObjects(t *testing.T) {
+	pp, err := yaml.New()
+	if err != nil {
+		t.Fatalf("yaml.New(): %v", err)
+	}
+	parse := func(stream string) *parser.Package {
+		pkg, err := pp.Parse(context.Background(), io.NopCloser(strings.NewReader(stream)))
+		if err != nil {
+			t.Fatalf("Parse(...): %v", err)
+		}
+		return pkg
+	}
+	crd := func(name, scope string) string {
+		return `
+---
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: ` + name + `
+spec:
+  group: example.org
+  names:
+    kind: Widget
+    plural: widgets
+  scope: ` + scope + `
+`
+	}
+
+	type args struct {
+		previous *parser.Package
+		current  *parser.Package
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   []xpv1.TypedReference
+	}{
+		"NilContents": {
+			reason: "We can't tell which objects changed without both revisions' contents.",
+			args: args{
+				current: parse(crd("widgets.example.org", "Cluster")),
+			},
+		},
+		"Unchanged": {
+			reason: "Identical objects didn't change.",
+			args: args{
+				previous: parse(crd("widgets.example.org", "Cluster")),
+				current:  parse(crd("widgets.example.org", "Cluster")),
+			},
+		},
+		"Changed": {
+			reason: "Objects that both revisions contain, but that differ, changed. Objects only one revision contains were added or removed, not changed.",
+			args: args{
+				previous: parse(crd("widgets.example.org", "Cluster") + crd("removed.example.org", "Cluster")),
+				current:  parse(crd("widgets.example.org", "Namespaced") + crd("added.example.org", "Cluster")),
+			},
+			want: []xpv1.TypedReference{{APIVersion: "apiextensions.k8s.io/v1", Kind: "CustomResourceDefinition", Name: "widgets.example.org"}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := changedObjects(tc.args.previous, tc.args.current)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nchangedObjects(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
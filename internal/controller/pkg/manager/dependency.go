@@ -0,0 +1,312 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"context"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+	"github.com/crossplane/crossplane/apis/pkg/v1beta1"
+	"github.com/crossplane/crossplane/internal/xpkg"
+)
+
+const (
+	errGetLock                  = "cannot get lock"
+	errCreateLock               = "cannot create lock"
+	errUpdateLock               = "cannot update lock"
+	errParseLockSource          = "cannot parse package revision source"
+	errFmtGetDependencyRevision = "cannot get dependency package revision %q"
+	errFmtGetDependencyPackage  = "cannot get dependency package %q"
+)
+
+const (
+	// lockName is the name of the Lock the revision reconcilers record
+	// resolved package dependencies in.
+	lockName = "lock"
+
+	// maxResolvedDependencies is the maximum number of dependencies listed in
+	// a package's status.
+	maxResolvedDependencies = 50
+)
+
+// A DependencyLister lists the resolved dependencies of a package revision.
+type DependencyLister interface {
+	// ResolvedDependencies returns the supplied revision's direct
+	// dependencies.
+	ResolvedDependencies(ctx context.Context, pr v1.PackageRevision) ([]v1.ResolvedDependency, error)
+}
+
+// A DependencyListerFn lists the resolved dependencies of a package revision.
+type DependencyListerFn func(ctx context.Context, pr v1.PackageRevision) ([]v1.ResolvedDependency, error)
+
+// ResolvedDependencies returns the supplied revision's direct dependencies.
+func (fn DependencyListerFn) ResolvedDependencies(ctx context.Context, pr v1.PackageRevision) ([]v1.ResolvedDependency, error) {
+	return fn(ctx, pr)
+}
+
+// A GarbageCollectingDependencyLister lists the dependencies of a package
+// revision that are garbage collecting their old revisions.
+type GarbageCollectingDependencyLister interface {
+	// GarbageCollectingDependencies returns the supplied revision's direct
+	// dependencies that are garbage collecting their old revisions.
+	GarbageCollectingDependencies(ctx context.Context, pr v1.PackageRevision) ([]string, error)
+}
+
+// A GarbageCollectingDependencyListerFn lists the dependencies of a package
+// revision that are garbage collecting their old revisions.
+type GarbageCollectingDependencyListerFn func(ctx context.Context, pr v1.PackageRevision) ([]string, error)
+
+// GarbageCollectingDependencies returns the supplied revision's direct
+// dependencies that are garbage collecting their old revisions.
+func (fn GarbageCollectingDependencyListerFn) GarbageCollectingDependencies(ctx context.Context, pr v1.PackageRevision) ([]string, error) {
+	return fn(ctx, pr)
+}
+
+// A LockDependencyLister lists the resolved dependencies of a package revision
+// using the Lock the revision reconcilers maintain.
+type LockDependencyLister struct {
+	client client.Reader
+}
+
+// NewLockDependencyLister returns a DependencyLister that lists the resolved
+// dependencies of a package revision using the Lock.
+func NewLockDependencyLister(c client.Reader) *LockDependencyLister {
+	return &LockDependencyLister{client: c}
+}
+
+// ResolvedDependencies returns the supplied revision's direct dependencies,
+// according to the Lock, with the version and health of each. At most
+// maxResolvedDependencies dependencies are returned. A revision that isn't in
+// the Lock, for example because it skips dependency resolution, has none.
+func (l *LockDependencyLister) ResolvedDependencies(ctx context.Context, pr v1.PackageRevision) ([]v1.ResolvedDependency, error) {
+	lock := &v1beta1.Lock{}
+	if err := l.client.Get(ctx, types.NamespacedName{Name: lockName}, lock); err != nil {
+		return nil, errors.Wrap(resource.IgnoreNotFound(err), errGetLock)
+	}
+
+	var self *v1beta1.LockPackage
+	sources := make(map[string]v1beta1.LockPackage, len(lock.Packages))
+	for i, lp := range lock.Packages {
+		sources[lp.Source] = lp
+		if lp.Name == pr.GetName() {
+			self = &lock.Packages[i]
+		}
+	}
+	if self == nil {
+		return nil, nil
+	}
+
+	deps := make([]v1.ResolvedDependency, 0, min(len(self.Dependencies), maxResolvedDependencies))
+	for _, dep := range self.Dependencies {
+		if len(deps) == maxResolvedDependencies {
+			break
+		}
+		rd := v1.ResolvedDependency{Package: dep.Package, Healthy: corev1.ConditionUnknown}
+
+		// The dependency isn't in the Lock until it's installed.
+		lp, ok := sources[dep.Package]
+		if !ok {
+			deps = append(deps, rd)
+			continue
+		}
+		rd.Version = lp.Version
+
+		rev, err := l.revision(ctx, lp)
+		if err != nil {
+			return nil, err
+		}
+		if rev != nil {
+			rd.Healthy = v1.PackageHealth(rev).Status
+		}
+		deps = append(deps, rd)
+	}
+	return deps, nil
+}
+
+// GarbageCollectingDependencies returns the supplied revision's direct
+// dependencies, according to the Lock, whose packages report that they're
+// garbage collecting their old revisions. Dependencies that aren't installed
+// aren't garbage collecting.
+func (l *LockDependencyLister) GarbageCollectingDependencies(ctx context.Context, pr v1.PackageRevision) ([]string, error) {
+	lock := &v1beta1.Lock{}
+	if err := l.client.Get(ctx, types.NamespacedName{Name: lockName}, lock); err != nil {
+		return nil, errors.Wrap(resource.IgnoreNotFound(err), errGetLock)
+	}
+
+	var self *v1beta1.LockPackage
+	sources := make(map[string]v1beta1.LockPackage, len(lock.Packages))
+	for i, lp := range lock.Packages {
+		sources[lp.Source] = lp
+		if lp.Name == pr.GetName() {
+			self = &lock.Packages[i]
+		}
+	}
+	if self == nil {
+		return nil, nil
+	}
+
+	var collecting []string
+	for _, dep := range self.Dependencies {
+		lp, ok := sources[dep.Package]
+		if !ok {
+			continue
+		}
+		p, err := l.pkg(ctx, lp)
+		if err != nil {
+			return nil, err
+		}
+		if p != nil && p.GetCondition(v1.TypeRevisionGarbageCollection).Reason == v1.ReasonGarbageCollecting {
+			collecting = append(collecting, dep.Package)
+		}
+	}
+	return collecting, nil
+}
+
+// A LockRecorder records package revisions in the Lock.
+type LockRecorder interface {
+	// RecordRevision ensures the Lock has an entry for the supplied
+	// revision.
+	RecordRevision(ctx context.Context, pr v1.PackageRevision) error
+}
+
+// A LockRecorderFn records package revisions in the Lock.
+type LockRecorderFn func(ctx context.Context, pr v1.PackageRevision) error
+
+// RecordRevision ensures the Lock has an entry for the supplied revision.
+func (fn LockRecorderFn) RecordRevision(ctx context.Context, pr v1.PackageRevision) error {
+	return fn(ctx, pr)
+}
+
+// An APILockRecorder records package revisions in the Lock the revision
+// reconcilers maintain.
+type APILockRecorder struct {
+	client client.Client
+	kind   schema.GroupVersionKind
+}
+
+// NewAPILockRecorder returns a LockRecorder that records revisions of the
+// supplied kind of package in the Lock.
+func NewAPILockRecorder(c client.Client, kind schema.GroupVersionKind) *APILockRecorder {
+	return &APILockRecorder{client: c, kind: kind}
+}
+
+// RecordRevision ensures the Lock has an entry for the supplied revision,
+// creating the Lock if necessary. It updates the source and version of an
+// existing entry, but leaves its dependencies to the revision reconcilers.
+func (l *APILockRecorder) RecordRevision(ctx context.Context, pr v1.PackageRevision) error {
+	ref, err := name.ParseReference(pr.GetSource(), name.WithDefaultRegistry(""))
+	if err != nil {
+		return errors.Wrap(err, errParseLockSource)
+	}
+	self := v1beta1.LockPackage{
+		APIVersion: ptr.To(l.kind.GroupVersion().String()),
+		Kind:       ptr.To(l.kind.Kind),
+		Name:       pr.GetName(),
+		Source:     xpkg.ParsePackageSourceFromReference(ref),
+		Version:    ref.Identifier(),
+	}
+
+	// The revision reconcilers update the Lock too, so we may contend with
+	// them. Retry with a fresh Lock if we do.
+	contended := func(err error) bool { return kerrors.IsConflict(err) || kerrors.IsAlreadyExists(err) }
+	return retry.OnError(retry.DefaultRetry, contended, func() error {
+		lock := &v1beta1.Lock{}
+		err := l.client.Get(ctx, types.NamespacedName{Name: lockName}, lock)
+		if kerrors.IsNotFound(err) {
+			lock.SetName(lockName)
+			lock.Packages = []v1beta1.LockPackage{self}
+			return errors.Wrap(l.client.Create(ctx, lock), errCreateLock)
+		}
+		if err != nil {
+			return errors.Wrap(err, errGetLock)
+		}
+		for i, lp := range lock.Packages {
+			if lp.Name != self.Name {
+				continue
+			}
+			if lp.Source == self.Source && lp.Version == self.Version {
+				return nil
+			}
+			lock.Packages[i].Source = self.Source
+			lock.Packages[i].Version = self.Version
+			return errors.Wrap(l.client.Update(ctx, lock), errUpdateLock)
+		}
+		lock.Packages = append(lock.Packages, self)
+		return errors.Wrap(l.client.Update(ctx, lock), errUpdateLock)
+	})
+}
+
+// revision returns the package revision the supplied Lock package corresponds
+// to. It returns nil if the revision's kind is unknown or it doesn't exist.
+func (l *LockDependencyLister) revision(ctx context.Context, lp v1beta1.LockPackage) (v1.PackageRevision, error) {
+	kind := ptr.Deref(lp.Kind, string(ptr.Deref(lp.Type, "")))
+	for _, k := range []PackageKind{ProviderPackageKind, ConfigurationPackageKind, FunctionPackageKind} {
+		if k.Package.Kind != kind {
+			continue
+		}
+		rev := k.NewPackageRevision()
+		if err := l.client.Get(ctx, types.NamespacedName{Name: lp.Name}, rev); err != nil {
+			if kerrors.IsNotFound(err) {
+				return nil, nil
+			}
+			return nil, errors.Wrapf(err, errFmtGetDependencyRevision, lp.Name)
+		}
+		return rev, nil
+	}
+	return nil, nil
+}
+
+// pkg returns the package whose revision the supplied Lock package corresponds
+// to. It returns nil if the package's kind is unknown or either the revision
+// or the package doesn't exist.
+func (l *LockDependencyLister) pkg(ctx context.Context, lp v1beta1.LockPackage) (v1.Package, error) {
+	kind := ptr.Deref(lp.Kind, string(ptr.Deref(lp.Type, "")))
+	for _, k := range []PackageKind{ProviderPackageKind, ConfigurationPackageKind, FunctionPackageKind} {
+		if k.Package.Kind != kind {
+			continue
+		}
+		rev, err := l.revision(ctx, lp)
+		if err != nil || rev == nil {
+			return nil, err
+		}
+		name := rev.GetLabels()[v1.LabelParentPackage]
+		if name == "" {
+			return nil, nil
+		}
+		p := k.NewPackage()
+		if err := l.client.Get(ctx, types.NamespacedName{Name: name}, p); err != nil {
+			if kerrors.IsNotFound(err) {
+				return nil, nil
+			}
+			return nil, errors.Wrapf(err, errFmtGetDependencyPackage, name)
+		}
+		return p, nil
+	}
+	return nil, nil
+}
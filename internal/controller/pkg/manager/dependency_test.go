@@ -0,0 +1,293 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+	"github.com/crossplane/crossplane/apis/pkg/v1beta1"
+)
+
+func TestLockDependencyLister(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	pr := &v1.ConfigurationRevision{}
+	pr.SetName("config-nop-1234")
+
+	lock := func(o client.Object) error {
+		l, ok := o.(*v1beta1.Lock)
+		if !ok {
+			return nil
+		}
+		l.Packages = []v1beta1.LockPackage{
+			{
+				Name:    "config-nop-1234",
+				Kind:    ptr.To(v1.ConfigurationKind),
+				Source:  "xpkg.crossplane.io/crossplane/config-nop",
+				Version: "v1.0.0",
+				Dependencies: []v1beta1.Dependency{
+					{Package: "xpkg.crossplane.io/crossplane/provider-nop"},
+					{Package: "xpkg.crossplane.io/crossplane/function-nop"},
+				},
+			},
+			{
+				Name:    "provider-nop-5678",
+				Kind:    ptr.To(v1.ProviderKind),
+				Source:  "xpkg.crossplane.io/crossplane/provider-nop",
+				Version: "v0.2.0",
+			},
+		}
+		return nil
+	}
+
+	type want struct {
+		deps []v1.ResolvedDependency
+		err  error
+	}
+
+	cases := map[string]struct {
+		reason string
+		get    test.MockGetFn
+		want   want
+	}{
+		"LockNotFound": {
+			reason: "A revision should have no dependencies if there's no Lock.",
+			get:    test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{Resource: "locks"}, lockName)),
+			want:   want{},
+		},
+		"GetLockError": {
+			reason: "We should return an error if we can't get the Lock.",
+			get:    test.NewMockGetFn(errBoom),
+			want: want{
+				err: errors.Wrap(errBoom, errGetLock),
+			},
+		},
+		"NotInLock": {
+			reason: "A revision that isn't in the Lock should have no dependencies.",
+			get:    test.NewMockGetFn(nil),
+			want:   want{},
+		},
+		"GetRevisionError": {
+			reason: "We should return an error if we can't get a dependency's revision.",
+			get: func(_ context.Context, _ client.ObjectKey, o client.Object) error {
+				if _, ok := o.(*v1.ProviderRevision); ok {
+					return errBoom
+				}
+				return lock(o)
+			},
+			want: want{
+				err: errors.Wrapf(errBoom, errFmtGetDependencyRevision, "provider-nop-5678"),
+			},
+		},
+		"ResolvedDependencies": {
+			reason: "We should list each dependency with its resolved version and health.",
+			get: func(_ context.Context, _ client.ObjectKey, o client.Object) error {
+				if rev, ok := o.(*v1.ProviderRevision); ok {
+					rev.SetConditions(v1.RevisionHealthy(), v1.RuntimeHealthy())
+					return nil
+				}
+				return lock(o)
+			},
+			want: want{
+				deps: []v1.ResolvedDependency{
+					{
+						Package: "xpkg.crossplane.io/crossplane/provider-nop",
+						Version: "v0.2.0",
+						Healthy: corev1.ConditionTrue,
+					},
+					{
+						Package: "xpkg.crossplane.io/crossplane/function-nop",
+						Healthy: corev1.ConditionUnknown,
+					},
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			l := NewLockDependencyLister(&test.MockClient{MockGet: tc.get})
+			got, err := l.ResolvedDependencies(context.Background(), pr)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nl.ResolvedDependencies(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.deps, got); diff != "" {
+				t.Errorf("\n%s\nl.ResolvedDependencies(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestAPILockRecorder(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	pr := &v1.ConfigurationRevision{}
+	pr.SetName("config-nop-1234")
+	pr.SetSource("xpkg.crossplane.io/crossplane/config-nop:v1.1.0")
+
+	self := v1beta1.LockPackage{
+		APIVersion: ptr.To(v1.ConfigurationGroupVersionKind.GroupVersion().String()),
+		Kind:       ptr.To(v1.ConfigurationKind),
+		Name:       "config-nop-1234",
+		Source:     "xpkg.crossplane.io/crossplane/config-nop",
+		Version:    "v1.1.0",
+	}
+	other := v1beta1.LockPackage{
+		Name:    "provider-nop-5678",
+		Kind:    ptr.To(v1.ProviderKind),
+		Source:  "xpkg.crossplane.io/crossplane/provider-nop",
+		Version: "v0.2.0",
+	}
+
+	type want struct {
+		packages []v1beta1.LockPackage
+		err      error
+	}
+
+	cases := map[string]struct {
+		reason   string
+		packages []v1beta1.LockPackage
+		client   *test.MockClient
+		want     want
+	}{
+		"CreateLock": {
+			reason: "We should create the Lock with an entry for the revision if it doesn't exist.",
+			client: &test.MockClient{
+				MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{Resource: "locks"}, lockName)),
+			},
+			want: want{
+				packages: []v1beta1.LockPackage{self},
+			},
+		},
+		"AddEntry": {
+			reason:   "We should add an entry for a revision that isn't in the Lock.",
+			packages: []v1beta1.LockPackage{other},
+			want: want{
+				packages: []v1beta1.LockPackage{other, self},
+			},
+		},
+		"UpdateEntry": {
+			reason: "We should update the source and version of a revision's existing entry, keeping its dependencies.",
+			packages: []v1beta1.LockPackage{
+				{
+					APIVersion:   self.APIVersion,
+					Kind:         self.Kind,
+					Name:         "config-nop-1234",
+					Source:       "registry.example.org/crossplane/config-nop",
+					Version:      "v1.0.0",
+					Dependencies: []v1beta1.Dependency{{Package: "xpkg.crossplane.io/crossplane/provider-nop"}},
+				},
+			},
+			want: want{
+				packages: []v1beta1.LockPackage{
+					{
+						APIVersion:   self.APIVersion,
+						Kind:         self.Kind,
+						Name:         "config-nop-1234",
+						Source:       "xpkg.crossplane.io/crossplane/config-nop",
+						Version:      "v1.1.0",
+						Dependencies: []v1beta1.Dependency{{Package: "xpkg.crossplane.io/crossplane/provider-nop"}},
+					},
+				},
+			},
+		},
+		"UpToDate": {
+			reason:   "We shouldn't update the Lock if it already has an up-to-date entry for the revision.",
+			packages: []v1beta1.LockPackage{self},
+			want:     want{},
+		},
+		"GetLockError": {
+			reason: "We should return an error if we can't get the Lock.",
+			client: &test.MockClient{
+				MockGet: test.NewMockGetFn(errBoom),
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errGetLock),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var got []v1beta1.LockPackage
+			record := func(o client.Object) error {
+				got = o.(*v1beta1.Lock).Packages
+				return nil
+			}
+			c := tc.client
+			if c == nil {
+				c = &test.MockClient{
+					MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+						o.(*v1beta1.Lock).Packages = tc.packages
+						return nil
+					}),
+				}
+			}
+			c.MockCreate = test.NewMockCreateFn(nil, record)
+			c.MockUpdate = test.NewMockUpdateFn(nil, record)
+
+			l := NewAPILockRecorder(c, v1.ConfigurationGroupVersionKind)
+			err := l.RecordRevision(context.Background(), pr)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nl.RecordRevision(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.packages, got); diff != "" {
+				t.Errorf("\n%s\nl.RecordRevision(...): -want lock packages, +got lock packages:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestAPILockRecorderContention(t *testing.T) {
+	pr := &v1.ConfigurationRevision{}
+	pr.SetName("config-nop-1234")
+	pr.SetSource("xpkg.crossplane.io/crossplane/config-nop:v1.1.0")
+
+	// Fail the first update as if a revision reconciler updated the Lock
+	// between our get and update.
+	updates := 0
+	c := &test.MockClient{
+		MockGet: test.NewMockGetFn(nil),
+		MockUpdate: test.NewMockUpdateFn(nil, func(_ client.Object) error {
+			updates++
+			if updates == 1 {
+				return kerrors.NewConflict(schema.GroupResource{Resource: "locks"}, lockName, errors.New("lock changed"))
+			}
+			return nil
+		}),
+	}
+
+	l := NewAPILockRecorder(c, v1.ConfigurationGroupVersionKind)
+	if err := l.RecordRevision(context.Background(), pr); err != nil {
+		t.Errorf("l.RecordRevision(...): want no error after retrying, got %v", err)
+	}
+	if updates != 2 {
+		t.Errorf("l.RecordRevision(...): want 2 updates, got %d", updates)
+	}
+}
@@ -0,0 +1,187 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+)
+
+const (
+	errGetErrorConditionConfigMap = "cannot get error condition ConfigMap"
+	errParseErrorConditionRules   = "cannot parse error condition rules"
+	errFmtCompilePattern          = "cannot compile error condition rule pattern %q"
+	errFmtMissingReason           = "error condition rule with pattern %q must specify a reason"
+)
+
+// ErrorConditionRulesKey is the key of the data of an error condition
+// ConfigMap that contains its rules.
+const ErrorConditionRulesKey = "rules"
+
+// An ErrorConditionRule maps errors encountered while fetching a package to
+// the reason and message of the package's Installed condition.
+type ErrorConditionRule struct {
+	// Pattern is matched against the error's message.
+	Pattern *regexp.Regexp
+
+	// Reason of the Installed condition.
+	Reason xpv1.ConditionReason
+
+	// Message is a friendly explanation of the error. It's prepended to the
+	// error's message. The error's message is used alone if it's empty.
+	Message string
+}
+
+// ErrorConditionRules map errors encountered while fetching a package to
+// conditions. Rules are evaluated in order. The first rule that matches wins.
+type ErrorConditionRules []ErrorConditionRule
+
+// Condition returns the Installed condition for the supplied error. It returns
+// false if no rule matches the error.
+func (rs ErrorConditionRules) Condition(err error) (xpv1.Condition, bool) {
+	for _, r := range rs {
+		if !r.Pattern.MatchString(err.Error()) {
+			continue
+		}
+		msg := err.Error()
+		if r.Message != "" {
+			msg = fmt.Sprintf("%s: %s", r.Message, err)
+		}
+		return xpv1.Condition{
+			Type:               v1.TypeInstalled,
+			Status:             corev1.ConditionFalse,
+			LastTransitionTime: metav1.Now(),
+			Reason:             r.Reason,
+			Message:            msg,
+		}, true
+	}
+	return xpv1.Condition{}, false
+}
+
+// DefaultErrorConditionRules returns rules that map errors commonly returned by
+// OCI registries to conditions. There's no default rule for authentication
+// failures; the Reconciler already reports them using the AuthenticationFailed
+// condition.
+func DefaultErrorConditionRules() ErrorConditionRules {
+	return ErrorConditionRules{
+		{
+			Pattern: regexp.MustCompile(`TOOMANYREQUESTS|429 Too Many Requests|(?i)rate limit`),
+			Reason:  v1.ReasonRateLimited,
+			Message: "The package registry is rate limiting requests",
+		},
+		{
+			Pattern: regexp.MustCompile(`MANIFEST_UNKNOWN|NAME_UNKNOWN|404 Not Found`),
+			Reason:  v1.ReasonPackageNotFound,
+			Message: "The package registry could not find the package",
+		},
+	}
+}
+
+// An ErrorConditionSource returns rules that map errors encountered while
+// fetching a package to conditions.
+type ErrorConditionSource interface {
+	// ErrorConditionRules returns the rules to evaluate, in order.
+	ErrorConditionRules(ctx context.Context) (ErrorConditionRules, error)
+}
+
+// An ErrorConditionSourceFn returns rules that map errors encountered while
+// fetching a package to conditions.
+type ErrorConditionSourceFn func(ctx context.Context) (ErrorConditionRules, error)
+
+// ErrorConditionRules returns the rules to evaluate, in order.
+func (fn ErrorConditionSourceFn) ErrorConditionRules(ctx context.Context) (ErrorConditionRules, error) {
+	return fn(ctx)
+}
+
+// A StaticErrorConditionSource always returns the same rules.
+type StaticErrorConditionSource struct {
+	rules ErrorConditionRules
+}
+
+// NewStaticErrorConditionSource returns an ErrorConditionSource that always
+// returns the supplied rules.
+func NewStaticErrorConditionSource(rs ErrorConditionRules) *StaticErrorConditionSource {
+	return &StaticErrorConditionSource{rules: rs}
+}
+
+// ErrorConditionRules returns the source's rules.
+func (s *StaticErrorConditionSource) ErrorConditionRules(_ context.Context) (ErrorConditionRules, error) {
+	return s.rules, nil
+}
+
+// An errorConditionRule is the serialized form of an ErrorConditionRule.
+type errorConditionRule struct {
+	Pattern string               `json:"pattern"`
+	Reason  xpv1.ConditionReason `json:"reason"`
+	Message string               `json:"message,omitempty"`
+}
+
+// A ConfigMapErrorConditionSource reads error condition rules from a
+// ConfigMap. The ConfigMap's rules key contains a YAML list of rules, each with
+// a pattern, reason, and optional message. Its rules are evaluated before the
+// default rules.
+type ConfigMapErrorConditionSource struct {
+	client client.Reader
+	ref    types.NamespacedName
+}
+
+// NewConfigMapErrorConditionSource returns an ErrorConditionSource that reads
+// error condition rules from the ConfigMap with the supplied namespace and
+// name.
+func NewConfigMapErrorConditionSource(c client.Reader, namespace, name string) *ConfigMapErrorConditionSource {
+	return &ConfigMapErrorConditionSource{client: c, ref: types.NamespacedName{Namespace: namespace, Name: name}}
+}
+
+// ErrorConditionRules returns the ConfigMap's rules followed by the default
+// rules. Only the default rules are returned if the ConfigMap doesn't exist.
+func (s *ConfigMapErrorConditionSource) ErrorConditionRules(ctx context.Context) (ErrorConditionRules, error) {
+	cm := &corev1.ConfigMap{}
+	if err := s.client.Get(ctx, s.ref, cm); resource.IgnoreNotFound(err) != nil {
+		return nil, errors.Wrap(err, errGetErrorConditionConfigMap)
+	}
+
+	in := []errorConditionRule{}
+	if err := yaml.Unmarshal([]byte(cm.Data[ErrorConditionRulesKey]), &in); err != nil {
+		return nil, errors.Wrap(err, errParseErrorConditionRules)
+	}
+
+	rs := make(ErrorConditionRules, 0, len(in))
+	for _, r := range in {
+		re, err := regexp.Compile(r.Pattern)
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtCompilePattern, r.Pattern)
+		}
+		if r.Reason == "" {
+			return nil, errors.Errorf(errFmtMissingReason, r.Pattern)
+		}
+		rs = append(rs, ErrorConditionRule{Pattern: re, Reason: r.Reason, Message: r.Message})
+	}
+	return append(rs, DefaultErrorConditionRules()...), nil
+}
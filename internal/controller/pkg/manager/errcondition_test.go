@@ -0,0 +1,152 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+)
+
+func TestConfigMapErrorConditionSource(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	withRules := func(rules string) test.MockGetFn {
+		return test.NewMockGetFn(nil, func(o client.Object) error {
+			o.(*corev1.ConfigMap).Data = map[string]string{ErrorConditionRulesKey: rules} //nolint:forcetypeassert // Guaranteed to be a ConfigMap.
+			return nil
+		})
+	}
+
+	type args struct {
+		get test.MockGetFn
+		err error
+	}
+	type want struct {
+		c       xpv1.Condition
+		matched bool
+		err     error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"GetConfigMapError": {
+			reason: "We should return an error if we can't get the ConfigMap.",
+			args: args{
+				get: test.NewMockGetFn(errBoom),
+				err: errors.New("MANIFEST_UNKNOWN: manifest unknown"),
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errGetErrorConditionConfigMap),
+			},
+		},
+		"ConfigMapNotFound": {
+			reason: "We should fall back to the default rules if the ConfigMap doesn't exist.",
+			args: args{
+				get: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{Resource: "configmaps"}, "rules")),
+				err: errors.New("MANIFEST_UNKNOWN: manifest unknown"),
+			},
+			want: want{
+				c: xpv1.Condition{
+					Type:    v1.TypeInstalled,
+					Status:  corev1.ConditionFalse,
+					Reason:  v1.ReasonPackageNotFound,
+					Message: "The package registry could not find the package: MANIFEST_UNKNOWN: manifest unknown",
+				},
+				matched: true,
+			},
+		},
+		"InvalidPattern": {
+			reason: "We should return an error if a rule's pattern isn't a valid regular expression.",
+			args: args{
+				get: withRules("- pattern: '('\n  reason: Broken\n"),
+				err: errors.New("boom"),
+			},
+			want: want{
+				err: errors.Wrapf(errors.New("error parsing regexp: missing closing ): `(`"), errFmtCompilePattern, "("),
+			},
+		},
+		"MissingReason": {
+			reason: "We should return an error if a rule doesn't specify a reason.",
+			args: args{
+				get: withRules("- pattern: 'quota'\n"),
+				err: errors.New("boom"),
+			},
+			want: want{
+				err: errors.Errorf(errFmtMissingReason, "quota"),
+			},
+		},
+		"CustomRule": {
+			reason: "A custom rule should take precedence over the default rules.",
+			args: args{
+				get: withRules("- pattern: 'pull quota of \\d+ exceeded'\n  reason: PullQuotaExceeded\n  message: The registry's pull quota is exhausted\n"),
+				err: errors.New("TOOMANYREQUESTS: pull quota of 100 exceeded"),
+			},
+			want: want{
+				c: xpv1.Condition{
+					Type:    v1.TypeInstalled,
+					Status:  corev1.ConditionFalse,
+					Reason:  "PullQuotaExceeded",
+					Message: "The registry's pull quota is exhausted: TOOMANYREQUESTS: pull quota of 100 exceeded",
+				},
+				matched: true,
+			},
+		},
+		"NoMatch": {
+			reason: "We should return false if no rule matches the error.",
+			args: args{
+				get: withRules("- pattern: 'quota'\n  reason: PullQuotaExceeded\n"),
+				err: errors.New("boom"),
+			},
+			want: want{},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			s := NewConfigMapErrorConditionSource(&test.MockClient{MockGet: tc.args.get}, "crossplane-system", "rules")
+			rs, err := s.ErrorConditionRules(context.Background())
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ns.ErrorConditionRules(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if err != nil {
+				return
+			}
+			c, matched := rs.Condition(tc.args.err)
+			if diff := cmp.Diff(tc.want.matched, matched); diff != "" {
+				t.Errorf("\n%s\nrs.Condition(...): -want matched, +got matched:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.c, c, test.EquateConditions()); diff != "" {
+				t.Errorf("\n%s\nrs.Condition(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,151 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"context"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+// Keys of a maintenance freeze ConfigMap's data.
+const (
+	// FreezeKeyEnabled enables the freeze when its value is true, e.g. "true".
+	FreezeKeyEnabled = "frozen"
+
+	// FreezeKeyReason optionally explains why the freeze is enabled.
+	FreezeKeyReason = "reason"
+)
+
+const (
+	errGetFreezeConfigMap = "cannot get maintenance freeze ConfigMap"
+	errFmtParseFreeze     = "cannot parse maintenance freeze ConfigMap key %q value %q"
+)
+
+// A FreezeSource returns whether a cluster-wide maintenance freeze stops the
+// package manager changing package revisions.
+type FreezeSource interface {
+	// Frozen returns true, and the reason for the freeze, if the freeze is
+	// enabled. The reason may be empty.
+	Frozen(ctx context.Context) (bool, string, error)
+}
+
+// A FreezeSourceFn returns whether a cluster-wide maintenance freeze is
+// enabled.
+type FreezeSourceFn func(ctx context.Context) (bool, string, error)
+
+// Frozen returns whether a cluster-wide maintenance freeze is enabled.
+func (fn FreezeSourceFn) Frozen(ctx context.Context) (bool, string, error) {
+	return fn(ctx)
+}
+
+// A ConfigMapFreezeSource reads whether a cluster-wide maintenance freeze is
+// enabled from a ConfigMap. The freeze is enabled while the ConfigMap's frozen
+// key is true.
+type ConfigMapFreezeSource struct {
+	client client.Reader
+	ref    types.NamespacedName
+}
+
+// NewConfigMapFreezeSource returns a FreezeSource that reads whether a
+// maintenance freeze is enabled from the ConfigMap with the supplied namespace
+// and name.
+func NewConfigMapFreezeSource(c client.Reader, namespace, name string) *ConfigMapFreezeSource {
+	return &ConfigMapFreezeSource{client: c, ref: types.NamespacedName{Namespace: namespace, Name: name}}
+}
+
+// Frozen returns true, and the ConfigMap's reason key, if the ConfigMap's
+// frozen key is true. The freeze isn't enabled if the ConfigMap or its
+// frozen key doesn't exist.
+func (s *ConfigMapFreezeSource) Frozen(ctx context.Context) (bool, string, error) {
+	cm := &corev1.ConfigMap{}
+	if err := s.client.Get(ctx, s.ref, cm); err != nil {
+		return false, "", errors.Wrap(resource.IgnoreNotFound(err), errGetFreezeConfigMap)
+	}
+	v, ok := cm.Data[FreezeKeyEnabled]
+	if !ok {
+		return false, "", nil
+	}
+	frozen, err := strconv.ParseBool(v)
+	if err != nil {
+		return false, "", errors.Errorf(errFmtParseFreeze, FreezeKeyEnabled, v)
+	}
+	return frozen, cm.Data[FreezeKeyReason], nil
+}
+
+// newFreezeCache returns a cache that only caches the freeze ConfigMap with
+// the supplied namespace and name. Using a dedicated cache avoids caching
+// every ConfigMap in the manager's shared cache.
+func newFreezeCache(mgr manager.Manager, namespace, name string) (cache.Cache, error) {
+	return cache.New(mgr.GetConfig(), cache.Options{
+		HTTPClient: mgr.GetHTTPClient(),
+		Scheme:     mgr.GetScheme(),
+		Mapper:     mgr.GetRESTMapper(),
+		ByObject: map[client.Object]cache.ByObject{
+			&corev1.ConfigMap{}: {
+				Namespaces: map[string]cache.Config{namespace: {}},
+				Field:      fields.OneTermEqualSelector("metadata.name", name),
+			},
+		},
+	})
+}
+
+// enqueuePackagesForFreeze returns an event handler that enqueues every
+// package of the supplied kind when the freeze ConfigMap with the supplied
+// namespace and name changes, so that packages notice promptly when a freeze
+// is enabled or lifted.
+func enqueuePackagesForFreeze(kube client.Client, k PackageKind, namespace, name string, log logging.Logger) handler.EventHandler {
+	return handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, o client.Object) []reconcile.Request {
+		if o.GetNamespace() != namespace || o.GetName() != name {
+			return nil
+		}
+		l := k.NewPackageList()
+		if err := kube.List(ctx, l); err != nil {
+			// Nothing we can do, except logging, if we can't list packages.
+			log.Debug("Cannot list packages while attempting to enqueue from freeze ConfigMap", "error", err)
+			return nil
+		}
+		items, err := apimeta.ExtractList(l)
+		if err != nil {
+			log.Debug("Cannot extract packages while attempting to enqueue from freeze ConfigMap", "error", err)
+			return nil
+		}
+
+		rs := make([]reconcile.Request, 0, len(items))
+		for _, i := range items {
+			p, ok := i.(client.Object)
+			if !ok {
+				continue
+			}
+			rs = append(rs, reconcile.Request{NamespacedName: types.NamespacedName{Name: p.GetName()}})
+		}
+		return rs
+	})
+}
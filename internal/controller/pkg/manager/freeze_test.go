@@ -0,0 +1,86 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	kevent "sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+)
+
+func TestEnqueuePackagesForFreeze(t *testing.T) {
+	kube := &test.MockClient{
+		MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+			l := o.(*v1.ProviderList)
+			l.Items = []v1.Provider{
+				{ObjectMeta: metav1.ObjectMeta{Name: "provider-a"}},
+				{ObjectMeta: metav1.ObjectMeta{Name: "provider-b"}},
+			}
+			return nil
+		}),
+	}
+	cm := func(namespace, name, rv string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name, ResourceVersion: rv}}
+	}
+
+	cases := map[string]struct {
+		reason string
+		event  kevent.UpdateEvent
+		want   []reconcile.Request
+	}{
+		"OtherNamespace": {
+			reason: "We should ignore ConfigMaps outside Crossplane's namespace.",
+			event:  kevent.UpdateEvent{ObjectOld: cm("default", "freeze", "1"), ObjectNew: cm("default", "freeze", "2")},
+		},
+		"OtherConfigMap": {
+			reason: "We should ignore ConfigMaps other than the freeze ConfigMap.",
+			event:  kevent.UpdateEvent{ObjectOld: cm("crossplane-system", "other", "1"), ObjectNew: cm("crossplane-system", "other", "2")},
+		},
+		"FreezeConfigMap": {
+			reason: "We should enqueue every package when the freeze ConfigMap changes.",
+			event:  kevent.UpdateEvent{ObjectOld: cm("crossplane-system", "freeze", "1"), ObjectNew: cm("crossplane-system", "freeze", "2")},
+			want: []reconcile.Request{
+				{NamespacedName: types.NamespacedName{Name: "provider-a"}},
+				{NamespacedName: types.NamespacedName{Name: "provider-b"}},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			h := enqueuePackagesForFreeze(kube, ProviderPackageKind, "crossplane-system", "freeze", logging.NewNopLogger())
+			q := &addQueueMock{}
+			h.Update(context.Background(), tc.event, q)
+
+			if diff := cmp.Diff(tc.want, q.added); diff != "" {
+				t.Errorf("\n%s\nh.Update(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+
+	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+)
+
+const (
+	errMissingFactory = "package kind must specify package, package list, package revision, and package revision list factories"
+	errFmtGetKind     = "cannot determine kind of %T"
+	errFmtWrongKind   = "factory for %s returns a %s"
+)
+
+// A PackageKind is a kind of package the package manager reconciles.
+type PackageKind struct {
+	// Package is the kind of package.
+	Package schema.GroupVersionKind
+
+	// Revision is the kind of the package's revisions. Its list kind is
+	// assumed to be the revision kind suffixed with 'List'.
+	Revision schema.GroupVersionKind
+
+	// NewPackage returns a new package of this kind.
+	NewPackage func() v1.Package
+
+	// NewPackageList returns a new list of packages of this kind. Its kind
+	// is assumed to be the package kind suffixed with 'List'.
+	NewPackageList func() client.ObjectList
+
+	// NewPackageRevision returns a new revision of this kind of package.
+	NewPackageRevision func() v1.PackageRevision
+
+	// NewPackageRevisionList returns a new list of revisions of this kind of
+	// package.
+	NewPackageRevisionList func() v1.PackageRevisionList
+
+	// EnqueueForImageConfig returns an event handler that enqueues packages
+	// of this kind when an ImageConfig that may apply to them changes.
+	EnqueueForImageConfig func(kube client.Client, log logging.Logger) handler.EventHandler
+}
+
+// Kinds of package reconciled by the package manager.
+var (
+	ProviderPackageKind = PackageKind{
+		Package:                v1.ProviderGroupVersionKind,
+		Revision:               v1.ProviderRevisionGroupVersionKind,
+		NewPackage:             func() v1.Package { return &v1.Provider{} },
+		NewPackageList:         func() client.ObjectList { return &v1.ProviderList{} },
+		NewPackageRevision:     func() v1.PackageRevision { return &v1.ProviderRevision{} },
+		NewPackageRevisionList: func() v1.PackageRevisionList { return &v1.ProviderRevisionList{} },
+		EnqueueForImageConfig:  enqueueProvidersForImageConfig,
+	}
+
+	ConfigurationPackageKind = PackageKind{
+		Package:                v1.ConfigurationGroupVersionKind,
+		Revision:               v1.ConfigurationRevisionGroupVersionKind,
+		NewPackage:             func() v1.Package { return &v1.Configuration{} },
+		NewPackageList:         func() client.ObjectList { return &v1.ConfigurationList{} },
+		NewPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+		NewPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+		EnqueueForImageConfig:  enqueueConfigurationsForImageConfig,
+	}
+
+	FunctionPackageKind = PackageKind{
+		Package:                v1.FunctionGroupVersionKind,
+		Revision:               v1.FunctionRevisionGroupVersionKind,
+		NewPackage:             func() v1.Package { return &v1.Function{} },
+		NewPackageList:         func() client.ObjectList { return &v1.FunctionList{} },
+		NewPackageRevision:     func() v1.PackageRevision { return &v1.FunctionRevision{} },
+		NewPackageRevisionList: func() v1.PackageRevisionList { return &v1.FunctionRevisionList{} },
+		EnqueueForImageConfig:  enqueueFunctionsForImageConfig,
+	}
+)
+
+// Validate returns an error if the kind's factories don't return objects of
+// the kind's package and revision kinds, according to the supplied scheme.
+func (k PackageKind) Validate(s *runtime.Scheme) error {
+	if k.NewPackage == nil || k.NewPackageList == nil || k.NewPackageRevision == nil || k.NewPackageRevisionList == nil {
+		return errors.New(errMissingFactory)
+	}
+	for _, c := range []struct {
+		want schema.GroupVersionKind
+		obj  runtime.Object
+	}{
+		{want: k.Package, obj: k.NewPackage()},
+		{want: k.Package.GroupVersion().WithKind(k.Package.Kind + "List"), obj: k.NewPackageList()},
+		{want: k.Revision, obj: k.NewPackageRevision()},
+		{want: k.Revision.GroupVersion().WithKind(k.Revision.Kind + "List"), obj: k.NewPackageRevisionList()},
+	} {
+		got, err := apiutil.GVKForObject(c.obj, s)
+		if err != nil {
+			return errors.Wrapf(err, errFmtGetKind, c.obj)
+		}
+		if got != c.want {
+			return errors.Errorf(errFmtWrongKind, c.want, got)
+		}
+	}
+	return nil
+}
+
+// WithPackageKind specifies the kind of package the Reconciler should
+// reconcile.
+func WithPackageKind(k PackageKind) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.newPackage = k.NewPackage
+		r.newPackageRevision = k.NewPackageRevision
+		r.newPackageRevisionList = k.NewPackageRevisionList
+	}
+}
@@ -0,0 +1,150 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/pkg/conditions"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+	"github.com/crossplane/crossplane/internal/controller/pkg/controller"
+	"github.com/crossplane/crossplane/internal/xpkg/fake"
+)
+
+func TestPackageKindValidate(t *testing.T) {
+	s := runtime.NewScheme()
+	_ = v1.AddToScheme(s)
+
+	mismatched := ProviderPackageKind
+	mismatched.NewPackageRevision = func() v1.PackageRevision { return &v1.FunctionRevision{} }
+
+	missing := ProviderPackageKind
+	missing.NewPackageRevisionList = nil
+
+	cases := map[string]struct {
+		reason string
+		kind   PackageKind
+		want   error
+	}{
+		"Provider": {
+			reason: "The Provider kind's factories should match its kinds.",
+			kind:   ProviderPackageKind,
+		},
+		"Configuration": {
+			reason: "The Configuration kind's factories should match its kinds.",
+			kind:   ConfigurationPackageKind,
+		},
+		"Function": {
+			reason: "The Function kind's factories should match its kinds.",
+			kind:   FunctionPackageKind,
+		},
+		"MissingFactory": {
+			reason: "We should return an error if a factory is missing.",
+			kind:   missing,
+			want:   errors.New(errMissingFactory),
+		},
+		"MismatchedFactory": {
+			reason: "We should return an error if a factory returns the wrong kind of object.",
+			kind:   mismatched,
+			want:   errors.Errorf(errFmtWrongKind, v1.ProviderRevisionGroupVersionKind, v1.FunctionRevisionGroupVersionKind),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := tc.kind.Validate(s)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nValidate(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestWithPackageKind(t *testing.T) {
+	testLog := logging.NewLogrLogger(zap.New(zap.UseDevMode(true), zap.WriteTo(io.Discard)).WithName("testlog"))
+
+	r := &Reconciler{
+		client: resource.ClientApplicator{
+			Client: &test.MockClient{
+				MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+					p, ok := o.(*v1.Function)
+					if !ok {
+						t.Errorf("Get(...): want *v1.Function, got %T", o)
+						return nil
+					}
+					p.SetName("test")
+					p.SetGroupVersionKind(v1.FunctionGroupVersionKind)
+					return nil
+				}),
+				MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+					if _, ok := o.(*v1.FunctionRevisionList); !ok {
+						t.Errorf("List(...): want *v1.FunctionRevisionList, got %T", o)
+					}
+					return nil
+				}),
+				MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+					p, ok := o.(*v1.Function)
+					if !ok {
+						t.Errorf("Status().Update(...): want *v1.Function, got %T", o)
+						return nil
+					}
+					if diff := cmp.Diff("test-1234567", p.GetCurrentRevision()); diff != "" {
+						t.Errorf("Status().Update(...): -want current revision, +got current revision:\n%s", diff)
+					}
+					return nil
+				}),
+			},
+			Applicator: resource.ApplyFn(func(_ context.Context, o client.Object, _ ...resource.ApplyOption) error {
+				if _, ok := o.(*v1.FunctionRevision); !ok {
+					t.Errorf("Apply(...): want *v1.FunctionRevision, got %T", o)
+				}
+				return nil
+			}),
+		},
+		pkg: &MockRevisioner{
+			MockRevision: NewMockRevisionFn("test-1234567", nil),
+		},
+		config: &fake.MockConfigStore{
+			MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+			MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+		},
+		log:        testLog,
+		record:     event.NewNopRecorder(),
+		conditions: conditions.ObservedGenerationPropagationManager{},
+		metrics:    &controller.NopMetrics{},
+		audit:      NewNopAuditSink(),
+	}
+	WithPackageKind(FunctionPackageKind)(r)
+
+	if _, err := r.Reconcile(context.Background(), reconcile.Request{}); err != nil {
+		t.Errorf("r.Reconcile(...): want no error, got %v", err)
+	}
+}
@@ -0,0 +1,72 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"time"
+
+	"k8s.io/utils/clock"
+)
+
+// Phases of a reconcile whose latency the Reconciler measures.
+const (
+	phaseFetch = "fetch"
+	phaseList  = "list"
+	phaseApply = "apply"
+	phaseGC    = "gc"
+)
+
+// A phaseTimer measures how long a reconcile, and each of its phases, takes.
+// A nil phaseTimer measures nothing.
+type phaseTimer struct {
+	clock  clock.PassiveClock
+	start  time.Time
+	phases map[string]time.Duration
+}
+
+func newPhaseTimer(c clock.PassiveClock) *phaseTimer {
+	return &phaseTimer{clock: c, start: c.Now(), phases: make(map[string]time.Duration)}
+}
+
+// Start timing the supplied phase. Call the returned function when the phase
+// ends. A phase may be timed more than once; its durations are summed.
+func (t *phaseTimer) Start(phase string) func() {
+	if t == nil {
+		return func() {}
+	}
+	start := t.clock.Now()
+	return func() {
+		t.phases[phase] += t.clock.Since(start)
+	}
+}
+
+// Elapsed returns how long has passed since the timer was created.
+func (t *phaseTimer) Elapsed() time.Duration {
+	return t.clock.Since(t.start)
+}
+
+// Dominant returns the phase that took the longest, and how long it took. It
+// returns an empty phase if no phases were timed.
+func (t *phaseTimer) Dominant() (string, time.Duration) {
+	phase, longest := "", time.Duration(0)
+	for _, p := range []string{phaseFetch, phaseList, phaseApply, phaseGC} {
+		if d := t.phases[p]; d > longest {
+			phase, longest = p, d
+		}
+	}
+	return phase, longest
+}
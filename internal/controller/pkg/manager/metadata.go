@@ -0,0 +1,119 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"context"
+	"maps"
+	"slices"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+
+	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+)
+
+const (
+	errFmtGetRevisionMetadata    = "cannot get package revision %q to propagate package metadata"
+	errFmtUpdateRevisionMetadata = "cannot propagate package metadata to package revision %q"
+)
+
+// propagateMetadata propagates the labels and annotations the Reconciler is
+// configured to propagate from the supplied package to the supplied revision.
+// Labels and annotations the package doesn't have are removed from the
+// revision. It returns true if the revision changed.
+func (r *Reconciler) propagateMetadata(p v1.Package, pr v1.PackageRevision) bool {
+	labels, lc := propagate(p.GetLabels(), pr.GetLabels(), r.propagateLabels)
+	annotations, ac := propagate(p.GetAnnotations(), pr.GetAnnotations(), r.propagateAnnotations)
+	pr.SetLabels(labels)
+	pr.SetAnnotations(annotations)
+	return lc || ac
+}
+
+// propagate the supplied keys from one map to another, removing keys that
+// are missing from the source. It returns the updated destination map, and
+// whether it changed.
+func propagate(from, to map[string]string, keys []string) (map[string]string, bool) {
+	out := maps.Clone(to)
+	for _, k := range keys {
+		v, ok := from[k]
+		if !ok {
+			delete(out, k)
+			continue
+		}
+		if out == nil {
+			out = make(map[string]string)
+		}
+		out[k] = v
+	}
+	return out, !maps.Equal(out, to)
+}
+
+// onlyPropagatedMetadataChanged returns true if the only changes to the
+// supplied package since the supplied state was recorded are to labels or
+// annotations the Reconciler propagates to revisions.
+func (r *Reconciler) onlyPropagatedMetadataChanged(p v1.Package, s syncedPackage) bool {
+	if len(r.propagateLabels) == 0 && len(r.propagateAnnotations) == 0 {
+		return false
+	}
+	if p.GetGeneration() != s.generation || !slices.Equal(p.GetFinalizers(), s.finalizers) {
+		return false
+	}
+	lc, lo := changedKeys(s.labels, p.GetLabels(), r.propagateLabels)
+	ac, ao := changedKeys(s.annotations, p.GetAnnotations(), r.propagateAnnotations)
+	return (lc || ac) && !lo && !ao
+}
+
+// changedKeys returns whether any of the supplied keys changed between the
+// supplied maps, and whether any other keys did.
+func changedKeys(before, after map[string]string, keys []string) (changed, others bool) {
+	b := maps.Clone(before)
+	a := maps.Clone(after)
+	for _, k := range keys {
+		bv, bok := b[k]
+		av, aok := a[k]
+		if bv != av || bok != aok {
+			changed = true
+		}
+		delete(b, k)
+		delete(a, k)
+	}
+	return changed, !maps.Equal(a, b)
+}
+
+// propagateMetadataToRevisions propagates the labels and annotations the
+// Reconciler is configured to propagate from the supplied package to the
+// named revisions it controls. It returns the resource version of each named
+// revision after propagation.
+func (r *Reconciler) propagateMetadataToRevisions(ctx context.Context, p v1.Package, revisions map[string]string) (map[string]string, error) {
+	rvs := make(map[string]string, len(revisions))
+	for name := range revisions {
+		pr := r.newPackageRevision()
+		if err := r.client.Get(ctx, types.NamespacedName{Name: name}, pr); err != nil {
+			return nil, errors.Wrapf(err, errFmtGetRevisionMetadata, name)
+		}
+		if metav1.IsControlledBy(pr, p) && r.propagateMetadata(p, pr) {
+			if err := r.client.Update(ctx, pr); err != nil {
+				return nil, errors.Wrapf(err, errFmtUpdateRevisionMetadata, name)
+			}
+		}
+		rvs[name] = pr.GetResourceVersion()
+	}
+	return rvs, nil
+}
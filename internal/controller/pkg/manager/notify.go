@@ -0,0 +1,164 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+)
+
+const (
+	errMarshalNotification = "cannot marshal activation notification"
+	errBuildNotification   = "cannot build activation notification request"
+	errSendNotification    = "cannot send activation notification"
+	errNotificationQueue   = "activation notification queue is full"
+
+	errFmtNotificationStatus = "activation notification webhook returned status %d"
+)
+
+// An ActivationNotification notifies that a package activated a revision.
+type ActivationNotification struct {
+	// Package that activated the revision.
+	Package xpv1.TypedReference `json:"package"`
+
+	// PreviousRevision that was active before the revision was activated.
+	// Empty if no revision was active.
+	PreviousRevision string `json:"previousRevision,omitempty"`
+
+	// Revision that was activated.
+	Revision string `json:"revision"`
+
+	// Digest of the image the activated revision was resolved to. Empty if
+	// it's unknown.
+	Digest string `json:"digest,omitempty"`
+}
+
+// An ActivationNotifier notifies an external system that a package activated
+// a revision.
+type ActivationNotifier interface {
+	// Notify that a package activated a revision.
+	Notify(ctx context.Context, n ActivationNotification) error
+}
+
+// An ActivationNotifierFn notifies an external system that a package
+// activated a revision.
+type ActivationNotifierFn func(ctx context.Context, n ActivationNotification) error
+
+// Notify that a package activated a revision.
+func (fn ActivationNotifierFn) Notify(ctx context.Context, n ActivationNotification) error {
+	return fn(ctx, n)
+}
+
+// A NopActivationNotifier does nothing.
+type NopActivationNotifier struct{}
+
+// NewNopActivationNotifier returns an ActivationNotifier that does nothing.
+func NewNopActivationNotifier() *NopActivationNotifier {
+	return &NopActivationNotifier{}
+}
+
+// Notify does nothing.
+func (n *NopActivationNotifier) Notify(_ context.Context, _ ActivationNotification) error {
+	return nil
+}
+
+// A WebhookActivationNotifier notifies a webhook that a package activated a
+// revision, by POSTing it a JSON encoded ActivationNotification.
+type WebhookActivationNotifier struct {
+	client *http.Client
+	url    string
+}
+
+// NewWebhookActivationNotifier returns an ActivationNotifier that POSTs
+// notifications to the supplied URL using the supplied HTTP client.
+func NewWebhookActivationNotifier(c *http.Client, url string) *WebhookActivationNotifier {
+	return &WebhookActivationNotifier{client: c, url: url}
+}
+
+// Notify the webhook that a package activated a revision. It returns an error
+// if the webhook doesn't respond with a 2xx status code.
+func (w *WebhookActivationNotifier) Notify(ctx context.Context, n ActivationNotification) error {
+	body, err := json.Marshal(n)
+	if err != nil {
+		return errors.Wrap(err, errMarshalNotification)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrap(err, errBuildNotification)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	rsp, err := w.client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, errSendNotification)
+	}
+	defer rsp.Body.Close() //nolint:errcheck // We don't read the body, so there's nothing to do if closing it fails.
+	_, _ = io.Copy(io.Discard, rsp.Body)
+	if rsp.StatusCode < http.StatusOK || rsp.StatusCode >= http.StatusMultipleChoices {
+		return errors.Errorf(errFmtNotificationStatus, rsp.StatusCode)
+	}
+	return nil
+}
+
+// An AsyncActivationNotifier sends notifications using another
+// ActivationNotifier without blocking its caller, so that a slow external
+// system doesn't slow down reconciles. Notifications are queued until they're
+// sent; they're dropped if the queue is full.
+type AsyncActivationNotifier struct {
+	notifier ActivationNotifier
+	queue    chan ActivationNotification
+	log      logging.Logger
+}
+
+// NewAsyncActivationNotifier returns an ActivationNotifier that queues up to
+// the supplied number of notifications, and sends them using the supplied
+// ActivationNotifier once started. Failures to send are logged.
+func NewAsyncActivationNotifier(n ActivationNotifier, size int, log logging.Logger) *AsyncActivationNotifier {
+	return &AsyncActivationNotifier{notifier: n, queue: make(chan ActivationNotification, size), log: log}
+}
+
+// Notify queues a notification that a package activated a revision. It returns
+// an error if the queue is full.
+func (a *AsyncActivationNotifier) Notify(_ context.Context, n ActivationNotification) error {
+	select {
+	case a.queue <- n:
+		return nil
+	default:
+		return errors.New(errNotificationQueue)
+	}
+}
+
+// Start sending queued notifications, one at a time, until the supplied
+// context is done.
+func (a *AsyncActivationNotifier) Start(ctx context.Context) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case n := <-a.queue:
+			if err := a.notifier.Notify(ctx, n); err != nil {
+				a.log.Info("Cannot notify that package activated revision", "package", n.Package.Name, "revision", n.Revision, "error", err)
+			}
+		}
+	}
+}
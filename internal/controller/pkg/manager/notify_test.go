@@ -0,0 +1,130 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestWebhookActivationNotifier(t *testing.T) {
+	n := ActivationNotification{
+		Package:          xpv1.TypedReference{APIVersion: "pkg.crossplane.io/v1", Kind: "Provider", Name: "provider-test"},
+		PreviousRevision: "provider-test-1111111",
+		Revision:         "provider-test-2222222",
+		Digest:           "sha256:ecc25c121431dfc7058754427f97c034ecde26d4aafa0da16d4fc1c6d1c2d07a",
+	}
+
+	type want struct {
+		err          error
+		notification *ActivationNotification
+	}
+	cases := map[string]struct {
+		reason string
+		status int
+		want   want
+	}{
+		"Success": {
+			reason: "We should POST a JSON encoded notification to the webhook.",
+			status: http.StatusOK,
+			want: want{
+				notification: &n,
+			},
+		},
+		"ErrorStatus": {
+			reason: "We should return an error if the webhook doesn't respond with a 2xx status code.",
+			status: http.StatusInternalServerError,
+			want: want{
+				err:          errors.Errorf(errFmtNotificationStatus, http.StatusInternalServerError),
+				notification: &n,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var got *ActivationNotification
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodPost {
+					t.Errorf("\n%s\nNotify(...): want method %s, got %s", tc.reason, http.MethodPost, r.Method)
+				}
+				if ct := r.Header.Get("Content-Type"); ct != "application/json" {
+					t.Errorf("\n%s\nNotify(...): want content type application/json, got %s", tc.reason, ct)
+				}
+				got = &ActivationNotification{}
+				if err := json.NewDecoder(r.Body).Decode(got); err != nil {
+					t.Errorf("\n%s\nNotify(...): cannot decode notification: %v", tc.reason, err)
+				}
+				w.WriteHeader(tc.status)
+			}))
+			defer srv.Close()
+
+			err := NewWebhookActivationNotifier(srv.Client(), srv.URL).Notify(context.Background(), n)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nNotify(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.notification, got); diff != "" {
+				t.Errorf("\n%s\nNotify(...): -want notification, +got notification:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestAsyncActivationNotifier(t *testing.T) {
+	first := ActivationNotification{Revision: "provider-test-1111111"}
+	second := ActivationNotification{Revision: "provider-test-2222222"}
+
+	sent := make(chan ActivationNotification)
+	a := NewAsyncActivationNotifier(ActivationNotifierFn(func(_ context.Context, n ActivationNotification) error {
+		sent <- n
+		return nil
+	}), 1, logging.NewNopLogger())
+
+	// Notifications are queued until the notifier is started.
+	if err := a.Notify(context.Background(), first); err != nil {
+		t.Errorf("Notify(...): want no error, got %v", err)
+	}
+	want := errors.New(errNotificationQueue)
+	if err := a.Notify(context.Background(), second); !cmp.Equal(want, err, test.EquateErrors()) {
+		t.Errorf("Notify(...): want error %v when the queue is full, got %v", want, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go a.Start(ctx) //nolint:errcheck // Start only returns nil.
+
+	if diff := cmp.Diff(first, <-sent); diff != "" {
+		t.Errorf("Start(...): -want notification, +got notification:\n%s", diff)
+	}
+	if err := a.Notify(context.Background(), second); err != nil {
+		t.Errorf("Notify(...): want no error once the queue drained, got %v", err)
+	}
+	if diff := cmp.Diff(second, <-sent); diff != "" {
+		t.Errorf("Start(...): -want notification, +got notification:\n%s", diff)
+	}
+}
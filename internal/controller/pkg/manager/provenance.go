@@ -0,0 +1,116 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"context"
+	"slices"
+
+	"github.com/google/go-containerregistry/pkg/name"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+
+	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+	"github.com/crossplane/crossplane/internal/xpkg"
+)
+
+// DefaultProvenanceArtifactType is the artifact type of an in-toto
+// attestation, such as a SLSA provenance attestation.
+const DefaultProvenanceArtifactType = "application/vnd.in-toto+json"
+
+const errParseProvenanceRef = "cannot parse package source"
+
+// A ProvenanceVerifier verifies that a package image has a required provenance
+// attestation before the package manager creates a new revision of it.
+type ProvenanceVerifier interface {
+	// VerifyProvenance returns true if the image of the supplied package
+	// with the supplied digest has a required provenance attestation. The
+	// digest is empty if the Revisioner didn't resolve it. It returns an
+	// error if it can't determine whether the image has an attestation.
+	VerifyProvenance(ctx context.Context, p v1.Package, digest string, secrets ...string) (bool, error)
+}
+
+// A ProvenanceVerifierFn verifies that a package image has a required
+// provenance attestation.
+type ProvenanceVerifierFn func(ctx context.Context, p v1.Package, digest string, secrets ...string) (bool, error)
+
+// VerifyProvenance of the supplied package's image.
+func (fn ProvenanceVerifierFn) VerifyProvenance(ctx context.Context, p v1.Package, digest string, secrets ...string) (bool, error) {
+	return fn(ctx, p, digest, secrets...)
+}
+
+// A NopProvenanceVerifier doesn't require package images to have a provenance
+// attestation.
+type NopProvenanceVerifier struct{}
+
+// NewNopProvenanceVerifier returns a ProvenanceVerifier that doesn't require
+// package images to have a provenance attestation.
+func NewNopProvenanceVerifier() *NopProvenanceVerifier {
+	return &NopProvenanceVerifier{}
+}
+
+// VerifyProvenance always returns true.
+func (v *NopProvenanceVerifier) VerifyProvenance(_ context.Context, _ v1.Package, _ string, _ ...string) (bool, error) {
+	return true, nil
+}
+
+// A ReferrersProvenanceVerifier requires a package image to be referred to by
+// an artifact of a provenance attestation type, using the OCI referrers API.
+type ReferrersProvenanceVerifier struct {
+	fetcher  xpkg.Fetcher
+	registry string
+	types    []string
+}
+
+// NewReferrersProvenanceVerifier returns a ProvenanceVerifier that requires
+// a package image to be referred to by an artifact of one of the supplied
+// types. It requires an in-toto attestation if no types are supplied. Package
+// sources that don't specify a registry are fetched from the supplied one.
+func NewReferrersProvenanceVerifier(f xpkg.Fetcher, registry string, artifactTypes ...string) *ReferrersProvenanceVerifier {
+	if len(artifactTypes) == 0 {
+		artifactTypes = []string{DefaultProvenanceArtifactType}
+	}
+	return &ReferrersProvenanceVerifier{fetcher: f, registry: registry, types: artifactTypes}
+}
+
+// VerifyProvenance returns true if an artifact of a provenance attestation
+// type refers to the supplied package's image. It returns false if the
+// image's digest is unknown, since it can't look up artifacts that refer to
+// it.
+func (v *ReferrersProvenanceVerifier) VerifyProvenance(ctx context.Context, p v1.Package, digest string, secrets ...string) (bool, error) {
+	if digest == "" {
+		return false, nil
+	}
+	ref, err := name.ParseReference(p.GetResolvedSource(), name.WithDefaultRegistry(v.registry))
+	if err != nil {
+		return false, errors.Wrap(err, errParseProvenanceRef)
+	}
+	idx, err := v.fetcher.Referrers(ctx, ref.Context().Digest(digest), secrets...)
+	if err != nil {
+		return false, errors.Wrap(err, errFetchReferrers)
+	}
+	m, err := idx.IndexManifest()
+	if err != nil {
+		return false, errors.Wrap(err, errReadReferrers)
+	}
+	for _, d := range m.Manifests {
+		if slices.Contains(v.types, d.ArtifactType) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-containerregistry/pkg/name"
+	conregv1 "github.com/google/go-containerregistry/pkg/v1"
+	conregfake "github.com/google/go-containerregistry/pkg/v1/fake"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+	"github.com/crossplane/crossplane/internal/xpkg/fake"
+)
+
+func TestReferrersProvenanceVerifier(t *testing.T) {
+	errBoom := errors.New("boom")
+	digest := "sha256:ecc25c121431dfc7058754427f97c034ecde26d4aafa0da16d4fc1c6d1c2d07a"
+
+	referrers := func(types ...string) conregv1.ImageIndex {
+		m := &conregv1.IndexManifest{}
+		for _, at := range types {
+			m.Manifests = append(m.Manifests, conregv1.Descriptor{ArtifactType: at})
+		}
+		idx := &conregfake.FakeImageIndex{}
+		idx.IndexManifestReturns(m, nil)
+		return idx
+	}
+
+	type args struct {
+		types  []string
+		digest string
+	}
+	type want struct {
+		ok  bool
+		err error
+	}
+	cases := map[string]struct {
+		reason    string
+		referrers func(name.Digest) (conregv1.ImageIndex, error)
+		args      args
+		want      want
+	}{
+		"UnknownDigest": {
+			reason: "We can't look up the referrers of an image whose digest is unknown.",
+			want: want{
+				ok: false,
+			},
+		},
+		"FetchError": {
+			reason: "We should return an error if we can't fetch the image's referrers.",
+			referrers: func(_ name.Digest) (conregv1.ImageIndex, error) {
+				return nil, errBoom
+			},
+			args: args{
+				digest: digest,
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errFetchReferrers),
+			},
+		},
+		"NoAttestation": {
+			reason: "An image that isn't referred to by an attestation of a required type has no provenance.",
+			referrers: func(_ name.Digest) (conregv1.ImageIndex, error) {
+				return referrers("application/vnd.dev.cosign.artifact.sig.v1+json"), nil
+			},
+			args: args{
+				digest: digest,
+			},
+			want: want{
+				ok: false,
+			},
+		},
+		"DefaultAttestation": {
+			reason: "An image referred to by an in-toto attestation has provenance by default.",
+			referrers: func(d name.Digest) (conregv1.ImageIndex, error) {
+				if d.String() != "xpkg.crossplane.io/crossplane/test@"+digest {
+					t.Errorf("Referrers(...): want the resolved image's digest, got %q", d.String())
+				}
+				return referrers(DefaultProvenanceArtifactType), nil
+			},
+			args: args{
+				digest: digest,
+			},
+			want: want{
+				ok: true,
+			},
+		},
+		"CustomAttestation": {
+			reason: "An image referred to by an attestation of a supplied type has provenance.",
+			referrers: func(_ name.Digest) (conregv1.ImageIndex, error) {
+				return referrers("application/vnd.example.provenance+json"), nil
+			},
+			args: args{
+				types:  []string{"application/vnd.example.provenance+json"},
+				digest: digest,
+			},
+			want: want{
+				ok: true,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			p := &v1.Configuration{}
+			p.SetSource("crossplane/test:v1.0.0")
+			p.SetResolvedSource("crossplane/test:v1.0.0")
+
+			v := NewReferrersProvenanceVerifier(&fake.MockFetcher{MockReferrers: tc.referrers}, "xpkg.crossplane.io", tc.args.types...)
+			ok, err := v.VerifyProvenance(context.Background(), p, tc.args.digest)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nVerifyProvenance(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.ok, ok); diff != "" {
+				t.Errorf("\n%s\nVerifyProvenance(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,145 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"text/template"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+)
+
+const (
+	errParsePullSecretTemplate   = "cannot parse pull secret name template"
+	errExecutePullSecretTemplate = "cannot execute pull secret name template"
+)
+
+// A PullSecretIndex tracks which packages use which pull secrets. The
+// Reconciler records the pull secrets it resolves for each package, whether
+// they're specified by the package or selected by an image config.
+type PullSecretIndex struct {
+	mu sync.RWMutex
+
+	// packages maps each pull secret to the packages that use it.
+	packages map[string]map[string]bool
+
+	// secrets maps each package to the pull secrets it uses.
+	secrets map[string][]string
+}
+
+// NewPullSecretIndex returns an empty PullSecretIndex.
+func NewPullSecretIndex() *PullSecretIndex {
+	return &PullSecretIndex{
+		packages: make(map[string]map[string]bool),
+		secrets:  make(map[string][]string),
+	}
+}
+
+// Set the pull secrets the named package uses, replacing any it previously
+// used.
+func (i *PullSecretIndex) Set(pkg string, secrets ...string) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	for _, s := range i.secrets[pkg] {
+		delete(i.packages[s], pkg)
+		if len(i.packages[s]) == 0 {
+			delete(i.packages, s)
+		}
+	}
+	delete(i.secrets, pkg)
+
+	for _, s := range secrets {
+		if i.packages[s] == nil {
+			i.packages[s] = make(map[string]bool)
+		}
+		i.packages[s][pkg] = true
+	}
+	if len(secrets) > 0 {
+		i.secrets[pkg] = slices.Clone(secrets)
+	}
+}
+
+// Delete the named package from the index.
+func (i *PullSecretIndex) Delete(pkg string) {
+	i.Set(pkg)
+}
+
+// Packages returns the names of the packages that use the named pull secret,
+// sorted by name.
+func (i *PullSecretIndex) Packages(secret string) []string {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	pkgs := make([]string, 0, len(i.packages[secret]))
+	for p := range i.packages[secret] {
+		pkgs = append(pkgs, p)
+	}
+	slices.Sort(pkgs)
+	return pkgs
+}
+
+// EnqueuePackagesForPullSecret enqueues the packages the supplied index says
+// use a Secret when the Secret changes, for example because it was rotated.
+// Pull secrets must be in the supplied namespace.
+func EnqueuePackagesForPullSecret(idx *PullSecretIndex, namespace string, log logging.Logger) handler.EventHandler {
+	return handler.EnqueueRequestsFromMapFunc(func(_ context.Context, o client.Object) []reconcile.Request {
+		if o.GetNamespace() != namespace {
+			return nil
+		}
+		pkgs := idx.Packages(o.GetName())
+		rs := make([]reconcile.Request, 0, len(pkgs))
+		for _, p := range pkgs {
+			log.Debug("Enqueuing package for pull secret", "package", p, "secret", o.GetName())
+			rs = append(rs, reconcile.Request{NamespacedName: types.NamespacedName{Name: p}})
+		}
+		return rs
+	})
+}
+
+// pullSecretTemplateData is the data available to a pull secret name template.
+type pullSecretTemplateData struct {
+	// Namespace is the namespace the package's pull secrets live in.
+	Namespace string
+}
+
+// renderPullSecretName renders the supplied pull secret name, which may be a
+// text/template that interpolates the supplied namespace - for example
+// "{{ .Namespace }}-registry". Names that aren't templates are returned as is.
+func renderPullSecretName(name, namespace string) (string, error) {
+	if !strings.Contains(name, "{{") {
+		return name, nil
+	}
+	t, err := template.New("pullSecret").Option("missingkey=error").Parse(name)
+	if err != nil {
+		return "", errors.Wrap(err, errParsePullSecretTemplate)
+	}
+	b := &strings.Builder{}
+	if err := t.Execute(b, pullSecretTemplateData{Namespace: namespace}); err != nil {
+		return "", errors.Wrap(err, errExecutePullSecretTemplate)
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,198 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	kevent "sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+)
+
+type addQueueMock struct {
+	workqueue.TypedRateLimitingInterface[reconcile.Request]
+
+	added []reconcile.Request
+}
+
+func (q *addQueueMock) Add(item reconcile.Request) {
+	q.added = append(q.added, item)
+}
+
+func TestPullSecretIndex(t *testing.T) {
+	idx := NewPullSecretIndex()
+	idx.Set("a", "shared", "only-a")
+	idx.Set("b", "shared", "stale")
+	idx.Set("b", "shared")
+	idx.Set("c", "only-c")
+	idx.Delete("c")
+
+	cases := map[string]struct {
+		reason string
+		secret string
+		want   []string
+	}{
+		"Shared": {
+			reason: "Every package that uses a secret should be returned.",
+			secret: "shared",
+			want:   []string{"a", "b"},
+		},
+		"Replaced": {
+			reason: "A package should no longer be returned for a secret it stopped using.",
+			secret: "stale",
+			want:   []string{},
+		},
+		"Deleted": {
+			reason: "A deleted package should no longer be returned.",
+			secret: "only-c",
+			want:   []string{},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := idx.Packages(tc.secret)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nPackages(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestEnqueuePackagesForPullSecret(t *testing.T) {
+	idx := NewPullSecretIndex()
+	idx.Set("provider-a", "registry-creds")
+	idx.Set("provider-b", "registry-creds", "other-creds")
+	idx.Set("provider-c", "other-creds")
+
+	secret := func(namespace, name, rv string) *metav1.PartialObjectMetadata {
+		return &metav1.PartialObjectMetadata{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name, ResourceVersion: rv}}
+	}
+
+	cases := map[string]struct {
+		reason string
+		event  kevent.UpdateEvent
+		want   []reconcile.Request
+	}{
+		"OtherNamespace": {
+			reason: "We should ignore Secrets outside the namespace pull secrets live in.",
+			event:  kevent.UpdateEvent{ObjectOld: secret("default", "registry-creds", "1"), ObjectNew: secret("default", "registry-creds", "2")},
+		},
+		"UnusedSecret": {
+			reason: "We should not enqueue anything when a Secret no package uses changes.",
+			event:  kevent.UpdateEvent{ObjectOld: secret("crossplane-system", "unused", "1"), ObjectNew: secret("crossplane-system", "unused", "2")},
+		},
+		"UsedSecret": {
+			reason: "We should enqueue only the packages that use the changed Secret.",
+			event:  kevent.UpdateEvent{ObjectOld: secret("crossplane-system", "registry-creds", "1"), ObjectNew: secret("crossplane-system", "registry-creds", "2")},
+			want: []reconcile.Request{
+				{NamespacedName: types.NamespacedName{Name: "provider-a"}},
+				{NamespacedName: types.NamespacedName{Name: "provider-b"}},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			h := EnqueuePackagesForPullSecret(idx, "crossplane-system", logging.NewNopLogger())
+			q := &addQueueMock{}
+			h.Update(context.Background(), tc.event, q)
+
+			if diff := cmp.Diff(tc.want, q.added); diff != "" {
+				t.Errorf("\n%s\nh.Update(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestRenderPullSecretName(t *testing.T) {
+	type args struct {
+		name      string
+		namespace string
+	}
+	type want struct {
+		name string
+		err  error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NotATemplate": {
+			reason: "A name that isn't a template should be returned as is.",
+			args: args{
+				name:      "registry-creds",
+				namespace: "team-a",
+			},
+			want: want{
+				name: "registry-creds",
+			},
+		},
+		"InterpolateNamespace": {
+			reason: "A template should be able to interpolate the package's namespace.",
+			args: args{
+				name:      "{{ .Namespace }}-registry-creds",
+				namespace: "team-a",
+			},
+			want: want{
+				name: "team-a-registry-creds",
+			},
+		},
+		"InvalidTemplate": {
+			reason: "We should return an error if the template can't be parsed.",
+			args: args{
+				name:      "{{ .Namespace",
+				namespace: "team-a",
+			},
+			want: want{
+				err: cmpopts.AnyError,
+			},
+		},
+		"UnknownField": {
+			reason: "We should return an error if the template references data that doesn't exist.",
+			args: args{
+				name:      "{{ .Tenant }}-registry-creds",
+				namespace: "team-a",
+			},
+			want: want{
+				err: cmpopts.AnyError,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := renderPullSecretName(tc.args.name, tc.args.namespace)
+			if diff := cmp.Diff(tc.want.err, err, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nrenderPullSecretName(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.name, got); diff != "" {
+				t.Errorf("\n%s\nrenderPullSecretName(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
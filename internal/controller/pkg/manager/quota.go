@@ -0,0 +1,97 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"context"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+const (
+	errGetQuotaConfigMap = "cannot get active revision quota ConfigMap"
+	errFmtParseQuota     = "cannot parse active revision quota %q for tenant %q"
+)
+
+// A QuotaSource returns how many active package revisions a tenant's packages
+// may have.
+type QuotaSource interface {
+	// ActiveRevisionQuota returns the supplied tenant's active revision
+	// quota. It returns false if the tenant has no quota.
+	ActiveRevisionQuota(ctx context.Context, tenant string) (int, bool, error)
+}
+
+// A QuotaSourceFn returns how many active package revisions a tenant's
+// packages may have.
+type QuotaSourceFn func(ctx context.Context, tenant string) (int, bool, error)
+
+// ActiveRevisionQuota returns the supplied tenant's active revision quota.
+func (fn QuotaSourceFn) ActiveRevisionQuota(ctx context.Context, tenant string) (int, bool, error) {
+	return fn(ctx, tenant)
+}
+
+// A NopQuotaSource never returns a quota.
+type NopQuotaSource struct{}
+
+// NewNopQuotaSource returns a QuotaSource that never returns a quota.
+func NewNopQuotaSource() *NopQuotaSource {
+	return &NopQuotaSource{}
+}
+
+// ActiveRevisionQuota always returns false, indicating the tenant has no
+// quota.
+func (s *NopQuotaSource) ActiveRevisionQuota(_ context.Context, _ string) (int, bool, error) {
+	return 0, false, nil
+}
+
+// A ConfigMapQuotaSource reads active revision quotas from a ConfigMap. Each
+// key of the ConfigMap's data is a tenant, and each value that tenant's quota.
+type ConfigMapQuotaSource struct {
+	client client.Reader
+	ref    types.NamespacedName
+}
+
+// NewConfigMapQuotaSource returns a QuotaSource that reads active revision
+// quotas from the ConfigMap with the supplied namespace and name.
+func NewConfigMapQuotaSource(c client.Reader, namespace, name string) *ConfigMapQuotaSource {
+	return &ConfigMapQuotaSource{client: c, ref: types.NamespacedName{Namespace: namespace, Name: name}}
+}
+
+// ActiveRevisionQuota returns the supplied tenant's active revision quota.
+// Tenants that don't appear in the ConfigMap, or all tenants if the ConfigMap
+// doesn't exist, have no quota.
+func (s *ConfigMapQuotaSource) ActiveRevisionQuota(ctx context.Context, tenant string) (int, bool, error) {
+	cm := &corev1.ConfigMap{}
+	if err := s.client.Get(ctx, s.ref, cm); err != nil {
+		return 0, false, errors.Wrap(resource.IgnoreNotFound(err), errGetQuotaConfigMap)
+	}
+	v, ok := cm.Data[tenant]
+	if !ok {
+		return 0, false, nil
+	}
+	q, err := strconv.Atoi(v)
+	if err != nil || q < 0 {
+		return 0, false, errors.Errorf(errFmtParseQuota, v, tenant)
+	}
+	return q, true, nil
+}
@@ -18,47 +18,196 @@ limitations under the License.
 package manager
 
 import (
+	"cmp"
 	"context"
 	"fmt"
-	"math"
+	"maps"
+	"net/http"
 	"reflect"
+	"slices"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
+	"unicode/utf8"
 
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+	"golang.org/x/sync/errgroup"
+	"golang.org/x/sync/semaphore"
 	corev1 "k8s.io/api/core/v1"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/utils/clock"
 	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/conditions"
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
 	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/feature"
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/parser"
 	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 
 	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
 	"github.com/crossplane/crossplane/apis/pkg/v1beta1"
 	"github.com/crossplane/crossplane/internal/controller/pkg/controller"
+	"github.com/crossplane/crossplane/internal/features"
+	"github.com/crossplane/crossplane/internal/xcrd"
 	"github.com/crossplane/crossplane/internal/xpkg"
+	"github.com/crossplane/crossplane/internal/xpkg/parser/yaml"
 )
 
 const (
 	reconcileTimeout = 1 * time.Minute
 
+	// finalizer is added to packages whose revisions must be orphaned when
+	// they're deleted.
+	finalizer = "package.pkg.crossplane.io"
+
 	// pullWait is the time after which the package manager will check for
 	// updated content for the given package reference. This behavior is only
 	// enabled when the packagePullPolicy is Always.
 	pullWait = 1 * time.Minute
 
-	reconcilePausedMsg = "Reconciliation (including deletion) is paused via the pause annotation"
+	reconcilePausedMsg         = "Reconciliation (including deletion) is paused via the pause annotation"
+	reconcilePausedUntilFmtMsg = "Reconciliation (including deletion) is paused until %s via the paused-until annotation"
+
+	// defaultMaxConcurrentRevisionDeletes is the default maximum number of
+	// package revisions garbage collected concurrently.
+	defaultMaxConcurrentRevisionDeletes = 5
+
+	// defaultRevisionHistoryLimit is the revision history limit used for
+	// packages that don't specify one, unless the Reconciler is configured
+	// with a different default.
+	defaultRevisionHistoryLimit = 1
+
+	// healthProbeWindow is how long after a package revision's health changes
+	// the package manager keeps probing it, in case the change was transient.
+	healthProbeWindow = 5 * time.Minute
+
+	// quotaRecheckInterval is how often the package manager checks whether
+	// it can activate a revision that would exceed its tenant's active
+	// revision quota.
+	quotaRecheckInterval = 1 * time.Minute
+
+	// runtimeConfigRecheckInterval is how often the package manager checks
+	// whether a missing runtime config that blocks activating a revision
+	// was created.
+	runtimeConfigRecheckInterval = 1 * time.Minute
+
+	// maxRevisionSummaries is the maximum number of revisions summarized in a
+	// package's status.
+	maxRevisionSummaries = 10
+
+	// establishingRecheckInterval is how often the package manager checks
+	// whether the CRDs installed by a package's revision are established.
+	establishingRecheckInterval = 10 * time.Second
+
+	// dependencyGCRecheckInterval is how often the package manager checks
+	// whether the dependencies of a package whose activation it deferred
+	// finished garbage collecting their old revisions.
+	dependencyGCRecheckInterval = 5 * time.Second
+
+	// familyHealthRecheckInterval is how often the package manager checks
+	// whether the unhealthy members of a package's provider family became
+	// healthy.
+	familyHealthRecheckInterval = 30 * time.Second
+
+	// resolutionsSaturatedRequeue is how long the package manager waits
+	// before retrying a reconcile that couldn't resolve its package's
+	// revision because too many packages were already being resolved.
+	resolutionsSaturatedRequeue = 2 * time.Second
+
+	// activationWebhookTimeout is how long the package manager waits for
+	// the activation notification webhook to respond.
+	activationWebhookTimeout = 10 * time.Second
+
+	// activationNotificationQueueSize is how many activation notifications
+	// the package manager queues while it waits to send them.
+	activationNotificationQueueSize = 100
+)
+
+// An ImmutableFieldPolicy determines how the package manager handles failing to
+// update a package revision because the update changes immutable fields.
+type ImmutableFieldPolicy string
+
+// Immutable field policies.
+const (
+	// ImmutableFieldPolicySkip leaves the package revision as is, and reports
+	// that it couldn't be updated using a package condition.
+	ImmutableFieldPolicySkip ImmutableFieldPolicy = "Skip"
+
+	// ImmutableFieldPolicyRecreate deletes the package revision so that it can
+	// be recreated with the updated immutable fields. The objects the revision
+	// installed are orphaned rather than deleted with it, and the recreated
+	// revision takes ownership of them again.
+	ImmutableFieldPolicyRecreate ImmutableFieldPolicy = "Recreate"
+)
+
+// An OwnershipDriftPolicy determines how the package manager handles package
+// revisions that are labelled as belonging to a package, but aren't controlled
+// by it.
+type OwnershipDriftPolicy string
+
+// Ownership drift policies.
+const (
+	// OwnershipDriftPolicyIgnore leaves the package revision as is, and
+	// doesn't report that it isn't controlled by the package.
+	OwnershipDriftPolicyIgnore OwnershipDriftPolicy = "Ignore"
+
+	// OwnershipDriftPolicyAdopt restores the package's controller reference
+	// to the package revision.
+	OwnershipDriftPolicyAdopt OwnershipDriftPolicy = "Adopt"
+
+	// OwnershipDriftPolicyReport leaves the package revision as is, and
+	// reports that it isn't controlled by the package using a package
+	// condition.
+	OwnershipDriftPolicyReport OwnershipDriftPolicy = "Report"
+)
+
+// An UnknownHealthGCPolicy determines whether the package manager garbage
+// collects package revisions whose health is still unknown.
+type UnknownHealthGCPolicy string
+
+// Unknown health garbage collection policies.
+const (
+	// UnknownHealthGCPolicyRetain never garbage collects a package revision
+	// whose health is unknown, because it may still be converging.
+	UnknownHealthGCPolicyRetain UnknownHealthGCPolicy = "Retain"
+
+	// UnknownHealthGCPolicyCollect garbage collects package revisions
+	// regardless of their health.
+	UnknownHealthGCPolicyCollect UnknownHealthGCPolicy = "Collect"
+)
+
+// A GCOrder determines whether the package manager garbage collects a
+// package's old revisions before or after it activates its current revision.
+type GCOrder string
+
+// Garbage collection orders.
+const (
+	// GCOrderActivateFirst activates the current revision, then garbage
+	// collects old revisions. The old revisions remain a fallback until the
+	// current revision is applied.
+	GCOrderActivateFirst GCOrder = "ActivateFirst"
+
+	// GCOrderCollectFirst garbage collects old revisions, then activates the
+	// current revision. This frees the old revisions' resources before the
+	// current revision's are created.
+	GCOrderCollectFirst GCOrder = "CollectFirst"
 )
 
 func pullBasedRequeue(p *corev1.PullPolicy) reconcile.Result {
@@ -69,19 +218,65 @@ func pullBasedRequeue(p *corev1.PullPolicy) reconcile.Result {
 }
 
 const (
-	errGetPackage           = "cannot get package"
-	errListRevisions        = "cannot list revisions for package"
-	errUnpack               = "cannot unpack package"
-	errApplyPackageRevision = "cannot apply package revision"
-	errGCPackageRevision    = "cannot garbage collect old package revision"
-	errGetPullConfig        = "cannot get image pull secret from config"
-	errRewriteImage         = "cannot rewrite image path using config"
+	errGetPackage              = "cannot get package"
+	errListRevisions           = "cannot list revisions for package"
+	errUnpack                  = "cannot unpack package"
+	errApplyPackageRevision    = "cannot apply package revision"
+	errRecreatePackageRevision = "cannot delete package revision in order to recreate it"
+	errRollbackPackageRevision = "cannot roll back package revision"
+	errHoldPackageRevision     = "cannot hold package revision"
+	errOrphanPackageRevision   = "cannot remove owner reference from package revision"
+	errMigrateRevisionLabels   = "cannot relabel package revision"
+	errAnnotatePackage         = "cannot annotate package"
+	errResumePackage           = "cannot remove expired paused-until annotation from package"
+	errAddFinalizer            = "cannot add package finalizer"
+	errRemoveFinalizer         = "cannot remove package finalizer"
+	errGCPackageRevision       = "cannot garbage collect old package revision"
+	errGetPullConfig           = "cannot get image pull secret from config"
+	errRewriteImage            = "cannot rewrite image path using config"
+	errResolveAlias            = "cannot resolve package source alias"
+	errGetPullSecret           = "cannot get image pull secret selected by config"
+	errGetServiceAccount       = "cannot get service account whose image pull secrets the package uses"
+	errRenderPullSecret        = "cannot render image pull secret name selected by config"
 
 	errUpdateStatus                  = "cannot update package status"
 	errUpdateInactivePackageRevision = "cannot update inactive package revision"
-
-	errCreateK8sClient = "failed to initialize clientset"
-	errBuildFetcher    = "cannot build fetcher"
+	errAnnotateSBOMs                 = "cannot annotate package with its SBOM references"
+	errCheckActiveRevisionQuota      = "cannot check active revision quota"
+	errCheckGlobalFreeze             = "cannot check whether package revisions are frozen"
+	errListGCDependencies            = "cannot list dependencies that are garbage collecting their revisions"
+	errUpdateRevisionStatus          = "cannot update package revision status"
+	errRecordLock                    = "cannot record package revision in lock"
+
+	errCreateK8sClient       = "failed to initialize clientset"
+	errBuildFetcher          = "cannot build fetcher"
+	errBuildContentParser    = "cannot build package content parser"
+	errInvalidPackageKind    = "invalid package kind"
+	errAddActivationNotifier = "cannot add activation notifier to manager"
+	errBuildFreezeCache      = "cannot build maintenance freeze ConfigMap cache"
+	errAddFreezeCache        = "cannot add maintenance freeze ConfigMap cache to manager"
+
+	errPullAlwaysDigest = "package is pinned to a digest, so pull policy Always has no effect; treating it as IfNotPresent"
+
+	errFmtInvalidActivationPolicyOverride = "ignoring invalid %s annotation value %q: must be %q, %q, or %q"
+	errFmtInvalidPausedUntil              = "ignoring invalid %s annotation value %q: must be an RFC3339 timestamp"
+	errFmtRevisionLimitExceeded           = "cannot create package revision %q: package already has the maximum of %d revisions"
+	errFmtListSBOMs                       = "cannot discover SBOMs of package revision %q"
+	errFmtRecordSBOMs                     = "cannot record SBOMs of package revision %q"
+	errFmtDesiredRevisionNotFound         = "cannot roll back to revision number %d: package has no such revision"
+	errFmtHeldRevisionNotFound            = "cannot hold revision %q: package has no such revision"
+	errFmtHealthSourceRevisionNotFound    = "cannot derive health from revision %q: package has no such revision"
+	errFmtContentPolicyViolation          = "cannot create package revision %q: package contents violate policy"
+	errFmtDigestMismatch                  = "cannot create package revision %q: %s"
+	errFmtProvenanceMissing               = "cannot create package revision %q: package image has no required provenance attestation"
+	errFmtPackageTypeMismatch             = "cannot create package revision %q: package is a %s, but its image contains a %s"
+	errFmtVerifyProvenance                = "cannot verify provenance of package revision %q"
+	errFmtDeleteRevision                  = "cannot delete package revision %q"
+	errFmtGetCRD                          = "cannot get CRD %q"
+	errFmtListProviderFamily              = "cannot list revisions in provider family %q"
+	errFmtMultipleActiveRevisions         = "package has %d active revisions (%s); deactivating all but revision %q"
+	errFmtCurrentRevisionDisagrees        = "package's current revision %q isn't active, but its healthy revision %q is; using %q as its current revision"
+	errFmtSlowReconcile                   = "reconcile took %s, more than %d%% of its %s deadline; it spent the most time in the %s phase (%s)"
 )
 
 // Event reasons.
@@ -93,6 +288,11 @@ const (
 	reasonInstall            event.Reason = "InstallPackageRevision"
 	reasonPaused             event.Reason = "ReconciliationPaused"
 	reasonImageConfig        event.Reason = "ImageConfigSelection"
+	reasonAlias              event.Reason = "PackageSourceAlias"
+	reasonActivationPolicy   event.Reason = "ActivationPolicyOverride"
+	reasonPullPolicy         event.Reason = "PullPolicy"
+	reasonDelete             event.Reason = "DeletePackage"
+	reasonSlowReconcile      event.Reason = "SlowReconcile"
 )
 
 // ReconcilerOption is used to configure the Reconciler.
@@ -119,6 +319,13 @@ func WithNewPackageRevisionListFn(f func() v1.PackageRevisionList) ReconcilerOpt
 	}
 }
 
+// WithApplicator specifies how the Reconciler should apply package revisions.
+func WithApplicator(a resource.Applicator) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.client.Applicator = a
+	}
+}
+
 // WithRevisioner specifies how the Reconciler should acquire a package image's
 // revision name.
 func WithRevisioner(d Revisioner) ReconcilerOption {
@@ -134,6 +341,38 @@ func WithConfigStore(c xpkg.ConfigStore) ReconcilerOption {
 	}
 }
 
+// WithMetrics specifies how the Reconciler should record metrics.
+func WithMetrics(m controller.Metrics) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.metrics = m
+	}
+}
+
+// WithRevisionSpecTemplate specifies defaults for the spec of every package
+// revision the Reconciler creates. Fields set on the package take precedence.
+func WithRevisionSpecTemplate(t v1.PackageRevisionSpec) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.revisionTemplate = t
+	}
+}
+
+// WithNamespace specifies the namespace in which the Reconciler should expect
+// pull secrets selected by image configs to exist.
+func WithNamespace(n string) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.namespace = n
+	}
+}
+
+// WithFeatureFlags specifies the feature flags to inject into the Reconciler.
+// The Reconciler only takes feature gated code paths when the corresponding
+// feature flag is enabled.
+func WithFeatureFlags(f *feature.Flags) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.features = f
+	}
+}
+
 // WithLogger specifies how the Reconciler should log messages.
 func WithLogger(log logging.Logger) ReconcilerOption {
 	return func(r *Reconciler) {
@@ -148,407 +387,3460 @@ func WithRecorder(er event.Recorder) ReconcilerOption {
 	}
 }
 
-// Reconciler reconciles packages.
-type Reconciler struct {
-	client     resource.ClientApplicator
-	pkg        Revisioner
-	config     xpkg.ConfigStore
-	log        logging.Logger
-	record     event.Recorder
-	conditions conditions.Manager
+// WithImmutableFieldPolicy specifies how the Reconciler should handle failing
+// to update a package revision because the update changes immutable fields.
+func WithImmutableFieldPolicy(p ImmutableFieldPolicy) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.immutableFieldPolicy = p
+	}
+}
 
-	newPackage             func() v1.Package
-	newPackageRevision     func() v1.PackageRevision
-	newPackageRevisionList func() v1.PackageRevisionList
+// WithActiveRevisionQuota specifies the label that identifies the tenant a
+// package belongs to, and where the Reconciler should get each tenant's active
+// revision quota. The Reconciler won't activate a revision that would give a
+// tenant's packages more active revisions than its quota allows.
+func WithActiveRevisionQuota(tenantLabel string, q QuotaSource) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.tenantLabel = tenantLabel
+		r.quota = q
+	}
 }
 
-// SetupProvider adds a controller that reconciles Providers.
-func SetupProvider(mgr ctrl.Manager, o controller.Options) error {
-	name := "packages/" + strings.ToLower(v1.ProviderGroupKind)
-	np := func() v1.Package { return &v1.Provider{} }
-	nr := func() v1.PackageRevision { return &v1.ProviderRevision{} }
-	nrl := func() v1.PackageRevisionList { return &v1.ProviderRevisionList{} }
+// WithGlobalFreeze specifies where the Reconciler should get whether a
+// cluster-wide maintenance freeze is enabled. While it is the Reconciler
+// doesn't create, activate, or garbage collect package revisions, but keeps
+// package status up to date.
+func WithGlobalFreeze(s FreezeSource) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.freeze = s
+	}
+}
 
-	cs, err := kubernetes.NewForConfig(mgr.GetConfig())
-	if err != nil {
-		return errors.Wrap(err, errCreateK8sClient)
+// WithErrorConditionSource specifies where the Reconciler should get the rules
+// that map errors encountered while fetching a package to the package's
+// Installed condition.
+func WithErrorConditionSource(s ErrorConditionSource) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.errorConditions = s
 	}
-	f, err := xpkg.NewK8sFetcher(cs, append(o.FetcherOptions, xpkg.WithNamespace(o.Namespace), xpkg.WithServiceAccount(o.ServiceAccount))...)
-	if err != nil {
-		return errors.Wrap(err, errBuildFetcher)
+}
+
+// WithContentValidator specifies how the Reconciler should validate the
+// contents of a package before creating a new revision of it. The Reconciler
+// validates the contents its Revisioner parsed, if any.
+func WithContentValidator(v ContentValidator) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.validator = v
 	}
+}
 
-	log := o.Logger.WithValues("controller", name)
-	opts := []ReconcilerOption{
-		WithNewPackageFn(np),
-		WithNewPackageRevisionFn(nr),
-		WithNewPackageRevisionListFn(nrl),
-		WithRevisioner(NewPackageRevisioner(f, WithDefaultRegistry(o.DefaultRegistry))),
-		WithConfigStore(xpkg.NewImageConfigStore(mgr.GetClient(), o.Namespace)),
-		WithLogger(log),
-		WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
+// WithRevisionContentsFetcher specifies how the Reconciler should fetch the
+// contents of the revision that was active when an inactive revision became
+// current, to report the objects that changed between them.
+func WithRevisionContentsFetcher(f RevisionContentsFetcher) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.contents = f
 	}
+}
 
-	return ctrl.NewControllerManagedBy(mgr).
-		Named(name).
-		For(&v1.Provider{}).
-		Owns(&v1.ProviderRevision{}).
-		Watches(&v1beta1.ImageConfig{}, enqueueProvidersForImageConfig(mgr.GetClient(), log)).
-		WithOptions(o.ForControllerRuntime()).
-		Complete(ratelimiter.NewReconciler(name, errors.WithSilentRequeueOnConflict(NewReconciler(mgr, opts...)), o.GlobalRateLimiter))
+// WithProvenanceVerifier specifies how the Reconciler should verify that a
+// package image has a required provenance attestation before creating a new
+// revision of it.
+func WithProvenanceVerifier(v ProvenanceVerifier) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.provenance = v
+	}
 }
 
-// SetupConfiguration adds a controller that reconciles Configurations.
-func SetupConfiguration(mgr ctrl.Manager, o controller.Options) error {
-	name := "packages/" + strings.ToLower(v1.ConfigurationGroupKind)
-	np := func() v1.Package { return &v1.Configuration{} }
-	nr := func() v1.PackageRevision { return &v1.ConfigurationRevision{} }
-	nrl := func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} }
+// WithPullSecretIndex specifies where the Reconciler should record the pull
+// secrets each package uses, so that packages can be requeued when one of
+// their pull secrets changes.
+func WithPullSecretIndex(i *PullSecretIndex) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.pullSecrets = i
+	}
+}
 
-	clientset, err := kubernetes.NewForConfig(mgr.GetConfig())
-	if err != nil {
-		return errors.Wrap(err, "failed to initialize clientset")
+// WithAliasResolver specifies how the Reconciler should resolve package
+// sources that refer to a PackageSourceAlias.
+func WithAliasResolver(a AliasResolver) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.aliases = a
 	}
-	fetcher, err := xpkg.NewK8sFetcher(clientset, append(o.FetcherOptions, xpkg.WithNamespace(o.Namespace), xpkg.WithServiceAccount(o.ServiceAccount))...)
-	if err != nil {
-		return errors.Wrap(err, "cannot build fetcher")
+}
+
+// WithDependencyGCCoordination specifies that the Reconciler should report
+// when it's garbage collecting a package's revisions, and defer activating a
+// package's current revision while any of its dependencies are garbage
+// collecting theirs, to avoid racing with their cleanup. The supplied lister
+// finds the dependencies that are garbage collecting.
+func WithDependencyGCCoordination(l GarbageCollectingDependencyLister) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.gcDependencies = l
 	}
+}
 
-	log := o.Logger.WithValues("controller", name)
-	r := NewReconciler(mgr,
-		WithNewPackageFn(np),
-		WithNewPackageRevisionFn(nr),
-		WithNewPackageRevisionListFn(nrl),
-		WithRevisioner(NewPackageRevisioner(fetcher, WithDefaultRegistry(o.DefaultRegistry))),
-		WithConfigStore(xpkg.NewImageConfigStore(mgr.GetClient(), o.Namespace)),
-		WithLogger(log),
-		WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
-	)
+// WithDependencyLister specifies how the Reconciler should list the resolved
+// dependencies of a package's current revision.
+func WithDependencyLister(l DependencyLister) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.dependencies = l
+	}
+}
 
-	return ctrl.NewControllerManagedBy(mgr).
-		Named(name).
-		For(&v1.Configuration{}).
-		Owns(&v1.ConfigurationRevision{}).
-		Watches(&v1beta1.ImageConfig{}, enqueueConfigurationsForImageConfig(mgr.GetClient(), log)).
-		WithOptions(o.ForControllerRuntime()).
-		Complete(ratelimiter.NewReconciler(name, errors.WithSilentRequeueOnConflict(r), o.GlobalRateLimiter))
+// WithLockRecorder specifies how the Reconciler should record active package
+// revisions that skip dependency resolution in the Lock. The revision
+// reconciler records revisions that resolve their dependencies.
+func WithLockRecorder(l LockRecorder) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.lock = l
+	}
 }
 
-// SetupFunction adds a controller that reconciles Functions.
-func SetupFunction(mgr ctrl.Manager, o controller.Options) error {
-	name := "packages/" + strings.ToLower(v1.FunctionGroupKind)
-	np := func() v1.Package { return &v1.Function{} }
-	nr := func() v1.PackageRevision { return &v1.FunctionRevision{} }
-	nrl := func() v1.PackageRevisionList { return &v1.FunctionRevisionList{} }
+// WithRevisionLabelMigration specifies a label key that package revisions
+// previously used to identify their parent package. The Reconciler relabels
+// each package's revisions that carry the supplied key, and are owned by the
+// package, to use the current key. It does so once per package.
+func WithRevisionLabelMigration(legacyKey string) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.legacyRevisionLabel = legacyKey
+	}
+}
 
-	cs, err := kubernetes.NewForConfig(mgr.GetConfig())
-	if err != nil {
-		return errors.Wrap(err, errCreateK8sClient)
+// WithResolvedDigestRevisionAnnotations specifies that the Reconciler should
+// annotate each package's active revision with the digest of the image it
+// resolved to, as well as the package.
+func WithResolvedDigestRevisionAnnotations() ReconcilerOption {
+	return func(r *Reconciler) {
+		r.annotateDigest = true
 	}
-	f, err := xpkg.NewK8sFetcher(cs, append(o.FetcherOptions, xpkg.WithNamespace(o.Namespace), xpkg.WithServiceAccount(o.ServiceAccount))...)
-	if err != nil {
-		return errors.Wrap(err, errBuildFetcher)
+}
+
+// WithLastActionAnnotations specifies that the Reconciler should annotate each
+// package with the last action it took on the package's revisions.
+func WithLastActionAnnotations() ReconcilerOption {
+	return func(r *Reconciler) {
+		r.annotateLastAction = true
 	}
+}
 
-	log := o.Logger.WithValues("controller", name)
-	opts := []ReconcilerOption{
-		WithNewPackageFn(np),
-		WithNewPackageRevisionFn(nr),
-		WithNewPackageRevisionListFn(nrl),
-		WithRevisioner(NewPackageRevisioner(f, WithDefaultRegistry(o.DefaultRegistry))),
-		WithConfigStore(xpkg.NewImageConfigStore(mgr.GetClient(), o.Namespace)),
-		WithLogger(log),
-		WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
+// WithCreationReasonAnnotations specifies that the Reconciler should annotate
+// each package revision it creates with why it created the revision.
+func WithCreationReasonAnnotations() ReconcilerOption {
+	return func(r *Reconciler) {
+		r.annotateCreationReason = true
 	}
+}
 
-	return ctrl.NewControllerManagedBy(mgr).
-		Named(name).
-		For(&v1.Function{}).
-		Owns(&v1.FunctionRevision{}).
-		Watches(&v1beta1.ImageConfig{}, enqueueFunctionsForImageConfig(mgr.GetClient(), log)).
-		WithOptions(o.ForControllerRuntime()).
-		Complete(ratelimiter.NewReconciler(name, errors.WithSilentRequeueOnConflict(NewReconciler(mgr, opts...)), o.GlobalRateLimiter))
+// WithRevisionSpecReconciliation specifies that the Reconciler should
+// reconcile the spec of a package's existing revision, like its pull secrets
+// and runtime config, toward the package's. By default it reuses an existing
+// revision's spec as-is.
+func WithRevisionSpecReconciliation() ReconcilerOption {
+	return func(r *Reconciler) {
+		r.reconcileRevisionSpec = true
+	}
 }
 
-// NewReconciler creates a new package reconciler.
-func NewReconciler(mgr ctrl.Manager, opts ...ReconcilerOption) *Reconciler {
-	r := &Reconciler{
-		client: resource.ClientApplicator{
-			Client:     mgr.GetClient(),
-			Applicator: resource.NewAPIPatchingApplicator(mgr.GetClient()),
-		},
-		pkg:        NewNopRevisioner(),
-		log:        logging.NewNopLogger(),
-		record:     event.NewNopRecorder(),
-		conditions: conditions.ObservedGenerationPropagationManager{},
+// WithFinalizer specifies how the Reconciler should finalize packages whose
+// revisions must be orphaned when they're deleted.
+func WithFinalizer(f resource.Finalizer) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.finalizer = f
 	}
+}
 
-	for _, f := range opts {
-		f(r)
+// WithFinalizerName specifies the name of the finalizer the Reconciler adds
+// to packages whose revisions must be orphaned when they're deleted. Use a
+// distinct name when several package managers reconcile the same packages.
+// Changing the name strands the old finalizer on existing packages.
+func WithFinalizerName(name string) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.finalizerName = name
 	}
+}
 
-	return r
+// WithOwnershipDriftPolicy specifies how the Reconciler should handle package
+// revisions that are labelled as belonging to a package, but aren't controlled
+// by it.
+func WithOwnershipDriftPolicy(p OwnershipDriftPolicy) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.ownershipDriftPolicy = p
+	}
 }
 
-// Reconcile package.
-func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) { //nolint:gocognit // Reconcilers are complex. Be wary of adding more.
-	log := r.log.WithValues("request", req)
-	log.Debug("Reconciling")
+// WithUnknownHealthGCPolicy specifies whether the Reconciler should garbage
+// collect package revisions whose health is unknown.
+func WithUnknownHealthGCPolicy(p UnknownHealthGCPolicy) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.unknownHealthGCPolicy = p
+	}
+}
 
-	ctx, cancel := context.WithTimeout(ctx, reconcileTimeout)
-	defer cancel()
+// WithDefaultActivationPolicy specifies the revision activation policy the
+// Reconciler uses for packages that don't specify one. The Reconciler notes
+// the policy that took effect in the package's Installed condition.
+func WithDefaultActivationPolicy(ap v1.RevisionActivationPolicy) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.defaultActivationPolicy = ap
+	}
+}
 
-	p := r.newPackage()
-	if err := r.client.Get(ctx, req.NamespacedName, p); err != nil {
-		// There's no need to requeue if we no longer exist. Otherwise
-		// we'll be requeued implicitly because we return an error.
-		log.Debug(errGetPackage, "error", err)
-		return reconcile.Result{}, errors.Wrap(resource.IgnoreNotFound(err), errGetPackage)
+// WithSlowReconcileThreshold specifies the fraction of the reconcile deadline
+// after which the Reconciler considers a reconcile slow. The Reconciler emits a
+// warning event when a reconcile is slow, naming the phase that dominated it.
+// Zero disables slow reconcile warnings.
+func WithSlowReconcileThreshold(fraction float64) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.slowReconcileThreshold = fraction
 	}
-	status := r.conditions.For(p)
+}
 
-	// Check the pause annotation and return if it has the value "true"
-	// after logging, publishing an event and updating the SYNC status condition
-	if meta.IsPaused(p) {
-		r.record.Event(p, event.Normal(reasonPaused, reconcilePausedMsg))
-		status.MarkConditions(xpv1.ReconcilePaused().WithMessage(reconcilePausedMsg))
-		// If the pause annotation is removed, we will have a chance to reconcile again and resume
-		// and if status update fails, we will reconcile again to retry to update the status
-		return reconcile.Result{}, errors.Wrap(r.client.Status().Update(ctx, p), errUpdateStatus)
+// WithDefaultRevisionHistoryLimit specifies the revision history limit the
+// Reconciler uses for packages that don't specify one.
+func WithDefaultRevisionHistoryLimit(n int64) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.defaultRevisionHistoryLimit = &n
 	}
-	if c := p.GetCondition(xpv1.ReconcilePaused().Type); c.Reason == xpv1.ReconcilePaused().Reason {
-		p.CleanConditions()
-		// Persist the removal of conditions and return. We'll be requeued
-		// with the updated status and resume reconciliation.
-		return reconcile.Result{}, errors.Wrap(r.client.Status().Update(ctx, p), errUpdateStatus)
+}
+
+// WithGCDeferredUntilHealthy specifies that the Reconciler should only garbage
+// collect a package's revisions once its current revision is healthy. This
+// ensures there's always a healthy revision to fall back to while a new
+// revision rolls out.
+func WithGCDeferredUntilHealthy() ReconcilerOption {
+	return func(r *Reconciler) {
+		r.deferGC = true
 	}
+}
 
-	// Get existing package revisions.
-	prs := r.newPackageRevisionList()
-	if err := r.client.List(ctx, prs, client.MatchingLabels(map[string]string{v1.LabelParentPackage: p.GetName()})); resource.IgnoreNotFound(err) != nil {
-		err = errors.Wrap(err, errListRevisions)
-		r.record.Event(p, event.Warning(reasonList, err))
-		return reconcile.Result{}, err
+// WithGCOrder specifies whether the Reconciler should garbage collect a
+// package's old revisions before or after it activates its current revision.
+// Revisions are never garbage collected before the current revision is
+// healthy if garbage collection is also deferred until healthy, regardless of
+// the order.
+func WithGCOrder(o GCOrder) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.gcOrder = o
 	}
+}
 
-	// Rewrite the image path if necessary. We need to do this before looking
-	// for pull secrets, since the rewritten path may use different secrets than
-	// the original.
-	imagePath := p.GetSource()
-	rewriteConfigName, newPath, err := r.config.RewritePath(ctx, imagePath)
-	if err != nil {
-		err = errors.Wrap(err, errRewriteImage)
-		p.SetConditions(v1.Unpacking().WithMessage(err.Error()))
-		_ = r.client.Status().Update(ctx, p)
+// WithGarbageCollectionModeConditions specifies that the Reconciler should
+// report how each package's revisions are garbage collected - using a default
+// revision history limit, using the limit the package specifies, or not at
+// all - using the package's RevisionGarbageCollectionMode condition.
+func WithGarbageCollectionModeConditions() ReconcilerOption {
+	return func(r *Reconciler) {
+		r.reportGCMode = true
+	}
+}
 
-		r.record.Event(p, event.Warning(reasonImageConfig, err))
+// WithRevisionCreationCount specifies that the Reconciler should count the
+// revisions it creates for each package using the package's
+// status.totalRevisionsCreated. The count only ever increases, so it helps to
+// spot packages that churn through revisions.
+func WithRevisionCreationCount() ReconcilerOption {
+	return func(r *Reconciler) {
+		r.countCreated = true
+	}
+}
 
-		return reconcile.Result{}, err
+// WithProviderFamilyHealth specifies that the Reconciler should only report a
+// package whose current revision belongs to a provider family healthy once the
+// active revisions of every other package in the family are healthy too.
+func WithProviderFamilyHealth() ReconcilerOption {
+	return func(r *Reconciler) {
+		r.familyHealth = true
 	}
-	if newPath != "" {
-		imagePath = newPath
-		p.SetAppliedImageConfigRefs(v1.ImageConfigRef{
-			Name:   rewriteConfigName,
-			Reason: v1.ImageConfigReasonRewrite,
-		})
-	} else {
-		p.ClearAppliedImageConfigRef(v1.ImageConfigReasonRewrite)
+}
+
+// WithStuckUnpackingThreshold specifies how long a package may be unpacking
+// before the Reconciler marks it Stuck. Zero disables the Stuck condition.
+func WithStuckUnpackingThreshold(d time.Duration) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.stuckThreshold = d
 	}
-	p.SetResolvedSource(imagePath)
+}
 
-	pullSecretConfig, pullSecretFromConfig, err := r.config.PullSecretFor(ctx, p.GetResolvedSource())
-	if err != nil {
-		err = errors.Wrap(err, errGetPullConfig)
-		status.MarkConditions(v1.Unpacking().WithMessage(err.Error()))
-		_ = r.client.Status().Update(ctx, p)
+// WithMinHealthyDuration specifies how long a package's revision must be
+// continuously healthy before the Reconciler reports the package healthy.
+func WithMinHealthyDuration(d time.Duration) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.minHealthyDuration = d
+	}
+}
 
-		r.record.Event(p, event.Warning(reasonImageConfig, err))
+// WithWarmUp specifies how long after it starts the Reconciler skips
+// resolving the revisions of healthy packages that aren't due to be polled,
+// the first time it reconciles them. This spreads the load on registries when
+// a new leader takes over and reconciles every package.
+func WithWarmUp(d time.Duration) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.warmUp = d
+	}
+}
 
-		return reconcile.Result{}, err
+// WithMaxConditionMessageLength specifies the maximum length in bytes of the
+// messages of the conditions the Reconciler sets on packages. Longer messages
+// are truncated with an ellipsis. Registries can return enormous errors, which
+// bloat etcd when written to conditions.
+func WithMaxConditionMessageLength(n int) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.maxConditionMessageLength = n
 	}
+}
 
-	var secrets []string
-	if pullSecretFromConfig != "" {
-		secrets = append(secrets, pullSecretFromConfig)
-		p.SetAppliedImageConfigRefs(v1.ImageConfigRef{
-			Name:   pullSecretConfig,
-			Reason: v1.ImageConfigReasonSetPullSecret,
-		})
-	} else {
-		p.ClearAppliedImageConfigRef(v1.ImageConfigReasonSetPullSecret)
+// WithMaxInformationalConditions specifies the maximum number of informational
+// conditions, like UpgradeAvailable or DeprecatedAPIs, the Reconciler keeps on
+// each package. When a package has more, the Reconciler drops those that
+// transitioned least recently. It never drops the conditions that report
+// whether a package is installed and healthy. This keeps package status small
+// as the number of condition types grows.
+func WithMaxInformationalConditions(n int) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.maxInformationalConditions = n
 	}
+}
 
-	revisionName, err := r.pkg.Revision(ctx, p, secrets...)
-	if err != nil {
-		err = errors.Wrap(err, errUnpack)
-		status.MarkConditions(v1.Unpacking().WithMessage(err.Error()))
-		r.record.Event(p, event.Warning(reasonUnpack, err))
+// WithStrictOffline specifies that the Reconciler never fetches a package
+// with pull policy IfNotPresent from its registry to create its first
+// revision. Such packages are marked NoCachedRevision instead.
+func WithStrictOffline() ReconcilerOption {
+	return func(r *Reconciler) {
+		r.strictOffline = true
+	}
+}
+
+// WithUpgradeCheckInterval specifies how often the Reconciler checks whether
+// the source of a package with pull policy IfNotPresent resolves to newer
+// content than its current revision. It reports what it finds using the
+// package's UpgradeAvailable condition, but never changes the package's
+// current revision. Zero disables checking.
+func WithUpgradeCheckInterval(d time.Duration) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.upgradeCheckInterval = d
+	}
+}
+
+// WithResolutionLimiter specifies a semaphore that limits how many packages
+// may be resolved concurrently. Each resolution acquires a weight of one. The
+// semaphore may be shared by several Reconcilers, to limit resolutions across
+// all kinds of package. The Reconciler requeues a package rather than waiting
+// for the semaphore when it's exhausted.
+func WithResolutionLimiter(s *semaphore.Weighted) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.resolutions = s
+	}
+}
+
+// WithInstanceID specifies the id of the package manager instance running the
+// Reconciler. When set, the Reconciler annotates each package it successfully
+// reconciles with this id.
+func WithInstanceID(id string) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.instanceID = id
+	}
+}
+
+// WithClock specifies the clock the Reconciler uses to measure how long
+// reconciles take.
+func WithClock(c clock.PassiveClock) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.clock = c
+	}
+}
+
+// WithMaxConcurrentRevisionDeletes specifies the maximum number of package
+// revisions the Reconciler will garbage collect concurrently.
+func WithMaxConcurrentRevisionDeletes(n int) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.maxConcurrentDeletes = n
+	}
+}
+
+// WithMaxRevisions specifies the maximum number of revisions the Reconciler
+// will create for each package, regardless of the package's revision history
+// limit. Zero means there is no maximum.
+func WithMaxRevisions(n int) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.maxRevisions = n
+	}
+}
+
+// WithHealthProbeInterval specifies how often the Reconciler should check the
+// health of a package whose current revision's health is unknown or recently
+// changed. Zero disables probing.
+func WithHealthProbeInterval(d time.Duration) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.healthProbeInterval = d
+	}
+}
+
+// WithPropagatedRevisionConditions specifies additional conditions the
+// Reconciler should mirror from a package's current revision to the package.
+// Each condition's type is prefixed with the supplied prefix when mirrored. A
+// package's Healthy condition is always derived from its current revision.
+func WithPropagatedRevisionConditions(prefix string, types ...xpv1.ConditionType) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.propagatePrefix = prefix
+		r.propagateConditions = types
+	}
+}
+
+// WithPropagatedMetadata specifies labels and annotations the Reconciler should
+// propagate from a package to the revisions it controls. When only these labels
+// or annotations change, the Reconciler propagates them without fully
+// reconciling the package.
+func WithPropagatedMetadata(labels, annotations []string) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.propagateLabels = labels
+		r.propagateAnnotations = annotations
+	}
+}
+
+// WithAuditSink specifies where the Reconciler should record the actions it
+// takes on package revisions for auditing purposes.
+func WithAuditSink(s AuditSink) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.audit = s
+	}
+}
+
+// WithActivationNotifier specifies how the Reconciler should notify external
+// systems when a package activates a revision. Notification is best effort;
+// the Reconciler logs but otherwise ignores failures to notify.
+func WithActivationNotifier(n ActivationNotifier) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.notifier = n
+	}
+}
+
+// WithActor specifies the name the Reconciler should use to identify itself
+// in audit events.
+func WithActor(name string) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.actor = name
+	}
+}
+
+// WithReconcileObserver specifies an observer the Reconciler should call at
+// the end of each reconcile with a report describing it.
+func WithReconcileObserver(o controller.ReconcileObserver) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.observer = o
+	}
+}
+
+// WithSBOMLister specifies how the Reconciler should discover the software
+// bill of materials (SBOM) artifacts that refer to a package image. The
+// Reconciler records them in the status of each new revision, and annotates
+// the package with those of its current revision.
+func WithSBOMLister(l SBOMLister) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.sboms = l
+	}
+}
+
+// Reconciler reconciles packages.
+type Reconciler struct {
+	client     resource.ClientApplicator
+	pkg        Revisioner
+	config     xpkg.ConfigStore
+	log        logging.Logger
+	record     event.Recorder
+	conditions conditions.Manager
+	finalizer  resource.Finalizer
+
+	// finalizerName is the name of the finalizer added by finalizer.
+	finalizerName string
+	metrics       controller.Metrics
+	features      *feature.Flags
+	audit         AuditSink
+	sboms         SBOMLister
+	notifier      ActivationNotifier
+	observer      controller.ReconcileObserver
+	quota         QuotaSource
+	freeze        FreezeSource
+	actor         string
+	namespace     string
+
+	revisionTemplate     v1.PackageRevisionSpec
+	immutableFieldPolicy ImmutableFieldPolicy
+	ownershipDriftPolicy OwnershipDriftPolicy
+	maxConcurrentDeletes int
+	maxRevisions         int
+	errorConditions      ErrorConditionSource
+	dependencies         DependencyLister
+	gcDependencies       GarbageCollectingDependencyLister
+	lock                 LockRecorder
+	aliases              AliasResolver
+	validator            ContentValidator
+	contents             RevisionContentsFetcher
+	provenance           ProvenanceVerifier
+	pullSecrets          *PullSecretIndex
+	healthProbeInterval  time.Duration
+	tenantLabel          string
+	legacyRevisionLabel  string
+	annotateDigest       bool
+	annotateLastAction   bool
+
+	annotateCreationReason bool
+	reconcileRevisionSpec  bool
+
+	propagatePrefix     string
+	propagateConditions []xpv1.ConditionType
+
+	propagateLabels      []string
+	propagateAnnotations []string
+
+	unknownHealthGCPolicy   UnknownHealthGCPolicy
+	defaultActivationPolicy v1.RevisionActivationPolicy
+	deferGC                 bool
+	gcOrder                 GCOrder
+	reportGCMode            bool
+	countCreated            bool
+	familyHealth            bool
+	strictOffline           bool
+	minHealthyDuration      time.Duration
+
+	maxConditionMessageLength  int
+	maxInformationalConditions int
+
+	defaultRevisionHistoryLimit *int64
+
+	clock                  clock.PassiveClock
+	slowReconcileThreshold float64
+
+	instanceID string
+
+	// started is when the Reconciler started. We skip resolving some
+	// packages' revisions for the warmUp duration after it.
+	started time.Time
+	warmUp  time.Duration
+
+	// warmed tracks the UIDs of packages we've reconciled since we started.
+	warmed sync.Map
+
+	// upgradeChecked tracks when we last checked whether a package has an
+	// upgrade available, keyed by package UID.
+	upgradeChecked       sync.Map
+	upgradeCheckInterval time.Duration
+
+	// unpackingSince tracks when we started failing to unpack a package,
+	// keyed by package UID. We use it to mark packages Stuck.
+	unpackingSince sync.Map
+	stuckThreshold time.Duration
+
+	// unpersisted tracks revisions we resolved for a package but failed to
+	// persist to its status, keyed by package UID.
+	unpersisted sync.Map
+
+	// warnedPullAlways tracks the UIDs of packages we've already warned
+	// about using pull policy Always with a digest.
+	warnedPullAlways sync.Map
+
+	// synced tracks the state of packages and their revisions the last time
+	// we fully reconciled them, keyed by package name. Keying by name lets
+	// the event handlers that enqueue packages forget their state.
+	synced sync.Map
+
+	// relabeled tracks the UIDs of packages whose revisions we've migrated
+	// from the legacy revision label key.
+	relabeled sync.Map
+
+	// uids tracks the UID of each package we've reconciled, keyed by package
+	// name, so that we can forget a package's UID keyed state once it's
+	// gone.
+	uids sync.Map
+
+	// resolutions limits how many packages may be resolved concurrently,
+	// potentially across several Reconcilers.
+	resolutions *semaphore.Weighted
+
+	newPackage             func() v1.Package
+	newPackageRevision     func() v1.PackageRevision
+	newPackageRevisionList func() v1.PackageRevisionList
+}
+
+// SetupProvider adds a controller that reconciles Providers.
+func SetupProvider(mgr ctrl.Manager, o controller.Options) error {
+	return Setup(mgr, o, ProviderPackageKind)
+}
+
+// SetupConfiguration adds a controller that reconciles Configurations.
+func SetupConfiguration(mgr ctrl.Manager, o controller.Options) error {
+	return Setup(mgr, o, ConfigurationPackageKind)
+}
+
+// SetupFunction adds a controller that reconciles Functions.
+func SetupFunction(mgr ctrl.Manager, o controller.Options) error {
+	return Setup(mgr, o, FunctionPackageKind)
+}
+
+// Setup adds a controller that reconciles the supplied kind of package.
+func Setup(mgr ctrl.Manager, o controller.Options, k PackageKind) error {
+	if err := k.Validate(mgr.GetScheme()); err != nil {
+		return errors.Wrap(err, errInvalidPackageKind)
+	}
+	name := "packages/" + strings.ToLower(k.Package.GroupKind().String())
+
+	cs, err := kubernetes.NewForConfig(mgr.GetConfig())
+	if err != nil {
+		return errors.Wrap(err, errCreateK8sClient)
+	}
+	f, err := xpkg.NewK8sFetcher(cs, append(o.FetcherOptions, xpkg.WithNamespace(o.Namespace), xpkg.WithServiceAccount(o.ServiceAccount))...)
+	if err != nil {
+		return errors.Wrap(err, errBuildFetcher)
+	}
+
+	ropts := []PackageRevisionerOption{WithDefaultRegistry(o.DefaultRegistry), WithPlatform(o.Platform), WithLocalPackageDir(o.LocalPackageDir)}
+	if o.CheckDeprecatedAPIs || o.DiffRevisionContents {
+		pp, err := yaml.New()
+		if err != nil {
+			return errors.Wrap(err, errBuildContentParser)
+		}
+		ropts = append(ropts, WithContentParser(pp))
+	}
+	if o.RevisionNamer != nil {
+		ropts = append(ropts, WithRevisionNamer(o.RevisionNamer))
+	}
+
+	rv := NewPackageRevisioner(f, ropts...)
+
+	log := o.Logger.WithValues("controller", name)
+	secrets := NewPullSecretIndex()
+	opts := []ReconcilerOption{
+		WithPackageKind(k),
+		WithRevisioner(rv),
+		WithConfigStore(xpkg.NewImageConfigStore(mgr.GetClient(), o.Namespace)),
+		WithNamespace(o.Namespace),
+		WithLogger(log),
+		WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
+		WithActor(name),
+		WithMaxConcurrentRevisionDeletes(o.MaxConcurrentRevisionDeletes),
+		WithMaxRevisions(o.MaxRevisionsPerPackage),
+		WithHealthProbeInterval(o.HealthProbeInterval),
+		WithMetrics(o.Metrics),
+		WithRevisionSpecTemplate(o.RevisionSpecTemplate),
+		WithFeatureFlags(o.Features),
+		WithPullSecretIndex(secrets),
+		WithLockRecorder(NewAPILockRecorder(mgr.GetClient(), k.Package)),
+	}
+	if len(o.SBOMArtifactTypes) > 0 {
+		opts = append(opts, WithSBOMLister(NewReferrersSBOMLister(f, o.DefaultRegistry, o.SBOMArtifactTypes...)))
+	}
+	if len(o.PropagatedRevisionConditions) > 0 {
+		opts = append(opts, WithPropagatedRevisionConditions(o.PropagatedConditionPrefix, o.PropagatedRevisionConditions...))
+	}
+	if o.TenantLabel != "" && o.ActiveRevisionQuotaConfigMap != "" {
+		opts = append(opts, WithActiveRevisionQuota(o.TenantLabel, NewConfigMapQuotaSource(mgr.GetAPIReader(), o.Namespace, o.ActiveRevisionQuotaConfigMap)))
+	}
+
+	if o.CollectUnknownHealthRevisions {
+		opts = append(opts, WithUnknownHealthGCPolicy(UnknownHealthGCPolicyCollect))
+	}
+	if o.AnnotateRevisionDigest {
+		opts = append(opts, WithResolvedDigestRevisionAnnotations())
+	}
+	if o.AnnotateLastAction {
+		opts = append(opts, WithLastActionAnnotations())
+	}
+	if o.AnnotateCreationReason {
+		opts = append(opts, WithCreationReasonAnnotations())
+	}
+	if o.ReconcileRevisionSpec {
+		opts = append(opts, WithRevisionSpecReconciliation())
+	}
+	if o.LegacyRevisionLabel != "" {
+		opts = append(opts, WithRevisionLabelMigration(o.LegacyRevisionLabel))
+	}
+	if o.SlowReconcileThreshold > 0 {
+		opts = append(opts, WithSlowReconcileThreshold(o.SlowReconcileThreshold))
+	}
+	if o.DefaultActivationPolicy != "" {
+		opts = append(opts, WithDefaultActivationPolicy(o.DefaultActivationPolicy))
+	}
+	if o.OwnershipDriftPolicy != "" {
+		opts = append(opts, WithOwnershipDriftPolicy(OwnershipDriftPolicy(o.OwnershipDriftPolicy)))
+	}
+	if o.InstanceID != "" {
+		opts = append(opts, WithInstanceID(o.InstanceID))
+	}
+	if n, ok := o.DefaultRevisionHistoryLimits[k.Package.Kind]; ok {
+		opts = append(opts, WithDefaultRevisionHistoryLimit(n))
+	}
+	if len(o.PropagatedLabels) > 0 || len(o.PropagatedAnnotations) > 0 {
+		opts = append(opts, WithPropagatedMetadata(o.PropagatedLabels, o.PropagatedAnnotations))
+	}
+	if o.RevisionFieldManager != "" {
+		opts = append(opts, WithApplicator(NewServerSideApplicator(mgr.GetClient(), o.RevisionFieldManager)))
+	}
+	if len(o.ProvenanceArtifactTypes) > 0 {
+		opts = append(opts, WithProvenanceVerifier(NewReferrersProvenanceVerifier(f, o.DefaultRegistry, o.ProvenanceArtifactTypes...)))
+	}
+	if o.DiffRevisionContents {
+		opts = append(opts, WithRevisionContentsFetcher(rv))
+	}
+	if o.DeferGCUntilHealthy {
+		opts = append(opts, WithGCDeferredUntilHealthy())
+	}
+	if o.AwaitDependencyGC {
+		opts = append(opts, WithDependencyGCCoordination(NewLockDependencyLister(mgr.GetClient())))
+	}
+	if o.GCBeforeActivation {
+		opts = append(opts, WithGCOrder(GCOrderCollectFirst))
+	}
+	if o.ReportGCMode {
+		opts = append(opts, WithGarbageCollectionModeConditions())
+	}
+	if o.CountRevisionsCreated {
+		opts = append(opts, WithRevisionCreationCount())
+	}
+	if o.ProviderFamilyHealth {
+		opts = append(opts, WithProviderFamilyHealth())
+	}
+	if o.FinalizerName != "" {
+		opts = append(opts, WithFinalizerName(o.FinalizerName))
+	}
+	if o.MaxConditionMessageLength > 0 {
+		opts = append(opts, WithMaxConditionMessageLength(o.MaxConditionMessageLength))
+	}
+	if o.MaxInformationalConditions > 0 {
+		opts = append(opts, WithMaxInformationalConditions(o.MaxInformationalConditions))
+	}
+	if o.ActivationWebhookURL != "" {
+		// Send notifications off the reconcile path, so that a slow webhook
+		// doesn't stall reconciles.
+		n := NewAsyncActivationNotifier(NewWebhookActivationNotifier(&http.Client{Timeout: activationWebhookTimeout}, o.ActivationWebhookURL), activationNotificationQueueSize, log)
+		if err := mgr.Add(n); err != nil {
+			return errors.Wrap(err, errAddActivationNotifier)
+		}
+		opts = append(opts, WithActivationNotifier(n))
+	}
+	if o.StrictOffline {
+		opts = append(opts, WithStrictOffline())
+	}
+	if o.WarmUp > 0 {
+		opts = append(opts, WithWarmUp(o.WarmUp))
+	}
+	if o.MinHealthyDuration > 0 {
+		opts = append(opts, WithMinHealthyDuration(o.MinHealthyDuration))
+	}
+	if o.UpgradeCheckInterval > 0 {
+		opts = append(opts, WithUpgradeCheckInterval(o.UpgradeCheckInterval))
+	}
+	if o.StuckUnpackingThreshold > 0 {
+		opts = append(opts, WithStuckUnpackingThreshold(o.StuckUnpackingThreshold))
+	}
+	if o.ReconcileObserver != nil {
+		opts = append(opts, WithReconcileObserver(o.ReconcileObserver))
+	}
+	if o.PackageResolutions != nil {
+		opts = append(opts, WithResolutionLimiter(o.PackageResolutions))
+	}
+	if o.ErrorConditionConfigMap != "" {
+		opts = append(opts, WithErrorConditionSource(NewConfigMapErrorConditionSource(mgr.GetAPIReader(), o.Namespace, o.ErrorConditionConfigMap)))
+	}
+	var fc cache.Cache
+	if o.FreezeConfigMap != "" {
+		c, err := newFreezeCache(mgr, o.Namespace, o.FreezeConfigMap)
+		if err != nil {
+			return errors.Wrap(err, errBuildFreezeCache)
+		}
+		if err := mgr.Add(c); err != nil {
+			return errors.Wrap(err, errAddFreezeCache)
+		}
+		fc = c
+		opts = append(opts, WithGlobalFreeze(NewConfigMapFreezeSource(fc, o.Namespace, o.FreezeConfigMap)))
+	}
+
+	r := NewReconciler(mgr, opts...)
+
+	// Packages enqueued because something other than the package or its
+	// revisions changed must be fully reconciled, so we wrap the handlers
+	// and sources that enqueue them. This includes packages enqueued when the
+	// cache is periodically resynced.
+	b := ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		Watches(k.NewPackage(), r.unsyncingResyncs(&handler.EnqueueRequestForObject{})).
+		Watches(k.NewPackageRevision(), r.unsyncingResyncs(handler.EnqueueRequestForOwner(mgr.GetScheme(), mgr.GetRESTMapper(), k.NewPackage(), handler.OnlyControllerOwner())))
+	if k.EnqueueForImageConfig != nil {
+		b = b.Watches(&v1beta1.ImageConfig{}, r.unsyncing(k.EnqueueForImageConfig(mgr.GetClient(), log)))
+	}
+	b = b.Watches(&v1beta1.PackageSourceAlias{}, r.unsyncing(enqueuePackagesForAlias(mgr.GetClient(), k, log)))
+	if _, ok := k.NewPackage().(v1.PackageWithRuntime); ok && o.Features.Enabled(features.EnableBetaDeploymentRuntimeConfigs) {
+		// Activate packages that were waiting for their runtime config as
+		// soon as it's created.
+		b = b.Watches(&v1beta1.DeploymentRuntimeConfig{}, r.unsyncing(enqueuePackagesForRuntimeConfig(mgr.GetClient(), k, log)))
+	}
+	// Requeue packages when their pull secrets change, e.g. because they
+	// were rotated. We only watch metadata to avoid caching every Secret.
+	b = b.WatchesMetadata(&corev1.Secret{}, r.unsyncing(EnqueuePackagesForPullSecret(secrets, o.Namespace, log)))
+	if o.FreezeConfigMap != "" {
+		// Reconcile packages as soon as a maintenance freeze is enabled or
+		// lifted.
+		b = b.WatchesRawSource(source.Kind(fc, client.Object(&corev1.ConfigMap{}), r.unsyncing(enqueuePackagesForFreeze(mgr.GetClient(), k, o.Namespace, o.FreezeConfigMap, log))))
+	}
+	if o.ResyncInterval > 0 {
+		// List packages using the API server rather than the cache, in
+		// case the cache is stale because watch events were lost.
+		b = b.WatchesRawSource(r.unsyncingSource(NewResyncSource(mgr.GetAPIReader(), k, o.ResyncInterval, log)))
+	}
+	return b.WithOptions(o.ForControllerRuntime()).
+		Complete(ratelimiter.NewReconciler(name, errors.WithSilentRequeueOnConflict(r), o.GlobalRateLimiter))
+}
+
+// NewReconciler creates a new package reconciler.
+func NewReconciler(mgr ctrl.Manager, opts ...ReconcilerOption) *Reconciler {
+	r := &Reconciler{
+		client: resource.ClientApplicator{
+			Client:     mgr.GetClient(),
+			Applicator: resource.NewAPIPatchingApplicator(mgr.GetClient()),
+		},
+		pkg:        NewNopRevisioner(),
+		log:        logging.NewNopLogger(),
+		record:     event.NewNopRecorder(),
+		conditions: conditions.ObservedGenerationPropagationManager{},
+		metrics:    &controller.NopMetrics{},
+		audit:      NewNopAuditSink(),
+		observer:   &controller.NopReconcileObserver{},
+		quota:      NewNopQuotaSource(),
+
+		immutableFieldPolicy: ImmutableFieldPolicySkip,
+		ownershipDriftPolicy: OwnershipDriftPolicyIgnore,
+		maxConcurrentDeletes: defaultMaxConcurrentRevisionDeletes,
+		errorConditions:      NewStaticErrorConditionSource(DefaultErrorConditionRules()),
+		dependencies:         NewLockDependencyLister(mgr.GetClient()),
+		aliases:              NewAPIAliasResolver(mgr.GetClient()),
+		validator:            NewNopContentValidator(),
+		provenance:           NewNopProvenanceVerifier(),
+
+		unknownHealthGCPolicy:       UnknownHealthGCPolicyRetain,
+		defaultActivationPolicy:     v1.AutomaticActivation,
+		defaultRevisionHistoryLimit: ptr.To[int64](defaultRevisionHistoryLimit),
+		gcOrder:                     GCOrderActivateFirst,
+
+		clock: clock.RealClock{},
+	}
+
+	for _, f := range opts {
+		f(r)
+	}
+	if r.finalizer == nil {
+		r.finalizer = resource.NewAPIFinalizer(mgr.GetClient(), cmp.Or(r.finalizerName, finalizer))
+	}
+	r.started = r.clock.Now()
+
+	return r
+}
+
+// Reconcile package.
+func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (result reconcile.Result, err error) { //nolint:gocognit // Reconcilers are complex. Be wary of adding more.
+	log := r.log.WithValues("request", req)
+	log.Debug("Reconciling")
+
+	ctx, cancel := context.WithTimeout(ctx, reconcileTimeout)
+	defer cancel()
+
+	// Report what we decided, and the result, once we're done.
+	report := controller.ReconcileReport{Request: req}
+	if r.observer != nil {
+		defer func() {
+			report.Result, report.Err = result, err
+			r.observer.ObserveReconcile(ctx, report)
+		}()
+	}
+
+	p := r.newPackage()
+	if err := r.client.Get(ctx, req.NamespacedName, p); err != nil {
+		// There's no need to requeue if we no longer exist. Otherwise
+		// we'll be requeued implicitly because we return an error.
+		log.Debug(errGetPackage, "error", err)
+		if kerrors.IsNotFound(err) {
+			r.forget(req.Name)
+		}
+		return reconcile.Result{}, errors.Wrap(resource.IgnoreNotFound(err), errGetPackage)
+	}
+	// Forget the state of any package that had the same name but was since
+	// deleted and recreated.
+	if uid, loaded := r.uids.Swap(p.GetName(), p.GetUID()); loaded && uid != p.GetUID() {
+		r.forgetUID(uid.(types.UID)) //nolint:forcetypeassert // We only store UIDs.
+	}
+	report.Source = p.GetSource()
+	var status conditions.ConditionSet = r.conditions.For(p)
+	if r.maxConditionMessageLength > 0 {
+		status = &truncatingConditionSet{ConditionSet: status, max: r.maxConditionMessageLength}
+	}
+	if r.maxInformationalConditions > 0 {
+		status = &cappingConditionSet{ConditionSet: status, pkg: p, max: r.maxInformationalConditions}
+	}
+
+	// Warn if this reconcile approaches its deadline, so that operators can
+	// tell what's slow.
+	var timer *phaseTimer
+	if r.slowReconcileThreshold > 0 {
+		timer = newPhaseTimer(r.clock)
+		defer r.warnIfSlow(p, log, timer)
+	}
+
+	// Trace how we reconcile packages annotated for debugging. Tracing logs
+	// at info level, so that it's visible without enabling debug logging for
+	// every package.
+	trace := logging.NewNopLogger()
+	if debugging(p) {
+		trace = log.WithValues("trace", true, "uid", p.GetUID(), "generation", p.GetGeneration())
+	}
+
+	// Check the pause annotation and return if it has the value "true"
+	// after logging, publishing an event and updating the SYNC status condition
+	if meta.IsPaused(p) {
+		r.record.Event(p, event.Normal(reasonPaused, reconcilePausedMsg))
+		status.MarkConditions(xpv1.ReconcilePaused().WithMessage(reconcilePausedMsg))
+		// If the pause annotation is removed, we will have a chance to reconcile again and resume
+		// and if status update fails, we will reconcile again to retry to update the status
+		return reconcile.Result{}, errors.Wrap(r.client.Status().Update(ctx, p), errUpdateStatus)
+	}
+
+	// Pause reconciliation until the time the paused-until annotation
+	// specifies. Once it passes, remove the annotation. Updating the package
+	// requeues it, and we resume reconciliation below.
+	if v, ok := p.GetAnnotations()[v1.AnnotationPausedUntil]; ok {
+		until, err := time.Parse(time.RFC3339, v)
+		now := r.clock.Now()
+		switch {
+		case err != nil:
+			r.record.Event(p, event.Warning(reasonPaused, errors.Errorf(errFmtInvalidPausedUntil, v1.AnnotationPausedUntil, v)))
+		case now.Before(until):
+			msg := fmt.Sprintf(reconcilePausedUntilFmtMsg, until.Format(time.RFC3339))
+			r.record.Event(p, event.Normal(reasonPaused, msg))
+			status.MarkConditions(xpv1.ReconcilePaused().WithMessage(msg))
+			if err := r.client.Status().Update(ctx, p); err != nil {
+				return reconcile.Result{}, errors.Wrap(err, errUpdateStatus)
+			}
+			// Wake up when the pause expires.
+			return reconcile.Result{RequeueAfter: until.Sub(now)}, nil
+		default:
+			meta.RemoveAnnotations(p, v1.AnnotationPausedUntil)
+			if err := r.client.Update(ctx, p); err != nil {
+				if kerrors.IsConflict(err) {
+					return reconcile.Result{Requeue: true}, nil
+				}
+				return reconcile.Result{}, errors.Wrap(err, errResumePackage)
+			}
+			r.record.Event(p, event.Normal(reasonPaused, "Resuming reconciliation because the paused-until time passed"))
+			return reconcile.Result{}, nil
+		}
+	}
+	if c := p.GetCondition(xpv1.ReconcilePaused().Type); c.Reason == xpv1.ReconcilePaused().Reason {
+		p.CleanConditions()
+		// Persist the removal of conditions and return. We'll be requeued
+		// with the updated status and resume reconciliation.
+		return reconcile.Result{}, errors.Wrap(r.client.Status().Update(ctx, p), errUpdateStatus)
+	}
+
+	if meta.WasDeleted(p) {
+		// Unless we added our finalizer to orphan them, the package's
+		// revisions are garbage collected along with it.
+		if !meta.FinalizerExists(p, cmp.Or(r.finalizerName, finalizer)) {
+			return reconcile.Result{}, nil
+		}
+		if p.GetDeletionPolicy() == xpv1.DeletionOrphan {
+			if err := r.orphan(ctx, p); err != nil {
+				if kerrors.IsConflict(errors.Cause(err)) {
+					return reconcile.Result{Requeue: true}, nil
+				}
+				r.record.Event(p, event.Warning(reasonDelete, err))
+				return reconcile.Result{}, err
+			}
+		}
+		if err := r.finalizer.RemoveFinalizer(ctx, p); err != nil {
+			if kerrors.IsConflict(err) {
+				return reconcile.Result{Requeue: true}, nil
+			}
+			err = errors.Wrap(err, errRemoveFinalizer)
+			r.record.Event(p, event.Warning(reasonDelete, err))
+			return reconcile.Result{}, err
+		}
+		return reconcile.Result{}, nil
+	}
+
+	// We only need a finalizer if we must orphan the package's revisions
+	// when it's deleted.
+	switch {
+	case p.GetDeletionPolicy() == xpv1.DeletionOrphan:
+		if err := r.finalizer.AddFinalizer(ctx, p); err != nil {
+			if kerrors.IsConflict(err) {
+				return reconcile.Result{Requeue: true}, nil
+			}
+			err = errors.Wrap(err, errAddFinalizer)
+			r.record.Event(p, event.Warning(reasonDelete, err))
+			return reconcile.Result{}, err
+		}
+	case meta.FinalizerExists(p, cmp.Or(r.finalizerName, finalizer)):
+		if err := r.finalizer.RemoveFinalizer(ctx, p); err != nil {
+			if kerrors.IsConflict(err) {
+				return reconcile.Result{Requeue: true}, nil
+			}
+			err = errors.Wrap(err, errRemoveFinalizer)
+			r.record.Event(p, event.Warning(reasonDelete, err))
+			return reconcile.Result{}, err
+		}
+	}
+
+	// Polling a package that's pinned to a digest is pointless. Let the user
+	// know, once, that we won't poll it.
+	if pinnedPullAlways(p) {
+		if _, warned := r.warnedPullAlways.LoadOrStore(p.GetUID(), true); !warned {
+			r.record.Event(p, event.Warning(reasonPullPolicy, errors.New(errPullAlwaysDigest)))
+		}
+	}
+
+	// Listing revisions is expensive for large numbers of packages. Skip it,
+	// and the rest of the reconcile, if nothing changed since we last fully
+	// reconciled this package and we don't need to poll for new content.
+	last, synced := r.lastSynced(ctx, p)
+	if synced && last.resourceVersion == p.GetResourceVersion() {
+		log.Debug("Package and its revisions are unchanged, skipping reconcile")
+		trace.Info("Package and its revisions are unchanged, skipping reconcile")
+		return reconcile.Result{}, nil
+	}
+
+	// Check for a cluster-wide maintenance freeze before we touch any of the
+	// package's revisions, including to propagate metadata to them or to
+	// migrate their labels.
+	var frozen bool
+	var freezeReason string
+	if r.freeze != nil {
+		frozen, freezeReason, err = r.freeze.Frozen(ctx)
+		if err != nil {
+			err = errors.Wrap(err, errCheckGlobalFreeze)
+			r.record.Event(p, event.Warning(reasonInstall, err))
+			return reconcile.Result{}, err
+		}
+	}
+
+	// If only labels or annotations we propagate to revisions changed, we
+	// just need to propagate them. There's no need to resolve the package.
+	if synced && !frozen && r.onlyPropagatedMetadataChanged(p, last) {
+		log.Debug("Only propagated package metadata changed, propagating it to revisions")
+		trace.Info("Only propagated package metadata changed, propagating it to revisions")
+		rvs, err := r.propagateMetadataToRevisions(ctx, p, last.revisions)
+		if err != nil {
+			if kerrors.IsConflict(errors.Cause(err)) {
+				return reconcile.Result{Requeue: true}, nil
+			}
+			r.synced.Delete(p.GetName())
+			r.record.Event(p, event.Warning(reasonTransitionRevision, err))
+			return reconcile.Result{}, err
+		}
+		r.synced.Store(p.GetName(), newSyncedPackage(p, rvs))
+		return reconcile.Result{}, nil
+	}
+	r.synced.Delete(p.GetName())
+
+	// Relabel revisions that still use the legacy parent package label key,
+	// so that we find them when we list the package's revisions below.
+	if _, migrated := r.relabeled.Load(p.GetUID()); r.legacyRevisionLabel != "" && !migrated && !frozen {
+		if err := r.migrateRevisionLabels(ctx, p); err != nil {
+			if kerrors.IsConflict(errors.Cause(err)) {
+				return reconcile.Result{Requeue: true}, nil
+			}
+			r.record.Event(p, event.Warning(reasonList, err))
+			return reconcile.Result{}, err
+		}
+		r.relabeled.Store(p.GetUID(), true)
+	}
+
+	// Get existing package revisions.
+	prs := r.newPackageRevisionList()
+	listed := timer.Start(phaseList)
+	err = r.client.List(ctx, prs, client.MatchingLabels(map[string]string{v1.LabelParentPackage: p.GetName()}))
+	listed()
+	if resource.IgnoreNotFound(err) != nil {
+		err = errors.Wrap(err, errListRevisions)
+		r.record.Event(p, event.Warning(reasonList, err))
+		return reconcile.Result{}, err
+	}
+
+	// Don't change the package's revisions while a cluster-wide maintenance
+	// freeze is enabled - not even to roll back or hold one. We still report
+	// the health of the package's current revision, so its status stays
+	// fresh.
+	if frozen {
+		trace.Info("Package revisions are frozen", "reason", freezeReason)
+		return r.frozen(ctx, p, status, prs.GetRevisions(), freezeReason)
+	}
+	if r.freeze != nil && p.GetCondition(v1.TypeGlobalFreeze).Reason == v1.ReasonFrozen {
+		status.MarkConditions(v1.GlobalFreezeLifted())
+	}
+
+	// Roll back to the desired revision number, if any. We don't resolve,
+	// create, or garbage collect revisions while rolled back.
+	if n := p.GetDesiredRevisionNumber(); n != nil {
+		trace.Info("Rolling back to desired revision number", "revisionNumber", *n)
+		return r.rollback(ctx, p, status, prs.GetRevisions(), *n)
+	}
+
+	// Hold the named revision active, if any. Like when rolled back, we
+	// don't resolve, create, or garbage collect revisions while held.
+	if name := p.GetAnnotations()[v1.AnnotationHold]; name != "" {
+		trace.Info("Holding package revision", "revision", name)
+		return r.hold(ctx, p, status, prs.GetRevisions(), name)
+	}
+	if c := p.GetCondition(v1.TypeHeld); c.Reason == v1.ReasonHeld || c.Reason == v1.ReasonHeldRevisionNotFound {
+		status.MarkConditions(v1.HoldReleased())
+	}
+
+	// The package's current revision should be its active revision, but a
+	// manual edit or a restore from backup could make them disagree. Prefer
+	// the healthy active revision, since it's what's actually running.
+	var disagreeing string
+	if active := r.disagreeingActiveRevision(p, prs.GetRevisions()); active != nil {
+		err := errors.Errorf(errFmtCurrentRevisionDisagrees, p.GetCurrentRevision(), active.GetName(), active.GetName())
+		log.Debug("Package's current revision disagrees with its active revision", "currentRevision", p.GetCurrentRevision(), "activeRevision", active.GetName())
+		r.record.Event(p, event.Warning(reasonTransitionRevision, err))
+		disagreeing = p.GetCurrentRevision()
+		p.SetCurrentRevision(active.GetName())
+	}
+
+	// Resolve the package's source if it refers to an alias. We need to do
+	// this before rewriting the image path, since the concrete path the
+	// alias refers to may be rewritten.
+	source := p.GetSource()
+	p.SetResolvedAlias("")
+	if aliasName(source) != "" {
+		alias, resolved, err := r.aliases.ResolveAlias(ctx, source)
+		if err != nil {
+			err = errors.Wrap(err, errResolveAlias)
+			c := v1.Unpacking().WithMessage(err.Error())
+			if kerrors.IsNotFound(err) {
+				c = v1.AliasNotFound(alias)
+			}
+			status.MarkConditions(c)
+			r.markIfStuck(p, status)
+			_ = r.client.Status().Update(ctx, p)
+
+			r.record.Event(p, event.Warning(reasonAlias, err))
+
+			return reconcile.Result{}, err
+		}
+		source = resolved
+		p.SetResolvedAlias(alias)
+	}
+
+	// Rewrite the image path if necessary. We need to do this before looking
+	// for pull secrets, since the rewritten path may use different secrets than
+	// the original.
+	imagePath := source
+	rewriteConfigName, newPath, err := r.config.RewritePath(ctx, imagePath)
+	if err != nil {
+		err = errors.Wrap(err, errRewriteImage)
+		status.MarkConditions(v1.Unpacking().WithMessage(err.Error()))
+		r.markIfStuck(p, status)
+		_ = r.client.Status().Update(ctx, p)
+
+		r.record.Event(p, event.Warning(reasonImageConfig, err))
+
+		return reconcile.Result{}, err
+	}
+	if newPath != "" {
+		imagePath = newPath
+		p.SetAppliedImageConfigRefs(v1.ImageConfigRef{
+			Name:   rewriteConfigName,
+			Reason: v1.ImageConfigReasonRewrite,
+		})
+	} else {
+		p.ClearAppliedImageConfigRef(v1.ImageConfigReasonRewrite)
+	}
+	p.SetResolvedSource(imagePath)
+	report.ResolvedSource = imagePath
+	trace.Info("Resolved package source", "source", p.GetSource(), "resolved", imagePath, "rewriteConfig", rewriteConfigName)
+
+	pullSecretConfig, pullSecretFromConfig, err := r.config.PullSecretFor(ctx, p.GetResolvedSource())
+	if err != nil {
+		err = errors.Wrap(err, errGetPullConfig)
+		status.MarkConditions(v1.Unpacking().WithMessage(err.Error()))
+		r.markIfStuck(p, status)
+		_ = r.client.Status().Update(ctx, p)
+
+		r.record.Event(p, event.Warning(reasonImageConfig, err))
+
+		return reconcile.Result{}, err
+	}
+
+	// The pull secret name selected by config may be a template that
+	// interpolates the namespace of the package it's for. Cluster scoped
+	// packages use pull secrets in the package manager's namespace.
+	secretNamespace := p.GetNamespace()
+	if secretNamespace == "" {
+		secretNamespace = r.namespace
+	}
+	pullSecretFromConfig, err = renderPullSecretName(pullSecretFromConfig, secretNamespace)
+	if err != nil {
+		err = errors.Wrap(err, errRenderPullSecret)
+		status.MarkConditions(v1.Unpacking().WithMessage(err.Error()))
+		r.markIfStuck(p, status)
+		_ = r.client.Status().Update(ctx, p)
+
+		r.record.Event(p, event.Warning(reasonImageConfig, err))
+
+		return reconcile.Result{}, err
+	}
+
+	// Use the image pull secrets of the ServiceAccount the package names, if
+	// any. Like a pull secret selected by config, the ServiceAccount may not
+	// exist yet, so wait for it rather than returning an error.
+	var serviceAccountSecrets []string
+	if name := p.GetServiceAccountName(); name != "" {
+		sa := &corev1.ServiceAccount{}
+		err := r.client.Get(ctx, types.NamespacedName{Namespace: secretNamespace, Name: name}, sa)
+		if kerrors.IsNotFound(err) {
+			c := v1.ServiceAccountPending(name)
+			status.MarkConditions(c)
+			r.record.Event(p, event.Warning(reasonUnpack, errors.New(c.Message)))
+
+			// Requeue with backoff to give the ServiceAccount time to appear.
+			return reconcile.Result{Requeue: true}, errors.Wrap(r.client.Status().Update(ctx, p), errUpdateStatus)
+		}
+		if err != nil {
+			err = errors.Wrap(err, errGetServiceAccount)
+			status.MarkConditions(v1.Unpacking().WithMessage(err.Error()))
+			r.markIfStuck(p, status)
+			_ = r.client.Status().Update(ctx, p)
+
+			r.record.Event(p, event.Warning(reasonUnpack, err))
+
+			return reconcile.Result{}, err
+		}
+		serviceAccountSecrets = v1.RefNames(sa.ImagePullSecrets)
+	}
+
+	// Remember which pull secrets the package uses, so that we can requeue
+	// it when one of them changes - or is created.
+	if r.pullSecrets != nil {
+		ps := v1.RefNames(p.GetPackagePullSecrets())
+		if pullSecretFromConfig != "" {
+			ps = append(ps, pullSecretFromConfig)
+		}
+		ps = append(ps, serviceAccountSecrets...)
+		r.pullSecrets.Set(p.GetName(), ps...)
+	}
+
+	if pullSecretFromConfig != "" {
+		// Catch a missing pull secret here rather than letting it surface as
+		// a less precise failure to pull the package. The secret may not
+		// exist yet, for example because it's synced from an external secret
+		// store, so wait for it rather than returning an error.
+		err := r.client.Get(ctx, types.NamespacedName{Namespace: secretNamespace, Name: pullSecretFromConfig}, &corev1.Secret{})
+		if kerrors.IsNotFound(err) {
+			c := v1.PullSecretPending(pullSecretFromConfig, pullSecretConfig)
+			status.MarkConditions(c)
+			r.record.Event(p, event.Warning(reasonImageConfig, errors.New(c.Message)))
+
+			// Requeue with backoff to give the secret time to appear.
+			return reconcile.Result{Requeue: true}, errors.Wrap(r.client.Status().Update(ctx, p), errUpdateStatus)
+		}
+		if err != nil {
+			err = errors.Wrap(err, errGetPullSecret)
+			status.MarkConditions(v1.Unpacking().WithMessage(err.Error()))
+			r.markIfStuck(p, status)
+			_ = r.client.Status().Update(ctx, p)
+
+			r.record.Event(p, event.Warning(reasonImageConfig, err))
+
+			return reconcile.Result{}, err
+		}
+	}
+
+	var secrets []string
+	if pullSecretFromConfig != "" {
+		secrets = append(secrets, pullSecretFromConfig)
+		p.SetAppliedImageConfigRefs(v1.ImageConfigRef{
+			Name:   pullSecretConfig,
+			Reason: v1.ImageConfigReasonSetPullSecret,
+		})
+	} else {
+		p.ClearAppliedImageConfigRef(v1.ImageConfigReasonSetPullSecret)
+	}
+	secrets = append(secrets, serviceAccountSecrets...)
+	trace.Info("Selected pull secrets", "pullSecrets", v1.RefNames(p.GetPackagePullSecrets()), "pullSecretFromConfig", pullSecretFromConfig, "pullSecretConfig", pullSecretConfig, "serviceAccount", p.GetServiceAccountName(), "serviceAccountPullSecrets", serviceAccountSecrets)
+
+	// In strict offline mode we only use revisions we already have. Don't
+	// fetch a package with pull policy IfNotPresent from its registry just
+	// because it doesn't have a revision yet. Local packages don't need to
+	// be fetched, so they're fine.
+	if pp := p.GetPackagePullPolicy(); r.strictOffline && pp != nil && *pp == corev1.PullIfNotPresent && len(prs.GetRevisions()) == 0 {
+		if _, local := xpkg.LocalPackagePath(p.GetResolvedSource()); !local {
+			c := v1.NoCachedRevision()
+			status.MarkConditions(c)
+			r.record.Event(p, event.Warning(reasonUnpack, errors.New(c.Message)))
+			return reconcile.Result{}, errors.Wrap(r.client.Status().Update(ctx, p), errUpdateStatus)
+		}
+	}
+
+	// Don't block a worker waiting to resolve the package if too many
+	// packages are already being resolved. Come back soon instead. We never
+	// reconcile the same package concurrently, so this also bounds how many
+	// memory intensive unpacks overlap.
+	if r.resolutions != nil && !r.resolutions.TryAcquire(1) {
+		log.Debug("Too many packages are being resolved, requeueing")
+		trace.Info("Too many packages are being resolved, requeueing")
+		return reconcile.Result{RequeueAfter: resolutionsSaturatedRequeue}, nil
+	}
+	// Record the registry's response headers when tracing. They're often
+	// the best clue as to why a registry behaves unexpectedly, e.g. when
+	// it's rate limiting us.
+	fctx := ctx
+	var headers *xpkg.ResponseHeaders
+	if debugging(p) {
+		fctx, headers = xpkg.WithResponseHeaders(ctx)
+	}
+	fetched := timer.Start(phaseFetch)
+	revisionName, image, err := r.unpack(fctx, p, secrets...)
+	fetched()
+	if h := headers.Header(); h != nil {
+		trace.Info("Received registry response", "responseHeaders", h)
+	}
+	if err != nil {
+		err = errors.Wrap(err, errUnpack)
+		p.SetLastResolverError(err.Error())
+
+		// The package's source no longer exists, e.g. because its tag was
+		// deleted. There's nothing to unpack, but if the package's current
+		// revision is healthy there's no need to fail - it keeps running.
+		if cur := healthyCurrentRevision(p, prs); cur != nil && manifestNotFound(err) {
+			status.MarkConditions(v1.SourceUnavailable(cur.GetName(), err))
+			r.record.Event(p, event.Warning(reasonUnpack, err))
+			return pullBasedRequeue(pullPolicy(p)), errors.Wrap(r.client.Status().Update(ctx, p), errUpdateStatus)
+		}
+
+		c := v1.Unpacking().WithMessage(err.Error())
+		if authenticationFailed(err) {
+			// Authentication failures are actionable - e.g. by fixing
+			// or rotating a pull secret - so call them out specifically.
+			c = v1.AuthenticationFailed().WithMessage(err.Error())
+		}
+		if ec, ok := r.errorCondition(ctx, err); ok {
+			c = ec
+		}
+		status.MarkConditions(c)
+		r.markIfStuck(p, status)
+		r.record.Event(p, event.Warning(reasonUnpack, err))
 
 		if updateErr := r.client.Status().Update(ctx, p); updateErr != nil {
 			return reconcile.Result{}, errors.Wrap(updateErr, errUpdateStatus)
 		}
-
-		return reconcile.Result{}, err
+
+		return reconcile.Result{}, err
+	}
+
+	trace.Info("Resolved package revision", "revision", revisionName, "currentRevision", p.GetCurrentRevision(), "image", image)
+	p.SetLastResolverError("")
+	if p.GetCondition(v1.TypeSourceUnavailable).Reason == v1.ReasonManifestNotFound {
+		status.MarkConditions(v1.SourceAvailable())
+	}
+
+	if revisionName == "" {
+		status.MarkConditions(v1.Unpacking().WithMessage("Waiting for unpack to complete"))
+		r.markIfStuck(p, status)
+		r.record.Event(p, event.Normal(reasonUnpack, "Waiting for unpack to complete"))
+		return reconcile.Result{Requeue: true}, errors.Wrap(r.client.Status().Update(ctx, p), errUpdateStatus)
+	}
+
+	// Resolving the package's source may produce the current revision that
+	// disagreed with its active revision, e.g. because the package is
+	// polled. Keep using the active revision in that case. A new source or
+	// digest still produces a new revision.
+	if disagreeing != "" && revisionName == disagreeing {
+		revisionName, image = p.GetCurrentRevision(), nil
+	}
+
+	// The package is no longer unpacking, so it's no longer stuck.
+	r.unpackingSince.Delete(p.GetUID())
+	if p.GetCondition(v1.TypeStuck).Reason == v1.ReasonStuckUnpacking {
+		status.MarkConditions(v1.Unstuck())
+	}
+
+	// Stop creating new revisions once a package has too many, regardless of
+	// its revision history limit. This guards against accumulating revisions
+	// when a package with a large revision history limit churns.
+	if r.maxRevisions > 0 && len(prs.GetRevisions()) >= r.maxRevisions && !hasRevision(prs, revisionName) {
+		status.MarkConditions(v1.RevisionLimitExceeded(len(prs.GetRevisions()), r.maxRevisions))
+		r.record.Event(p, event.Warning(reasonInstall, errors.Errorf(errFmtRevisionLimitExceeded, revisionName, r.maxRevisions)))
+		return reconcile.Result{}, errors.Wrap(r.client.Status().Update(ctx, p), errUpdateStatus)
+	}
+
+	// Don't create a new revision of a package that resolved to a different
+	// digest than expected, e.g. because its tag was repointed. We can't
+	// verify a digest we don't know, so we don't create the revision then
+	// either.
+	if expected := p.GetExpectedDigest(); expected != "" && !hasRevision(prs, revisionName) {
+		if resolved := resolvedDigest(p, image); resolved != expected {
+			c := v1.DigestMismatch(expected, resolved)
+			status.MarkConditions(c)
+			r.record.Event(p, event.Warning(reasonInstall, errors.Errorf(errFmtDigestMismatch, revisionName, c.Message)))
+			return reconcile.Result{}, errors.Wrap(r.client.Status().Update(ctx, p), errUpdateStatus)
+		}
+	}
+
+	// Don't create a new revision of a package whose image lacks a required
+	// provenance attestation.
+	if r.provenance != nil && !hasRevision(prs, revisionName) {
+		ok, err := r.provenance.VerifyProvenance(ctx, p, resolvedDigest(p, image), secrets...)
+		if err != nil {
+			err = errors.Wrapf(err, errFmtVerifyProvenance, revisionName)
+			r.record.Event(p, event.Warning(reasonInstall, err))
+			return reconcile.Result{}, err
+		}
+		if !ok {
+			err := errors.Errorf(errFmtProvenanceMissing, revisionName)
+			status.MarkConditions(v1.ProvenanceMissing(err.Error()))
+			r.record.Event(p, event.Warning(reasonInstall, err))
+			return reconcile.Result{}, errors.Wrap(r.client.Status().Update(ctx, p), errUpdateStatus)
+		}
+	}
+
+	// Don't create a new revision of a package whose metadata declares a
+	// different kind of package, e.g. a Configuration that references a
+	// Provider's image. We can only tell if the Revisioner parsed the
+	// package's contents.
+	if image != nil && !hasRevision(prs, revisionName) {
+		kind, declared := packageKind(p), declaredKind(image.Contents)
+		if kind != "" && declared != "" && kind != declared {
+			status.MarkConditions(v1.PackageTypeMismatch(kind, declared))
+			r.record.Event(p, event.Warning(reasonInstall, errors.Errorf(errFmtPackageTypeMismatch, revisionName, kind, declared)))
+			return reconcile.Result{}, errors.Wrap(r.client.Status().Update(ctx, p), errUpdateStatus)
+		}
+	}
+
+	// Don't create a new revision of a package whose contents violate
+	// policy. We validate before setting the current revision, so that the
+	// package doesn't reference a revision we won't create.
+	if r.validator != nil && !hasRevision(prs, revisionName) {
+		var contents *parser.Package
+		if image != nil {
+			contents = image.Contents
+		}
+		if err := r.validator.Validate(ctx, p, contents); err != nil {
+			err = errors.Wrapf(err, errFmtContentPolicyViolation, revisionName)
+			status.MarkConditions(v1.ContentPolicyViolation(err.Error()))
+			r.record.Event(p, event.Warning(reasonInstall, err))
+			return reconcile.Result{}, errors.Wrap(r.client.Status().Update(ctx, p), errUpdateStatus)
+		}
+	}
+
+	// Let the user know if a new revision's contents use deprecated APIs.
+	// This is informational - it doesn't stop the revision becoming active.
+	if image != nil && image.Contents != nil {
+		if apis := deprecatedAPIs(image.Contents); len(apis) > 0 {
+			status.MarkConditions(v1.UsesDeprecatedAPIs(apis...))
+		} else if p.GetCondition(v1.TypeDeprecatedAPIs).Reason == v1.ReasonDeprecatedAPIs {
+			status.MarkConditions(v1.NoDeprecatedAPIs())
+		}
+	}
+
+	// Set the current revision and identifier.
+	becameCurrent := p.GetCurrentRevision() != revisionName
+	p.SetCurrentRevision(revisionName)
+	// Use the original source as the identifier, even if it was rewritten by
+	// ImageConfig. The revisioning and dependency resolution logic are all
+	// based on the original package sources, so it's important that we preserve
+	// the original until it's time to actually pull an image.
+	p.SetCurrentIdentifier(p.GetSource())
+
+	// Let the user know if a package we don't poll has an upgrade available.
+	upgradeCheck := r.checkForUpgrade(ctx, p, status, log, secrets...)
+
+	ap, err := activationPolicy(p)
+	if err != nil {
+		log.Debug("Falling back to spec activation policy", "error", err)
+		r.record.Event(p, event.Warning(reasonActivationPolicy, err))
+	}
+
+	// Use the default activation policy if the package doesn't specify one.
+	// Without a configured default the policy is implicitly automatic.
+	var defaultedMsg string
+	if ap == nil {
+		ap = ptr.To(v1.AutomaticActivation)
+		if r.defaultActivationPolicy != "" {
+			ap = ptr.To(r.defaultActivationPolicy)
+			defaultedMsg = fmt.Sprintf("Package doesn't specify a revision activation policy, so the default %s policy took effect", *ap)
+		}
+	}
+
+	// Under the HighestHealthy activation policy, keep the highest numbered
+	// healthy revision active instead of the current revision while the
+	// current revision is unhealthy.
+	var lastGood v1.PackageRevision
+	if *ap == v1.HighestHealthyActivation {
+		lastGood = lastGoodRevision(p.GetCurrentRevision(), prs.GetRevisions())
+	}
+
+	pr := r.newPackageRevision()
+	var previous v1.PackageRevision
+	maxRevision := int64(0)
+	var collectable []v1.PackageRevision
+	protections := map[string]string{}
+	var drifted []string
+	var action *LastAction
+	revisions := prs.GetRevisions()
+
+	// Use the current revision if it already exists.
+	if i := slices.IndexFunc(revisions, func(rev v1.PackageRevision) bool { return rev.GetName() == p.GetCurrentRevision() }); i >= 0 {
+		pr = revisions[i]
+	}
+
+	// If the current revision is not active, and we have an automatic
+	// activation policy, always activate - once the package's activation
+	// delay, if any, elapses. We decide before we deactivate the package's
+	// other revisions, so that we don't deactivate them for nothing.
+	activated := false
+	var activationWait time.Duration
+	automatic := *ap == v1.AutomaticActivation || (*ap == v1.HighestHealthyActivation && lastGood == nil)
+	if pr.GetDesiredState() != v1.PackageRevisionActive && automatic {
+		activationWait = activationDelayRemaining(p, pr, r.clock)
+		activated = activationWait == 0
+	}
+
+	// Don't activate the current revision if doing so would exceed its
+	// tenant's active revision quota.
+	quotaExceeded := false
+	tenant, tenanted := r.tenant(p)
+	quota := 0
+	if activated && tenanted {
+		var err error
+		quota, quotaExceeded, err = r.activeRevisionQuotaExceeded(ctx, p, tenant)
+		if err != nil {
+			err = errors.Wrap(err, errCheckActiveRevisionQuota)
+			r.record.Event(p, event.Warning(reasonInstall, err))
+			return reconcile.Result{}, err
+		}
+		activated = !quotaExceeded
+	}
+
+	// Don't activate the current revision if the runtime config its package
+	// references doesn't exist, since its runtime couldn't be deployed.
+	runtimeConfig, runtimeConfigMissing := "", false
+	if activated && r.features.Enabled(features.EnableBetaDeploymentRuntimeConfigs) {
+		var err error
+		runtimeConfig, runtimeConfigMissing, err = missingRuntimeConfig(ctx, r.client, p)
+		if err != nil {
+			r.record.Event(p, event.Warning(reasonInstall, err))
+			return reconcile.Result{}, err
+		}
+		activated = !runtimeConfigMissing
+	}
+
+	// Don't activate the current revision while any of its dependencies are
+	// garbage collecting their old revisions. We don't watch dependencies,
+	// so we'll check again shortly.
+	var gcDependencies []string
+	if activated && r.gcDependencies != nil {
+		var err error
+		gcDependencies, err = r.gcDependencies.GarbageCollectingDependencies(ctx, pr)
+		if err != nil {
+			err = errors.Wrap(err, errListGCDependencies)
+			r.record.Event(p, event.Warning(reasonInstall, err))
+			return reconcile.Result{}, err
+		}
+		activated = len(gcDependencies) == 0
+	}
+
+	// Keep the package's active revision active while we defer activating
+	// its current revision, so that the package keeps working meanwhile.
+	var retained v1.PackageRevision
+	if activationWait > 0 || quotaExceeded || runtimeConfigMissing || len(gcDependencies) > 0 {
+		retained = latestActiveRevision(revisions, pr.GetName())
+	}
+
+	// Only one revision should ever be active, but a race or a manual edit
+	// could activate more. Let the user know; we deactivate all but the
+	// revision that should be active below.
+	if active := activeRevisions(revisions); len(active) > 1 {
+		keep := p.GetCurrentRevision()
+		if lastGood != nil {
+			keep = lastGood.GetName()
+		}
+		if retained != nil {
+			keep = retained.GetName()
+		}
+		err := errors.Errorf(errFmtMultipleActiveRevisions, len(active), strings.Join(active, ", "), keep)
+		log.Debug("Package has multiple active revisions", "revisions", active, "keep", keep)
+		r.record.Event(p, event.Warning(reasonTransitionRevision, err))
+	}
+
+	// Remember which revision was active before we deactivate any, so we
+	// can report which revision an activation replaced.
+	previouslyActive := otherActiveRevision(revisions, p.GetCurrentRevision())
+	wasActive := latestActiveRevision(revisions, p.GetCurrentRevision())
+
+	// Check to see if revision already exists.
+	for _, rev := range revisions {
+		revisionNum := rev.GetRevision()
+
+		// Set max revision to the highest numbered existing revision.
+		if revisionNum > maxRevision {
+			maxRevision = revisionNum
+		}
+
+		// Record revisions that could be garbage collected. The current
+		// revision and externally managed revisions are never garbage
+		// collected.
+		if !protected(p, rev) {
+			collectable = append(collectable, rev)
+		} else {
+			protections[rev.GetName()] = protection(p, rev)
+		}
+		// Skip the current revision, but finish iterating through all
+		// revisions to make sure all non-current revisions are inactive.
+		if rev.GetName() == p.GetCurrentRevision() {
+			continue
+		}
+		// Keep track of the most recent revision before the current
+		// revision.
+		if previous == nil || revisionNum > previous.GetRevision() {
+			previous = rev
+		}
+		// Detect revisions that lost their controller reference to the
+		// package, e.g. because it was removed out-of-band. The current
+		// revision's controller reference is always restored when we
+		// apply it below.
+		adopt := false
+		if !externallyManaged(rev) && !metav1.IsControlledBy(rev, p) {
+			drifted = append(drifted, rev.GetName())
+			adopt = r.ownershipDriftPolicy == OwnershipDriftPolicyAdopt
+		}
+		if adopt {
+			log.Debug("Restoring package's controller reference to package revision", "revision", rev.GetName())
+			meta.AddOwnerReference(rev, controllerReference(p))
+		}
+		// If revision is not the current revision, set to inactive.
+		// This should always be done, regardless of the package's
+		// revision activation policy - unless it's the last good
+		// revision, or the revision we're retaining while we defer
+		// activating the current one, that we're keeping active instead
+		// of the current one.
+		fallback := (lastGood != nil && rev.GetName() == lastGood.GetName()) || (retained != nil && rev.GetName() == retained.GetName())
+		propagated := metav1.IsControlledBy(rev, p) && r.propagateMetadata(p, rev)
+		switch {
+		case fallback && (rev.GetDesiredState() != v1.PackageRevisionActive || adopt):
+			rev.SetDesiredState(v1.PackageRevisionActive)
+		case !fallback && (rev.GetDesiredState() == v1.PackageRevisionActive || adopt):
+			rev.SetDesiredState(v1.PackageRevisionInactive)
+		case propagated:
+			// Only the revision's propagated metadata changed.
+		default:
+			continue
+		}
+		applied := timer.Start(phaseApply)
+		err := r.client.Apply(ctx, rev, resource.MustBeControllableBy(p.GetUID()))
+		applied()
+		if err != nil {
+			if kerrors.IsConflict(err) {
+				return reconcile.Result{Requeue: true}, nil
+			}
+			err = errors.Wrap(err, errUpdateInactivePackageRevision)
+			r.record.Event(p, event.Warning(reasonTransitionRevision, err))
+			return reconcile.Result{}, err
+		}
+	}
+
+	switch {
+	case len(drifted) > 0 && r.ownershipDriftPolicy == OwnershipDriftPolicyReport:
+		status.MarkConditions(v1.OwnershipDrift(drifted...))
+	case p.GetCondition(v1.TypeRevisionOwnership).Reason == v1.ReasonOwnershipDrift:
+		status.MarkConditions(v1.OwnershipIntact())
+	}
+
+	// The current revision should always be the highest numbered revision.
+	if pr.GetRevision() < maxRevision || maxRevision == 0 {
+		pr.SetRevision(maxRevision + 1)
+	}
+
+	// Never garbage collect the newest healthy revision, so that there's
+	// always a healthy revision to fall back to. Nor the last good or
+	// retained revision we're keeping active instead of the current
+	// revision.
+	fallbacks := []struct {
+		rev        v1.PackageRevision
+		protection string
+	}{
+		{rev: newestHealthy(revisions), protection: protectionNewestHealthy},
+		{rev: lastGood, protection: protectionLastGood},
+		{rev: retained, protection: protectionRetained},
+	}
+	for _, fallback := range fallbacks {
+		if fallback.rev == nil {
+			continue
+		}
+		collectable = slices.DeleteFunc(collectable, func(rev v1.PackageRevision) bool {
+			if rev.GetName() != fallback.rev.GetName() {
+				return false
+			}
+			protections[rev.GetName()] = fallback.protection
+			return true
+		})
+	}
+
+	// Don't garbage collect revisions whose health is still unknown, for
+	// example because they were just created. They may be converging.
+	if r.unknownHealthGCPolicy == UnknownHealthGCPolicyRetain {
+		collectable = slices.DeleteFunc(collectable, func(rev v1.PackageRevision) bool {
+			if rev.GetCondition(v1.TypeRevisionHealthy).Status != corev1.ConditionUnknown {
+				return false
+			}
+			protections[rev.GetName()] = protectionUnknownHealth
+			return true
+		})
+	}
+
+	// Check to see if there are revisions eligible for garbage collection.
+	// Use the default revision history limit if the package doesn't
+	// specify one, and record which limit is in effect.
+	limit := orDefault(p.GetRevisionHistoryLimit(), r.defaultRevisionHistoryLimit)
+	p.SetEffectiveRevisionHistoryLimit(nil)
+	if limit != nil {
+		p.SetEffectiveRevisionHistoryLimit(ptr.To(*limit))
+	}
+	if r.reportGCMode {
+		switch {
+		case limit == nil || *limit == 0:
+			status.MarkConditions(v1.GarbageCollectionDisabled())
+		case p.GetRevisionHistoryLimit() == nil:
+			status.MarkConditions(v1.GarbageCollectionDefaultLimit(*limit))
+		default:
+			status.MarkConditions(v1.GarbageCollectionExplicitLimit(*limit))
+		}
+	}
+	// Garbage collect the oldest revisions in excess of the limit. Depending
+	// on the configured order we do so either before we activate the
+	// current revision, to free the old revisions' resources, or after, to
+	// keep them as a fallback until the current revision is applied.
+	var deleted []string
+	collectGarbage := func() error {
+		switch {
+		case limit == nil ||
+			*limit == 0 ||
+			len(revisions) <= (int(*limit)+1):
+			// Nothing to garbage collect.
+			if p.GetCondition(v1.TypeRevisionGarbageCollection).Reason == v1.ReasonGarbageCollectionBlocked {
+				status.MarkConditions(v1.GarbageCollectionUnblocked())
+			}
+		case r.deferGC && v1.PackageHealth(pr).Status != corev1.ConditionTrue:
+			// Don't garbage collect until the current revision is healthy, so
+			// that we don't delete the revisions we'd fall back to while it
+			// rolls out. We'll be requeued when its health changes.
+			trace.Info("Deferring garbage collection until current revision is healthy", "revision", pr.GetName())
+		case len(collectable) == 0:
+			// Every revision is protected from garbage collection.
+			status.MarkConditions(v1.GarbageCollectionBlocked(len(revisions), *limit, describeProtections(protections)...))
+		default:
+			// Delete the oldest revisions in excess of the limit.
+			slices.SortFunc(collectable, func(a, b v1.PackageRevision) int {
+				return cmp.Compare(a.GetRevision(), b.GetRevision())
+			})
+			excess := len(revisions) - (int(*limit) + 1)
+			collected := timer.Start(phaseGC)
+			gc, err := r.garbageCollect(ctx, collectable[:min(excess, len(collectable))])
+			collected()
+			deleted = gc.Collected
+			report.GarbageCollected = deleted
+			for _, name := range deleted {
+				r.audit.Record(ctx, r.auditEvent(p, name, AuditActionGarbageCollect))
+				action = &LastAction{Action: AuditActionGarbageCollect, Revision: name, Reason: LastActionReasonRevisionHistoryLimit}
+			}
+			p.SetGarbageCollection(gc)
+			if err != nil {
+				err = errors.Wrap(err, errGCPackageRevision)
+				r.record.Event(p, event.Warning(reasonGarbageCollect, err))
+
+				// Record which revisions we did and didn't collect.
+				if updateErr := r.client.Status().Update(ctx, p); updateErr != nil {
+					return errors.Wrap(updateErr, errUpdateStatus)
+				}
+				return err
+			}
+			if p.GetCondition(v1.TypeRevisionGarbageCollection).Reason == v1.ReasonGarbageCollectionBlocked {
+				status.MarkConditions(v1.GarbageCollectionUnblocked())
+			}
+		}
+		// Let packages that depend on this one know while we're garbage
+		// collecting its revisions, including revisions we deleted
+		// previously that are still being deleted.
+		if r.gcDependencies != nil {
+			if collecting := collectingRevisions(revisions, deleted); len(collecting) > 0 {
+				status.MarkConditions(v1.GarbageCollecting(collecting...))
+			} else if p.GetCondition(v1.TypeRevisionGarbageCollection).Reason == v1.ReasonGarbageCollecting {
+				status.MarkConditions(v1.GarbageCollectionUnblocked())
+			}
+		}
+		trace.Info("Considered package revisions for garbage collection", "revisions", len(revisions), "revisionHistoryLimit", ptr.Deref(limit, 0), "collectable", revisionNames(collectable), "deleted", deleted)
+		return nil
+	}
+	if r.gcOrder == GCOrderCollectFirst {
+		if err := collectGarbage(); err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
+	// Derive the package's health from the last good or retained revision
+	// while it's active instead of the current revision.
+	healthOf := pr
+	if lastGood != nil {
+		healthOf = lastGood
+	}
+	if retained != nil {
+		healthOf = retained
+	}
+	health, healthSource := r.health(p, healthOf, revisions)
+
+	// A healthy revision may still be waiting for the CRDs it installed to
+	// become established. Don't report the package healthy until they are.
+	var establishing []string
+	if health.Status == corev1.ConditionTrue {
+		establishing, err = r.establishingCRDs(ctx, healthOf)
+		if err != nil {
+			r.record.Event(p, event.Warning(reasonInstall, err))
+			return reconcile.Result{}, err
+		}
+		if len(establishing) > 0 {
+			health = v1.EstablishingCRDs(establishing...)
+		}
+	}
+
+	// A provider family's members share types, like ProviderConfig, so a
+	// member may not work while others are unhealthy. Only report it
+	// healthy once every member of its family is.
+	var unhealthyMembers []string
+	if health.Status == corev1.ConditionTrue && r.familyHealth {
+		unhealthyMembers, err = r.unhealthyFamilyMembers(ctx, p, healthOf)
+		if err != nil {
+			r.record.Event(p, event.Warning(reasonInstall, err))
+			return reconcile.Result{}, err
+		}
+		if len(unhealthyMembers) > 0 {
+			health = v1.PartiallyHealthy(unhealthyMembers...)
+		}
+	}
+
+	// Damp revisions that flap between healthy and unhealthy by only
+	// reporting the package healthy once its revision has been healthy
+	// for long enough.
+	var stabilizing time.Duration
+	if health.Status == corev1.ConditionTrue && r.minHealthyDuration > 0 {
+		if stabilizing = r.minHealthyDuration - healthyFor(healthSource, r.clock.Now()); stabilizing > 0 {
+			health = v1.Stabilizing(stabilizing)
+		}
+	}
+	wasHealthy := p.GetCondition(v1.TypeHealthy).Status == corev1.ConditionTrue
+	status.MarkConditions(health)
+	if health.Status == corev1.ConditionTrue && !wasHealthy {
+		// NOTE(phisco): We don't want to spam the user with events if the
+		// package is already healthy.
+		r.record.Event(p, event.Normal(reasonInstall, "Successfully installed package revision"))
+		r.metrics.RecordFirstTimeHealthy(p)
+	}
+	status.MarkConditions(r.propagatedConditions(pr)...)
+
+	if pr.GetUID() == "" && pullSecretConfig != "" {
+		// We only record this event if the revision is new, as we don't want to
+		// spam the user with events if the revision already exists.
+		log.Debug("Selected pull secret from image config store", "image", p.GetResolvedSource(), "pullSecretConfig", pullSecretConfig, "pullSecret", pullSecretFromConfig, "rewriteConfig", rewriteConfigName)
+		r.record.Event(p, event.Normal(reasonImageConfig, fmt.Sprintf("Selected pullSecret %q from ImageConfig %q for registry authentication", pullSecretFromConfig, pullSecretConfig)))
+	}
+
+	// Create the non-existent package revision.
+	pr.SetName(revisionName)
+	report.Revision = revisionName
+	pr.SetLabels(map[string]string{v1.LabelParentPackage: p.GetName()})
+	if tenant, ok := r.tenant(p); ok {
+		// Label the revision with its tenant, so we can find the
+		// tenant's active revisions when enforcing its quota.
+		meta.AddLabels(pr, map[string]string{r.tenantLabel: tenant})
+	}
+	if pr.GetUID() == "" && p.GetGeneration() > 0 {
+		// Record which generation of the package produced the revision.
+		// We only do this when we create the revision, so later edits to
+		// the package don't overwrite it.
+		meta.AddAnnotations(pr, map[string]string{v1.AnnotationPackageGeneration: strconv.FormatInt(p.GetGeneration(), 10)})
+	}
+	if tag := sourceTag(source); pr.GetUID() == "" && tag != "" {
+		// Record which tag the revision came from, since its source
+		// may be resolved to a digest. Like the generation, we only do
+		// this when we create the revision.
+		meta.AddAnnotations(pr, map[string]string{v1.AnnotationSourceTag: tag})
+	}
+	if r.annotateCreationReason && pr.GetUID() == "" {
+		meta.AddAnnotations(pr, map[string]string{v1.AnnotationCreationReason: creationReason(prs.GetRevisions(), source)})
+	}
+	r.propagateMetadata(p, pr)
+
+	// Reuse an existing revision's spec as-is, unless we're configured to
+	// reconcile it toward the package's, e.g. because its pull secrets
+	// or runtime config changed.
+	if pr.GetUID() == "" || r.reconcileRevisionSpec {
+		r.setRevisionSpec(p, pr, source)
+	}
+	commonLabels := r.revisionCommonLabels(p)
+	pr.SetCommonLabels(commonLabels)
+
+	switch {
+	case activated:
+		pr.SetDesiredState(v1.PackageRevisionActive)
+	case activationWait > 0, quotaExceeded, runtimeConfigMissing, len(gcDependencies) > 0, lastGood != nil:
+		pr.SetDesiredState(v1.PackageRevisionInactive)
+	case pr.GetDesiredState() == "":
+		// Create revisions we don't activate explicitly inactive, so that
+		// they're summarized as such.
+		pr.SetDesiredState(v1.PackageRevisionInactive)
+	}
+	trace.Info("Decided whether to activate package revision", "revision", pr.GetName(), "activationPolicy", *ap, "activated", activated, "activationWait", activationWait, "quotaExceeded", quotaExceeded, "runtimeConfigMissing", runtimeConfigMissing, "garbageCollectingDependencies", gcDependencies)
+
+	// The digest the current revision resolved to. We only know it if we
+	// inspected the revision's image, now or in a previous reconcile.
+	digest := pr.GetResolvedDigest()
+	if image != nil && image.Digest != "" {
+		digest = image.Digest
+	}
+	if r.annotateDigest && digest != "" && pr.GetDesiredState() == v1.PackageRevisionActive {
+		meta.AddAnnotations(pr, map[string]string{v1.AnnotationResolvedDigest: digest})
+	}
+
+	// Don't take ownership of an externally managed revision. Whatever
+	// manages it is responsible for its lifecycle.
+	if !externallyManaged(pr) {
+		meta.AddOwnerReference(pr, controllerReference(p))
+	}
+	created := pr.GetUID() == ""
+
+	// Discover the SBOMs that refer to a new revision's image before we
+	// create it. The image is immutable, so we don't look for them again.
+	var sboms []string
+	if created && r.sboms != nil {
+		sboms, err = r.sboms.ListSBOMs(ctx, p, secrets...)
+		if err != nil {
+			err = errors.Wrapf(err, errFmtListSBOMs, pr.GetName())
+			r.record.Event(p, event.Warning(reasonInstall, err))
+			return reconcile.Result{}, err
+		}
+	}
+
+	skipped := false
+	applied := timer.Start(phaseApply)
+	err = r.client.Apply(ctx, pr, resource.MustBeControllableBy(p.GetUID()))
+	applied()
+	switch {
+	case err == nil:
+		report.Created, report.Activated = created, activated
+		if created {
+			r.audit.Record(ctx, r.auditEvent(p, pr.GetName(), AuditActionCreate))
+			action = &LastAction{Action: AuditActionCreate, Revision: pr.GetName(), Reason: LastActionReasonNewRevision}
+		}
+		if created && r.countCreated {
+			// The count lives on the package, so it survives garbage
+			// collection of the revisions it counts.
+			p.SetTotalRevisionsCreated(p.GetTotalRevisionsCreated() + 1)
+		}
+		if activated {
+			r.audit.Record(ctx, r.auditEvent(p, pr.GetName(), AuditActionActivate))
+			r.notifyActivation(ctx, p, previouslyActive, pr.GetName(), digest)
+			action = &LastAction{Action: AuditActionActivate, Revision: pr.GetName(), Reason: activationReason(*ap)}
+		}
+		if p.GetCondition(v1.TypeRevisionUpdated).Status == corev1.ConditionFalse {
+			status.MarkConditions(v1.RevisionUpdated())
+		}
+	case kerrors.IsConflict(err):
+		return reconcile.Result{Requeue: true}, nil
+	case immutableFieldError(err) && r.immutableFieldPolicy == ImmutableFieldPolicyRecreate:
+		// Delete the revision. We'll create it again with the updated
+		// immutable fields once it's gone. Orphan the objects it
+		// installed, so that deleting it doesn't garbage collect its
+		// CRDs, and with them every custom resource of their kinds. The
+		// recreated revision takes ownership of them again.
+		log.Debug("Recreating package revision to update immutable fields", "revision", pr.GetName(), "error", err)
+		if err := r.client.Delete(ctx, pr, client.PropagationPolicy(metav1.DeletePropagationOrphan)); resource.IgnoreNotFound(err) != nil {
+			err = errors.Wrap(err, errRecreatePackageRevision)
+			r.record.Event(p, event.Warning(reasonInstall, err))
+			return reconcile.Result{}, err
+		}
+		r.record.Event(p, event.Normal(reasonInstall, "Deleted package revision in order to recreate it with updated immutable fields"))
+		return reconcile.Result{Requeue: true}, nil
+	case immutableFieldError(err):
+		// Leave the revision as is, and let the user know its immutable
+		// fields don't match the package.
+		err = errors.Wrap(err, errApplyPackageRevision)
+		r.record.Event(p, event.Warning(reasonInstall, err))
+		status.MarkConditions(v1.RevisionUpdateSkipped(err))
+		skipped = true
+	default:
+		err = errors.Wrap(err, errApplyPackageRevision)
+		r.record.Event(p, event.Warning(reasonInstall, err))
+		return reconcile.Result{}, err
+	}
+
+	// Handle changes in labels. We can't update a revision we couldn't
+	// apply.
+	same := reflect.DeepEqual(pr.GetCommonLabels(), commonLabels)
+	if !same && !skipped {
+		pr.SetCommonLabels(commonLabels)
+		if err := r.client.Update(ctx, pr); err != nil {
+			if kerrors.IsConflict(err) {
+				return reconcile.Result{Requeue: true}, nil
+			}
+			err = errors.Wrap(err, errApplyPackageRevision)
+			r.record.Event(p, event.Warning(reasonInstall, err))
+			return reconcile.Result{}, err
+		}
+	}
+
+	// Record the SBOMs of a revision we just created in its status. We patch
+	// rather than update the status so that we don't conflict with the
+	// revision reconciler, which starts reconciling the revision as soon as
+	// it's created.
+	if created && !skipped && len(sboms) > 0 {
+		orig := pr.DeepCopyObject().(client.Object) //nolint:forcetypeassert // Guaranteed to satisfy client.Object.
+		pr.SetSBOMReferences(sboms)
+		if err := r.client.Status().Patch(ctx, pr, client.MergeFrom(orig)); err != nil {
+			err = errors.Wrapf(err, errFmtRecordSBOMs, pr.GetName())
+			r.record.Event(p, event.Warning(reasonInstall, err))
+			return reconcile.Result{}, err
+		}
+	}
+
+	// Record the digest, size, and platform of the image the revision was
+	// resolved to, and how we resolved it, if we inspected it.
+	prov := provenance(p, source, rewriteConfigName, digest)
+	if image != nil && !skipped && (pr.GetResolvedDigest() != image.Digest || pr.GetResolvedPlatform() != image.Platform || !ptr.Equal(pr.GetResolvedImageSize(), image.Size) || !slices.Equal(pr.GetProvenance(), prov)) {
+		pr.SetResolvedDigest(image.Digest)
+		pr.SetResolvedPlatform(image.Platform)
+		pr.SetResolvedImageSize(image.Size)
+		pr.SetProvenance(prov)
+		if err := r.client.Status().Update(ctx, pr); err != nil {
+			if kerrors.IsConflict(err) {
+				return reconcile.Result{Requeue: true}, nil
+			}
+			err = errors.Wrap(err, errUpdateRevisionStatus)
+			r.record.Event(p, event.Warning(reasonInstall, err))
+			return reconcile.Result{}, err
+		}
+	}
+
+	// Make sure the Lock inventories every active revision. The revision
+	// reconciler records revisions that resolve their dependencies - even
+	// if they have none - but not revisions that skip resolving them.
+	resolvesDependencies := pr.GetSkipDependencyResolution() != nil && !*pr.GetSkipDependencyResolution()
+	if r.lock != nil && !skipped && !resolvesDependencies && pr.GetDesiredState() == v1.PackageRevisionActive {
+		if err := r.lock.RecordRevision(ctx, pr); err != nil {
+			if kerrors.IsConflict(err) {
+				return reconcile.Result{Requeue: true}, nil
+			}
+			err = errors.Wrap(err, errRecordLock)
+			r.record.Event(p, event.Warning(reasonInstall, err))
+			return reconcile.Result{}, err
+		}
+	}
+
+	if r.gcOrder != GCOrderCollectFirst {
+		if err := collectGarbage(); err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
+	status.MarkConditions(v1.Active().WithMessage(defaultedMsg))
+
+	// If current revision is still not active, the package is inactive.
+	switch {
+	case quotaExceeded:
+		status.MarkConditions(v1.QuotaExceeded(tenant, quota))
+	case runtimeConfigMissing:
+		status.MarkConditions(v1.RuntimeConfigMissing(runtimeConfig))
+	case len(gcDependencies) > 0:
+		status.MarkConditions(v1.DependencyGarbageCollecting(gcDependencies...))
+	case lastGood != nil:
+		status.MarkConditions(v1.Active().WithMessage(fmt.Sprintf("Current revision %q is unhealthy, so the last healthy revision %q remains active", pr.GetName(), lastGood.GetName())))
+	case retained != nil && activationWait > 0:
+		status.MarkConditions(v1.Active().WithMessage(fmt.Sprintf("Revision %q remains active until current revision %q's activation delay elapses", retained.GetName(), pr.GetName())))
+	case pr.GetDesiredState() != v1.PackageRevisionActive:
+		msg := "Package is inactive"
+		if activationWait > 0 {
+			msg = "Package is inactive until its current revision's activation delay elapses"
+		}
+		if defaultedMsg != "" {
+			msg += ". " + defaultedMsg
+		}
+		status.MarkConditions(v1.Inactive().WithMessage(msg))
+	}
+
+	// Summarize how an inactive current revision differs from the revision
+	// that was active when it became current, to help decide whether to
+	// activate it. That revision may since have been deactivated, e.g. by
+	// the Manual activation policy, so we remember it using the diff.
+	existing := p.GetRevisionDiff()
+	p.SetRevisionDiff(nil)
+	base := wasActive
+	if base == nil && existing != nil && !becameCurrent {
+		base = namedRevision(revisions, existing.PreviousRevision)
+	}
+	if pr.GetDesiredState() != v1.PackageRevisionActive && base != nil {
+		// We can only report added and removed objects once the revision
+		// reconciler has reported the objects the current revision
+		// installs.
+		d := &v1.RevisionDiff{PreviousRevision: base.GetName()}
+		if len(pr.GetObjects()) > 0 {
+			d = revisionDiff(base, pr)
+		}
+		// Comparing contents requires fetching both package images, so
+		// only do it when the revisions we compare change.
+		if !becameCurrent && existing != nil && existing.PreviousRevision == base.GetName() {
+			d.ChangedObjects = existing.ChangedObjects
+		} else {
+			d.ChangedObjects = r.changedObjects(ctx, base, pr, image, log, secrets...)
+		}
+		p.SetRevisionDiff(d)
+	}
+
+	// Summarize the package's revisions, including the current revision we
+	// may have just created, but not the revision we may have just deleted.
+	summarize := []v1.PackageRevision{pr}
+	for _, rev := range revisions {
+		if rev.GetName() != pr.GetName() && !slices.Contains(deleted, rev.GetName()) {
+			summarize = append(summarize, rev)
+		}
+	}
+	p.SetRevisionSummaries(revisionSummaries(summarize))
+
+	// List the current revision's dependencies, as resolved by the revision
+	// reconciler. This is informational, so we keep the dependencies we
+	// last listed if we can't list them now.
+	if r.dependencies != nil {
+		if deps, err := r.dependencies.ResolvedDependencies(ctx, pr); err != nil {
+			log.Debug("Cannot list resolved dependencies", "error", err)
+		} else {
+			p.SetResolvedDependencies(deps)
+		}
+	}
+
+	res := pullBasedRequeue(pullPolicy(p))
+	if activationWait > 0 {
+		// Come back to activate the current revision once its activation
+		// delay elapses.
+		res = sooner(res, activationWait)
+	}
+	if quotaExceeded {
+		// Come back to check whether the tenant's other packages freed
+		// up some of its quota.
+		res = sooner(res, quotaRecheckInterval)
+	}
+	if runtimeConfigMissing {
+		// Come back to check whether the runtime config was created, in
+		// case we're not watching runtime configs.
+		res = sooner(res, runtimeConfigRecheckInterval)
+	}
+	if len(gcDependencies) > 0 {
+		// Come back to activate the current revision once its
+		// dependencies finish garbage collecting.
+		res = sooner(res, dependencyGCRecheckInterval)
+	}
+	if len(establishing) > 0 {
+		// Come back to check whether the CRDs are established. We don't
+		// watch CRDs, so we won't be requeued when they are.
+		res = sooner(res, establishingRecheckInterval)
+	}
+	if len(unhealthyMembers) > 0 {
+		// Come back to check whether the other members of the package's
+		// provider family became healthy. We don't watch them, so we
+		// won't be requeued when they do.
+		res = sooner(res, familyHealthRecheckInterval)
+	}
+	if stabilizing > 0 {
+		// Come back to report the package healthy once its revision has
+		// been healthy for long enough.
+		res = sooner(res, stabilizing)
+	}
+	if r.healthProbeInterval > 0 && probeHealth(pr, r.clock.Now()) {
+		// Come back soon to check whether the current revision's health
+		// settled, rather than waiting for the next poll.
+		res = sooner(res, r.healthProbeInterval)
+	}
+	if upgradeCheck > 0 {
+		// Come back to check for an upgrade again. We don't poll the
+		// package, so we wouldn't otherwise.
+		res = sooner(res, upgradeCheck)
+	}
+	res = nextPoll(p, res, r.clock)
+
+	// NOTE(hasheddan): when the first package revision is created for a
+	// package, the health of the package is not set until the revision reports
+	// its health. If updating from an existing revision, the package health
+	// will match the health of the old revision until the next reconcile.
+	if err := r.client.Status().Update(ctx, p); err != nil {
+		// The package was deleted while we reconciled it. There's nothing
+		// left to update, and we'll be told when it's deleted.
+		if kerrors.IsNotFound(err) {
+			log.Debug("Package was deleted before its status could be updated", "error", err)
+			return reconcile.Result{}, nil
+		}
+		// Remember the revision we resolved so that we don't need to resolve
+		// it again when we're requeued to retry the status update.
+		r.unpersisted.Store(p.GetUID(), unpersistedRevision{
+			generation: p.GetGeneration(),
+			source:     p.GetResolvedSource(),
+			name:       revisionName,
+			image:      image,
+		})
+		return reconcile.Result{}, errors.Wrap(err, errUpdateStatus)
+	}
+
+	// Annotate the package with the digest its current revision resolved to,
+	// and with the instance that reconciled it. We do this after updating the
+	// package's status, because updating the package overwrites the status we
+	// just set with what's stored.
+	if err := r.annotatePackage(ctx, p, digest, action); err != nil {
+		if kerrors.IsConflict(errors.Cause(err)) {
+			return reconcile.Result{Requeue: true}, nil
+		}
+		r.record.Event(p, event.Warning(reasonInstall, err))
+		return reconcile.Result{}, err
+	}
+
+	// Annotate the package with the SBOMs of its current revision. We do this
+	// after updating the package's status, because updating the package
+	// overwrites the status we just set with what's stored.
+	if r.sboms != nil && annotateSBOMs(p, pr.GetSBOMReferences()) {
+		if err := r.client.Update(ctx, p); err != nil {
+			if kerrors.IsConflict(err) {
+				return reconcile.Result{Requeue: true}, nil
+			}
+			return reconcile.Result{}, errors.Wrap(err, errAnnotateSBOMs)
+		}
+	}
+
+	r.synced.Store(p.GetName(), newSyncedPackage(p, resourceVersions(summarize)))
+
+	return res, nil
+}
+
+// health returns the supplied package's Healthy condition, and the revision
+// it was derived from. It's derived from the package's health source revision
+// if it names one, or the supplied current revision if not. The package is of
+// unknown health, derived from no revision, if its health source revision
+// isn't one of the supplied revisions.
+func (r *Reconciler) health(p v1.Package, current v1.PackageRevision, revs []v1.PackageRevision) (xpv1.Condition, v1.PackageRevision) {
+	name := ptr.Deref(p.GetHealthSourceRevision(), "")
+	if name == "" || name == current.GetName() {
+		return v1.PackageHealth(current), current
+	}
+	for _, rev := range revs {
+		if rev.GetName() == name {
+			return v1.PackageHealth(rev), rev
+		}
+	}
+	err := errors.Errorf(errFmtHealthSourceRevisionNotFound, name)
+	r.record.Event(p, event.Warning(reasonInstall, err))
+	return v1.UnknownHealth().WithMessage(err.Error()), nil
+}
+
+// healthyFor returns how long the supplied healthy revision has been
+// healthy, i.e. since its most recent health condition became healthy.
+func healthyFor(pr v1.PackageRevision, now time.Time) time.Duration {
+	since := pr.GetCondition(v1.TypeRevisionHealthy).LastTransitionTime.Time
+	if _, ok := pr.(v1.PackageRevisionWithRuntime); ok {
+		if t := pr.GetCondition(v1.TypeRuntimeHealthy).LastTransitionTime.Time; t.After(since) {
+			since = t
+		}
+	}
+	return now.Sub(since)
+}
+
+// establishingCRDs returns the names of the CRDs the supplied revision
+// controls that aren't yet established.
+func (r *Reconciler) establishingCRDs(ctx context.Context, pr v1.PackageRevision) ([]string, error) {
+	var names []string
+	for _, ref := range pr.GetObjects() {
+		if ref.APIVersion != extv1.SchemeGroupVersion.String() || ref.Kind != "CustomResourceDefinition" {
+			continue
+		}
+		crd := &extv1.CustomResourceDefinition{}
+		err := r.client.Get(ctx, types.NamespacedName{Name: ref.Name}, crd)
+		if resource.IgnoreNotFound(err) != nil {
+			return nil, errors.Wrapf(err, errFmtGetCRD, ref.Name)
+		}
+		// A CRD that doesn't exist yet is still being established.
+		if kerrors.IsNotFound(err) || !xcrd.IsEstablished(crd.Status) {
+			names = append(names, ref.Name)
+		}
+	}
+	return names, nil
+}
+
+// unhealthyFamilyMembers returns the names of the other packages in the
+// provider family of the supplied revision whose active revisions aren't
+// healthy. It returns nothing if the revision isn't a member of a family.
+func (r *Reconciler) unhealthyFamilyMembers(ctx context.Context, p v1.Package, pr v1.PackageRevision) ([]string, error) {
+	family := pr.GetLabels()[v1.LabelProviderFamily]
+	if family == "" {
+		return nil, nil
+	}
+	l := r.newPackageRevisionList()
+	if err := r.client.List(ctx, l, client.MatchingLabels{v1.LabelProviderFamily: family}); err != nil {
+		return nil, errors.Wrapf(err, errFmtListProviderFamily, family)
+	}
+	var names []string
+	for _, rev := range l.GetRevisions() {
+		member := rev.GetLabels()[v1.LabelParentPackage]
+		if member == "" || member == p.GetName() || rev.GetDesiredState() != v1.PackageRevisionActive {
+			continue
+		}
+		if v1.PackageHealth(rev).Status != corev1.ConditionTrue {
+			names = append(names, member)
+		}
+	}
+	slices.Sort(names)
+	return names, nil
+}
+
+// propagatedConditions returns the conditions of the supplied revision that
+// should be mirrored to its package, with their types prefixed.
+func (r *Reconciler) propagatedConditions(pr v1.PackageRevision) []xpv1.Condition {
+	cs := make([]xpv1.Condition, 0, len(r.propagateConditions))
+	for _, t := range r.propagateConditions {
+		c := pr.GetCondition(t)
+		c.Type = xpv1.ConditionType(r.propagatePrefix) + t
+		cs = append(cs, c)
+	}
+	return cs
+}
+
+// tenant returns the tenant the supplied package belongs to, if any.
+func (r *Reconciler) tenant(p v1.Package) (string, bool) {
+	if r.tenantLabel == "" {
+		return "", false
+	}
+	t, ok := p.GetLabels()[r.tenantLabel]
+	return t, ok && t != ""
+}
+
+// activeRevisionQuotaExceeded returns the supplied tenant's active revision
+// quota, and whether activating the supplied package's current revision would
+// exceed it. Active revisions of all kinds of package count toward the quota,
+// except the supplied package's own, because activating a package's current
+// revision deactivates its other revisions.
+func (r *Reconciler) activeRevisionQuotaExceeded(ctx context.Context, p v1.Package, tenant string) (int, bool, error) {
+	quota, ok, err := r.quota.ActiveRevisionQuota(ctx, tenant)
+	if err != nil || !ok {
+		return 0, false, err
+	}
+	own := reflect.TypeOf(r.newPackageRevisionList())
+	active := 0
+	for _, k := range []PackageKind{ProviderPackageKind, ConfigurationPackageKind, FunctionPackageKind} {
+		prs := k.NewPackageRevisionList()
+		if err := r.client.List(ctx, prs, client.MatchingLabels(map[string]string{r.tenantLabel: tenant})); err != nil {
+			return 0, false, errors.Wrap(err, errListRevisions)
+		}
+		for _, rev := range prs.GetRevisions() {
+			if reflect.TypeOf(prs) == own && rev.GetLabels()[v1.LabelParentPackage] == p.GetName() {
+				continue
+			}
+			if rev.GetDesiredState() == v1.PackageRevisionActive {
+				active++
+			}
+		}
+	}
+	return quota, active >= quota, nil
+}
+
+// orphan removes the supplied package's owner references from its revisions,
+// so that they're not garbage collected when the package is deleted.
+func (r *Reconciler) orphan(ctx context.Context, p v1.Package) error {
+	prs := r.newPackageRevisionList()
+	if err := r.client.List(ctx, prs, client.MatchingLabels(map[string]string{v1.LabelParentPackage: p.GetName()})); err != nil {
+		return errors.Wrap(err, errListRevisions)
+	}
+	for _, rev := range prs.GetRevisions() {
+		refs := rev.GetOwnerReferences()
+		orphaned := slices.DeleteFunc(slices.Clone(refs), func(ref metav1.OwnerReference) bool { return ref.UID == p.GetUID() })
+		if len(orphaned) == len(refs) {
+			continue
+		}
+		rev.SetOwnerReferences(orphaned)
+		if err := r.client.Update(ctx, rev); err != nil {
+			return errors.Wrap(err, errOrphanPackageRevision)
+		}
+	}
+	return nil
+}
+
+// annotatePackage annotates the supplied package with the supplied resolved
+// digest if the Reconciler annotates digests, removing the annotation if the
+// digest is empty. If the Reconciler has an instance id it also annotates the
+// package with it, and if it records last actions it annotates the package
+// with the supplied action, if any. The package is only updated if its
+// annotations changed.
+func (r *Reconciler) annotatePackage(ctx context.Context, p v1.Package, digest string, a *LastAction) error {
+	before := maps.Clone(p.GetAnnotations())
+	switch {
+	case !r.annotateDigest:
+	case digest == "":
+		meta.RemoveAnnotations(p, v1.AnnotationResolvedDigest)
+	default:
+		meta.AddAnnotations(p, map[string]string{v1.AnnotationResolvedDigest: digest})
+	}
+	if r.instanceID != "" {
+		meta.AddAnnotations(p, map[string]string{v1.AnnotationReconciledBy: r.instanceID})
+	}
+	if r.annotateLastAction && a != nil {
+		meta.AddAnnotations(p, map[string]string{v1.AnnotationLastAction: a.String()})
+	}
+	if maps.Equal(before, p.GetAnnotations()) {
+		return nil
+	}
+	return errors.Wrap(r.client.Update(ctx, p), errAnnotatePackage)
+}
+
+// migrateRevisionLabels relabels the supplied package's revisions that use the
+// legacy parent package label key to use the current key. Only revisions owned
+// by the package are relabeled.
+func (r *Reconciler) migrateRevisionLabels(ctx context.Context, p v1.Package) error {
+	prs := r.newPackageRevisionList()
+	if err := r.client.List(ctx, prs, client.HasLabels{r.legacyRevisionLabel}); err != nil {
+		return errors.Wrap(err, errListRevisions)
+	}
+	for _, rev := range prs.GetRevisions() {
+		owned := slices.ContainsFunc(rev.GetOwnerReferences(), func(ref metav1.OwnerReference) bool { return ref.UID == p.GetUID() })
+		if !owned {
+			continue
+		}
+		meta.RemoveLabels(rev, r.legacyRevisionLabel)
+		meta.AddLabels(rev, map[string]string{v1.LabelParentPackage: p.GetName()})
+		if err := r.client.Update(ctx, rev); err != nil {
+			return errors.Wrap(err, errMigrateRevisionLabels)
+		}
+	}
+	return nil
+}
+
+// rollback activates the supplied package's revision with the supplied
+// revision number, and deactivates all of its other revisions.
+func (r *Reconciler) rollback(ctx context.Context, p v1.Package, status conditions.ConditionSet, revs []v1.PackageRevision, n int64) (reconcile.Result, error) {
+	i := slices.IndexFunc(revs, func(rev v1.PackageRevision) bool { return rev.GetRevision() == n })
+	if i < 0 {
+		status.MarkConditions(v1.DesiredRevisionNotFound(n))
+		r.record.Event(p, event.Warning(reasonTransitionRevision, errors.Errorf(errFmtDesiredRevisionNotFound, n)))
+		return reconcile.Result{}, errors.Wrap(r.client.Status().Update(ctx, p), errUpdateStatus)
+	}
+	return r.activateOnly(ctx, p, status, revs, i, LastActionReasonRollback, errRollbackPackageRevision, "Rolled back to package revision %q")
+}
+
+// frozen reports the health of the supplied package's current revision, but
+// doesn't create, activate, or garbage collect any of its revisions. We watch
+// the freeze ConfigMap, so we don't need to check whether the freeze was
+// lifted periodically.
+func (r *Reconciler) frozen(ctx context.Context, p v1.Package, status conditions.ConditionSet, revs []v1.PackageRevision, reason string) (reconcile.Result, error) {
+	status.MarkConditions(v1.GlobalFreeze(reason))
+	if i := slices.IndexFunc(revs, func(rev v1.PackageRevision) bool { return rev.GetName() == p.GetCurrentRevision() }); i >= 0 {
+		status.MarkConditions(v1.PackageHealth(revs[i]))
+		status.MarkConditions(r.propagatedConditions(revs[i])...)
+	}
+	p.SetRevisionSummaries(revisionSummaries(revs))
+	if err := r.client.Status().Update(ctx, p); err != nil {
+		return reconcile.Result{}, errors.Wrap(err, errUpdateStatus)
+	}
+	return reconcile.Result{}, nil
+}
+
+// hold activates the supplied package's revision with the supplied name, and
+// deactivates all of its other revisions.
+func (r *Reconciler) hold(ctx context.Context, p v1.Package, status conditions.ConditionSet, revs []v1.PackageRevision, name string) (reconcile.Result, error) {
+	i := slices.IndexFunc(revs, func(rev v1.PackageRevision) bool { return rev.GetName() == name })
+	if i < 0 {
+		status.MarkConditions(v1.HeldRevisionNotFound(name))
+		r.record.Event(p, event.Warning(reasonTransitionRevision, errors.Errorf(errFmtHeldRevisionNotFound, name)))
+		return reconcile.Result{}, errors.Wrap(r.client.Status().Update(ctx, p), errUpdateStatus)
+	}
+	status.MarkConditions(v1.Held(name))
+	return r.activateOnly(ctx, p, status, revs, i, LastActionReasonHold, errHoldPackageRevision, "Holding package revision %q active")
+}
+
+// activateOnly activates the supplied package's i-th revision, and
+// deactivates all of its other revisions. It wraps errors transitioning
+// revisions with the supplied message, and records an event using the
+// supplied format, and a last action with the supplied reason, when it
+// activates the revision.
+func (r *Reconciler) activateOnly(ctx context.Context, p v1.Package, status conditions.ConditionSet, revs []v1.PackageRevision, i int, reason, errMsg, eventFmt string) (reconcile.Result, error) {
+	target := revs[i]
+	previouslyActive := otherActiveRevision(revs, target.GetName())
+	var action *LastAction
+
+	// Deactivate the other revisions before we activate the target, so that
+	// two revisions are never active at once.
+	transition := slices.Concat(slices.Delete(slices.Clone(revs), i, i+1), []v1.PackageRevision{target})
+	for _, rev := range transition {
+		want := v1.PackageRevisionInactive
+		if rev.GetName() == target.GetName() {
+			want = v1.PackageRevisionActive
+		}
+		if rev.GetDesiredState() == want {
+			continue
+		}
+		rev.SetDesiredState(want)
+		if err := r.client.Apply(ctx, rev, resource.MustBeControllableBy(p.GetUID())); err != nil {
+			if kerrors.IsConflict(err) {
+				return reconcile.Result{Requeue: true}, nil
+			}
+			err = errors.Wrap(err, errMsg)
+			r.record.Event(p, event.Warning(reasonTransitionRevision, err))
+			return reconcile.Result{}, err
+		}
+		if want == v1.PackageRevisionActive {
+			r.audit.Record(ctx, r.auditEvent(p, rev.GetName(), AuditActionActivate))
+			r.notifyActivation(ctx, p, previouslyActive, rev.GetName(), rev.GetResolvedDigest())
+			action = &LastAction{Action: AuditActionActivate, Revision: rev.GetName(), Reason: reason}
+			r.record.Event(p, event.Normal(reasonTransitionRevision, fmt.Sprintf(eventFmt, rev.GetName())))
+		}
+	}
+
+	status.MarkConditions(v1.PackageHealth(target), v1.Active())
+	status.MarkConditions(r.propagatedConditions(target)...)
+	p.SetRevisionSummaries(revisionSummaries(revs))
+	p.SetRevisionDiff(nil)
+	p.SetNextPollTime(nil)
+	if err := r.client.Status().Update(ctx, p); err != nil {
+		return reconcile.Result{}, errors.Wrap(err, errUpdateStatus)
+	}
+	if !r.annotateLastAction || action == nil {
+		return reconcile.Result{}, nil
+	}
+	meta.AddAnnotations(p, map[string]string{v1.AnnotationLastAction: action.String()})
+	return reconcile.Result{}, errors.Wrap(r.client.Update(ctx, p), errAnnotatePackage)
+}
+
+// newestHealthy returns the highest numbered healthy revision of the supplied
+// revisions, or nil if none are healthy.
+func newestHealthy(revs []v1.PackageRevision) v1.PackageRevision {
+	var newest v1.PackageRevision
+	for _, rev := range revs {
+		if v1.PackageHealth(rev).Status != corev1.ConditionTrue {
+			continue
+		}
+		if newest == nil || rev.GetRevision() > newest.GetRevision() {
+			newest = rev
+		}
+	}
+	return newest
+}
+
+// lastGoodRevision returns the highest numbered revision whose RevisionHealthy
+// condition is true if the named current revision's is false. It returns nil
+// if the current revision isn't known to be unhealthy - e.g. because it was
+// just created - or if no other revision is healthy.
+func lastGoodRevision(current string, revs []v1.PackageRevision) v1.PackageRevision {
+	unhealthy := slices.ContainsFunc(revs, func(rev v1.PackageRevision) bool {
+		return rev.GetName() == current && rev.GetCondition(v1.TypeRevisionHealthy).Status == corev1.ConditionFalse
+	})
+	if !unhealthy {
+		return nil
+	}
+	var good v1.PackageRevision
+	for _, rev := range revs {
+		if rev.GetName() == current || rev.GetCondition(v1.TypeRevisionHealthy).Status != corev1.ConditionTrue {
+			continue
+		}
+		if good == nil || rev.GetRevision() > good.GetRevision() {
+			good = rev
+		}
+	}
+	return good
+}
+
+// activeRevisions returns the names of the supplied revisions that are active.
+func activeRevisions(revs []v1.PackageRevision) []string {
+	var names []string
+	for _, rev := range revs {
+		if rev.GetDesiredState() == v1.PackageRevisionActive {
+			names = append(names, rev.GetName())
+		}
+	}
+	return names
+}
+
+// latestActiveRevision returns the highest numbered active revision other
+// than the named one, or nil if there is none.
+func latestActiveRevision(revs []v1.PackageRevision, name string) v1.PackageRevision {
+	var latest v1.PackageRevision
+	for _, rev := range revs {
+		if rev.GetName() == name || rev.GetDesiredState() != v1.PackageRevisionActive {
+			continue
+		}
+		if latest == nil || rev.GetRevision() > latest.GetRevision() {
+			latest = rev
+		}
+	}
+	return latest
+}
+
+// namedRevision returns the revision with the supplied name, or nil if there
+// is none.
+func namedRevision(revs []v1.PackageRevision, name string) v1.PackageRevision {
+	for _, rev := range revs {
+		if rev.GetName() == name {
+			return rev
+		}
+	}
+	return nil
+}
+
+// otherActiveRevision returns the name of an active revision other than the
+// named one, or an empty string if there is none.
+func otherActiveRevision(revs []v1.PackageRevision, name string) string {
+	for _, n := range activeRevisions(revs) {
+		if n != name {
+			return n
+		}
+	}
+	return ""
+}
+
+// disagreeingActiveRevision returns the supplied package's only active
+// revision if it's healthy, and the package's current revision isn't active
+// even though the package's activation policy would have activated it. It
+// returns nil if the package's current revision and active revision agree.
+// Under the Manual and HighestHealthy activation policies an inactive current
+// revision is expected, unless the current revision doesn't exist.
+func (r *Reconciler) disagreeingActiveRevision(p v1.Package, revs []v1.PackageRevision) v1.PackageRevision {
+	current := p.GetCurrentRevision()
+	if current == "" {
+		return nil
+	}
+	var active []v1.PackageRevision
+	exists := false
+	for _, rev := range revs {
+		if rev.GetDesiredState() == v1.PackageRevisionActive {
+			active = append(active, rev)
+		}
+		exists = exists || rev.GetName() == current
+	}
+	if len(active) != 1 || active[0].GetName() == current {
+		return nil
+	}
+	if v1.PackageHealth(active[0]).Status != corev1.ConditionTrue {
+		return nil
+	}
+	// We report an invalid activation policy override when we resolve the
+	// package's activation policy later in the reconcile.
+	ap, _ := activationPolicy(p)
+	if ap == nil {
+		ap = ptr.To(cmp.Or(r.defaultActivationPolicy, v1.AutomaticActivation))
+	}
+	if exists && *ap != v1.AutomaticActivation {
+		return nil
+	}
+	return active[0]
+}
+
+// hasRevision returns true if the supplied list contains the named revision.
+func hasRevision(l v1.PackageRevisionList, name string) bool {
+	return slices.ContainsFunc(l.GetRevisions(), func(rev v1.PackageRevision) bool {
+		return rev.GetName() == name
+	})
+}
+
+// garbageCollect concurrently deletes the supplied revisions. A failure to
+// delete one revision doesn't stop it deleting the others. It returns which
+// revisions it did and didn't delete, and an error aggregating any errors it
+// encountered.
+func (r *Reconciler) garbageCollect(ctx context.Context, revs []v1.PackageRevision) (*v1.GarbageCollectionResult, error) {
+	errs := make([]error, len(revs))
+
+	g := &errgroup.Group{}
+	g.SetLimit(max(r.maxConcurrentDeletes, 1))
+	for i, rev := range revs {
+		g.Go(func() error {
+			// We don't return errors so that a failure to delete one
+			// revision doesn't cancel deleting the others. A revision
+			// that's already gone was garbage collected.
+			errs[i] = errors.Wrapf(resource.IgnoreNotFound(r.client.Delete(ctx, rev)), errFmtDeleteRevision, rev.GetName())
+			return nil
+		})
+	}
+	_ = g.Wait()
+
+	gc := &v1.GarbageCollectionResult{Time: ptr.To(metav1.NewTime(r.clock.Now()))}
+	for i, rev := range revs {
+		if errs[i] != nil {
+			gc.Failed = append(gc.Failed, rev.GetName())
+			continue
+		}
+		gc.Collected = append(gc.Collected, rev.GetName())
 	}
+	gc.CollectedCount = int64(len(gc.Collected))
+	return gc, errors.Join(errs...)
+}
 
-	if revisionName == "" {
-		status.MarkConditions(v1.Unpacking().WithMessage("Waiting for unpack to complete"))
-		r.record.Event(p, event.Normal(reasonUnpack, "Waiting for unpack to complete"))
-		return reconcile.Result{Requeue: true}, errors.Wrap(r.client.Status().Update(ctx, p), errUpdateStatus)
+// auditEvent returns an audit event recording that the Reconciler took the
+// supplied action on the supplied revision of the supplied package.
+func (r *Reconciler) auditEvent(p v1.Package, revision string, a AuditAction) AuditEvent {
+	return AuditEvent{
+		Package:  *meta.TypedReferenceTo(p, p.GetObjectKind().GroupVersionKind()),
+		Revision: revision,
+		Action:   a,
+		Actor:    r.actor,
 	}
+}
 
-	// Set the current revision and identifier.
-	p.SetCurrentRevision(revisionName)
-	// Use the original source as the identifier, even if it was rewritten by
-	// ImageConfig. The revisioning and dependency resolution logic are all
-	// based on the original package sources, so it's important that we preserve
-	// the original until it's time to actually pull an image.
-	p.SetCurrentIdentifier(p.GetSource())
+// notifyActivation notifies the Reconciler's ActivationNotifier, if any, that
+// the supplied package activated the supplied revision, replacing the
+// supplied previously active revision. Failures to notify are logged, but
+// don't fail the reconcile.
+func (r *Reconciler) notifyActivation(ctx context.Context, p v1.Package, previous, revision, digest string) {
+	if r.notifier == nil {
+		return
+	}
+	n := ActivationNotification{
+		Package:          *meta.TypedReferenceTo(p, p.GetObjectKind().GroupVersionKind()),
+		PreviousRevision: previous,
+		Revision:         revision,
+		Digest:           digest,
+	}
+	if err := r.notifier.Notify(ctx, n); err != nil {
+		r.log.Info("Cannot notify that package activated revision", "package", p.GetName(), "revision", revision, "error", err)
+	}
+}
 
-	pr := r.newPackageRevision()
-	maxRevision := int64(0)
-	oldestRevision := int64(math.MaxInt64)
-	oldestRevisionIndex := -1
-	revisions := prs.GetRevisions()
+// revisionSummaries summarizes the supplied revisions, most recent first. At
+// most maxRevisionSummaries revisions are summarized.
+func revisionSummaries(revs []v1.PackageRevision) []v1.RevisionSummary {
+	sorted := slices.Clone(revs)
+	slices.SortFunc(sorted, func(a, b v1.PackageRevision) int {
+		return cmp.Compare(b.GetRevision(), a.GetRevision())
+	})
+	if len(sorted) > maxRevisionSummaries {
+		sorted = sorted[:maxRevisionSummaries]
+	}
+	s := make([]v1.RevisionSummary, len(sorted))
+	for i, rev := range sorted {
+		s[i] = v1.RevisionSummary{
+			Name:         rev.GetName(),
+			Revision:     rev.GetRevision(),
+			DesiredState: rev.GetDesiredState(),
+			Healthy:      v1.PackageHealth(rev).Status,
+		}
+	}
+	return s
+}
 
-	// Check to see if revision already exists.
-	for index, rev := range revisions {
-		revisionNum := rev.GetRevision()
+// revisionDiff returns the objects installed by the current revision but not
+// by the previous revision, and vice versa. Objects are identified by their
+// group, kind, and name.
+func revisionDiff(previous, current v1.PackageRevision) *v1.RevisionDiff {
+	prev := make(map[string]bool, len(previous.GetObjects()))
+	for _, ref := range previous.GetObjects() {
+		prev[objectID(ref)] = true
+	}
+	cur := make(map[string]bool, len(current.GetObjects()))
+	for _, ref := range current.GetObjects() {
+		cur[objectID(ref)] = true
+	}
 
-		// Set max revision to the highest numbered existing revision.
-		if revisionNum > maxRevision {
-			maxRevision = revisionNum
+	d := &v1.RevisionDiff{PreviousRevision: previous.GetName()}
+	for _, ref := range current.GetObjects() {
+		if !prev[objectID(ref)] {
+			d.AddedObjects = append(d.AddedObjects, xpv1.TypedReference{APIVersion: ref.APIVersion, Kind: ref.Kind, Name: ref.Name})
+		}
+	}
+	for _, ref := range previous.GetObjects() {
+		if !cur[objectID(ref)] {
+			d.RemovedObjects = append(d.RemovedObjects, xpv1.TypedReference{APIVersion: ref.APIVersion, Kind: ref.Kind, Name: ref.Name})
 		}
+	}
+	return d
+}
 
-		// Set oldest revision to the lowest numbered revision and
-		// record its index.
-		if revisionNum < oldestRevision {
-			oldestRevision = revisionNum
-			oldestRevisionIndex = index
+// changedObjects returns the objects that differ between the contents of the
+// supplied previous and current revisions. It uses the supplied image's
+// contents as the current revision's, if any. It returns nil if the Reconciler
+// can't fetch revision contents.
+func (r *Reconciler) changedObjects(ctx context.Context, previous, current v1.PackageRevision, image *ImageInfo, log logging.Logger, secrets ...string) []xpv1.TypedReference {
+	if r.contents == nil {
+		return nil
+	}
+	prev, err := r.contents.RevisionContents(ctx, previous, secrets...)
+	if err != nil {
+		log.Debug("Cannot fetch contents of previous package revision", "revision", previous.GetName(), "error", err)
+		return nil
+	}
+	var cur *parser.Package
+	if image != nil {
+		cur = image.Contents
+	}
+	if cur == nil {
+		if cur, err = r.contents.RevisionContents(ctx, current, secrets...); err != nil {
+			log.Debug("Cannot fetch contents of current package revision", "revision", current.GetName(), "error", err)
+			return nil
 		}
-		// If revision name is same as current revision, then revision
-		// already exists.
-		if rev.GetName() == p.GetCurrentRevision() {
-			pr = rev
-			// Finish iterating through all revisions to make sure
-			// all non-current revisions are inactive.
+	}
+	return changedObjects(prev, cur)
+}
+
+// controllerReference returns a controller reference to the supplied package,
+// suitable for its revisions.
+func controllerReference(p v1.Package) metav1.OwnerReference {
+	ref := meta.AsController(meta.TypedReferenceTo(p, p.GetObjectKind().GroupVersionKind()))
+	ref.BlockOwnerDeletion = ptr.To(true)
+	return ref
+}
+
+// protected returns true if the supplied revision of the supplied package must
+// not be garbage collected.
+func protected(p v1.Package, pr v1.PackageRevision) bool {
+	return pr.GetName() == p.GetCurrentRevision() || externallyManaged(pr)
+}
+
+// Why a package revision is protected from garbage collection.
+const (
+	protectionCurrent           = "current revision"
+	protectionExternallyManaged = "externally managed"
+	protectionNewestHealthy     = "newest healthy revision"
+	protectionLastGood          = "last healthy revision, kept active while the current revision is unhealthy"
+	protectionRetained          = "kept active while activating the current revision is deferred"
+	protectionUnknownHealth     = "health unknown"
+)
+
+// protection returns why the supplied protected revision of the supplied
+// package must not be garbage collected.
+func protection(p v1.Package, pr v1.PackageRevision) string {
+	if pr.GetName() == p.GetCurrentRevision() {
+		return protectionCurrent
+	}
+	return protectionExternallyManaged
+}
+
+// describeProtections describes the supplied protections, keyed by revision
+// name, sorted by revision name.
+func describeProtections(protections map[string]string) []string {
+	d := make([]string, 0, len(protections))
+	for _, name := range slices.Sorted(maps.Keys(protections)) {
+		d = append(d, fmt.Sprintf("%s (%s)", name, protections[name]))
+	}
+	return d
+}
+
+// authenticationFailed returns true if the supplied error indicates a registry
+// rejected a request because it couldn't authenticate it.
+func authenticationFailed(err error) bool {
+	terr := &transport.Error{}
+	return errors.As(err, &terr) && terr.StatusCode == http.StatusUnauthorized
+}
+
+// manifestNotFound returns true if the supplied error indicates that the
+// registry doesn't have the requested manifest, e.g. because its tag was
+// deleted.
+func manifestNotFound(err error) bool {
+	terr := &transport.Error{}
+	if !errors.As(err, &terr) {
+		return false
+	}
+	return terr.StatusCode == http.StatusNotFound || slices.ContainsFunc(terr.Errors, func(d transport.Diagnostic) bool {
+		return d.Code == transport.ManifestUnknownErrorCode
+	})
+}
+
+// healthyCurrentRevision returns the supplied package's current revision if
+// it's active and healthy, or nil if it isn't.
+func healthyCurrentRevision(p v1.Package, l v1.PackageRevisionList) v1.PackageRevision {
+	for _, rev := range l.GetRevisions() {
+		if rev.GetName() != p.GetCurrentRevision() {
 			continue
 		}
-		if rev.GetDesiredState() == v1.PackageRevisionActive {
-			// If revision is not the current revision, set to
-			// inactive. This should always be done, regardless of
-			// the package's revision activation policy.
-			rev.SetDesiredState(v1.PackageRevisionInactive)
-			if err := r.client.Apply(ctx, rev, resource.MustBeControllableBy(p.GetUID())); err != nil {
-				if kerrors.IsConflict(err) {
-					return reconcile.Result{Requeue: true}, nil
-				}
-				err = errors.Wrap(err, errUpdateInactivePackageRevision)
-				r.record.Event(p, event.Warning(reasonTransitionRevision, err))
-				return reconcile.Result{}, err
-			}
+		if rev.GetDesiredState() == v1.PackageRevisionActive && v1.PackageHealth(rev).Status == corev1.ConditionTrue {
+			return rev
 		}
 	}
+	return nil
+}
 
-	// The current revision should always be the highest numbered revision.
-	if pr.GetRevision() < maxRevision || maxRevision == 0 {
-		pr.SetRevision(maxRevision + 1)
+// warnIfSlow emits a warning event and log if the reconcile measured by the
+// supplied timer took more than the Reconciler's slow reconcile threshold of
+// its deadline.
+func (r *Reconciler) warnIfSlow(p v1.Package, log logging.Logger, t *phaseTimer) {
+	elapsed := t.Elapsed()
+	if elapsed <= time.Duration(r.slowReconcileThreshold*float64(reconcileTimeout)) {
+		return
 	}
+	phase, d := t.Dominant()
+	err := errors.Errorf(errFmtSlowReconcile, elapsed, int(r.slowReconcileThreshold*100), reconcileTimeout, phase, d)
+	log.Info("Slow reconcile", "elapsed", elapsed, "deadline", reconcileTimeout, "phase", phase, "phaseElapsed", d)
+	r.record.Event(p, event.Warning(reasonSlowReconcile, err))
+}
 
-	// Check to see if there are revisions eligible for garbage collection.
-	if p.GetRevisionHistoryLimit() != nil &&
-		*p.GetRevisionHistoryLimit() != 0 &&
-		len(revisions) > (int(*p.GetRevisionHistoryLimit())+1) {
-		gcRev := revisions[oldestRevisionIndex]
-		// Find the oldest revision and delete it.
-		if err := r.client.Delete(ctx, gcRev); err != nil {
-			err = errors.Wrap(err, errGCPackageRevision)
-			r.record.Event(p, event.Warning(reasonGarbageCollect, err))
-			return reconcile.Result{}, err
+// markIfStuck marks the supplied package Stuck if it has been unpacking for
+// longer than the Reconciler's stuck threshold. It should be called each time
+// the package fails to unpack.
+func (r *Reconciler) markIfStuck(p v1.Package, status conditions.ConditionSet) {
+	if r.stuckThreshold <= 0 {
+		return
+	}
+	now := r.clock.Now()
+	since, _ := r.unpackingSince.LoadOrStore(p.GetUID(), now)
+	elapsed := now.Sub(since.(time.Time)) //nolint:forcetypeassert // We only store time.Time.
+	if elapsed < r.stuckThreshold {
+		return
+	}
+	status.MarkConditions(v1.StuckUnpacking(elapsed.Round(time.Second)))
+}
+
+// errorCondition returns the Installed condition the Reconciler's error
+// condition rules map the supplied error to. It returns false if no rule
+// matches the error.
+func (r *Reconciler) errorCondition(ctx context.Context, err error) (xpv1.Condition, bool) {
+	if r.errorConditions == nil {
+		return xpv1.Condition{}, false
+	}
+	rs, rerr := r.errorConditions.ErrorConditionRules(ctx)
+	if rerr != nil {
+		// We still have the fetch error to surface, so just log that we
+		// couldn't get the rules.
+		r.log.Debug("Cannot get error condition rules", "error", rerr)
+		return xpv1.Condition{}, false
+	}
+	return rs.Condition(err)
+}
+
+// immutableFieldError returns true if the supplied error indicates an update
+// was rejected because it changed immutable fields.
+func immutableFieldError(err error) bool {
+	return kerrors.IsInvalid(err) && strings.Contains(err.Error(), "immutable")
+}
+
+// debugging returns true if the supplied package is annotated for debugging.
+func debugging(p v1.Package) bool {
+	return p.GetAnnotations()[v1.AnnotationDebug] == "true"
+}
+
+// revisionNames returns the names of the supplied revisions.
+func revisionNames(revs []v1.PackageRevision) []string {
+	names := make([]string, len(revs))
+	for i, rev := range revs {
+		names[i] = rev.GetName()
+	}
+	return names
+}
+
+// externallyManaged returns true if the supplied package revision is annotated
+// as being managed by something other than the package manager.
+func externallyManaged(pr v1.PackageRevision) bool {
+	return pr.GetAnnotations()[v1.AnnotationExternallyManaged] == "true"
+}
+
+// A truncatingConditionSet truncates the messages of the conditions it marks
+// to a maximum length.
+type truncatingConditionSet struct {
+	conditions.ConditionSet
+
+	max int
+}
+
+// MarkConditions marks the supplied conditions, truncating their messages.
+func (s *truncatingConditionSet) MarkConditions(c ...xpv1.Condition) {
+	for i := range c {
+		c[i].Message = truncate(c[i].Message, s.max)
+	}
+	s.ConditionSet.MarkConditions(c...)
+}
+
+// coreConditionTypes are the types of the conditions that report a package's
+// state. The Installed condition reports whether the package is active. A
+// cappingConditionSet never drops them.
+var coreConditionTypes = []xpv1.ConditionType{v1.TypeInstalled, v1.TypeHealthy, xpv1.TypeSynced, xpv1.TypeReady}
+
+// A cappingConditionSet caps the number of informational conditions - those
+// that aren't core conditions - of the package whose conditions it marks. It
+// drops the informational conditions that transitioned least recently.
+type cappingConditionSet struct {
+	conditions.ConditionSet
+
+	pkg v1.Package
+	max int
+}
+
+// MarkConditions marks the supplied conditions, then drops informational
+// conditions in excess of the cap.
+func (s *cappingConditionSet) MarkConditions(c ...xpv1.Condition) {
+	s.ConditionSet.MarkConditions(c...)
+
+	var core, info []xpv1.Condition
+	for _, cond := range s.pkg.GetConditions() {
+		if slices.Contains(coreConditionTypes, cond.Type) {
+			core = append(core, cond)
+			continue
 		}
+		info = append(info, cond)
+	}
+	if len(info) <= s.max {
+		return
 	}
 
-	health := v1.PackageHealth(pr)
-	if health.Status == corev1.ConditionTrue && p.GetCondition(v1.TypeHealthy).Status != corev1.ConditionTrue {
-		// NOTE(phisco): We don't want to spam the user with events if the
-		// package is already healthy.
-		r.record.Event(p, event.Normal(reasonInstall, "Successfully installed package revision"))
+	// Keep the informational conditions that transitioned most recently.
+	slices.SortStableFunc(info, func(a, b xpv1.Condition) int {
+		return b.LastTransitionTime.Compare(a.LastTransitionTime.Time)
+	})
+	s.pkg.CleanConditions()
+	s.pkg.SetConditions(append(core, info[:s.max]...)...)
+}
+
+// truncate the supplied message to at most n bytes, replacing its tail with an
+// ellipsis if it's too long. It doesn't split multi-byte characters.
+func truncate(msg string, n int) string {
+	const ellipsis = "..."
+	if len(msg) <= n {
+		return msg
 	}
-	status.MarkConditions(health)
+	if n <= len(ellipsis) {
+		return ellipsis[:n]
+	}
+	i := n - len(ellipsis)
+	for i > 0 && !utf8.RuneStart(msg[i]) {
+		i--
+	}
+	return msg[:i] + ellipsis
+}
 
-	if pr.GetUID() == "" && pullSecretConfig != "" {
-		// We only record this event if the revision is new, as we don't want to
-		// spam the user with events if the revision already exists.
-		log.Debug("Selected pull secret from image config store", "image", p.GetResolvedSource(), "pullSecretConfig", pullSecretConfig, "pullSecret", pullSecretFromConfig, "rewriteConfig", rewriteConfigName)
-		r.record.Event(p, event.Normal(reasonImageConfig, fmt.Sprintf("Selected pullSecret %q from ImageConfig %q for registry authentication", pullSecretFromConfig, pullSecretConfig)))
+// provenance returns, in order, how the supplied package's requested source
+// was resolved to the supplied source after alias expansion, then rewritten by
+// the named ImageConfig, if any, and finally resolved to the supplied digest.
+func provenance(p v1.Package, source, rewriteConfig, digest string) []v1.ProvenanceStep {
+	steps := []v1.ProvenanceStep{{Type: v1.ProvenanceStepRequested, Reference: p.GetSource()}}
+	if alias := p.GetResolvedAlias(); alias != "" {
+		steps = append(steps, v1.ProvenanceStep{Type: v1.ProvenanceStepAliasExpanded, Reference: source, Via: alias})
+	}
+	if rewriteConfig != "" {
+		steps = append(steps, v1.ProvenanceStep{Type: v1.ProvenanceStepRewritten, Reference: p.GetResolvedSource(), Via: rewriteConfig})
+	}
+	if digest != "" {
+		steps = append(steps, v1.ProvenanceStep{Type: v1.ProvenanceStepDigestResolved, Reference: digest})
+	}
+	return steps
+}
+
+// probeHealth returns true if the supplied revision's health is unknown, or
+// changed recently enough that it might change again.
+func probeHealth(pr v1.PackageRevision, now time.Time) bool {
+	c := pr.GetCondition(v1.TypeRevisionHealthy)
+	return c.Status == corev1.ConditionUnknown || now.Sub(c.LastTransitionTime.Time) < healthProbeWindow
+}
+
+// sooner returns the supplied result, requeued after the supplied duration if
+// that's sooner than the result would otherwise be requeued.
+func sooner(res reconcile.Result, d time.Duration) reconcile.Result {
+	if res.RequeueAfter == 0 || d < res.RequeueAfter {
+		res.RequeueAfter = d
 	}
+	return res
+}
 
-	// Create the non-existent package revision.
-	pr.SetName(revisionName)
-	pr.SetLabels(map[string]string{v1.LabelParentPackage: p.GetName()})
-	// Use the original source; the revision reconciler will rewrite it if
-	// needed. The revision reconciler also inserts packages into the dependency
-	// manager's lock, which must use the original source to ensure dependency
-	// packages have the expected names even when rewritten.
-	pr.SetSource(p.GetSource())
-	pr.SetPackagePullPolicy(p.GetPackagePullPolicy())
-	pr.SetPackagePullSecrets(p.GetPackagePullSecrets())
-	pr.SetIgnoreCrossplaneConstraints(p.GetIgnoreCrossplaneConstraints())
-	pr.SetSkipDependencyResolution(p.GetSkipDependencyResolution())
-	pr.SetCommonLabels(p.GetCommonLabels())
+// nextPoll records in the supplied package's status when it will next be
+// reconciled, according to the supplied result. If the package already has a
+// sooner reconcile scheduled we keep it, and requeue accordingly. Otherwise
+// we'd record a new time - and trigger another reconcile by updating the
+// package's status - every time we reconciled it.
+func nextPoll(p v1.Package, res reconcile.Result, c clock.PassiveClock) reconcile.Result {
+	if res.RequeueAfter <= 0 {
+		p.SetNextPollTime(nil)
+		return res
+	}
+	now := c.Now()
+	if t := p.GetNextPollTime(); t != nil && t.After(now) && t.Time.Before(now.Add(res.RequeueAfter)) {
+		res.RequeueAfter = t.Sub(now)
+		return res
+	}
+	// Times are serialized with second precision.
+	t := metav1.NewTime(now.Add(res.RequeueAfter)).Rfc3339Copy()
+	p.SetNextPollTime(&t)
+	return res
+}
 
-	pwr, pwok := p.(v1.PackageWithRuntime)
-	prwr, prok := pr.(v1.PackageRevisionWithRuntime)
-	if pwok && prok {
-		prwr.SetRuntimeConfigRef(pwr.GetRuntimeConfigRef())
-		prwr.SetTLSServerSecretName(pwr.GetTLSServerSecretName())
-		prwr.SetTLSClientSecretName(pwr.GetTLSClientSecretName())
+// pullPolicy returns the supplied package's effective pull policy. A package
+// that's pinned to a digest always has the same content, so there's no point
+// polling it for new content even if its pull policy is Always.
+func pullPolicy(p v1.Package) *corev1.PullPolicy {
+	if pinnedPullAlways(p) {
+		return ptr.To(corev1.PullIfNotPresent)
 	}
+	return p.GetPackagePullPolicy()
+}
 
-	// If the current revision is not active, and we have an automatic or
-	// undefined activation policy, always activate.
-	if pr.GetDesiredState() != v1.PackageRevisionActive && (p.GetActivationPolicy() == nil || *p.GetActivationPolicy() == v1.AutomaticActivation) {
-		pr.SetDesiredState(v1.PackageRevisionActive)
+// pinnedPullAlways returns true if the supplied package is pinned to a digest,
+// but has pull policy Always.
+func pinnedPullAlways(p v1.Package) bool {
+	pp := p.GetPackagePullPolicy()
+	if pp == nil || *pp != corev1.PullAlways {
+		return false
 	}
+	_, err := name.NewDigest(p.GetSource())
+	return err == nil
+}
 
-	controlRef := meta.AsController(meta.TypedReferenceTo(p, p.GetObjectKind().GroupVersionKind()))
-	controlRef.BlockOwnerDeletion = ptr.To(true)
-	meta.AddOwnerReference(pr, controlRef)
-	if err := r.client.Apply(ctx, pr, resource.MustBeControllableBy(p.GetUID())); err != nil {
-		if kerrors.IsConflict(err) {
-			return reconcile.Result{Requeue: true}, nil
+// sourceTag returns the tag the supplied package source explicitly specifies.
+// It returns an empty string if the source doesn't specify a tag, for example
+// because it's pinned to a digest.
+func sourceTag(source string) string {
+	t, err := name.NewTag(source)
+	if err != nil || !strings.HasSuffix(source, ":"+t.TagStr()) {
+		return ""
+	}
+	return t.TagStr()
+}
+
+// creationReason returns why a revision of a package with the supplied
+// revisions and source is being created. A revision's source is that of its
+// package when the revision was created, so if the package already has a
+// revision with the same source its tag must have moved.
+func creationReason(revs []v1.PackageRevision, source string) string {
+	if len(revs) == 0 {
+		return v1.CreationReasonInitial
+	}
+	for _, rev := range revs {
+		if rev.GetSource() == source {
+			return v1.CreationReasonTagMoved
 		}
-		err = errors.Wrap(err, errApplyPackageRevision)
-		r.record.Event(p, event.Warning(reasonInstall, err))
-		return reconcile.Result{}, err
 	}
+	return v1.CreationReasonSourceChanged
+}
 
-	// Handle changes in labels
-	same := reflect.DeepEqual(pr.GetCommonLabels(), p.GetCommonLabels())
-	if !same {
-		pr.SetCommonLabels(p.GetCommonLabels())
-		if err := r.client.Update(ctx, pr); err != nil {
-			if kerrors.IsConflict(err) {
-				return reconcile.Result{Requeue: true}, nil
-			}
-			err = errors.Wrap(err, errApplyPackageRevision)
-			r.record.Event(p, event.Warning(reasonInstall, err))
-			return reconcile.Result{}, err
+// collectingRevisions returns the names of the supplied revisions that are
+// being deleted, as well as the supplied names of revisions that were just
+// deleted, sorted.
+func collectingRevisions(revs []v1.PackageRevision, deleted []string) []string {
+	names := slices.Clone(deleted)
+	for _, rev := range revs {
+		if rev.GetDeletionTimestamp() != nil {
+			names = append(names, rev.GetName())
 		}
 	}
+	slices.Sort(names)
+	return slices.Compact(names)
+}
 
-	status.MarkConditions(v1.Active())
+// resolvedDigest returns the digest the supplied package's source resolved
+// to, or an empty string if it's unknown. We know it if the Revisioner
+// resolved it, or if the package's source is pinned to a digest - e.g. when
+// the package's pull policy is Never and the Revisioner doesn't contact its
+// registry.
+func resolvedDigest(p v1.Package, image *ImageInfo) string {
+	if image != nil && image.Digest != "" {
+		return image.Digest
+	}
+	if d, err := name.NewDigest(p.GetResolvedSource()); err == nil {
+		return d.DigestStr()
+	}
+	return ""
+}
 
-	// If current revision is still not active, the package is inactive.
-	if pr.GetDesiredState() != v1.PackageRevisionActive {
-		status.MarkConditions(v1.Inactive().WithMessage("Package is inactive"))
+// activationDelayRemaining returns how much longer we must wait before
+// activating the supplied revision of the supplied package. The package's
+// activation delay is measured from when the revision was created, so we must
+// wait the full delay for a revision that doesn't exist yet.
+func activationDelayRemaining(p v1.Package, pr v1.PackageRevision, c clock.PassiveClock) time.Duration {
+	d := p.GetActivationDelay()
+	if d == nil || d.Duration <= 0 {
+		return 0
 	}
+	created := pr.GetCreationTimestamp()
+	if created.IsZero() {
+		return d.Duration
+	}
+	return max(d.Duration-c.Since(created.Time), 0)
+}
 
-	// NOTE(hasheddan): when the first package revision is created for a
-	// package, the health of the package is not set until the revision reports
-	// its health. If updating from an existing revision, the package health
-	// will match the health of the old revision until the next reconcile.
-	return pullBasedRequeue(p.GetPackagePullPolicy()), errors.Wrap(r.client.Status().Update(ctx, p), errUpdateStatus)
+// activationPolicy returns the supplied package's revision activation policy.
+// A valid policy set using the activation policy override annotation takes
+// precedence over the policy in the package's spec. It returns the policy in
+// the package's spec and an error if the override is invalid.
+func activationPolicy(p v1.Package) (*v1.RevisionActivationPolicy, error) {
+	o, ok := p.GetAnnotations()[v1.AnnotationActivationPolicyOverride]
+	if !ok {
+		return p.GetActivationPolicy(), nil
+	}
+	switch ap := v1.RevisionActivationPolicy(o); ap {
+	case v1.AutomaticActivation, v1.ManualActivation, v1.HighestHealthyActivation:
+		return &ap, nil
+	default:
+		return p.GetActivationPolicy(), errors.Errorf(errFmtInvalidActivationPolicyOverride, v1.AnnotationActivationPolicyOverride, o, v1.AutomaticActivation, v1.ManualActivation, v1.HighestHealthyActivation)
+	}
+}
+
+// An unpersistedRevision is a revision that was resolved for a package, but
+// that was not persisted to the package's status.
+type unpersistedRevision struct {
+	generation int64
+	source     string
+	name       string
+	image      *ImageInfo
+}
+
+// unpack resolves the supplied package's revision, then releases the
+// resolution it holds, even if resolving the revision panics.
+func (r *Reconciler) unpack(ctx context.Context, p v1.Package, extraPullSecrets ...string) (string, *ImageInfo, error) {
+	if r.resolutions != nil {
+		defer r.resolutions.Release(1)
+	}
+	return r.revision(ctx, p, extraPullSecrets...)
+}
+
+// revision returns the name of the supplied package's revision, and what we
+// know about the image it was resolved to. Resolving a revision may require a
+// round trip to the package's registry, so if we already resolved a revision
+// for this generation and source of the package but failed to persist it to
+// the package's status we return it instead. We only return it once, so that
+// we don't keep returning a stale revision if this reconcile fails too; it's
+// remembered again if persisting the package's status fails again.
+func (r *Reconciler) revision(ctx context.Context, p v1.Package, extraPullSecrets ...string) (string, *ImageInfo, error) {
+	if v, ok := r.unpersisted.LoadAndDelete(p.GetUID()); ok {
+		if u, ok := v.(unpersistedRevision); ok && u.generation == p.GetGeneration() && u.source == p.GetResolvedSource() {
+			return u.name, u.image, nil
+		}
+	}
+	if r.warmingUp(p) {
+		return p.GetCurrentRevision(), nil, nil
+	}
+	return r.pkg.Revision(ctx, p, extraPullSecrets...)
+}
+
+// checkForUpgrade resolves the source of the supplied package if its pull
+// policy is IfNotPresent, to tell whether the source now resolves to a
+// different revision than the package's current revision. It checks each
+// package at most once per upgrade check interval, and returns how long until
+// the package is due to be checked again, or zero if it's never checked. The
+// check is informational, so it doesn't return an error.
+func (r *Reconciler) checkForUpgrade(ctx context.Context, p v1.Package, status conditions.ConditionSet, log logging.Logger, extraPullSecrets ...string) time.Duration {
+	if r.upgradeCheckInterval <= 0 {
+		return 0
+	}
+	if pp := pullPolicy(p); pp == nil || *pp != corev1.PullIfNotPresent {
+		return 0
+	}
+	// A package that's pinned to a digest always resolves to the same
+	// content.
+	if _, err := name.NewDigest(p.GetSource()); err == nil {
+		return 0
+	}
+
+	now := r.clock.Now()
+	if v, ok := r.upgradeChecked.Load(p.GetUID()); ok {
+		if last, ok := v.(time.Time); ok && now.Sub(last) < r.upgradeCheckInterval {
+			return r.upgradeCheckInterval - now.Sub(last)
+		}
+	}
+	// Check again soon if too many packages are already being resolved.
+	if r.resolutions != nil {
+		if !r.resolutions.TryAcquire(1) {
+			return resolutionsSaturatedRequeue
+		}
+		defer r.resolutions.Release(1)
+	}
+	r.upgradeChecked.Store(p.GetUID(), now)
+
+	// Resolve the package's source as if it were polled.
+	pc, ok := p.DeepCopyObject().(v1.Package)
+	if !ok {
+		return r.upgradeCheckInterval
+	}
+	pc.SetPackagePullPolicy(ptr.To(corev1.PullAlways))
+	latest, image, err := r.pkg.Revision(ctx, pc, extraPullSecrets...)
+	if err != nil || latest == "" {
+		log.Debug("Cannot check whether package has an upgrade available", "error", err)
+		return r.upgradeCheckInterval
+	}
+
+	switch {
+	case latest != p.GetCurrentRevision():
+		var digest string
+		if image != nil {
+			digest = image.Digest
+		}
+		status.MarkConditions(v1.UpgradeAvailable(latest, digest))
+	case p.GetCondition(v1.TypeUpgradeAvailable).Reason == v1.ReasonNewerDigestAvailable:
+		status.MarkConditions(v1.UpToDate())
+	}
+	return r.upgradeCheckInterval
+}
+
+// warmingUp returns true if we can skip resolving the supplied package's
+// revision because we started recently and this is the first time we've
+// reconciled it. We only skip packages whose current revision is healthy,
+// whose source and spec haven't changed, and that aren't due to be polled.
+func (r *Reconciler) warmingUp(p v1.Package) bool {
+	if r.warmUp <= 0 {
+		return false
+	}
+	if _, seen := r.warmed.LoadOrStore(p.GetUID(), true); seen {
+		return false
+	}
+	now := r.clock.Now()
+	if now.Sub(r.started) > r.warmUp {
+		return false
+	}
+	if p.GetCurrentRevision() == "" || p.GetCurrentIdentifier() != p.GetSource() {
+		return false
+	}
+	// Resolve the revision of a package whose spec changed since we last
+	// reconciled it, e.g. because its pull policy changed, so the change
+	// takes effect immediately.
+	if c := p.GetCondition(v1.TypeHealthy); c.Status != corev1.ConditionTrue || c.ObservedGeneration != p.GetGeneration() {
+		return false
+	}
+	if t := p.GetNextPollTime(); t != nil && !t.After(now) {
+		return false
+	}
+	return true
+}
+
+// A syncedPackage records the state of a package and its revisions the last
+// time we fully reconciled it.
+type syncedPackage struct {
+	resourceVersion string
+	generation      int64
+	labels          map[string]string
+	annotations     map[string]string
+	finalizers      []string
+
+	// revisions maps the name of each of the package's revisions to its
+	// resource version.
+	revisions map[string]string
+}
+
+// newSyncedPackage records the state of the supplied package, whose revisions
+// have the supplied resource versions.
+func newSyncedPackage(p v1.Package, revisions map[string]string) syncedPackage {
+	return syncedPackage{
+		resourceVersion: p.GetResourceVersion(),
+		generation:      p.GetGeneration(),
+		labels:          maps.Clone(p.GetLabels()),
+		annotations:     maps.Clone(p.GetAnnotations()),
+		finalizers:      slices.Clone(p.GetFinalizers()),
+		revisions:       revisions,
+	}
+}
+
+// lastSynced returns the state of the supplied package and its revisions the
+// last time we fully reconciled it. It returns false if we can't skip fully
+// reconciling the package, regardless of whether the package changed. That
+// is the case unless the package is healthy and active, doesn't poll for new
+// content, and none of its revisions changed since we last fully reconciled
+// it. The package's resource version changes whenever its generation does, so
+// comparing the returned resource version to the package's also catches
+// metadata changes like annotations. Revisions are read individually from the
+// cache, which is cheaper than listing them by label. A revision that changed
+// or was deleted externally triggers a full reconcile.
+func (r *Reconciler) lastSynced(ctx context.Context, p v1.Package) (syncedPackage, bool) {
+	if pp := pullPolicy(p); pp != nil && *pp == corev1.PullAlways {
+		return syncedPackage{}, false
+	}
+	if p.GetCondition(v1.TypeHealthy).Status != corev1.ConditionTrue {
+		return syncedPackage{}, false
+	}
+	// We may be waiting to activate an inactive package's current revision.
+	if p.GetCondition(v1.TypeInstalled).Status != corev1.ConditionTrue {
+		return syncedPackage{}, false
+	}
+	// We scheduled a reconcile, e.g. to probe the package's health.
+	if p.GetNextPollTime() != nil {
+		return syncedPackage{}, false
+	}
+	v, ok := r.synced.Load(p.GetName())
+	if !ok {
+		return syncedPackage{}, false
+	}
+	s, ok := v.(syncedPackage)
+	if !ok {
+		return syncedPackage{}, false
+	}
+	if _, ok := s.revisions[p.GetCurrentRevision()]; !ok {
+		return syncedPackage{}, false
+	}
+	for name, rv := range s.revisions {
+		pr := r.newPackageRevision()
+		if err := r.client.Get(ctx, types.NamespacedName{Name: name}, pr); err != nil || pr.GetResourceVersion() != rv {
+			return syncedPackage{}, false
+		}
+	}
+	return s, true
+}
+
+// forget everything we track about the named package, which no longer
+// exists.
+func (r *Reconciler) forget(name string) {
+	r.synced.Delete(name)
+	if r.pullSecrets != nil {
+		r.pullSecrets.Delete(name)
+	}
+	if uid, ok := r.uids.LoadAndDelete(name); ok {
+		r.forgetUID(uid.(types.UID)) //nolint:forcetypeassert // We only store UIDs.
+	}
+}
+
+// forgetUID forgets everything we track about the package with the supplied
+// UID.
+func (r *Reconciler) forgetUID(uid types.UID) {
+	for _, m := range []*sync.Map{&r.warmed, &r.upgradeChecked, &r.unpackingSince, &r.unpersisted, &r.warnedPullAlways, &r.relabeled} {
+		m.Delete(uid)
+	}
+}
+
+// resourceVersions returns the resource version of each of the supplied
+// revisions, keyed by revision name.
+func resourceVersions(revs []v1.PackageRevision) map[string]string {
+	rvs := make(map[string]string, len(revs))
+	for _, rev := range revs {
+		rvs[rev.GetName()] = rev.GetResourceVersion()
+	}
+	return rvs
+}
+
+// revisionCommonLabels returns the common labels for the supplied package's
+// revisions. Labels set on the package take precedence over those set in the
+// revision spec template.
+func (r *Reconciler) revisionCommonLabels(p v1.Package) map[string]string {
+	if len(r.revisionTemplate.CommonLabels) == 0 {
+		return p.GetCommonLabels()
+	}
+	l := make(map[string]string, len(r.revisionTemplate.CommonLabels)+len(p.GetCommonLabels()))
+	maps.Copy(l, r.revisionTemplate.CommonLabels)
+	maps.Copy(l, p.GetCommonLabels())
+	return l
+}
+
+// setRevisionSpec sets the spec of the supplied package revision to the spec
+// we'd create it with for the supplied package and source.
+func (r *Reconciler) setRevisionSpec(p v1.Package, pr v1.PackageRevision, source string) {
+	// Use the original source, after resolving any alias; the revision
+	// reconciler will rewrite it if needed. The revision reconciler also
+	// inserts packages into the dependency manager's lock, which must use the
+	// original source to ensure dependency packages have the expected names
+	// even when rewritten.
+	pr.SetSource(source)
+	pr.SetPackagePullPolicy(orDefault(p.GetPackagePullPolicy(), r.revisionTemplate.PackagePullPolicy))
+	pr.SetPackagePullSecrets(p.GetPackagePullSecrets())
+	if len(pr.GetPackagePullSecrets()) == 0 {
+		pr.SetPackagePullSecrets(r.revisionTemplate.PackagePullSecrets)
+	}
+	pr.SetIgnoreCrossplaneConstraints(orDefault(p.GetIgnoreCrossplaneConstraints(), r.revisionTemplate.IgnoreCrossplaneConstraints))
+	pr.SetSkipDependencyResolution(orDefault(p.GetSkipDependencyResolution(), r.revisionTemplate.SkipDependencyResolution))
+
+	pwr, pwok := p.(v1.PackageWithRuntime)
+	prwr, prok := pr.(v1.PackageRevisionWithRuntime)
+	if pwok && prok {
+		prwr.SetRuntimeConfigRef(pwr.GetRuntimeConfigRef())
+		prwr.SetTLSServerSecretName(pwr.GetTLSServerSecretName())
+		prwr.SetTLSClientSecretName(pwr.GetTLSClientSecretName())
+	}
+}
+
+// orDefault returns v, or d if v is nil.
+func orDefault[T any](v, d *T) *T {
+	if v != nil {
+		return v
+	}
+	return d
 }
 
 func enqueueProvidersForImageConfig(kube client.Client, log logging.Logger) handler.EventHandler {
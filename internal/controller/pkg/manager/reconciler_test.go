@@ -18,15 +18,29 @@ package manager
 
 import (
 	"context"
+	"fmt"
 	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+	"golang.org/x/sync/semaphore"
 	corev1 "k8s.io/api/core/v1"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	testingclock "k8s.io/utils/clock/testing"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -35,19 +49,27 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/conditions"
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
 	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/feature"
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/parser"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
 
 	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+	"github.com/crossplane/crossplane/apis/pkg/v1beta1"
+	"github.com/crossplane/crossplane/internal/controller/pkg/controller"
+	"github.com/crossplane/crossplane/internal/features"
 	"github.com/crossplane/crossplane/internal/xpkg/fake"
+	"github.com/crossplane/crossplane/internal/xpkg/parser/yaml"
 )
 
 var _ Revisioner = &MockRevisioner{}
 
 type MockRevisioner struct {
-	MockRevision func() (string, error)
+	MockRevision    func() (string, error)
+	MockImageInfo   func() *ImageInfo
+	MockPullSecrets func(extra []string)
 }
 
 func NewMockRevisionFn(hash string, err error) func() (string, error) {
@@ -56,16 +78,174 @@ func NewMockRevisionFn(hash string, err error) func() (string, error) {
 	}
 }
 
-func (m *MockRevisioner) Revision(context.Context, v1.Package, ...string) (string, error) {
-	return m.MockRevision()
+func (m *MockRevisioner) Revision(_ context.Context, _ v1.Package, extraPullSecrets ...string) (string, *ImageInfo, error) {
+	if m.MockPullSecrets != nil {
+		m.MockPullSecrets(extraPullSecrets)
+	}
+	h, err := m.MockRevision()
+	if m.MockImageInfo == nil {
+		return h, nil, err
+	}
+	return h, m.MockImageInfo(), err
+}
+
+var _ ContentValidator = &MockContentValidator{}
+
+type MockContentValidator struct {
+	MockValidate func(contents *parser.Package) error
+}
+
+func (m *MockContentValidator) Validate(_ context.Context, _ v1.Package, contents *parser.Package) error {
+	return m.MockValidate(contents)
+}
+
+var _ RevisionContentsFetcher = &MockRevisionContentsFetcher{}
+
+type MockRevisionContentsFetcher struct {
+	MockRevisionContents func(pr v1.PackageRevision) (*parser.Package, error)
+}
+
+func (m *MockRevisionContentsFetcher) RevisionContents(_ context.Context, pr v1.PackageRevision, _ ...string) (*parser.Package, error) {
+	return m.MockRevisionContents(pr)
+}
+
+var _ ProvenanceVerifier = &MockProvenanceVerifier{}
+
+type MockProvenanceVerifier struct {
+	MockVerifyProvenance func(digest string) (bool, error)
+}
+
+func (m *MockProvenanceVerifier) VerifyProvenance(_ context.Context, _ v1.Package, digest string, _ ...string) (bool, error) {
+	return m.MockVerifyProvenance(digest)
+}
+
+var _ controller.Metrics = &MockMetrics{}
+
+type MockMetrics struct {
+	MockRecordFirstTimeHealthy func(p v1.Package)
+}
+
+func (m *MockMetrics) RecordFirstTimeHealthy(p v1.Package) {
+	m.MockRecordFirstTimeHealthy(p)
 }
 
 func TestReconcile(t *testing.T) {
 	errBoom := errors.New("boom")
 	testLog := logging.NewLogrLogger(zap.New(zap.UseDevMode(true), zap.WriteTo(io.Discard)).WithName("testlog"))
 	pullAlways := corev1.PullAlways
+	digest := "xpkg.crossplane.io/crossplane/test@sha256:ecc25c121431dfc7058754427f97c034ecde26d4aafa0da16d4fc1c6d1c2d07a"
 	trueVal := true
+	falseVal := false
 	revHistory := int64(1)
+	rollbackTo := int64(1)
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	errUnauthorized := &transport.Error{StatusCode: http.StatusUnauthorized, Errors: []transport.Diagnostic{{Code: transport.UnauthorizedErrorCode, Message: "authentication required"}}}
+	pp, err := yaml.New()
+	if err != nil {
+		t.Fatalf("yaml.New(): %v", err)
+	}
+	parse := func(stream string) *parser.Package {
+		pkg, err := pp.Parse(context.Background(), io.NopCloser(strings.NewReader(stream)))
+		if err != nil {
+			t.Fatalf("Parse(...): %v", err)
+		}
+		return pkg
+	}
+	clusterWidgets := parse(`
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: widgets.example.org
+spec:
+  group: example.org
+  names:
+    kind: Widget
+    plural: widgets
+  scope: Cluster
+`)
+	namespacedWidgets := parse(`
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: widgets.example.org
+spec:
+  group: example.org
+  names:
+    kind: Widget
+    plural: widgets
+  scope: Namespaced
+`)
+	errImmutable := kerrors.NewInvalid(schema.GroupKind{Group: v1.Group, Kind: v1.ConfigurationRevisionKind}, "test-1234567", field.ErrorList{
+		field.Invalid(field.NewPath("spec", "ignoreCrossplaneConstraints"), true, "field is immutable"),
+	})
+	deletedAt := metav1.NewTime(now)
+	customFinalizer := "package.example.org"
+	imageSize := int64(4096)
+	imageDigest := "sha256:ecc25c121431dfc7058754427f97c034ecde26d4aafa0da16d2ed5b6fc6d1b3f"
+	sbomRefs := make([]string, maxAnnotatedSBOMReferences+2)
+	for i := range sbomRefs {
+		sbomRefs[i] = fmt.Sprintf("xpkg.crossplane.io/crossplane/configuration-test@sha256:%064d", i)
+	}
+	emptyContents := parser.NewPackage()
+	deprecatedWidgets := parse(`
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: widgets.example.org
+spec:
+  group: example.org
+  names:
+    kind: Widget
+    plural: widgets
+  scope: Cluster
+  versions:
+  - name: v1alpha1
+    served: true
+    storage: false
+    deprecated: true
+  - name: v1
+    served: true
+    storage: true
+`)
+	providerMeta := parse(`
+apiVersion: meta.pkg.crossplane.io/v1
+kind: Provider
+metadata:
+  name: provider-test
+`)
+	configurationMeta := parse(`
+apiVersion: meta.pkg.crossplane.io/v1
+kind: Configuration
+metadata:
+  name: configuration-test
+`)
+	// Registries sometimes include their entire response body in errors.
+	errHuge := errors.New(strings.Repeat("<html>Service Unavailable</html>", 256))
+	// The package has many informational conditions. The higher the number,
+	// the more recently the condition transitioned.
+	informational := make([]commonv1.Condition, 5)
+	for i := range informational {
+		informational[i] = commonv1.Condition{
+			Type:               commonv1.ConditionType(fmt.Sprintf("Informational%d", i)),
+			Status:             corev1.ConditionTrue,
+			LastTransitionTime: metav1.NewTime(time.Unix(int64(i), 0)),
+			Reason:             "Testing",
+		}
+	}
+	familyMember := func(pkg string, state v1.PackageRevisionDesiredState, cs ...commonv1.Condition) v1.ProviderRevision {
+		pr := v1.ProviderRevision{ObjectMeta: metav1.ObjectMeta{
+			Name:   pkg + "-1234567",
+			Labels: map[string]string{v1.LabelParentPackage: pkg, v1.LabelProviderFamily: "family-aws"},
+		}}
+		pr.SetRevision(1)
+		pr.SetDesiredState(state)
+		pr.SetConditions(cs...)
+		return pr
+	}
+	runtimeConfigs := &feature.Flags{}
+	runtimeConfigs.Enable(features.EnableBetaDeploymentRuntimeConfigs)
+	errManifestUnknown := &transport.Error{StatusCode: http.StatusNotFound, Errors: []transport.Diagnostic{{Code: transport.ManifestUnknownErrorCode, Message: "manifest unknown"}}}
+	otherDigest := "sha256:fedcba0987654321fedcba0987654321fedcba0987654321fedcba0987654321"
 
 	type args struct {
 		req reconcile.Request
@@ -217,6 +397,48 @@ func TestReconcile(t *testing.T) {
 				err: errors.Wrap(errBoom, errGetPullConfig),
 			},
 		},
+		"PullSecretPending": {
+			reason: "We should requeue and report which pull secret we're waiting for if an image config selects a pull secret that does not exist.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: func(_ context.Context, _ client.ObjectKey, o client.Object) error {
+								if _, ok := o.(*corev1.Secret); ok {
+									return kerrors.NewNotFound(schema.GroupResource{}, "")
+								}
+								return nil
+							},
+							MockList: test.NewMockListFn(kerrors.NewNotFound(schema.GroupResource{}, "")),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								want := &v1.Configuration{}
+								want.SetConditions(v1.PullSecretPending("missing-secret", "image-config"))
+								if diff := cmp.Diff(want, o); diff != "" {
+									t.Errorf("-want, +got:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+					},
+					log:    testLog,
+					record: event.NewNopRecorder(),
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("image-config", "missing-secret", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					conditions: conditions.ObservedGenerationPropagationManager{},
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: true},
+			},
+		},
 		"ErrFetchRevision": {
 			reason: "We should return an error if fetching the revision for a package fails.",
 			args: args{
@@ -231,6 +453,7 @@ func TestReconcile(t *testing.T) {
 							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
 								want := &v1.Configuration{}
 								want.SetConditions(v1.Unpacking().WithMessage(errors.Wrap(errBoom, errUnpack).Error()))
+								want.SetLastResolverError(errors.Wrap(errBoom, errUnpack).Error())
 								if diff := cmp.Diff(want, o); diff != "" {
 									t.Errorf("-want, +got:\n%s", diff)
 								}
@@ -257,6 +480,44 @@ func TestReconcile(t *testing.T) {
 				err: errors.Wrap(errBoom, errUnpack),
 			},
 		},
+		"ErrFetchRevisionUnauthorized": {
+			reason: "We should report an authentication failure specifically if the registry rejects our credentials.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet:  test.NewMockGetFn(nil),
+							MockList: test.NewMockListFn(kerrors.NewNotFound(schema.GroupResource{}, "")),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								want := &v1.Configuration{}
+								want.SetConditions(v1.AuthenticationFailed().WithMessage(errors.Wrap(errUnauthorized, errUnpack).Error()))
+								want.SetLastResolverError(errors.Wrap(errUnauthorized, errUnpack).Error())
+								if diff := cmp.Diff(want, o); diff != "" {
+									t.Errorf("-want, +got:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+					},
+					log:    testLog,
+					record: event.NewNopRecorder(),
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("", errUnauthorized),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					conditions: conditions.ObservedGenerationPropagationManager{},
+				},
+			},
+			want: want{
+				err: errors.Wrap(errUnauthorized, errUnpack),
+			},
+		},
 		"SuccessfulRerwiteImage": {
 			reason: "We should record the rewritten image path if an image config is used.",
 			args: args{
@@ -288,6 +549,7 @@ func TestReconcile(t *testing.T) {
 									Name:   "imageConfigName",
 									Reason: v1.ImageConfigReasonRewrite,
 								})
+								want.SetRevisionSummaries([]v1.RevisionSummary{{Name: "test-1234567", Revision: 1, DesiredState: v1.PackageRevisionActive, Healthy: corev1.ConditionFalse}})
 								if diff := cmp.Diff(want, o); diff != "" {
 									t.Errorf("-want, +got:\n%s", diff)
 								}
@@ -308,6 +570,8 @@ func TestReconcile(t *testing.T) {
 					log:        testLog,
 					record:     event.NewNopRecorder(),
 					conditions: conditions.ObservedGenerationPropagationManager{},
+					metrics:    &controller.NopMetrics{},
+					audit:      NewNopAuditSink(),
 				},
 			},
 			want: want{
@@ -340,6 +604,7 @@ func TestReconcile(t *testing.T) {
 								want.SetActivationPolicy(&v1.AutomaticActivation)
 								want.SetConditions(v1.Unhealthy().WithMessage("Package revision health is \"Unknown\""))
 								want.SetConditions(v1.Active())
+								want.SetRevisionSummaries([]v1.RevisionSummary{{Name: "test-1234567", Revision: 1, DesiredState: v1.PackageRevisionActive, Healthy: corev1.ConditionFalse}})
 								if diff := cmp.Diff(want, o); diff != "" {
 									t.Errorf("-want, +got:\n%s", diff)
 								}
@@ -360,14 +625,16 @@ func TestReconcile(t *testing.T) {
 					log:        testLog,
 					record:     event.NewNopRecorder(),
 					conditions: conditions.ObservedGenerationPropagationManager{},
+					metrics:    &controller.NopMetrics{},
+					audit:      NewNopAuditSink(),
 				},
 			},
 			want: want{
 				r: reconcile.Result{Requeue: false},
 			},
 		},
-		"SuccessfulNoExistingRevisionsAutoActivatePullAlways": {
-			reason: "We should be active and requeue after wait on successful creation of the first revision with auto activation and package pull policy Always.",
+		"SuccessfulNoExistingRevisionsProbeHealth": {
+			reason: "We should requeue after the health probe interval when the current revision's health is unknown.",
 			args: args{
 				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
 				rec: &Reconciler{
@@ -381,7 +648,6 @@ func TestReconcile(t *testing.T) {
 								p.SetName("test")
 								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
 								p.SetActivationPolicy(&v1.AutomaticActivation)
-								p.SetPackagePullPolicy(&pullAlways)
 								return nil
 							}),
 							MockList: test.NewMockListFn(kerrors.NewNotFound(schema.GroupResource{}, "")),
@@ -391,10 +657,13 @@ func TestReconcile(t *testing.T) {
 								want.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
 								want.SetCurrentRevision("test-1234567")
 								want.SetActivationPolicy(&v1.AutomaticActivation)
-								want.SetPackagePullPolicy(&pullAlways)
 								want.SetConditions(v1.Unhealthy().WithMessage("Package revision health is \"Unknown\""))
 								want.SetConditions(v1.Active())
-								if diff := cmp.Diff(want, o); diff != "" {
+								want.SetRevisionSummaries([]v1.RevisionSummary{{Name: "test-1234567", Revision: 1, DesiredState: v1.PackageRevisionActive, Healthy: corev1.ConditionFalse}})
+								// We should record when we'll next reconcile.
+								want.SetNextPollTime(&metav1.Time{Time: now.Add(10 * time.Second)})
+								got := o.(*v1.Configuration)
+								if diff := cmp.Diff(want, got); diff != "" {
 									t.Errorf("-want, +got:\n%s", diff)
 								}
 								return nil
@@ -411,17 +680,21 @@ func TestReconcile(t *testing.T) {
 						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
 						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
 					},
-					log:        testLog,
-					record:     event.NewNopRecorder(),
-					conditions: conditions.ObservedGenerationPropagationManager{},
+					log:                 testLog,
+					record:              event.NewNopRecorder(),
+					conditions:          conditions.ObservedGenerationPropagationManager{},
+					metrics:             &controller.NopMetrics{},
+					audit:               NewNopAuditSink(),
+					clock:               testingclock.NewFakePassiveClock(now),
+					healthProbeInterval: 10 * time.Second,
 				},
 			},
 			want: want{
-				r: reconcile.Result{RequeueAfter: pullWait},
+				r: reconcile.Result{RequeueAfter: 10 * time.Second},
 			},
 		},
-		"SuccessfulNoExistingRevisionsManualActivate": {
-			reason: "We should be inactive and not requeue on successful creation of the first revision with manual activation policy.",
+		"SuccessfulNoExistingRevisionsAutoActivatePullAlways": {
+			reason: "We should be active and requeue after wait on successful creation of the first revision with auto activation and package pull policy Always.",
 			args: args{
 				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
 				rec: &Reconciler{
@@ -434,7 +707,8 @@ func TestReconcile(t *testing.T) {
 								p := o.(*v1.Configuration)
 								p.SetName("test")
 								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
-								p.SetActivationPolicy(&v1.ManualActivation)
+								p.SetActivationPolicy(&v1.AutomaticActivation)
+								p.SetPackagePullPolicy(&pullAlways)
 								return nil
 							}),
 							MockList: test.NewMockListFn(kerrors.NewNotFound(schema.GroupResource{}, "")),
@@ -442,11 +716,16 @@ func TestReconcile(t *testing.T) {
 								want := &v1.Configuration{}
 								want.SetName("test")
 								want.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
-								want.SetActivationPolicy(&v1.ManualActivation)
 								want.SetCurrentRevision("test-1234567")
+								want.SetActivationPolicy(&v1.AutomaticActivation)
+								want.SetPackagePullPolicy(&pullAlways)
 								want.SetConditions(v1.Unhealthy().WithMessage("Package revision health is \"Unknown\""))
-								want.SetConditions(v1.Inactive().WithMessage("Package is inactive"))
-								if diff := cmp.Diff(want, o); diff != "" {
+								want.SetConditions(v1.Active())
+								want.SetRevisionSummaries([]v1.RevisionSummary{{Name: "test-1234567", Revision: 1, DesiredState: v1.PackageRevisionActive, Healthy: corev1.ConditionFalse}})
+								// We should record when we'll next reconcile.
+								want.SetNextPollTime(&metav1.Time{Time: now.Add(pullWait)})
+								got := o.(*v1.Configuration)
+								if diff := cmp.Diff(want, got); diff != "" {
 									t.Errorf("-want, +got:\n%s", diff)
 								}
 								return nil
@@ -466,14 +745,17 @@ func TestReconcile(t *testing.T) {
 					log:        testLog,
 					record:     event.NewNopRecorder(),
 					conditions: conditions.ObservedGenerationPropagationManager{},
+					metrics:    &controller.NopMetrics{},
+					audit:      NewNopAuditSink(),
+					clock:      testingclock.NewFakePassiveClock(now),
 				},
 			},
 			want: want{
-				r: reconcile.Result{Requeue: false},
+				r: reconcile.Result{RequeueAfter: pullWait},
 			},
 		},
-		"SuccessfulActiveRevisionExists": {
-			reason: "We should match revision health and not requeue when active revision already exists.",
+		"SuccessfulNoExistingRevisionsPullAlwaysDigest": {
+			reason: "We should not requeue after wait when a package pinned to a digest has package pull policy Always.",
 			args: args{
 				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
 				rec: &Reconciler{
@@ -486,30 +768,26 @@ func TestReconcile(t *testing.T) {
 								p := o.(*v1.Configuration)
 								p.SetName("test")
 								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								p.SetActivationPolicy(&v1.AutomaticActivation)
+								p.SetPackagePullPolicy(&pullAlways)
+								p.SetSource(digest)
 								return nil
 							}),
-							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
-								l := o.(*v1.ConfigurationRevisionList)
-								cr := v1.ConfigurationRevision{
-									ObjectMeta: metav1.ObjectMeta{
-										Name: "test-1234567",
-									},
-								}
-								cr.SetConditions(v1.RevisionHealthy())
-								c := v1.ConfigurationRevisionList{
-									Items: []v1.ConfigurationRevision{cr},
-								}
-								*l = c
-								return nil
-							}),
+							MockList: test.NewMockListFn(kerrors.NewNotFound(schema.GroupResource{}, "")),
 							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
 								want := &v1.Configuration{}
 								want.SetName("test")
 								want.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
 								want.SetCurrentRevision("test-1234567")
-								want.SetConditions(v1.Healthy())
+								want.SetActivationPolicy(&v1.AutomaticActivation)
+								want.SetPackagePullPolicy(&pullAlways)
+								want.SetSource(digest)
+								want.SetCurrentIdentifier(digest)
+								want.SetResolvedSource(digest)
+								want.SetConditions(v1.Unhealthy().WithMessage("Package revision health is \"Unknown\""))
 								want.SetConditions(v1.Active())
-								if diff := cmp.Diff(want, o, test.EquateConditions()); diff != "" {
+								want.SetRevisionSummaries([]v1.RevisionSummary{{Name: "test-1234567", Revision: 1, DesiredState: v1.PackageRevisionActive, Healthy: corev1.ConditionFalse}})
+								if diff := cmp.Diff(want, o); diff != "" {
 									t.Errorf("-want, +got:\n%s", diff)
 								}
 								return nil
@@ -529,14 +807,16 @@ func TestReconcile(t *testing.T) {
 					log:        testLog,
 					record:     event.NewNopRecorder(),
 					conditions: conditions.ObservedGenerationPropagationManager{},
+					metrics:    &controller.NopMetrics{},
+					audit:      NewNopAuditSink(),
 				},
 			},
 			want: want{
 				r: reconcile.Result{Requeue: false},
 			},
 		},
-		"SuccessfulRevisionExistsNeedsActive": {
-			reason: "We should match revision health, set to active, and not requeue when inactive revision already exists and activation policy is automatic.",
+		"SuccessfulNoExistingRevisionsManualActivate": {
+			reason: "We should be inactive and not requeue on successful creation of the first revision with manual activation policy.",
 			args: args{
 				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
 				rec: &Reconciler{
@@ -549,56 +829,26 @@ func TestReconcile(t *testing.T) {
 								p := o.(*v1.Configuration)
 								p.SetName("test")
 								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								p.SetActivationPolicy(&v1.ManualActivation)
 								return nil
 							}),
-							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
-								l := o.(*v1.ConfigurationRevisionList)
-								cr := v1.ConfigurationRevision{
-									ObjectMeta: metav1.ObjectMeta{
-										Name: "test-1234567",
-									},
-								}
-								cr.SetGroupVersionKind(v1.ConfigurationRevisionGroupVersionKind)
-								cr.SetConditions(v1.RevisionHealthy())
-								cr.SetDesiredState(v1.PackageRevisionInactive)
-								cr.SetRevision(1)
-								c := v1.ConfigurationRevisionList{
-									Items: []v1.ConfigurationRevision{cr},
-								}
-								*l = c
-								return nil
-							}),
+							MockList: test.NewMockListFn(kerrors.NewNotFound(schema.GroupResource{}, "")),
 							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
 								want := &v1.Configuration{}
 								want.SetName("test")
 								want.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								want.SetActivationPolicy(&v1.ManualActivation)
 								want.SetCurrentRevision("test-1234567")
-								want.SetConditions(v1.Healthy())
-								want.SetConditions(v1.Active())
-								if diff := cmp.Diff(want, o, test.EquateConditions()); diff != "" {
+								want.SetConditions(v1.Unhealthy().WithMessage("Package revision health is \"Unknown\""))
+								want.SetConditions(v1.Inactive().WithMessage("Package is inactive"))
+								want.SetRevisionSummaries([]v1.RevisionSummary{{Name: "test-1234567", Revision: 1, DesiredState: v1.PackageRevisionInactive, Healthy: corev1.ConditionFalse}})
+								if diff := cmp.Diff(want, o); diff != "" {
 									t.Errorf("-want, +got:\n%s", diff)
 								}
 								return nil
 							}),
 						},
-						Applicator: resource.ApplyFn(func(_ context.Context, o client.Object, _ ...resource.ApplyOption) error {
-							want := &v1.ConfigurationRevision{}
-							want.SetLabels(map[string]string{"pkg.crossplane.io/package": "test"})
-							want.SetName("test-1234567")
-							want.SetOwnerReferences([]metav1.OwnerReference{{
-								APIVersion:         v1.SchemeGroupVersion.String(),
-								Kind:               v1.ConfigurationKind,
-								Name:               "test",
-								Controller:         &trueVal,
-								BlockOwnerDeletion: &trueVal,
-							}})
-							want.SetGroupVersionKind(v1.ConfigurationRevisionGroupVersionKind)
-							want.SetDesiredState(v1.PackageRevisionActive)
-							want.SetConditions(v1.RevisionHealthy())
-							want.SetRevision(1)
-							if diff := cmp.Diff(want, o, test.EquateConditions()); diff != "" {
-								t.Errorf("-want, +got:\n%s", diff)
-							}
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
 							return nil
 						}),
 					},
@@ -612,14 +862,16 @@ func TestReconcile(t *testing.T) {
 					log:        testLog,
 					record:     event.NewNopRecorder(),
 					conditions: conditions.ObservedGenerationPropagationManager{},
+					metrics:    &controller.NopMetrics{},
+					audit:      NewNopAuditSink(),
 				},
 			},
 			want: want{
 				r: reconcile.Result{Requeue: false},
 			},
 		},
-		"ErrUpdatePackageRevision": {
-			reason: "Failing to update a package revision should cause us to return an error.",
+		"SuccessfulActivationPolicyOverride": {
+			reason: "We should be inactive when the activation policy override annotation is Manual, even if the spec activation policy is Automatic.",
 			args: args{
 				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
 				rec: &Reconciler{
@@ -631,39 +883,30 @@ func TestReconcile(t *testing.T) {
 							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
 								p := o.(*v1.Configuration)
 								p.SetName("test")
+								p.SetAnnotations(map[string]string{v1.AnnotationActivationPolicyOverride: string(v1.ManualActivation)})
 								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								p.SetActivationPolicy(&v1.AutomaticActivation)
 								return nil
 							}),
-							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
-								l := o.(*v1.ConfigurationRevisionList)
-								cr := v1.ConfigurationRevision{
-									ObjectMeta: metav1.ObjectMeta{
-										Name: "test-1234567",
-									},
-								}
-								cr.SetGroupVersionKind(v1.ConfigurationRevisionGroupVersionKind)
-								cr.SetConditions(v1.RevisionHealthy())
-								cr.SetDesiredState(v1.PackageRevisionInactive)
-								c := v1.ConfigurationRevisionList{
-									Items: []v1.ConfigurationRevision{cr},
-								}
-								*l = c
-								return nil
-							}),
+							MockList: test.NewMockListFn(kerrors.NewNotFound(schema.GroupResource{}, "")),
 							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
 								want := &v1.Configuration{}
 								want.SetName("test")
+								want.SetAnnotations(map[string]string{v1.AnnotationActivationPolicyOverride: string(v1.ManualActivation)})
 								want.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								want.SetActivationPolicy(&v1.AutomaticActivation)
 								want.SetCurrentRevision("test-1234567")
-								want.SetConditions(v1.Healthy())
-								if diff := cmp.Diff(want, o, test.EquateConditions()); diff != "" {
+								want.SetConditions(v1.Unhealthy().WithMessage("Package revision health is \"Unknown\""))
+								want.SetConditions(v1.Inactive().WithMessage("Package is inactive"))
+								want.SetRevisionSummaries([]v1.RevisionSummary{{Name: "test-1234567", Revision: 1, DesiredState: v1.PackageRevisionInactive, Healthy: corev1.ConditionFalse}})
+								if diff := cmp.Diff(want, o); diff != "" {
 									t.Errorf("-want, +got:\n%s", diff)
 								}
 								return nil
 							}),
 						},
 						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
-							return errBoom
+							return nil
 						}),
 					},
 					pkg: &MockRevisioner{
@@ -676,14 +919,16 @@ func TestReconcile(t *testing.T) {
 					log:        testLog,
 					record:     event.NewNopRecorder(),
 					conditions: conditions.ObservedGenerationPropagationManager{},
+					metrics:    &controller.NopMetrics{},
+					audit:      NewNopAuditSink(),
 				},
 			},
 			want: want{
-				err: errors.Wrap(errBoom, errApplyPackageRevision),
+				r: reconcile.Result{Requeue: false},
 			},
 		},
-		"SuccessfulTransitionUnhealthy": {
-			reason: "If the current revision is unhealthy the package should be also.",
+		"InvalidActivationPolicyOverride": {
+			reason: "We should fall back to the spec activation policy when the activation policy override annotation is invalid.",
 			args: args{
 				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
 				rec: &Reconciler{
@@ -695,33 +940,23 @@ func TestReconcile(t *testing.T) {
 							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
 								p := o.(*v1.Configuration)
 								p.SetName("test")
+								p.SetAnnotations(map[string]string{v1.AnnotationActivationPolicyOverride: "Sometimes"})
 								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								p.SetActivationPolicy(&v1.ManualActivation)
 								return nil
 							}),
-							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
-								l := o.(*v1.ConfigurationRevisionList)
-								cr := v1.ConfigurationRevision{
-									ObjectMeta: metav1.ObjectMeta{
-										Name: "test-1234567",
-									},
-								}
-								cr.SetGroupVersionKind(v1.ConfigurationRevisionGroupVersionKind)
-								cr.SetConditions(v1.RevisionUnhealthy().WithMessage("some message"))
-								cr.SetDesiredState(v1.PackageRevisionActive)
-								c := v1.ConfigurationRevisionList{
-									Items: []v1.ConfigurationRevision{cr},
-								}
-								*l = c
-								return nil
-							}),
+							MockList: test.NewMockListFn(kerrors.NewNotFound(schema.GroupResource{}, "")),
 							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
 								want := &v1.Configuration{}
 								want.SetName("test")
+								want.SetAnnotations(map[string]string{v1.AnnotationActivationPolicyOverride: "Sometimes"})
 								want.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								want.SetActivationPolicy(&v1.ManualActivation)
 								want.SetCurrentRevision("test-1234567")
-								want.SetConditions(v1.Unhealthy().WithMessage("Package revision health is \"False\" with message: some message"))
-								want.SetConditions(v1.Active())
-								if diff := cmp.Diff(want, o, test.EquateConditions()); diff != "" {
+								want.SetConditions(v1.Unhealthy().WithMessage("Package revision health is \"Unknown\""))
+								want.SetConditions(v1.Inactive().WithMessage("Package is inactive"))
+								want.SetRevisionSummaries([]v1.RevisionSummary{{Name: "test-1234567", Revision: 1, DesiredState: v1.PackageRevisionInactive, Healthy: corev1.ConditionFalse}})
+								if diff := cmp.Diff(want, o); diff != "" {
 									t.Errorf("-want, +got:\n%s", diff)
 								}
 								return nil
@@ -741,14 +976,16 @@ func TestReconcile(t *testing.T) {
 					log:        testLog,
 					record:     event.NewNopRecorder(),
 					conditions: conditions.ObservedGenerationPropagationManager{},
+					metrics:    &controller.NopMetrics{},
+					audit:      NewNopAuditSink(),
 				},
 			},
 			want: want{
 				r: reconcile.Result{Requeue: false},
 			},
 		},
-		"SuccessfulRevisionExistsNeedGC": {
-			reason: "We should successfully garbage collect when an old revision falls outside range.",
+		"SuccessfulActiveRevisionExists": {
+			reason: "We should match revision health and not requeue when active revision already exists.",
 			args: args{
 				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
 				rec: &Reconciler{
@@ -770,30 +1007,9 @@ func TestReconcile(t *testing.T) {
 										Name: "test-1234567",
 									},
 								}
-								cr.SetRevision(3)
-								cr.SetGroupVersionKind(v1.ConfigurationRevisionGroupVersionKind)
 								cr.SetConditions(v1.RevisionHealthy())
-								cr.SetDesiredState(v1.PackageRevisionInactive)
 								c := v1.ConfigurationRevisionList{
-									Items: []v1.ConfigurationRevision{
-										cr,
-										{
-											ObjectMeta: metav1.ObjectMeta{
-												Name: "made-the-cut",
-											},
-											Spec: v1.PackageRevisionSpec{
-												Revision: 2,
-											},
-										},
-										{
-											ObjectMeta: metav1.ObjectMeta{
-												Name: "missed-the-cut",
-											},
-											Spec: v1.PackageRevisionSpec{
-												Revision: 1,
-											},
-										},
-									},
+									Items: []v1.ConfigurationRevision{cr},
 								}
 								*l = c
 								return nil
@@ -805,31 +1021,14 @@ func TestReconcile(t *testing.T) {
 								want.SetCurrentRevision("test-1234567")
 								want.SetConditions(v1.Healthy())
 								want.SetConditions(v1.Active())
+								want.SetRevisionSummaries([]v1.RevisionSummary{{Name: "test-1234567", Revision: 1, DesiredState: v1.PackageRevisionActive, Healthy: corev1.ConditionTrue}})
 								if diff := cmp.Diff(want, o, test.EquateConditions()); diff != "" {
 									t.Errorf("-want, +got:\n%s", diff)
 								}
 								return nil
 							}),
-							MockDelete: test.NewMockDeleteFn(nil),
 						},
-						Applicator: resource.ApplyFn(func(_ context.Context, o client.Object, _ ...resource.ApplyOption) error {
-							want := &v1.ConfigurationRevision{}
-							want.SetLabels(map[string]string{"pkg.crossplane.io/package": "test"})
-							want.SetName("test-1234567")
-							want.SetOwnerReferences([]metav1.OwnerReference{{
-								APIVersion:         v1.SchemeGroupVersion.String(),
-								Kind:               v1.ConfigurationKind,
-								Name:               "test",
-								Controller:         &trueVal,
-								BlockOwnerDeletion: &trueVal,
-							}})
-							want.SetGroupVersionKind(v1.ConfigurationRevisionGroupVersionKind)
-							want.SetDesiredState(v1.PackageRevisionActive)
-							want.SetConditions(v1.RevisionHealthy())
-							want.SetRevision(3)
-							if diff := cmp.Diff(want, o, test.EquateConditions()); diff != "" {
-								t.Errorf("-want, +got:\n%s", diff)
-							}
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
 							return nil
 						}),
 					},
@@ -843,14 +1042,16 @@ func TestReconcile(t *testing.T) {
 					log:        testLog,
 					record:     event.NewNopRecorder(),
 					conditions: conditions.ObservedGenerationPropagationManager{},
+					metrics:    &controller.NopMetrics{},
+					audit:      NewNopAuditSink(),
 				},
 			},
 			want: want{
 				r: reconcile.Result{Requeue: false},
 			},
 		},
-		"ErrGC": {
-			reason: "Failure to garbage collect old package revision should cause return an error.",
+		"SuccessfulRevisionExistsNeedsActive": {
+			reason: "We should match revision health, set to active, and not requeue when inactive revision already exists and activation policy is automatic.",
 			args: args{
 				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
 				rec: &Reconciler{
@@ -863,7 +1064,6 @@ func TestReconcile(t *testing.T) {
 								p := o.(*v1.Configuration)
 								p.SetName("test")
 								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
-								p.SetRevisionHistoryLimit(&revHistory)
 								return nil
 							}),
 							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
@@ -873,32 +1073,12 @@ func TestReconcile(t *testing.T) {
 										Name: "test-1234567",
 									},
 								}
-								cr.SetRevision(3)
 								cr.SetGroupVersionKind(v1.ConfigurationRevisionGroupVersionKind)
 								cr.SetConditions(v1.RevisionHealthy())
 								cr.SetDesiredState(v1.PackageRevisionInactive)
+								cr.SetRevision(1)
 								c := v1.ConfigurationRevisionList{
-									Items: []v1.ConfigurationRevision{
-										cr,
-										{
-											ObjectMeta: metav1.ObjectMeta{
-												Name: "made-the-cut",
-											},
-											Spec: v1.PackageRevisionSpec{
-												Revision:     2,
-												DesiredState: v1.PackageRevisionInactive,
-											},
-										},
-										{
-											ObjectMeta: metav1.ObjectMeta{
-												Name: "missed-the-cut",
-											},
-											Spec: v1.PackageRevisionSpec{
-												Revision:     1,
-												DesiredState: v1.PackageRevisionInactive,
-											},
-										},
-									},
+									Items: []v1.ConfigurationRevision{cr},
 								}
 								*l = c
 								return nil
@@ -908,14 +1088,35 @@ func TestReconcile(t *testing.T) {
 								want.SetName("test")
 								want.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
 								want.SetCurrentRevision("test-1234567")
-								want.SetRevisionHistoryLimit(&revHistory)
+								want.SetConditions(v1.Healthy())
+								want.SetConditions(v1.Active())
+								want.SetRevisionSummaries([]v1.RevisionSummary{{Name: "test-1234567", Revision: 1, DesiredState: v1.PackageRevisionActive, Healthy: corev1.ConditionTrue}})
 								if diff := cmp.Diff(want, o, test.EquateConditions()); diff != "" {
 									t.Errorf("-want, +got:\n%s", diff)
 								}
 								return nil
 							}),
-							MockDelete: test.NewMockDeleteFn(errBoom),
 						},
+						Applicator: resource.ApplyFn(func(_ context.Context, o client.Object, _ ...resource.ApplyOption) error {
+							want := &v1.ConfigurationRevision{}
+							want.SetLabels(map[string]string{"pkg.crossplane.io/package": "test"})
+							want.SetName("test-1234567")
+							want.SetOwnerReferences([]metav1.OwnerReference{{
+								APIVersion:         v1.SchemeGroupVersion.String(),
+								Kind:               v1.ConfigurationKind,
+								Name:               "test",
+								Controller:         &trueVal,
+								BlockOwnerDeletion: &trueVal,
+							}})
+							want.SetGroupVersionKind(v1.ConfigurationRevisionGroupVersionKind)
+							want.SetDesiredState(v1.PackageRevisionActive)
+							want.SetConditions(v1.RevisionHealthy())
+							want.SetRevision(1)
+							if diff := cmp.Diff(want, o, test.EquateConditions()); diff != "" {
+								t.Errorf("-want, +got:\n%s", diff)
+							}
+							return nil
+						}),
 					},
 					pkg: &MockRevisioner{
 						MockRevision: NewMockRevisionFn("test-1234567", nil),
@@ -927,14 +1128,16 @@ func TestReconcile(t *testing.T) {
 					log:        testLog,
 					record:     event.NewNopRecorder(),
 					conditions: conditions.ObservedGenerationPropagationManager{},
+					metrics:    &controller.NopMetrics{},
+					audit:      NewNopAuditSink(),
 				},
 			},
 			want: want{
-				err: errors.Wrap(errBoom, errGCPackageRevision),
+				r: reconcile.Result{Requeue: false},
 			},
 		},
-		"PauseReconcile": {
-			reason: "Pause reconciliation if the pause annotation is set",
+		"SuccessfulRevisionSpecTemplate": {
+			reason: "We should apply revision spec template fields to the revision unless they are set on the package.",
 			args: args{
 				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
 				rec: &Reconciler{
@@ -947,27 +1150,34 @@ func TestReconcile(t *testing.T) {
 								p := o.(*v1.Configuration)
 								p.SetName("test")
 								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
-								p.SetActivationPolicy(&v1.AutomaticActivation)
-								p.SetAnnotations(map[string]string{
-									meta.AnnotationKeyReconciliationPaused: "true",
-								})
-								return nil
-							}),
-							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
-								want := &v1.Configuration{}
-								want.SetName("test")
-								want.SetAnnotations(map[string]string{
-									meta.AnnotationKeyReconciliationPaused: "true",
-								})
-								want.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
-								want.SetActivationPolicy(&v1.AutomaticActivation)
-								want.SetConditions(commonv1.ReconcilePaused().WithMessage(reconcilePausedMsg))
-								if diff := cmp.Diff(want, o); diff != "" {
-									t.Errorf("-want, +got:\n%s", diff)
-								}
+								p.SetIgnoreCrossplaneConstraints(&falseVal)
+								p.SetCommonLabels(map[string]string{"team": "pkg"})
 								return nil
 							}),
+							MockList:         test.NewMockListFn(kerrors.NewNotFound(schema.GroupResource{}, "")),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
 						},
+						Applicator: resource.ApplyFn(func(_ context.Context, o client.Object, _ ...resource.ApplyOption) error {
+							want := &v1.ConfigurationRevision{}
+							want.SetLabels(map[string]string{"pkg.crossplane.io/package": "test"})
+							want.SetName("test-1234567")
+							want.SetOwnerReferences([]metav1.OwnerReference{{
+								APIVersion:         v1.SchemeGroupVersion.String(),
+								Kind:               v1.ConfigurationKind,
+								Name:               "test",
+								Controller:         &trueVal,
+								BlockOwnerDeletion: &trueVal,
+							}})
+							want.SetDesiredState(v1.PackageRevisionActive)
+							want.SetRevision(1)
+							want.SetIgnoreCrossplaneConstraints(&falseVal)
+							want.SetSkipDependencyResolution(&trueVal)
+							want.SetCommonLabels(map[string]string{"team": "pkg", "distro": "acme"})
+							if diff := cmp.Diff(want, o); diff != "" {
+								t.Errorf("-want, +got:\n%s", diff)
+							}
+							return nil
+						}),
 					},
 					pkg: &MockRevisioner{
 						MockRevision: NewMockRevisionFn("test-1234567", nil),
@@ -979,14 +1189,21 @@ func TestReconcile(t *testing.T) {
 					log:        testLog,
 					record:     event.NewNopRecorder(),
 					conditions: conditions.ObservedGenerationPropagationManager{},
+					metrics:    &controller.NopMetrics{},
+					audit:      NewNopAuditSink(),
+					revisionTemplate: v1.PackageRevisionSpec{
+						IgnoreCrossplaneConstraints: &trueVal,
+						SkipDependencyResolution:    &trueVal,
+						CommonLabels:                map[string]string{"team": "platform", "distro": "acme"},
+					},
 				},
 			},
 			want: want{
 				r: reconcile.Result{Requeue: false},
 			},
 		},
-		"ResumeReconcile": {
-			reason: "We should be active and not requeue on successful creation of the first revision with auto activation.",
+		"SuccessfulCreateRecordsPackageGeneration": {
+			reason: "We should annotate a new revision with the generation of the package that produced it.",
 			args: args{
 				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
 				rec: &Reconciler{
@@ -998,25 +1215,89 @@ func TestReconcile(t *testing.T) {
 							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
 								p := o.(*v1.Configuration)
 								p.SetName("test")
+								p.SetGeneration(3)
 								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
-								p.SetActivationPolicy(&v1.AutomaticActivation)
-								p.SetConditions(commonv1.ReconcilePaused())
 								return nil
 							}),
-							MockList: test.NewMockListFn(kerrors.NewNotFound(schema.GroupResource{}, "")),
-							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
-								want := &v1.Configuration{}
-								want.SetName("test")
-								want.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
-								want.SetActivationPolicy(&v1.AutomaticActivation)
-								want.Status.Conditions = []commonv1.Condition{}
-								if diff := cmp.Diff(want, o); diff != "" {
-									t.Errorf("-want, +got:\n%s", diff)
-								}
+							MockList:         test.NewMockListFn(kerrors.NewNotFound(schema.GroupResource{}, "")),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, o client.Object, _ ...resource.ApplyOption) error {
+							want := &v1.ConfigurationRevision{}
+							want.SetLabels(map[string]string{"pkg.crossplane.io/package": "test"})
+							want.SetAnnotations(map[string]string{v1.AnnotationPackageGeneration: "3"})
+							want.SetName("test-1234567")
+							want.SetOwnerReferences([]metav1.OwnerReference{{
+								APIVersion:         v1.SchemeGroupVersion.String(),
+								Kind:               v1.ConfigurationKind,
+								Name:               "test",
+								Controller:         &trueVal,
+								BlockOwnerDeletion: &trueVal,
+							}})
+							want.SetDesiredState(v1.PackageRevisionActive)
+							want.SetRevision(1)
+							if diff := cmp.Diff(want, o); diff != "" {
+								t.Errorf("-want, +got:\n%s", diff)
+							}
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:        testLog,
+					record:     event.NewNopRecorder(),
+					conditions: conditions.ObservedGenerationPropagationManager{},
+					metrics:    &controller.NopMetrics{},
+					audit:      NewNopAuditSink(),
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: false},
+			},
+		},
+		"SuccessfulCreateRecordsSourceTag": {
+			reason: "We should annotate a new revision with the tag its package's source specified.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetSource("xpkg.example.com/test:v1.2.3")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
 								return nil
 							}),
+							MockList:         test.NewMockListFn(kerrors.NewNotFound(schema.GroupResource{}, "")),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
 						},
-						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+						Applicator: resource.ApplyFn(func(_ context.Context, o client.Object, _ ...resource.ApplyOption) error {
+							want := &v1.ConfigurationRevision{}
+							want.SetLabels(map[string]string{"pkg.crossplane.io/package": "test"})
+							want.SetAnnotations(map[string]string{v1.AnnotationSourceTag: "v1.2.3"})
+							want.SetName("test-1234567")
+							want.SetOwnerReferences([]metav1.OwnerReference{{
+								APIVersion:         v1.SchemeGroupVersion.String(),
+								Kind:               v1.ConfigurationKind,
+								Name:               "test",
+								Controller:         &trueVal,
+								BlockOwnerDeletion: &trueVal,
+							}})
+							want.SetDesiredState(v1.PackageRevisionActive)
+							want.SetRevision(1)
+							want.SetSource("xpkg.example.com/test:v1.2.3")
+							if diff := cmp.Diff(want, o); diff != "" {
+								t.Errorf("-want, +got:\n%s", diff)
+							}
 							return nil
 						}),
 					},
@@ -1030,24 +1311,11301 @@ func TestReconcile(t *testing.T) {
 					log:        testLog,
 					record:     event.NewNopRecorder(),
 					conditions: conditions.ObservedGenerationPropagationManager{},
+					metrics:    &controller.NopMetrics{},
+					audit:      NewNopAuditSink(),
 				},
 			},
 			want: want{
 				r: reconcile.Result{Requeue: false},
 			},
 		},
-	}
-
-	for name, tc := range cases {
-		t.Run(name, func(t *testing.T) {
-			got, err := tc.args.rec.Reconcile(context.Background(), reconcile.Request{})
-
-			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
-				t.Errorf("\n%s\nr.Reconcile(...): -want error, +got error:\n%s", tc.reason, diff)
-			}
-			if diff := cmp.Diff(tc.want.r, got, test.EquateErrors()); diff != "" {
-				t.Errorf("\n%s\nr.Reconcile(...): -want, +got:\n%s", tc.reason, diff)
-			}
-		})
+		"SuccessfulCreateRecordsCreationReason": {
+			reason: "We should annotate the first revision of a package with an initial creation reason.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								return nil
+							}),
+							MockList:         test.NewMockListFn(kerrors.NewNotFound(schema.GroupResource{}, "")),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, o client.Object, _ ...resource.ApplyOption) error {
+							want := &v1.ConfigurationRevision{}
+							want.SetLabels(map[string]string{"pkg.crossplane.io/package": "test"})
+							want.SetAnnotations(map[string]string{v1.AnnotationCreationReason: v1.CreationReasonInitial})
+							want.SetName("test-1234567")
+							want.SetOwnerReferences([]metav1.OwnerReference{{
+								APIVersion:         v1.SchemeGroupVersion.String(),
+								Kind:               v1.ConfigurationKind,
+								Name:               "test",
+								Controller:         &trueVal,
+								BlockOwnerDeletion: &trueVal,
+							}})
+							want.SetDesiredState(v1.PackageRevisionActive)
+							want.SetRevision(1)
+							if diff := cmp.Diff(want, o); diff != "" {
+								t.Errorf("-want, +got:\n%s", diff)
+							}
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:                    testLog,
+					record:                 event.NewNopRecorder(),
+					conditions:             conditions.ObservedGenerationPropagationManager{},
+					metrics:                &controller.NopMetrics{},
+					audit:                  NewNopAuditSink(),
+					annotateCreationReason: true,
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: false},
+			},
+		},
+		"SuccessfulRevisionDiff": {
+			reason: "We should report how an inactive current revision differs from the active revision.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								p.SetActivationPolicy(&v1.ManualActivation)
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+								l := o.(*v1.ConfigurationRevisionList)
+								prev := v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: "test-previous"}}
+								prev.SetDesiredState(v1.PackageRevisionActive)
+								prev.SetRevision(1)
+								prev.SetObjects([]commonv1.TypedReference{
+									{APIVersion: "apiextensions.k8s.io/v1", Kind: "CustomResourceDefinition", Name: "removed", UID: "a"},
+									{APIVersion: "apiextensions.k8s.io/v1", Kind: "CustomResourceDefinition", Name: "unchanged", UID: "b"},
+								})
+								cur := v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: "test-1234567"}}
+								cur.SetDesiredState(v1.PackageRevisionInactive)
+								cur.SetRevision(2)
+								cur.SetObjects([]commonv1.TypedReference{
+									{APIVersion: "apiextensions.k8s.io/v1", Kind: "CustomResourceDefinition", Name: "unchanged", UID: "b"},
+									{APIVersion: "apiextensions.k8s.io/v1", Kind: "CustomResourceDefinition", Name: "added", UID: "c"},
+								})
+								*l = v1.ConfigurationRevisionList{Items: []v1.ConfigurationRevision{prev, cur}}
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								want := &v1.Configuration{}
+								want.SetName("test")
+								want.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								want.SetActivationPolicy(&v1.ManualActivation)
+								want.SetCurrentRevision("test-1234567")
+								want.SetConditions(v1.Unhealthy().WithMessage("Package revision health is \"Unknown\""))
+								want.SetConditions(v1.Inactive().WithMessage("Package is inactive"))
+								want.SetRevisionDiff(&v1.RevisionDiff{
+									PreviousRevision: "test-previous",
+									AddedObjects:     []commonv1.TypedReference{{APIVersion: "apiextensions.k8s.io/v1", Kind: "CustomResourceDefinition", Name: "added"}},
+									RemovedObjects:   []commonv1.TypedReference{{APIVersion: "apiextensions.k8s.io/v1", Kind: "CustomResourceDefinition", Name: "removed"}},
+								})
+								want.SetRevisionSummaries([]v1.RevisionSummary{
+									{Name: "test-1234567", Revision: 2, DesiredState: v1.PackageRevisionInactive, Healthy: corev1.ConditionFalse},
+									{Name: "test-previous", Revision: 1, DesiredState: v1.PackageRevisionInactive, Healthy: corev1.ConditionFalse},
+								})
+								if diff := cmp.Diff(want, o, test.EquateConditions()); diff != "" {
+									t.Errorf("-want, +got:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:        testLog,
+					record:     event.NewNopRecorder(),
+					conditions: conditions.ObservedGenerationPropagationManager{},
+					metrics:    &controller.NopMetrics{},
+					audit:      NewNopAuditSink(),
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: false},
+			},
+		},
+		"SuccessfulRevisionDiffAgainstActiveRevision": {
+			reason: "We should report how an inactive current revision differs from the revision that was active when it became current, including the objects that changed.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								p.SetActivationPolicy(&v1.ManualActivation)
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+								l := o.(*v1.ConfigurationRevisionList)
+								active := v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: "test-active"}}
+								active.SetDesiredState(v1.PackageRevisionActive)
+								active.SetRevision(1)
+								active.SetObjects([]commonv1.TypedReference{
+									{APIVersion: "apiextensions.k8s.io/v1", Kind: "CustomResourceDefinition", Name: "removed", UID: "a"},
+									{APIVersion: "apiextensions.k8s.io/v1", Kind: "CustomResourceDefinition", Name: "widgets.example.org", UID: "b"},
+								})
+								skipped := v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: "test-skipped"}}
+								skipped.SetDesiredState(v1.PackageRevisionInactive)
+								skipped.SetRevision(2)
+								skipped.SetObjects([]commonv1.TypedReference{
+									{APIVersion: "apiextensions.k8s.io/v1", Kind: "CustomResourceDefinition", Name: "skipped", UID: "d"},
+								})
+								cur := v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: "test-1234567"}}
+								cur.SetDesiredState(v1.PackageRevisionInactive)
+								cur.SetRevision(3)
+								cur.SetObjects([]commonv1.TypedReference{
+									{APIVersion: "apiextensions.k8s.io/v1", Kind: "CustomResourceDefinition", Name: "widgets.example.org", UID: "b"},
+									{APIVersion: "apiextensions.k8s.io/v1", Kind: "CustomResourceDefinition", Name: "added", UID: "c"},
+								})
+								*l = v1.ConfigurationRevisionList{Items: []v1.ConfigurationRevision{active, skipped, cur}}
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								want := &v1.Configuration{}
+								want.SetName("test")
+								want.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								want.SetActivationPolicy(&v1.ManualActivation)
+								want.SetCurrentRevision("test-1234567")
+								want.SetConditions(v1.Unhealthy().WithMessage("Package revision health is \"Unknown\""))
+								want.SetConditions(v1.Inactive().WithMessage("Package is inactive"))
+								want.SetRevisionDiff(&v1.RevisionDiff{
+									PreviousRevision: "test-active",
+									AddedObjects:     []commonv1.TypedReference{{APIVersion: "apiextensions.k8s.io/v1", Kind: "CustomResourceDefinition", Name: "added"}},
+									RemovedObjects:   []commonv1.TypedReference{{APIVersion: "apiextensions.k8s.io/v1", Kind: "CustomResourceDefinition", Name: "removed"}},
+									ChangedObjects:   []commonv1.TypedReference{{APIVersion: "apiextensions.k8s.io/v1", Kind: "CustomResourceDefinition", Name: "widgets.example.org"}},
+								})
+								want.SetRevisionSummaries([]v1.RevisionSummary{
+									{Name: "test-1234567", Revision: 3, DesiredState: v1.PackageRevisionInactive, Healthy: corev1.ConditionFalse},
+									{Name: "test-skipped", Revision: 2, DesiredState: v1.PackageRevisionInactive, Healthy: corev1.ConditionFalse},
+									{Name: "test-active", Revision: 1, DesiredState: v1.PackageRevisionInactive, Healthy: corev1.ConditionFalse},
+								})
+								if diff := cmp.Diff(want, o, test.EquateConditions()); diff != "" {
+									t.Errorf("-want, +got:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					contents: &MockRevisionContentsFetcher{
+						MockRevisionContents: func(pr v1.PackageRevision) (*parser.Package, error) {
+							if pr.GetName() == "test-active" {
+								return clusterWidgets, nil
+							}
+							return namespacedWidgets, nil
+						},
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:        testLog,
+					record:     event.NewNopRecorder(),
+					conditions: conditions.ObservedGenerationPropagationManager{},
+					metrics:    &controller.NopMetrics{},
+					audit:      NewNopAuditSink(),
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: false},
+			},
+		},
+		"SuccessfulRevisionDiffAfterDeactivation": {
+			reason: "We should keep comparing an inactive current revision to the revision that was active when it became current after that revision is deactivated, without fetching their contents again.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								p.SetActivationPolicy(&v1.ManualActivation)
+								p.SetCurrentRevision("test-1234567")
+								p.SetRevisionDiff(&v1.RevisionDiff{
+									PreviousRevision: "test-active",
+									ChangedObjects:   []commonv1.TypedReference{{APIVersion: "apiextensions.k8s.io/v1", Kind: "CustomResourceDefinition", Name: "widgets.example.org"}},
+								})
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+								l := o.(*v1.ConfigurationRevisionList)
+								prev := v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: "test-active"}}
+								prev.SetDesiredState(v1.PackageRevisionInactive)
+								prev.SetRevision(1)
+								prev.SetObjects([]commonv1.TypedReference{
+									{APIVersion: "apiextensions.k8s.io/v1", Kind: "CustomResourceDefinition", Name: "removed", UID: "a"},
+									{APIVersion: "apiextensions.k8s.io/v1", Kind: "CustomResourceDefinition", Name: "widgets.example.org", UID: "b"},
+								})
+								cur := v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: "test-1234567"}}
+								cur.SetDesiredState(v1.PackageRevisionInactive)
+								cur.SetRevision(2)
+								cur.SetObjects([]commonv1.TypedReference{
+									{APIVersion: "apiextensions.k8s.io/v1", Kind: "CustomResourceDefinition", Name: "widgets.example.org", UID: "b"},
+								})
+								*l = v1.ConfigurationRevisionList{Items: []v1.ConfigurationRevision{prev, cur}}
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								want := &v1.Configuration{}
+								want.SetName("test")
+								want.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								want.SetActivationPolicy(&v1.ManualActivation)
+								want.SetCurrentRevision("test-1234567")
+								want.SetConditions(v1.Unhealthy().WithMessage("Package revision health is \"Unknown\""))
+								want.SetConditions(v1.Inactive().WithMessage("Package is inactive"))
+								want.SetRevisionDiff(&v1.RevisionDiff{
+									PreviousRevision: "test-active",
+									RemovedObjects:   []commonv1.TypedReference{{APIVersion: "apiextensions.k8s.io/v1", Kind: "CustomResourceDefinition", Name: "removed"}},
+									ChangedObjects:   []commonv1.TypedReference{{APIVersion: "apiextensions.k8s.io/v1", Kind: "CustomResourceDefinition", Name: "widgets.example.org"}},
+								})
+								want.SetRevisionSummaries([]v1.RevisionSummary{
+									{Name: "test-1234567", Revision: 2, DesiredState: v1.PackageRevisionInactive, Healthy: corev1.ConditionFalse},
+									{Name: "test-active", Revision: 1, DesiredState: v1.PackageRevisionInactive, Healthy: corev1.ConditionFalse},
+								})
+								if diff := cmp.Diff(want, o, test.EquateConditions()); diff != "" {
+									t.Errorf("-want, +got:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					contents: &MockRevisionContentsFetcher{
+						MockRevisionContents: func(_ v1.PackageRevision) (*parser.Package, error) {
+							return nil, errBoom
+						},
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:        testLog,
+					record:     event.NewNopRecorder(),
+					conditions: conditions.ObservedGenerationPropagationManager{},
+					metrics:    &controller.NopMetrics{},
+					audit:      NewNopAuditSink(),
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: false},
+			},
+		},
+		"ErrUpdatePackageRevision": {
+			reason: "Failing to update a package revision should cause us to return an error.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+								l := o.(*v1.ConfigurationRevisionList)
+								cr := v1.ConfigurationRevision{
+									ObjectMeta: metav1.ObjectMeta{
+										Name: "test-1234567",
+									},
+								}
+								cr.SetGroupVersionKind(v1.ConfigurationRevisionGroupVersionKind)
+								cr.SetConditions(v1.RevisionHealthy())
+								cr.SetDesiredState(v1.PackageRevisionInactive)
+								c := v1.ConfigurationRevisionList{
+									Items: []v1.ConfigurationRevision{cr},
+								}
+								*l = c
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								want := &v1.Configuration{}
+								want.SetName("test")
+								want.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								want.SetCurrentRevision("test-1234567")
+								want.SetConditions(v1.Healthy())
+								if diff := cmp.Diff(want, o, test.EquateConditions()); diff != "" {
+									t.Errorf("-want, +got:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							return errBoom
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:        testLog,
+					record:     event.NewNopRecorder(),
+					conditions: conditions.ObservedGenerationPropagationManager{},
+					metrics:    &controller.NopMetrics{},
+					audit:      NewNopAuditSink(),
+				},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errApplyPackageRevision),
+			},
+		},
+		"SuccessfulTransitionUnhealthy": {
+			reason: "If the current revision is unhealthy the package should be also.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+								l := o.(*v1.ConfigurationRevisionList)
+								cr := v1.ConfigurationRevision{
+									ObjectMeta: metav1.ObjectMeta{
+										Name: "test-1234567",
+									},
+								}
+								cr.SetGroupVersionKind(v1.ConfigurationRevisionGroupVersionKind)
+								cr.SetConditions(v1.RevisionUnhealthy().WithMessage("some message"))
+								cr.SetDesiredState(v1.PackageRevisionActive)
+								c := v1.ConfigurationRevisionList{
+									Items: []v1.ConfigurationRevision{cr},
+								}
+								*l = c
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								want := &v1.Configuration{}
+								want.SetName("test")
+								want.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								want.SetCurrentRevision("test-1234567")
+								want.SetConditions(v1.Unhealthy().WithMessage("Package revision health is \"False\" with message: some message"))
+								want.SetConditions(v1.Active())
+								want.SetRevisionSummaries([]v1.RevisionSummary{{Name: "test-1234567", Revision: 1, DesiredState: v1.PackageRevisionActive, Healthy: corev1.ConditionFalse}})
+								if diff := cmp.Diff(want, o, test.EquateConditions()); diff != "" {
+									t.Errorf("-want, +got:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:        testLog,
+					record:     event.NewNopRecorder(),
+					conditions: conditions.ObservedGenerationPropagationManager{},
+					metrics:    &controller.NopMetrics{},
+					audit:      NewNopAuditSink(),
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: false},
+			},
+		},
+		"SuccessfulRevisionExistsNeedGC": {
+			reason: "We should successfully garbage collect when an old revision falls outside range.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								p.SetRevisionHistoryLimit(&revHistory)
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+								l := o.(*v1.ConfigurationRevisionList)
+								cr := v1.ConfigurationRevision{
+									ObjectMeta: metav1.ObjectMeta{
+										Name: "test-1234567",
+									},
+								}
+								cr.SetRevision(3)
+								cr.SetGroupVersionKind(v1.ConfigurationRevisionGroupVersionKind)
+								cr.SetConditions(v1.RevisionHealthy())
+								cr.SetDesiredState(v1.PackageRevisionInactive)
+								c := v1.ConfigurationRevisionList{
+									Items: []v1.ConfigurationRevision{
+										cr,
+										{
+											ObjectMeta: metav1.ObjectMeta{
+												Name: "made-the-cut",
+											},
+											Spec: v1.PackageRevisionSpec{
+												Revision: 2,
+											},
+										},
+										{
+											ObjectMeta: metav1.ObjectMeta{
+												Name: "missed-the-cut",
+											},
+											Spec: v1.PackageRevisionSpec{
+												Revision: 1,
+											},
+										},
+									},
+								}
+								*l = c
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								want := &v1.Configuration{}
+								want.SetName("test")
+								want.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								want.SetCurrentRevision("test-1234567")
+								want.SetRevisionHistoryLimit(&revHistory)
+								want.SetEffectiveRevisionHistoryLimit(&revHistory)
+								want.SetConditions(v1.Healthy())
+								want.SetConditions(v1.Active())
+								want.SetRevisionSummaries([]v1.RevisionSummary{
+									{Name: "test-1234567", Revision: 3, DesiredState: v1.PackageRevisionActive, Healthy: corev1.ConditionTrue},
+									{Name: "made-the-cut", Revision: 2, Healthy: corev1.ConditionFalse},
+								})
+								want.SetGarbageCollection(&v1.GarbageCollectionResult{
+									Time:           ptr.To(metav1.NewTime(now)),
+									Collected:      []string{"missed-the-cut"},
+									CollectedCount: 1,
+								})
+								if diff := cmp.Diff(want, o, test.EquateConditions()); diff != "" {
+									t.Errorf("-want, +got:\n%s", diff)
+								}
+								if gc := o.(*v1.Configuration).GetGarbageCollection(); gc == nil || gc.Time == nil {
+									t.Errorf("Status().Update(...): want garbage collection time, got %v", gc)
+								}
+								return nil
+							}),
+							MockDelete: test.NewMockDeleteFn(nil),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, o client.Object, _ ...resource.ApplyOption) error {
+							want := &v1.ConfigurationRevision{}
+							want.SetLabels(map[string]string{"pkg.crossplane.io/package": "test"})
+							want.SetName("test-1234567")
+							want.SetOwnerReferences([]metav1.OwnerReference{{
+								APIVersion:         v1.SchemeGroupVersion.String(),
+								Kind:               v1.ConfigurationKind,
+								Name:               "test",
+								Controller:         &trueVal,
+								BlockOwnerDeletion: &trueVal,
+							}})
+							want.SetGroupVersionKind(v1.ConfigurationRevisionGroupVersionKind)
+							want.SetDesiredState(v1.PackageRevisionActive)
+							want.SetConditions(v1.RevisionHealthy())
+							want.SetRevision(3)
+							if diff := cmp.Diff(want, o, test.EquateConditions()); diff != "" {
+								t.Errorf("-want, +got:\n%s", diff)
+							}
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:        testLog,
+					record:     event.NewNopRecorder(),
+					conditions: conditions.ObservedGenerationPropagationManager{},
+					metrics:    &controller.NopMetrics{},
+					audit:      NewNopAuditSink(),
+					clock:      testingclock.NewFakePassiveClock(now),
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: false},
+			},
+		},
+		"ErrGC": {
+			reason: "We should garbage collect what we can, record what we couldn't, and return an error aggregating the failures.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								p.SetRevisionHistoryLimit(&revHistory)
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+								l := o.(*v1.ConfigurationRevisionList)
+								cr := v1.ConfigurationRevision{
+									ObjectMeta: metav1.ObjectMeta{
+										Name: "test-1234567",
+									},
+								}
+								cr.SetRevision(4)
+								cr.SetGroupVersionKind(v1.ConfigurationRevisionGroupVersionKind)
+								cr.SetConditions(v1.RevisionHealthy())
+								cr.SetDesiredState(v1.PackageRevisionInactive)
+								c := v1.ConfigurationRevisionList{
+									Items: []v1.ConfigurationRevision{
+										cr,
+										{
+											ObjectMeta: metav1.ObjectMeta{
+												Name: "made-the-cut",
+											},
+											Spec: v1.PackageRevisionSpec{
+												Revision:     3,
+												DesiredState: v1.PackageRevisionInactive,
+											},
+										},
+										{
+											ObjectMeta: metav1.ObjectMeta{
+												Name: "missed-the-cut",
+											},
+											Spec: v1.PackageRevisionSpec{
+												Revision:     2,
+												DesiredState: v1.PackageRevisionInactive,
+											},
+										},
+										{
+											ObjectMeta: metav1.ObjectMeta{
+												Name: "also-missed-the-cut",
+											},
+											Spec: v1.PackageRevisionSpec{
+												Revision:     1,
+												DesiredState: v1.PackageRevisionInactive,
+											},
+										},
+									},
+								}
+								*l = c
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								want := &v1.Configuration{}
+								want.SetName("test")
+								want.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								want.SetCurrentRevision("test-1234567")
+								want.SetRevisionHistoryLimit(&revHistory)
+								want.SetEffectiveRevisionHistoryLimit(&revHistory)
+								want.SetConditions(v1.Healthy())
+								want.SetGarbageCollection(&v1.GarbageCollectionResult{
+									Time:           ptr.To(metav1.NewTime(now)),
+									Collected:      []string{"also-missed-the-cut"},
+									Failed:         []string{"missed-the-cut"},
+									CollectedCount: 1,
+								})
+								if diff := cmp.Diff(want, o, test.EquateConditions()); diff != "" {
+									t.Errorf("-want, +got:\n%s", diff)
+								}
+								return nil
+							}),
+							MockDelete: test.NewMockDeleteFn(nil, func(o client.Object) error {
+								if o.GetName() == "missed-the-cut" {
+									return errBoom
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:        testLog,
+					record:     event.NewNopRecorder(),
+					conditions: conditions.ObservedGenerationPropagationManager{},
+					metrics:    &controller.NopMetrics{},
+					clock:      testingclock.NewFakePassiveClock(now),
+					audit:      NewNopAuditSink(),
+				},
+			},
+			want: want{
+				err: errors.Wrap(errors.Wrapf(errBoom, errFmtDeleteRevision, "missed-the-cut"), errGCPackageRevision),
+			},
+		},
+		"GCRevisionNotFound": {
+			reason: "We should treat a revision that was already deleted as garbage collected.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								p.SetRevisionHistoryLimit(&revHistory)
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+								l := o.(*v1.ConfigurationRevisionList)
+								cr := v1.ConfigurationRevision{
+									ObjectMeta: metav1.ObjectMeta{
+										Name: "test-1234567",
+									},
+								}
+								cr.SetRevision(4)
+								cr.SetGroupVersionKind(v1.ConfigurationRevisionGroupVersionKind)
+								cr.SetConditions(v1.RevisionHealthy())
+								cr.SetDesiredState(v1.PackageRevisionInactive)
+								c := v1.ConfigurationRevisionList{
+									Items: []v1.ConfigurationRevision{
+										cr,
+										{
+											ObjectMeta: metav1.ObjectMeta{
+												Name: "made-the-cut",
+											},
+											Spec: v1.PackageRevisionSpec{
+												Revision:     3,
+												DesiredState: v1.PackageRevisionInactive,
+											},
+										},
+										{
+											ObjectMeta: metav1.ObjectMeta{
+												Name: "missed-the-cut",
+											},
+											Spec: v1.PackageRevisionSpec{
+												Revision:     2,
+												DesiredState: v1.PackageRevisionInactive,
+											},
+										},
+										{
+											ObjectMeta: metav1.ObjectMeta{
+												Name: "also-missed-the-cut",
+											},
+											Spec: v1.PackageRevisionSpec{
+												Revision:     1,
+												DesiredState: v1.PackageRevisionInactive,
+											},
+										},
+									},
+								}
+								*l = c
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								want := &v1.Configuration{}
+								want.SetName("test")
+								want.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								want.SetCurrentRevision("test-1234567")
+								want.SetRevisionHistoryLimit(&revHistory)
+								want.SetEffectiveRevisionHistoryLimit(&revHistory)
+								want.SetConditions(v1.Healthy(), v1.Active())
+								want.SetRevisionSummaries([]v1.RevisionSummary{
+									{Name: "test-1234567", Revision: 4, DesiredState: v1.PackageRevisionActive, Healthy: corev1.ConditionTrue},
+									{Name: "made-the-cut", Revision: 3, DesiredState: v1.PackageRevisionInactive, Healthy: corev1.ConditionFalse},
+								})
+								want.SetGarbageCollection(&v1.GarbageCollectionResult{
+									Time:           ptr.To(metav1.NewTime(now)),
+									Collected:      []string{"also-missed-the-cut", "missed-the-cut"},
+									CollectedCount: 2,
+								})
+								if diff := cmp.Diff(want, o, test.EquateConditions()); diff != "" {
+									t.Errorf("-want, +got:\n%s", diff)
+								}
+								return nil
+							}),
+							MockDelete: test.NewMockDeleteFn(nil, func(o client.Object) error {
+								if o.GetName() == "missed-the-cut" {
+									return kerrors.NewNotFound(schema.GroupResource{Resource: "configurationrevisions"}, "missed-the-cut")
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:        testLog,
+					record:     event.NewNopRecorder(),
+					conditions: conditions.ObservedGenerationPropagationManager{},
+					metrics:    &controller.NopMetrics{},
+					clock:      testingclock.NewFakePassiveClock(now),
+					audit:      NewNopAuditSink(),
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"SuccessfulExternallyManagedRevisionNotGC": {
+			reason: "We should never garbage collect or take ownership of an externally managed revision, even when it falls outside range.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								p.SetRevisionHistoryLimit(&revHistory)
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+								l := o.(*v1.ConfigurationRevisionList)
+								cr := v1.ConfigurationRevision{
+									ObjectMeta: metav1.ObjectMeta{
+										Name: "test-1234567",
+									},
+								}
+								cr.SetRevision(3)
+								cr.SetGroupVersionKind(v1.ConfigurationRevisionGroupVersionKind)
+								cr.SetConditions(v1.RevisionHealthy())
+								cr.SetDesiredState(v1.PackageRevisionActive)
+								c := v1.ConfigurationRevisionList{
+									Items: []v1.ConfigurationRevision{
+										cr,
+										{
+											ObjectMeta: metav1.ObjectMeta{
+												Name: "missed-the-cut",
+											},
+											Spec: v1.PackageRevisionSpec{
+												Revision:     2,
+												DesiredState: v1.PackageRevisionInactive,
+											},
+										},
+										{
+											ObjectMeta: metav1.ObjectMeta{
+												Name:        "externally-managed",
+												Annotations: map[string]string{v1.AnnotationExternallyManaged: "true"},
+											},
+											Spec: v1.PackageRevisionSpec{
+												Revision:     1,
+												DesiredState: v1.PackageRevisionInactive,
+											},
+										},
+									},
+								}
+								*l = c
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								want := &v1.Configuration{}
+								want.SetName("test")
+								want.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								want.SetCurrentRevision("test-1234567")
+								want.SetRevisionHistoryLimit(&revHistory)
+								want.SetEffectiveRevisionHistoryLimit(&revHistory)
+								want.SetConditions(v1.Healthy())
+								want.SetConditions(v1.Active())
+								want.SetRevisionSummaries([]v1.RevisionSummary{
+									{Name: "test-1234567", Revision: 3, DesiredState: v1.PackageRevisionActive, Healthy: corev1.ConditionTrue},
+									{Name: "externally-managed", Revision: 1, DesiredState: v1.PackageRevisionInactive, Healthy: corev1.ConditionFalse},
+								})
+								want.SetGarbageCollection(&v1.GarbageCollectionResult{Time: ptr.To(metav1.NewTime(now)), Collected: []string{"missed-the-cut"}, CollectedCount: 1})
+								if diff := cmp.Diff(want, o, test.EquateConditions()); diff != "" {
+									t.Errorf("-want, +got:\n%s", diff)
+								}
+								return nil
+							}),
+							MockDelete: test.NewMockDeleteFn(nil, func(o client.Object) error {
+								if o.GetName() != "missed-the-cut" {
+									t.Errorf("Delete(...): unexpected deletion of revision %q", o.GetName())
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, o client.Object, _ ...resource.ApplyOption) error {
+							if o.GetName() != "test-1234567" {
+								t.Errorf("Apply(...): unexpected apply of revision %q", o.GetName())
+							}
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:        testLog,
+					record:     event.NewNopRecorder(),
+					conditions: conditions.ObservedGenerationPropagationManager{},
+					metrics:    &controller.NopMetrics{},
+					clock:      testingclock.NewFakePassiveClock(now),
+					audit:      NewNopAuditSink(),
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: false},
+			},
+		},
+		"SuccessfulExternallyManagedRevisionNoOwnerReference": {
+			reason: "We should not add an owner reference to an externally managed current revision.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+								l := o.(*v1.ConfigurationRevisionList)
+								cr := v1.ConfigurationRevision{
+									ObjectMeta: metav1.ObjectMeta{
+										Name:        "test-1234567",
+										Annotations: map[string]string{v1.AnnotationExternallyManaged: "true"},
+									},
+								}
+								cr.SetRevision(1)
+								cr.SetConditions(v1.RevisionHealthy())
+								cr.SetDesiredState(v1.PackageRevisionActive)
+								*l = v1.ConfigurationRevisionList{Items: []v1.ConfigurationRevision{cr}}
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, o client.Object, _ ...resource.ApplyOption) error {
+							if refs := o.GetOwnerReferences(); len(refs) != 0 {
+								t.Errorf("Apply(...): unexpected owner references %v", refs)
+							}
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:        testLog,
+					record:     event.NewNopRecorder(),
+					conditions: conditions.ObservedGenerationPropagationManager{},
+					metrics:    &controller.NopMetrics{},
+					audit:      NewNopAuditSink(),
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: false},
+			},
+		},
+		"SuccessfulGCBlocked": {
+			reason: "We should explain why we can't garbage collect revisions when every revision outside range is protected.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								p.SetRevisionHistoryLimit(&revHistory)
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+								l := o.(*v1.ConfigurationRevisionList)
+								cr := v1.ConfigurationRevision{
+									ObjectMeta: metav1.ObjectMeta{
+										Name: "test-1234567",
+									},
+								}
+								cr.SetRevision(3)
+								cr.SetConditions(v1.RevisionHealthy())
+								cr.SetDesiredState(v1.PackageRevisionActive)
+								c := v1.ConfigurationRevisionList{
+									Items: []v1.ConfigurationRevision{
+										cr,
+										{
+											ObjectMeta: metav1.ObjectMeta{
+												Name:        "external-a",
+												Annotations: map[string]string{v1.AnnotationExternallyManaged: "true"},
+											},
+											Spec: v1.PackageRevisionSpec{
+												Revision:     2,
+												DesiredState: v1.PackageRevisionInactive,
+											},
+										},
+										{
+											ObjectMeta: metav1.ObjectMeta{
+												Name:        "external-b",
+												Annotations: map[string]string{v1.AnnotationExternallyManaged: "true"},
+											},
+											Spec: v1.PackageRevisionSpec{
+												Revision:     1,
+												DesiredState: v1.PackageRevisionInactive,
+											},
+										},
+									},
+								}
+								*l = c
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								want := &v1.Configuration{}
+								want.SetName("test")
+								want.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								want.SetCurrentRevision("test-1234567")
+								want.SetRevisionHistoryLimit(&revHistory)
+								want.SetEffectiveRevisionHistoryLimit(&revHistory)
+								want.SetConditions(v1.GarbageCollectionBlocked(3, revHistory, "external-a (externally managed)", "external-b (externally managed)", "test-1234567 (current revision)"))
+								want.SetConditions(v1.Healthy())
+								want.SetConditions(v1.Active())
+								want.SetRevisionSummaries([]v1.RevisionSummary{
+									{Name: "test-1234567", Revision: 3, DesiredState: v1.PackageRevisionActive, Healthy: corev1.ConditionTrue},
+									{Name: "external-a", Revision: 2, DesiredState: v1.PackageRevisionInactive, Healthy: corev1.ConditionFalse},
+									{Name: "external-b", Revision: 1, DesiredState: v1.PackageRevisionInactive, Healthy: corev1.ConditionFalse},
+								})
+								if diff := cmp.Diff(want, o, test.EquateConditions()); diff != "" {
+									t.Errorf("-want, +got:\n%s", diff)
+								}
+								return nil
+							}),
+							MockDelete: test.NewMockDeleteFn(nil, func(o client.Object) error {
+								t.Errorf("Delete(...): unexpected deletion of revision %q", o.GetName())
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:        testLog,
+					record:     event.NewNopRecorder(),
+					conditions: conditions.ObservedGenerationPropagationManager{},
+					metrics:    &controller.NopMetrics{},
+					audit:      NewNopAuditSink(),
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: false},
+			},
+		},
+		"SuccessfulGCBlockedByFallbacks": {
+			reason: "We should name the fallback revisions that block garbage collection, and why each is protected.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								p.SetRevisionHistoryLimit(&revHistory)
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+								l := o.(*v1.ConfigurationRevisionList)
+								cr := v1.ConfigurationRevision{
+									ObjectMeta: metav1.ObjectMeta{
+										Name: "test-1234567",
+									},
+								}
+								cr.SetRevision(3)
+								cr.SetConditions(v1.RevisionUnhealthy())
+								cr.SetDesiredState(v1.PackageRevisionActive)
+								healthy := v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: "test-healthy"}}
+								healthy.SetRevision(2)
+								healthy.SetConditions(v1.RevisionHealthy())
+								healthy.SetDesiredState(v1.PackageRevisionInactive)
+								unknown := v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: "test-unknown"}}
+								unknown.SetRevision(1)
+								unknown.SetDesiredState(v1.PackageRevisionInactive)
+								c := v1.ConfigurationRevisionList{Items: []v1.ConfigurationRevision{cr, healthy, unknown}}
+								*l = c
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								want := &v1.Configuration{}
+								want.SetName("test")
+								want.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								want.SetCurrentRevision("test-1234567")
+								want.SetRevisionHistoryLimit(&revHistory)
+								want.SetEffectiveRevisionHistoryLimit(&revHistory)
+								want.SetConditions(v1.GarbageCollectionBlocked(3, revHistory, "test-1234567 (current revision)", "test-healthy (newest healthy revision)", "test-unknown (health unknown)"))
+								want.SetConditions(v1.Unhealthy().WithMessage("Package revision health is \"False\""))
+								want.SetConditions(v1.Active())
+								want.SetRevisionSummaries([]v1.RevisionSummary{
+									{Name: "test-1234567", Revision: 3, DesiredState: v1.PackageRevisionActive, Healthy: corev1.ConditionFalse},
+									{Name: "test-healthy", Revision: 2, DesiredState: v1.PackageRevisionInactive, Healthy: corev1.ConditionTrue},
+									{Name: "test-unknown", Revision: 1, DesiredState: v1.PackageRevisionInactive, Healthy: corev1.ConditionFalse},
+								})
+								if diff := cmp.Diff(want, o, test.EquateConditions()); diff != "" {
+									t.Errorf("-want, +got:\n%s", diff)
+								}
+								return nil
+							}),
+							MockDelete: test.NewMockDeleteFn(nil, func(o client.Object) error {
+								t.Errorf("Delete(...): unexpected deletion of revision %q", o.GetName())
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:                   testLog,
+					record:                event.NewNopRecorder(),
+					conditions:            conditions.ObservedGenerationPropagationManager{},
+					metrics:               &controller.NopMetrics{},
+					audit:                 NewNopAuditSink(),
+					unknownHealthGCPolicy: UnknownHealthGCPolicyRetain,
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: false},
+			},
+		},
+		"SuccessfulSkipImmutableRevisionField": {
+			reason: "We should leave the revision as is and report why if applying it would change immutable fields and the policy is to skip.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+								l := o.(*v1.ConfigurationRevisionList)
+								cr := v1.ConfigurationRevision{
+									ObjectMeta: metav1.ObjectMeta{
+										Name: "test-1234567",
+										UID:  "test-uid",
+									},
+								}
+								cr.SetRevision(1)
+								cr.SetConditions(v1.RevisionHealthy())
+								cr.SetDesiredState(v1.PackageRevisionActive)
+								*l = v1.ConfigurationRevisionList{Items: []v1.ConfigurationRevision{cr}}
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								want := &v1.Configuration{}
+								want.SetName("test")
+								want.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								want.SetCurrentRevision("test-1234567")
+								want.SetConditions(v1.RevisionUpdateSkipped(errors.Wrap(errImmutable, errApplyPackageRevision)))
+								want.SetConditions(v1.Healthy())
+								want.SetConditions(v1.Active())
+								want.SetRevisionSummaries([]v1.RevisionSummary{{Name: "test-1234567", Revision: 1, DesiredState: v1.PackageRevisionActive, Healthy: corev1.ConditionTrue}})
+								if diff := cmp.Diff(want, o, test.EquateConditions()); diff != "" {
+									t.Errorf("-want, +got:\n%s", diff)
+								}
+								return nil
+							}),
+							MockDelete: test.NewMockDeleteFn(nil, func(o client.Object) error {
+								t.Errorf("Delete(...): unexpected deletion of revision %q", o.GetName())
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							return errImmutable
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:        testLog,
+					record:     event.NewNopRecorder(),
+					conditions: conditions.ObservedGenerationPropagationManager{},
+					metrics:    &controller.NopMetrics{},
+					audit:      NewNopAuditSink(),
+
+					immutableFieldPolicy: ImmutableFieldPolicySkip,
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: false},
+			},
+		},
+		"SuccessfulRecreateImmutableRevisionField": {
+			reason: "We should delete the revision, orphaning the objects it installed, so that it can be recreated if applying it would change immutable fields and the policy is to recreate.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+								l := o.(*v1.ConfigurationRevisionList)
+								cr := v1.ConfigurationRevision{
+									ObjectMeta: metav1.ObjectMeta{
+										Name: "test-1234567",
+										UID:  "test-uid",
+									},
+								}
+								cr.SetRevision(1)
+								cr.SetConditions(v1.RevisionHealthy())
+								cr.SetDesiredState(v1.PackageRevisionActive)
+								*l = v1.ConfigurationRevisionList{Items: []v1.ConfigurationRevision{cr}}
+								return nil
+							}),
+							MockDelete: func(_ context.Context, o client.Object, opts ...client.DeleteOption) error {
+								if o.GetName() != "test-1234567" {
+									t.Errorf("Delete(...): unexpected deletion of revision %q", o.GetName())
+								}
+								// The revision's objects must outlive it,
+								// lest garbage collecting its CRDs deletes
+								// every custom resource of their kinds.
+								do := &client.DeleteOptions{}
+								do.ApplyOptions(opts)
+								if diff := cmp.Diff(ptr.To(metav1.DeletePropagationOrphan), do.PropagationPolicy); diff != "" {
+									t.Errorf("Delete(...): -want propagation policy, +got propagation policy:\n%s", diff)
+								}
+								return nil
+							},
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							return errImmutable
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:        testLog,
+					record:     event.NewNopRecorder(),
+					conditions: conditions.ObservedGenerationPropagationManager{},
+					metrics:    &controller.NopMetrics{},
+					audit:      NewNopAuditSink(),
+
+					immutableFieldPolicy: ImmutableFieldPolicyRecreate,
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: true},
+			},
+		},
+		"RevisionLimitExceeded": {
+			reason: "We should not create a new revision if the package already has the maximum number of revisions.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+								l := o.(*v1.ConfigurationRevisionList)
+								*l = v1.ConfigurationRevisionList{Items: []v1.ConfigurationRevision{
+									{ObjectMeta: metav1.ObjectMeta{Name: "test-old-2"}, Spec: v1.PackageRevisionSpec{Revision: 2, DesiredState: v1.PackageRevisionActive}},
+									{ObjectMeta: metav1.ObjectMeta{Name: "test-old-1"}, Spec: v1.PackageRevisionSpec{Revision: 1, DesiredState: v1.PackageRevisionInactive}},
+								}}
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								want := &v1.Configuration{}
+								want.SetName("test")
+								want.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								want.SetConditions(v1.RevisionLimitExceeded(2, 2))
+								if diff := cmp.Diff(want, o, test.EquateConditions()); diff != "" {
+									t.Errorf("-want, +got:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, o client.Object, _ ...resource.ApplyOption) error {
+							t.Errorf("Apply(...): unexpected apply of revision %q", o.GetName())
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:          testLog,
+					record:       event.NewNopRecorder(),
+					conditions:   conditions.ObservedGenerationPropagationManager{},
+					metrics:      &controller.NopMetrics{},
+					audit:        NewNopAuditSink(),
+					maxRevisions: 2,
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: false},
+			},
+		},
+		"SuccessfulGCRetainsNewestHealthy": {
+			reason: "We should never garbage collect the newest healthy revision, even when it falls outside range.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								p.SetRevisionHistoryLimit(&revHistory)
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+								l := o.(*v1.ConfigurationRevisionList)
+								cur := v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: "test-1234567"}}
+								cur.SetRevision(3)
+								cur.SetConditions(v1.RevisionUnhealthy())
+								cur.SetDesiredState(v1.PackageRevisionActive)
+								unhealthy := v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: "test-unhealthy"}}
+								unhealthy.SetRevision(2)
+								unhealthy.SetConditions(v1.RevisionUnhealthy())
+								unhealthy.SetDesiredState(v1.PackageRevisionInactive)
+								healthy := v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: "test-healthy"}}
+								healthy.SetRevision(1)
+								healthy.SetConditions(v1.RevisionHealthy())
+								healthy.SetDesiredState(v1.PackageRevisionInactive)
+								*l = v1.ConfigurationRevisionList{Items: []v1.ConfigurationRevision{cur, unhealthy, healthy}}
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								want := &v1.Configuration{}
+								want.SetName("test")
+								want.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								want.SetCurrentRevision("test-1234567")
+								want.SetRevisionHistoryLimit(&revHistory)
+								want.SetEffectiveRevisionHistoryLimit(&revHistory)
+								want.SetConditions(v1.Unhealthy().WithMessage("Package revision health is \"False\""))
+								want.SetConditions(v1.Active())
+								want.SetRevisionSummaries([]v1.RevisionSummary{
+									{Name: "test-1234567", Revision: 3, DesiredState: v1.PackageRevisionActive, Healthy: corev1.ConditionFalse},
+									{Name: "test-healthy", Revision: 1, DesiredState: v1.PackageRevisionInactive, Healthy: corev1.ConditionTrue},
+								})
+								want.SetGarbageCollection(&v1.GarbageCollectionResult{Time: ptr.To(metav1.NewTime(now)), Collected: []string{"test-unhealthy"}, CollectedCount: 1})
+								if diff := cmp.Diff(want, o, test.EquateConditions()); diff != "" {
+									t.Errorf("-want, +got:\n%s", diff)
+								}
+								return nil
+							}),
+							MockDelete: test.NewMockDeleteFn(nil, func(o client.Object) error {
+								if o.GetName() != "test-unhealthy" {
+									t.Errorf("Delete(...): unexpected deletion of revision %q", o.GetName())
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:        testLog,
+					record:     event.NewNopRecorder(),
+					conditions: conditions.ObservedGenerationPropagationManager{},
+					metrics:    &controller.NopMetrics{},
+					clock:      testingclock.NewFakePassiveClock(now),
+					audit:      NewNopAuditSink(),
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: false},
+			},
+		},
+		"ErrDesiredRevisionNotFound": {
+			reason: "We should report that we can't roll back to a revision number that doesn't exist.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								p.SetDesiredRevisionNumber(&rollbackTo)
+								return nil
+							}),
+							MockList: test.NewMockListFn(kerrors.NewNotFound(schema.GroupResource{}, "")),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								want := &v1.Configuration{}
+								want.SetName("test")
+								want.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								want.SetDesiredRevisionNumber(&rollbackTo)
+								want.SetConditions(v1.DesiredRevisionNotFound(1))
+								if diff := cmp.Diff(want, o); diff != "" {
+									t.Errorf("-want, +got:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("", errBoom),
+					},
+					log:        testLog,
+					record:     event.NewNopRecorder(),
+					conditions: conditions.ObservedGenerationPropagationManager{},
+					metrics:    &controller.NopMetrics{},
+					audit:      NewNopAuditSink(),
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: false},
+			},
+		},
+		"SuccessfulRollback": {
+			reason: "We should activate the revision with the desired revision number, and deactivate all others.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								p.SetCurrentRevision("test-new")
+								p.SetDesiredRevisionNumber(&rollbackTo)
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+								l := o.(*v1.ConfigurationRevisionList)
+								cur := v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: "test-new"}}
+								cur.SetRevision(2)
+								cur.SetConditions(v1.RevisionUnhealthy())
+								cur.SetDesiredState(v1.PackageRevisionActive)
+								old := v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: "test-old"}}
+								old.SetRevision(1)
+								old.SetConditions(v1.RevisionHealthy())
+								old.SetDesiredState(v1.PackageRevisionInactive)
+								*l = v1.ConfigurationRevisionList{Items: []v1.ConfigurationRevision{cur, old}}
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								want := &v1.Configuration{}
+								want.SetName("test")
+								want.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								want.SetCurrentRevision("test-new")
+								want.SetDesiredRevisionNumber(&rollbackTo)
+								want.SetConditions(v1.Healthy(), v1.Active())
+								want.SetRevisionSummaries([]v1.RevisionSummary{
+									{Name: "test-new", Revision: 2, DesiredState: v1.PackageRevisionInactive, Healthy: corev1.ConditionFalse},
+									{Name: "test-old", Revision: 1, DesiredState: v1.PackageRevisionActive, Healthy: corev1.ConditionTrue},
+								})
+								if diff := cmp.Diff(want, o, test.EquateConditions()); diff != "" {
+									t.Errorf("-want, +got:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, o client.Object, _ ...resource.ApplyOption) error {
+							want := map[string]v1.PackageRevisionDesiredState{
+								"test-new": v1.PackageRevisionInactive,
+								"test-old": v1.PackageRevisionActive,
+							}
+							if got := o.(*v1.ConfigurationRevision).GetDesiredState(); got != want[o.GetName()] {
+								t.Errorf("Apply(...): want revision %q to be %s, got %s", o.GetName(), want[o.GetName()], got)
+							}
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("", errBoom),
+					},
+					log:        testLog,
+					record:     event.NewNopRecorder(),
+					conditions: conditions.ObservedGenerationPropagationManager{},
+					metrics:    &controller.NopMetrics{},
+					audit:      NewNopAuditSink(),
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: false},
+			},
+		},
+		"PauseReconcile": {
+			reason: "Pause reconciliation if the pause annotation is set",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								p.SetActivationPolicy(&v1.AutomaticActivation)
+								p.SetAnnotations(map[string]string{
+									meta.AnnotationKeyReconciliationPaused: "true",
+								})
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								want := &v1.Configuration{}
+								want.SetName("test")
+								want.SetAnnotations(map[string]string{
+									meta.AnnotationKeyReconciliationPaused: "true",
+								})
+								want.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								want.SetActivationPolicy(&v1.AutomaticActivation)
+								want.SetConditions(commonv1.ReconcilePaused().WithMessage(reconcilePausedMsg))
+								if diff := cmp.Diff(want, o); diff != "" {
+									t.Errorf("-want, +got:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:        testLog,
+					record:     event.NewNopRecorder(),
+					conditions: conditions.ObservedGenerationPropagationManager{},
+					metrics:    &controller.NopMetrics{},
+					audit:      NewNopAuditSink(),
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: false},
+			},
+		},
+		"ResumeReconcile": {
+			reason: "We should be active and not requeue on successful creation of the first revision with auto activation.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								p.SetActivationPolicy(&v1.AutomaticActivation)
+								p.SetConditions(commonv1.ReconcilePaused())
+								return nil
+							}),
+							MockList: test.NewMockListFn(kerrors.NewNotFound(schema.GroupResource{}, "")),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								want := &v1.Configuration{}
+								want.SetName("test")
+								want.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								want.SetActivationPolicy(&v1.AutomaticActivation)
+								want.Status.Conditions = []commonv1.Condition{}
+								if diff := cmp.Diff(want, o); diff != "" {
+									t.Errorf("-want, +got:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:        testLog,
+					record:     event.NewNopRecorder(),
+					conditions: conditions.ObservedGenerationPropagationManager{},
+					metrics:    &controller.NopMetrics{},
+					audit:      NewNopAuditSink(),
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: false},
+			},
+		},
+		"SuccessfulNewRevisionSBOMs": {
+			reason: "We should record all of a new revision's SBOMs in its status, but only annotate the package with a bounded number of them.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								return nil
+							}),
+							MockList:         test.NewMockListFn(kerrors.NewNotFound(schema.GroupResource{}, "")),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
+							MockStatusPatch: test.NewMockSubResourcePatchFn(nil, func(o client.Object) error {
+								pr := o.(*v1.ConfigurationRevision)
+								if diff := cmp.Diff(sbomRefs, pr.GetSBOMReferences()); diff != "" {
+									t.Errorf("-want SBOM references, +got SBOM references:\n%s", diff)
+								}
+								return nil
+							}),
+							MockUpdate: test.NewMockUpdateFn(nil, func(o client.Object) error {
+								want := strings.Join(sbomRefs[:maxAnnotatedSBOMReferences], ",")
+								if diff := cmp.Diff(want, o.GetAnnotations()[v1.AnnotationSBOMReferences]); diff != "" {
+									t.Errorf("-want SBOM annotation, +got SBOM annotation:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					sboms: SBOMListerFn(func(_ context.Context, _ v1.Package, _ ...string) ([]string, error) {
+						return sbomRefs, nil
+					}),
+					log:        testLog,
+					record:     event.NewNopRecorder(),
+					conditions: conditions.ObservedGenerationPropagationManager{},
+					metrics:    &controller.NopMetrics{},
+					audit:      NewNopAuditSink(),
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: false},
+			},
+		},
+		"SuccessfulRemoveSBOMAnnotation": {
+			reason: "We should remove a package's SBOM annotation if its current revision has no SBOMs, without discovering the SBOMs of an existing revision again.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								p.SetAnnotations(map[string]string{v1.AnnotationSBOMReferences: sbomRefs[0]})
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+								l := o.(*v1.ConfigurationRevisionList)
+								cr := v1.ConfigurationRevision{
+									ObjectMeta: metav1.ObjectMeta{
+										Name: "test-1234567",
+										UID:  "existing",
+									},
+								}
+								cr.SetConditions(v1.RevisionHealthy())
+								l.Items = []v1.ConfigurationRevision{cr}
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
+							MockUpdate: test.NewMockUpdateFn(nil, func(o client.Object) error {
+								if _, ok := o.GetAnnotations()[v1.AnnotationSBOMReferences]; ok {
+									t.Errorf("Update(...): want SBOM annotation removed")
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					sboms: SBOMListerFn(func(_ context.Context, _ v1.Package, _ ...string) ([]string, error) {
+						t.Errorf("ListSBOMs(...): want no call for an existing revision")
+						return nil, nil
+					}),
+					log:        testLog,
+					record:     event.NewNopRecorder(),
+					conditions: conditions.ObservedGenerationPropagationManager{},
+					metrics:    &controller.NopMetrics{},
+					audit:      NewNopAuditSink(),
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: false},
+			},
+		},
+		"ErrListSBOMs": {
+			reason: "We should return an error without creating a new revision if we can't discover its SBOMs.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								return nil
+							}),
+							MockList: test.NewMockListFn(kerrors.NewNotFound(schema.GroupResource{}, "")),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							t.Errorf("Apply(...): want no revision created")
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					sboms: SBOMListerFn(func(_ context.Context, _ v1.Package, _ ...string) ([]string, error) {
+						return nil, errBoom
+					}),
+					log:        testLog,
+					record:     event.NewNopRecorder(),
+					conditions: conditions.ObservedGenerationPropagationManager{},
+					metrics:    &controller.NopMetrics{},
+					audit:      NewNopAuditSink(),
+				},
+			},
+			want: want{
+				err: errors.Wrapf(errBoom, errFmtListSBOMs, "test-1234567"),
+			},
+		},
+		"Audit": {
+			reason: "We should record an audit event for each revision we create, activate, and garbage collect.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								p.SetActivationPolicy(&v1.AutomaticActivation)
+								p.SetRevisionHistoryLimit(&revHistory)
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+								l := o.(*v1.ConfigurationRevisionList)
+								*l = v1.ConfigurationRevisionList{Items: []v1.ConfigurationRevision{
+									{ObjectMeta: metav1.ObjectMeta{Name: "test-old-1"}, Spec: v1.PackageRevisionSpec{Revision: 1, DesiredState: v1.PackageRevisionInactive}},
+									{ObjectMeta: metav1.ObjectMeta{Name: "test-old-2"}, Spec: v1.PackageRevisionSpec{Revision: 2, DesiredState: v1.PackageRevisionInactive}},
+									{ObjectMeta: metav1.ObjectMeta{Name: "test-old-3"}, Spec: v1.PackageRevisionSpec{Revision: 3, DesiredState: v1.PackageRevisionInactive}},
+								}}
+								return nil
+							}),
+							MockDelete:       test.NewMockDeleteFn(nil),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:        testLog,
+					record:     event.NewNopRecorder(),
+					conditions: conditions.ObservedGenerationPropagationManager{},
+					metrics:    &controller.NopMetrics{},
+					clock:      testingclock.NewFakePassiveClock(now),
+					audit: AuditSinkFn(func(_ context.Context, e AuditEvent) {
+						pkg := commonv1.TypedReference{APIVersion: v1.SchemeGroupVersion.String(), Kind: v1.ConfigurationKind, Name: "test"}
+						want := map[AuditAction]AuditEvent{
+							AuditActionCreate:         {Package: pkg, Revision: "test-1234567", Action: AuditActionCreate, Actor: "packages/configuration.pkg.crossplane.io"},
+							AuditActionActivate:       {Package: pkg, Revision: "test-1234567", Action: AuditActionActivate, Actor: "packages/configuration.pkg.crossplane.io"},
+							AuditActionGarbageCollect: {Package: pkg, Revision: "test-old-1", Action: AuditActionGarbageCollect, Actor: "packages/configuration.pkg.crossplane.io"},
+						}
+						if diff := cmp.Diff(want[e.Action], e); diff != "" {
+							t.Errorf("Audit(...): -want audit event, +got audit event:\n%s", diff)
+						}
+					}),
+					actor: "packages/configuration.pkg.crossplane.io",
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"OwnershipDriftIgnore": {
+			reason: "We should leave a revision that lost the package's controller reference as is by default.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetUID("test-uid")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+								l := o.(*v1.ConfigurationRevisionList)
+								cur := v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: "test-1234567"}}
+								cur.SetRevision(2)
+								cur.SetConditions(v1.RevisionHealthy())
+								cur.SetDesiredState(v1.PackageRevisionActive)
+
+								// This revision is labelled as belonging to the
+								// package, but has no owner references.
+								old := v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: "test-old"}}
+								old.SetRevision(1)
+								old.SetDesiredState(v1.PackageRevisionInactive)
+								*l = v1.ConfigurationRevisionList{Items: []v1.ConfigurationRevision{cur, old}}
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								want := []commonv1.Condition{v1.Healthy(), v1.Active()}
+								if diff := cmp.Diff(want, o.(*v1.Configuration).Status.Conditions, test.EquateConditions()); diff != "" {
+									t.Errorf("StatusUpdate(...): -want conditions, +got conditions:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, o client.Object, _ ...resource.ApplyOption) error {
+							if o.GetName() != "test-1234567" {
+								t.Errorf("Apply(...): we shouldn't adopt revision %q", o.GetName())
+							}
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:                  testLog,
+					record:               event.NewNopRecorder(),
+					conditions:           conditions.ObservedGenerationPropagationManager{},
+					metrics:              &controller.NopMetrics{},
+					audit:                NewNopAuditSink(),
+					ownershipDriftPolicy: OwnershipDriftPolicyIgnore,
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"OwnershipDriftAdopt": {
+			reason: "We should restore the package's controller reference to a revision that lost it.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetUID("test-uid")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+								l := o.(*v1.ConfigurationRevisionList)
+								cur := v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: "test-1234567"}}
+								cur.SetRevision(2)
+								cur.SetConditions(v1.RevisionHealthy())
+								cur.SetDesiredState(v1.PackageRevisionActive)
+
+								// This revision is labelled as belonging to the
+								// package, but has no owner references.
+								old := v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: "test-old"}}
+								old.SetRevision(1)
+								old.SetDesiredState(v1.PackageRevisionInactive)
+								*l = v1.ConfigurationRevisionList{Items: []v1.ConfigurationRevision{cur, old}}
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								want := []commonv1.Condition{v1.Healthy(), v1.Active()}
+								if diff := cmp.Diff(want, o.(*v1.Configuration).Status.Conditions, test.EquateConditions()); diff != "" {
+									t.Errorf("StatusUpdate(...): -want conditions, +got conditions:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, o client.Object, _ ...resource.ApplyOption) error {
+							if o.GetName() == "test-1234567" {
+								return nil
+							}
+							if o.GetName() != "test-old" {
+								t.Errorf("Apply(...): we shouldn't adopt revision %q", o.GetName())
+							}
+							if ref := metav1.GetControllerOf(o); ref == nil || ref.UID != "test-uid" {
+								t.Errorf("Apply(...): want revision %q to be controlled by the package", o.GetName())
+							}
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:                  testLog,
+					record:               event.NewNopRecorder(),
+					conditions:           conditions.ObservedGenerationPropagationManager{},
+					metrics:              &controller.NopMetrics{},
+					audit:                NewNopAuditSink(),
+					ownershipDriftPolicy: OwnershipDriftPolicyAdopt,
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"OwnershipDriftReport": {
+			reason: "We should report a revision that lost the package's controller reference using a condition.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetUID("test-uid")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+								l := o.(*v1.ConfigurationRevisionList)
+								cur := v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: "test-1234567"}}
+								cur.SetRevision(2)
+								cur.SetConditions(v1.RevisionHealthy())
+								cur.SetDesiredState(v1.PackageRevisionActive)
+
+								// This revision is labelled as belonging to the
+								// package, but has no owner references.
+								old := v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: "test-old"}}
+								old.SetRevision(1)
+								old.SetDesiredState(v1.PackageRevisionInactive)
+								*l = v1.ConfigurationRevisionList{Items: []v1.ConfigurationRevision{cur, old}}
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								want := []commonv1.Condition{v1.Healthy(), v1.Active(), v1.OwnershipDrift("test-old")}
+								if diff := cmp.Diff(want, o.(*v1.Configuration).Status.Conditions, test.EquateConditions()); diff != "" {
+									t.Errorf("StatusUpdate(...): -want conditions, +got conditions:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, o client.Object, _ ...resource.ApplyOption) error {
+							if o.GetName() != "test-1234567" {
+								t.Errorf("Apply(...): we shouldn't adopt revision %q", o.GetName())
+							}
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:                  testLog,
+					record:               event.NewNopRecorder(),
+					conditions:           conditions.ObservedGenerationPropagationManager{},
+					metrics:              &controller.NopMetrics{},
+					audit:                NewNopAuditSink(),
+					ownershipDriftPolicy: OwnershipDriftPolicyReport,
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"ActivationDelayNotElapsed": {
+			reason: "We should requeue without activating the revision, or deactivating the previously active revision, until its activation delay elapses.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								p.SetActivationPolicy(&v1.AutomaticActivation)
+								p.SetActivationDelay(&metav1.Duration{Duration: time.Hour})
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+								l := o.(*v1.ConfigurationRevisionList)
+								old := v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: "test-old"}}
+								old.SetRevision(1)
+								old.SetConditions(v1.RevisionHealthy())
+								old.SetDesiredState(v1.PackageRevisionActive)
+								cr := v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: "test-1234567", CreationTimestamp: metav1.NewTime(now.Add(-10 * time.Minute))}}
+								cr.SetRevision(2)
+								cr.SetConditions(v1.RevisionHealthy())
+								cr.SetDesiredState(v1.PackageRevisionInactive)
+								*l = v1.ConfigurationRevisionList{Items: []v1.ConfigurationRevision{old, cr}}
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, o client.Object, _ ...resource.ApplyOption) error {
+							if o.GetName() != "test-1234567" {
+								t.Errorf("Apply(...): we shouldn't deactivate revision %q during the activation delay", o.GetName())
+							}
+							if got := o.(*v1.ConfigurationRevision).GetDesiredState(); got != v1.PackageRevisionInactive {
+								t.Errorf("Apply(...): want revision %q inactive during the activation delay, got %q", o.GetName(), got)
+							}
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:        testLog,
+					record:     event.NewNopRecorder(),
+					conditions: conditions.ObservedGenerationPropagationManager{},
+					metrics:    &controller.NopMetrics{},
+					audit:      NewNopAuditSink(),
+					clock:      testingclock.NewFakePassiveClock(now),
+				},
+			},
+			want: want{
+				// The revision was created 10 minutes into its hour long
+				// activation delay, so we should requeue in 50 minutes.
+				r: reconcile.Result{RequeueAfter: 50 * time.Minute},
+			},
+		},
+		"ActivationDelayElapsed": {
+			reason: "We should activate the revision, and deactivate the previously active revision, once its activation delay elapses.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								p.SetActivationPolicy(&v1.AutomaticActivation)
+								p.SetActivationDelay(&metav1.Duration{Duration: time.Hour})
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+								l := o.(*v1.ConfigurationRevisionList)
+								old := v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: "test-old"}}
+								old.SetRevision(1)
+								old.SetConditions(v1.RevisionHealthy())
+								old.SetDesiredState(v1.PackageRevisionActive)
+								cr := v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: "test-1234567", CreationTimestamp: metav1.NewTime(now.Add(-2 * time.Hour))}}
+								cr.SetRevision(2)
+								cr.SetConditions(v1.RevisionHealthy())
+								cr.SetDesiredState(v1.PackageRevisionInactive)
+								*l = v1.ConfigurationRevisionList{Items: []v1.ConfigurationRevision{old, cr}}
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, o client.Object, _ ...resource.ApplyOption) error {
+							want := map[string]v1.PackageRevisionDesiredState{
+								"test-old":     v1.PackageRevisionInactive,
+								"test-1234567": v1.PackageRevisionActive,
+							}
+							if diff := cmp.Diff(want[o.GetName()], o.(*v1.ConfigurationRevision).GetDesiredState()); diff != "" {
+								t.Errorf("Apply(...): -want revision %q desired state, +got desired state:\n%s", o.GetName(), diff)
+							}
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:        testLog,
+					record:     event.NewNopRecorder(),
+					conditions: conditions.ObservedGenerationPropagationManager{},
+					metrics:    &controller.NopMetrics{},
+					audit:      NewNopAuditSink(),
+					clock:      testingclock.NewFakePassiveClock(now),
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"PullSecretSynced": {
+			reason: "We should install the package once its pull secret has been synced from the external secret store.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: func(_ context.Context, _ client.ObjectKey, o client.Object) error {
+								if o, ok := o.(*v1.Configuration); ok {
+									o.SetName("test")
+									o.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								}
+								return nil
+							},
+							MockList: test.NewMockListFn(kerrors.NewNotFound(schema.GroupResource{}, "")),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								want := []commonv1.Condition{v1.Unhealthy().WithMessage("Package revision health is \"Unknown\""), v1.Active()}
+								if diff := cmp.Diff(want, o.(*v1.Configuration).Status.Conditions, test.EquateConditions()); diff != "" {
+									t.Errorf("StatusUpdate(...): -want conditions, +got conditions:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("image-config", "synced-secret", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:        testLog,
+					record:     event.NewNopRecorder(),
+					conditions: conditions.ObservedGenerationPropagationManager{},
+					metrics:    &controller.NopMetrics{},
+					audit:      NewNopAuditSink(),
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"DeletionPolicyOrphan": {
+			reason: "We should remove the package's owner references from its revisions before removing our finalizer.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetUID("test-uid")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								p.SetDeletionPolicy(commonv1.DeletionOrphan)
+								p.SetDeletionTimestamp(&deletedAt)
+								p.SetFinalizers([]string{finalizer})
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+								ref := metav1.OwnerReference{UID: "test-uid", Name: "test"}
+								other := metav1.OwnerReference{UID: "other-uid", Name: "other"}
+
+								l := o.(*v1.ConfigurationRevisionList)
+								cur := v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: "test-1234567", OwnerReferences: []metav1.OwnerReference{ref, other}}}
+								old := v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: "test-old", OwnerReferences: []metav1.OwnerReference{ref}}}
+								unowned := v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: "test-unowned"}}
+								*l = v1.ConfigurationRevisionList{Items: []v1.ConfigurationRevision{cur, old, unowned}}
+								return nil
+							}),
+							MockUpdate: test.NewMockUpdateFn(nil, func(o client.Object) error {
+								if o.GetName() == "test-unowned" {
+									t.Errorf("Update(...): we shouldn't orphan revision %q, which the package doesn't own", o.GetName())
+								}
+								for _, ref := range o.GetOwnerReferences() {
+									if ref.UID == "test-uid" {
+										t.Errorf("Update(...): want revision %q not to be owned by the package", o.GetName())
+									}
+								}
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					finalizer: resource.FinalizerFns{
+						AddFinalizerFn: func(_ context.Context, _ resource.Object) error {
+							t.Errorf("AddFinalizer(...): we shouldn't add our finalizer to a deleted package")
+							return nil
+						},
+						RemoveFinalizerFn: func(_ context.Context, _ resource.Object) error {
+							return nil
+						},
+					},
+					log:        testLog,
+					record:     event.NewNopRecorder(),
+					conditions: conditions.ObservedGenerationPropagationManager{},
+					metrics:    &controller.NopMetrics{},
+					audit:      NewNopAuditSink(),
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"DeletionPolicyDelete": {
+			reason: "We should remove our finalizer without orphaning revisions if the policy changed to Delete.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetUID("test-uid")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								p.SetDeletionPolicy(commonv1.DeletionDelete)
+								p.SetDeletionTimestamp(&deletedAt)
+								p.SetFinalizers([]string{finalizer})
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+								ref := metav1.OwnerReference{UID: "test-uid", Name: "test"}
+								other := metav1.OwnerReference{UID: "other-uid", Name: "other"}
+
+								l := o.(*v1.ConfigurationRevisionList)
+								cur := v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: "test-1234567", OwnerReferences: []metav1.OwnerReference{ref, other}}}
+								old := v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: "test-old", OwnerReferences: []metav1.OwnerReference{ref}}}
+								unowned := v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: "test-unowned"}}
+								*l = v1.ConfigurationRevisionList{Items: []v1.ConfigurationRevision{cur, old, unowned}}
+								return nil
+							}),
+							MockUpdate: test.NewMockUpdateFn(nil, func(o client.Object) error {
+								t.Errorf("Update(...): we shouldn't orphan revision %q", o.GetName())
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					finalizer: resource.FinalizerFns{
+						AddFinalizerFn: func(_ context.Context, _ resource.Object) error {
+							t.Errorf("AddFinalizer(...): we shouldn't add our finalizer to a deleted package")
+							return nil
+						},
+						RemoveFinalizerFn: func(_ context.Context, _ resource.Object) error {
+							return nil
+						},
+					},
+					log:        testLog,
+					record:     event.NewNopRecorder(),
+					conditions: conditions.ObservedGenerationPropagationManager{},
+					metrics:    &controller.NopMetrics{},
+					audit:      NewNopAuditSink(),
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"DeletionPolicyAddFinalizer": {
+			reason: "We should add our finalizer to a package whose revisions must be orphaned.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetUID("test-uid")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								p.SetDeletionPolicy(commonv1.DeletionOrphan)
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+								ref := metav1.OwnerReference{UID: "test-uid", Name: "test"}
+								other := metav1.OwnerReference{UID: "other-uid", Name: "other"}
+
+								l := o.(*v1.ConfigurationRevisionList)
+								cur := v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: "test-1234567", OwnerReferences: []metav1.OwnerReference{ref, other}}}
+								old := v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: "test-old", OwnerReferences: []metav1.OwnerReference{ref}}}
+								unowned := v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: "test-unowned"}}
+								*l = v1.ConfigurationRevisionList{Items: []v1.ConfigurationRevision{cur, old, unowned}}
+								return nil
+							}),
+							MockUpdate: test.NewMockUpdateFn(nil, func(o client.Object) error {
+								t.Errorf("Update(...): we shouldn't orphan revision %q", o.GetName())
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					finalizer: resource.FinalizerFns{
+						AddFinalizerFn: func(_ context.Context, _ resource.Object) error {
+							return nil
+						},
+						RemoveFinalizerFn: func(_ context.Context, _ resource.Object) error {
+							t.Errorf("RemoveFinalizer(...): we shouldn't remove our finalizer from a package that isn't deleted")
+							return nil
+						},
+					},
+					log:        testLog,
+					record:     event.NewNopRecorder(),
+					conditions: conditions.ObservedGenerationPropagationManager{},
+					metrics:    &controller.NopMetrics{},
+					audit:      NewNopAuditSink(),
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"ActiveRevisionQuotaWithinQuota": {
+			reason: "We should activate a revision, and deactivate the previously active revision, if its tenant's other packages have fewer active revisions than its quota.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								p.SetLabels(map[string]string{"example.org/tenant": "acme"})
+								return nil
+							}),
+							MockList: func(_ context.Context, o client.ObjectList, opts ...client.ListOption) error {
+								lo := &client.ListOptions{}
+								lo.ApplyOptions(opts)
+
+								// The package's previous revision is active.
+								old := v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{
+									Name:   "test-old",
+									Labels: map[string]string{v1.LabelParentPackage: "test", "example.org/tenant": "acme"},
+								}}
+								old.SetRevision(1)
+								old.SetDesiredState(v1.PackageRevisionActive)
+
+								if lo.LabelSelector == nil || lo.LabelSelector.String() != "example.org/tenant=acme" {
+									if l, ok := o.(*v1.ConfigurationRevisionList); ok {
+										*l = v1.ConfigurationRevisionList{Items: []v1.ConfigurationRevision{old}}
+									}
+									return nil
+								}
+
+								// The tenant has another configuration and a provider with the
+								// same name as the package, each with an active revision.
+								switch l := o.(type) {
+								case *v1.ConfigurationRevisionList:
+									other := v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{
+										Name:   "other-1234567",
+										Labels: map[string]string{v1.LabelParentPackage: "other", "example.org/tenant": "acme"},
+									}}
+									other.SetDesiredState(v1.PackageRevisionActive)
+									*l = v1.ConfigurationRevisionList{Items: []v1.ConfigurationRevision{old, other}}
+								case *v1.ProviderRevisionList:
+									provider := v1.ProviderRevision{ObjectMeta: metav1.ObjectMeta{
+										Name:   "test-7654321",
+										Labels: map[string]string{v1.LabelParentPackage: "test", "example.org/tenant": "acme"},
+									}}
+									provider.SetDesiredState(v1.PackageRevisionActive)
+									*l = v1.ProviderRevisionList{Items: []v1.ProviderRevision{provider}}
+								}
+								return nil
+							},
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								want := []commonv1.Condition{v1.Unhealthy().WithMessage("Package revision health is \"Unknown\""), v1.Active()}
+								if diff := cmp.Diff(want, o.(*v1.Configuration).Status.Conditions, test.EquateConditions()); diff != "" {
+									t.Errorf("StatusUpdate(...): -want conditions, +got conditions:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, o client.Object, _ ...resource.ApplyOption) error {
+							pr := o.(*v1.ConfigurationRevision)
+							if diff := cmp.Diff("acme", pr.GetLabels()["example.org/tenant"]); diff != "" {
+								t.Errorf("Apply(...): -want tenant label, +got tenant label:\n%s", diff)
+							}
+							want := map[string]v1.PackageRevisionDesiredState{
+								"test-old":     v1.PackageRevisionInactive,
+								"test-1234567": v1.PackageRevisionActive,
+							}
+							if diff := cmp.Diff(want[pr.GetName()], pr.GetDesiredState()); diff != "" {
+								t.Errorf("Apply(...): -want revision %q desired state, +got desired state:\n%s", pr.GetName(), diff)
+							}
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:         testLog,
+					record:      event.NewNopRecorder(),
+					conditions:  conditions.ObservedGenerationPropagationManager{},
+					metrics:     &controller.NopMetrics{},
+					audit:       NewNopAuditSink(),
+					clock:       testingclock.NewFakePassiveClock(now),
+					tenantLabel: "example.org/tenant",
+					quota: QuotaSourceFn(func(_ context.Context, tenant string) (int, bool, error) {
+						return 3, tenant == "acme", nil
+					}),
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"ActiveRevisionQuotaExceeded": {
+			reason: "We should not activate a revision, or deactivate the previously active revision, if its tenant's other packages of any kind already have its quota of active revisions.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								p.SetLabels(map[string]string{"example.org/tenant": "acme"})
+								return nil
+							}),
+							MockList: func(_ context.Context, o client.ObjectList, opts ...client.ListOption) error {
+								lo := &client.ListOptions{}
+								lo.ApplyOptions(opts)
+
+								// The package's previous revision is active.
+								old := v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{
+									Name:   "test-old",
+									Labels: map[string]string{v1.LabelParentPackage: "test", "example.org/tenant": "acme"},
+								}}
+								old.SetRevision(1)
+								old.SetDesiredState(v1.PackageRevisionActive)
+
+								if lo.LabelSelector == nil || lo.LabelSelector.String() != "example.org/tenant=acme" {
+									if l, ok := o.(*v1.ConfigurationRevisionList); ok {
+										*l = v1.ConfigurationRevisionList{Items: []v1.ConfigurationRevision{old}}
+									}
+									return nil
+								}
+
+								// The tenant has another configuration and a provider with the
+								// same name as the package, each with an active revision.
+								switch l := o.(type) {
+								case *v1.ConfigurationRevisionList:
+									other := v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{
+										Name:   "other-1234567",
+										Labels: map[string]string{v1.LabelParentPackage: "other", "example.org/tenant": "acme"},
+									}}
+									other.SetDesiredState(v1.PackageRevisionActive)
+									*l = v1.ConfigurationRevisionList{Items: []v1.ConfigurationRevision{old, other}}
+								case *v1.ProviderRevisionList:
+									provider := v1.ProviderRevision{ObjectMeta: metav1.ObjectMeta{
+										Name:   "test-7654321",
+										Labels: map[string]string{v1.LabelParentPackage: "test", "example.org/tenant": "acme"},
+									}}
+									provider.SetDesiredState(v1.PackageRevisionActive)
+									*l = v1.ProviderRevisionList{Items: []v1.ProviderRevision{provider}}
+								}
+								return nil
+							},
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								want := []commonv1.Condition{v1.Unhealthy().WithMessage("Package revision health is \"Unknown\""), v1.QuotaExceeded("acme", 2)}
+								if diff := cmp.Diff(want, o.(*v1.Configuration).Status.Conditions, test.EquateConditions()); diff != "" {
+									t.Errorf("StatusUpdate(...): -want conditions, +got conditions:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, o client.Object, _ ...resource.ApplyOption) error {
+							pr := o.(*v1.ConfigurationRevision)
+							if diff := cmp.Diff("acme", pr.GetLabels()["example.org/tenant"]); diff != "" {
+								t.Errorf("Apply(...): -want tenant label, +got tenant label:\n%s", diff)
+							}
+							want := map[string]v1.PackageRevisionDesiredState{"test-1234567": v1.PackageRevisionInactive}
+							if diff := cmp.Diff(want[pr.GetName()], pr.GetDesiredState()); diff != "" {
+								t.Errorf("Apply(...): -want revision %q desired state, +got desired state:\n%s", pr.GetName(), diff)
+							}
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:         testLog,
+					record:      event.NewNopRecorder(),
+					conditions:  conditions.ObservedGenerationPropagationManager{},
+					metrics:     &controller.NopMetrics{},
+					audit:       NewNopAuditSink(),
+					clock:       testingclock.NewFakePassiveClock(now),
+					tenantLabel: "example.org/tenant",
+					quota: QuotaSourceFn(func(_ context.Context, tenant string) (int, bool, error) {
+						return 2, tenant == "acme", nil
+					}),
+				},
+			},
+			want: want{
+				r: reconcile.Result{RequeueAfter: quotaRecheckInterval},
+			},
+		},
+		"ImageInfo": {
+			reason: "We should record the size and platform of the image a revision was resolved to in its status.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								pr, ok := o.(*v1.ConfigurationRevision)
+								if !ok {
+									return nil
+								}
+
+								if diff := cmp.Diff("linux/arm64", pr.GetResolvedPlatform()); diff != "" {
+									t.Errorf("StatusUpdate(...): -want platform, +got platform:\n%s", diff)
+								}
+								if diff := cmp.Diff(&imageSize, pr.GetResolvedImageSize()); diff != "" {
+									t.Errorf("StatusUpdate(...): -want size, +got size:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision:  NewMockRevisionFn("test-1234567", nil),
+						MockImageInfo: func() *ImageInfo { return &ImageInfo{Platform: "linux/arm64", Size: &imageSize} },
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:        testLog,
+					record:     event.NewNopRecorder(),
+					conditions: conditions.ObservedGenerationPropagationManager{},
+					metrics:    &controller.NopMetrics{},
+					audit:      NewNopAuditSink(),
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"ImageInfoUnknownSize": {
+			reason: "We should record the platform of the image a revision was resolved to even if its size is unknown.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								pr, ok := o.(*v1.ConfigurationRevision)
+								if !ok {
+									return nil
+								}
+
+								if diff := cmp.Diff("linux/arm64", pr.GetResolvedPlatform()); diff != "" {
+									t.Errorf("StatusUpdate(...): -want platform, +got platform:\n%s", diff)
+								}
+								if size := pr.GetResolvedImageSize(); size != nil {
+									t.Errorf("StatusUpdate(...): want no size, got %d", *size)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision:  NewMockRevisionFn("test-1234567", nil),
+						MockImageInfo: func() *ImageInfo { return &ImageInfo{Platform: "linux/arm64"} },
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:        testLog,
+					record:     event.NewNopRecorder(),
+					conditions: conditions.ObservedGenerationPropagationManager{},
+					metrics:    &controller.NopMetrics{},
+					audit:      NewNopAuditSink(),
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"NoImageInfo": {
+			reason: "We shouldn't update a revision's status if the revisioner didn't inspect its image.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								pr, ok := o.(*v1.ConfigurationRevision)
+								if !ok {
+									return nil
+								}
+
+								t.Errorf("StatusUpdate(...): we shouldn't update revision %q's status", pr.GetName())
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision:  NewMockRevisionFn("test-1234567", nil),
+						MockImageInfo: func() *ImageInfo { return nil },
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:        testLog,
+					record:     event.NewNopRecorder(),
+					conditions: conditions.ObservedGenerationPropagationManager{},
+					metrics:    &controller.NopMetrics{},
+					audit:      NewNopAuditSink(),
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"PropagatedRevisionConditionsDefault": {
+			reason: "We should only derive the package's health from its current revision by default.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+								l := o.(*v1.ConfigurationRevisionList)
+								cur := v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: "test-1234567"}}
+								cur.SetRevision(1)
+								cur.SetConditions(v1.RevisionHealthy(), commonv1.Condition{
+									Type:    "RevisionDegraded",
+									Status:  corev1.ConditionTrue,
+									Reason:  "SlowWebhook",
+									Message: "Conversion webhook is slow to respond",
+								})
+								cur.SetDesiredState(v1.PackageRevisionActive)
+								*l = v1.ConfigurationRevisionList{Items: []v1.ConfigurationRevision{cur}}
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								want := []commonv1.Condition{v1.Healthy(), v1.Active()}
+								if diff := cmp.Diff(want, o.(*v1.Configuration).Status.Conditions, test.EquateConditions()); diff != "" {
+									t.Errorf("StatusUpdate(...): -want conditions, +got conditions:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:        testLog,
+					record:     event.NewNopRecorder(),
+					conditions: conditions.ObservedGenerationPropagationManager{},
+					metrics:    &controller.NopMetrics{},
+					audit:      NewNopAuditSink(),
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"PropagatedRevisionConditions": {
+			reason: "We should mirror the configured revision conditions to the package, with a prefix.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+								l := o.(*v1.ConfigurationRevisionList)
+								cur := v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: "test-1234567"}}
+								cur.SetRevision(1)
+								cur.SetConditions(v1.RevisionHealthy(), commonv1.Condition{
+									Type:    "RevisionDegraded",
+									Status:  corev1.ConditionTrue,
+									Reason:  "SlowWebhook",
+									Message: "Conversion webhook is slow to respond",
+								})
+								cur.SetDesiredState(v1.PackageRevisionActive)
+								*l = v1.ConfigurationRevisionList{Items: []v1.ConfigurationRevision{cur}}
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								want := []commonv1.Condition{
+									v1.Healthy(),
+									{
+										Type:    "CurrentRevisionDegraded",
+										Status:  corev1.ConditionTrue,
+										Reason:  "SlowWebhook",
+										Message: "Conversion webhook is slow to respond",
+									},
+									{
+										Type:   "CurrentRevisionMissing",
+										Status: corev1.ConditionUnknown,
+									},
+									v1.Active(),
+								}
+								if diff := cmp.Diff(want, o.(*v1.Configuration).Status.Conditions, test.EquateConditions()); diff != "" {
+									t.Errorf("StatusUpdate(...): -want conditions, +got conditions:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:                 testLog,
+					record:              event.NewNopRecorder(),
+					conditions:          conditions.ObservedGenerationPropagationManager{},
+					metrics:             &controller.NopMetrics{},
+					audit:               NewNopAuditSink(),
+					propagatePrefix:     "Current",
+					propagateConditions: []commonv1.ConditionType{"RevisionDegraded", "RevisionMissing"},
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"HealthSourceRevisionUnset": {
+			reason: "We should derive the package's health from its current revision by default.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+								l := o.(*v1.ConfigurationRevisionList)
+								canary := v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: "test-canary"}}
+								canary.SetRevision(1)
+								canary.SetConditions(v1.RevisionUnhealthy())
+								canary.SetDesiredState(v1.PackageRevisionActive)
+								cur := v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: "test-1234567"}}
+								cur.SetRevision(2)
+								cur.SetConditions(v1.RevisionHealthy())
+								cur.SetDesiredState(v1.PackageRevisionActive)
+								*l = v1.ConfigurationRevisionList{Items: []v1.ConfigurationRevision{canary, cur}}
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								want := []commonv1.Condition{v1.Healthy(), v1.Active()}
+								if diff := cmp.Diff(want, o.(*v1.Configuration).Status.Conditions, test.EquateConditions()); diff != "" {
+									t.Errorf("StatusUpdate(...): -want conditions, +got conditions:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:        testLog,
+					record:     event.NewNopRecorder(),
+					conditions: conditions.ObservedGenerationPropagationManager{},
+					metrics:    &controller.NopMetrics{},
+					audit:      NewNopAuditSink(),
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"HealthSourceRevisionCanary": {
+			reason: "We should derive the package's health from its health source revision if it names one.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								p.SetHealthSourceRevision(ptr.To("test-canary"))
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+								l := o.(*v1.ConfigurationRevisionList)
+								canary := v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: "test-canary"}}
+								canary.SetRevision(1)
+								canary.SetConditions(v1.RevisionUnhealthy())
+								canary.SetDesiredState(v1.PackageRevisionActive)
+								cur := v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: "test-1234567"}}
+								cur.SetRevision(2)
+								cur.SetConditions(v1.RevisionHealthy())
+								cur.SetDesiredState(v1.PackageRevisionActive)
+								*l = v1.ConfigurationRevisionList{Items: []v1.ConfigurationRevision{canary, cur}}
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								want := []commonv1.Condition{
+									v1.Unhealthy().WithMessage("Package revision health is \"False\""),
+									v1.Active(),
+								}
+								if diff := cmp.Diff(want, o.(*v1.Configuration).Status.Conditions, test.EquateConditions()); diff != "" {
+									t.Errorf("StatusUpdate(...): -want conditions, +got conditions:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:        testLog,
+					record:     event.NewNopRecorder(),
+					conditions: conditions.ObservedGenerationPropagationManager{},
+					metrics:    &controller.NopMetrics{},
+					audit:      NewNopAuditSink(),
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"HealthSourceRevisionNotFound": {
+			reason: "The package's health should be unknown if its health source revision doesn't exist.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								p.SetHealthSourceRevision(ptr.To("test-missing"))
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+								l := o.(*v1.ConfigurationRevisionList)
+								canary := v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: "test-canary"}}
+								canary.SetRevision(1)
+								canary.SetConditions(v1.RevisionUnhealthy())
+								canary.SetDesiredState(v1.PackageRevisionActive)
+								cur := v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: "test-1234567"}}
+								cur.SetRevision(2)
+								cur.SetConditions(v1.RevisionHealthy())
+								cur.SetDesiredState(v1.PackageRevisionActive)
+								*l = v1.ConfigurationRevisionList{Items: []v1.ConfigurationRevision{canary, cur}}
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								want := []commonv1.Condition{
+									v1.UnknownHealth().WithMessage(errors.Errorf(errFmtHealthSourceRevisionNotFound, "test-missing").Error()),
+									v1.Active(),
+								}
+								if diff := cmp.Diff(want, o.(*v1.Configuration).Status.Conditions, test.EquateConditions()); diff != "" {
+									t.Errorf("StatusUpdate(...): -want conditions, +got conditions:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:        testLog,
+					record:     event.NewNopRecorder(),
+					conditions: conditions.ObservedGenerationPropagationManager{},
+					metrics:    &controller.NopMetrics{},
+					audit:      NewNopAuditSink(),
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"RevisionLabelMigrationDisabled": {
+			reason: "We shouldn't relabel, and so shouldn't find, a revision with the legacy label key unless migration is enabled.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								p.SetUID("pkg-uid")
+								return nil
+							}),
+							MockList: func(_ context.Context, o client.ObjectList, opts ...client.ListOption) error {
+								l := o.(*v1.ConfigurationRevisionList)
+								lo := &client.ListOptions{}
+								lo.ApplyOptions(opts)
+
+								// The package's revision uses the legacy label key.
+								legacy := v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{
+									Name:            "test-1234567",
+									Labels:          map[string]string{"pkg.example.org/parent": "test"},
+									OwnerReferences: []metav1.OwnerReference{{UID: "pkg-uid"}},
+								}}
+								legacy.SetRevision(1)
+								legacy.SetConditions(v1.RevisionHealthy())
+								legacy.SetDesiredState(v1.PackageRevisionActive)
+								if lo.LabelSelector != nil && lo.LabelSelector.Matches(labels.Set(legacy.GetLabels())) {
+									*l = v1.ConfigurationRevisionList{Items: []v1.ConfigurationRevision{legacy}}
+								}
+								return nil
+							},
+							MockUpdate: test.NewMockUpdateFn(nil, func(o client.Object) error {
+								t.Errorf("Update(...): we shouldn't relabel revision %q", o.GetName())
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								want := []commonv1.Condition{
+									v1.Unhealthy().WithMessage("Package revision health is \"Unknown\""),
+									v1.Active(),
+								}
+								if diff := cmp.Diff(want, o.(*v1.Configuration).Status.Conditions, test.EquateConditions()); diff != "" {
+									t.Errorf("StatusUpdate(...): -want conditions, +got conditions:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:        testLog,
+					record:     event.NewNopRecorder(),
+					conditions: conditions.ObservedGenerationPropagationManager{},
+					metrics:    &controller.NopMetrics{},
+					audit:      NewNopAuditSink(),
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"RevisionLabelMigrationNotOwned": {
+			reason: "We shouldn't relabel a revision with the legacy label key that the package doesn't own.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								p.SetUID("pkg-uid")
+								return nil
+							}),
+							MockList: func(_ context.Context, o client.ObjectList, opts ...client.ListOption) error {
+								l := o.(*v1.ConfigurationRevisionList)
+								lo := &client.ListOptions{}
+								lo.ApplyOptions(opts)
+
+								// The package's revision uses the legacy label key.
+								legacy := v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{
+									Name:            "test-1234567",
+									Labels:          map[string]string{"pkg.example.org/parent": "test"},
+									OwnerReferences: []metav1.OwnerReference{{UID: "other-uid"}},
+								}}
+								legacy.SetRevision(1)
+								legacy.SetConditions(v1.RevisionHealthy())
+								legacy.SetDesiredState(v1.PackageRevisionActive)
+								if lo.LabelSelector != nil && lo.LabelSelector.Matches(labels.Set(legacy.GetLabels())) {
+									*l = v1.ConfigurationRevisionList{Items: []v1.ConfigurationRevision{legacy}}
+								}
+								return nil
+							},
+							MockUpdate: test.NewMockUpdateFn(nil, func(o client.Object) error {
+								t.Errorf("Update(...): we shouldn't relabel revision %q", o.GetName())
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								want := []commonv1.Condition{
+									v1.Unhealthy().WithMessage("Package revision health is \"Unknown\""),
+									v1.Active(),
+								}
+								if diff := cmp.Diff(want, o.(*v1.Configuration).Status.Conditions, test.EquateConditions()); diff != "" {
+									t.Errorf("StatusUpdate(...): -want conditions, +got conditions:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:                 testLog,
+					record:              event.NewNopRecorder(),
+					conditions:          conditions.ObservedGenerationPropagationManager{},
+					metrics:             &controller.NopMetrics{},
+					audit:               NewNopAuditSink(),
+					legacyRevisionLabel: "pkg.example.org/parent",
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"RevisionLabelMigrationRelabeled": {
+			reason: "We should relabel a revision with the legacy label key that the package owns, and then adopt it as the current revision.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								p.SetUID("pkg-uid")
+								return nil
+							}),
+							MockList: func(_ context.Context, o client.ObjectList, opts ...client.ListOption) error {
+								l := o.(*v1.ConfigurationRevisionList)
+								lo := &client.ListOptions{}
+								lo.ApplyOptions(opts)
+
+								// The package's revision uses the legacy label key.
+								legacy := v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{
+									Name:            "test-1234567",
+									Labels:          map[string]string{"pkg.example.org/parent": "test"},
+									OwnerReferences: []metav1.OwnerReference{{UID: "pkg-uid"}},
+								}}
+								legacy.SetRevision(1)
+								legacy.SetConditions(v1.RevisionHealthy())
+								legacy.SetDesiredState(v1.PackageRevisionActive)
+								if lo.LabelSelector != nil && lo.LabelSelector.Matches(labels.Set(legacy.GetLabels())) {
+									*l = v1.ConfigurationRevisionList{Items: []v1.ConfigurationRevision{legacy}}
+								}
+
+								// We relabel the revision before we list the package's revisions.
+								relabeled := legacy.DeepCopy()
+								relabeled.SetLabels(map[string]string{v1.LabelParentPackage: "test"})
+								if lo.LabelSelector != nil && lo.LabelSelector.Matches(labels.Set(relabeled.GetLabels())) {
+									*l = v1.ConfigurationRevisionList{Items: []v1.ConfigurationRevision{*relabeled}}
+								}
+								return nil
+							},
+							MockUpdate: test.NewMockUpdateFn(nil, func(o client.Object) error {
+								if diff := cmp.Diff(map[string]string{v1.LabelParentPackage: "test"}, o.GetLabels()); diff != "" {
+									t.Errorf("Update(...): -want revision labels, +got revision labels:\n%s", diff)
+								}
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								want := []commonv1.Condition{v1.Healthy(), v1.Active()}
+								if diff := cmp.Diff(want, o.(*v1.Configuration).Status.Conditions, test.EquateConditions()); diff != "" {
+									t.Errorf("StatusUpdate(...): -want conditions, +got conditions:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:                 testLog,
+					record:              event.NewNopRecorder(),
+					conditions:          conditions.ObservedGenerationPropagationManager{},
+					metrics:             &controller.NopMetrics{},
+					audit:               NewNopAuditSink(),
+					legacyRevisionLabel: "pkg.example.org/parent",
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"ResolvedDigestAnnotationDisabled": {
+			reason: "We shouldn't annotate the package or its revision unless configured to.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil),
+							MockUpdate: test.NewMockUpdateFn(nil, func(o client.Object) error {
+								t.Errorf("Update(...): we shouldn't update package %q", o.GetName())
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, o client.Object, _ ...resource.ApplyOption) error {
+							if diff := cmp.Diff(map[string]string(nil), o.GetAnnotations()); diff != "" {
+								t.Errorf("Apply(...): -want revision annotations, +got revision annotations:\n%s", diff)
+							}
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision:  NewMockRevisionFn("test-1234567", nil),
+						MockImageInfo: func() *ImageInfo { return &ImageInfo{Digest: imageDigest} },
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:        testLog,
+					record:     event.NewNopRecorder(),
+					conditions: conditions.ObservedGenerationPropagationManager{},
+					metrics:    &controller.NopMetrics{},
+					audit:      NewNopAuditSink(),
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"ResolvedDigestAnnotation": {
+			reason: "We should annotate the package and its active revision with the digest they resolved to if configured to.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil),
+							MockUpdate: test.NewMockUpdateFn(nil, func(o client.Object) error {
+								if diff := cmp.Diff(map[string]string{v1.AnnotationResolvedDigest: imageDigest}, o.GetAnnotations()); diff != "" {
+									t.Errorf("Update(...): -want package annotations, +got package annotations:\n%s", diff)
+								}
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, o client.Object, _ ...resource.ApplyOption) error {
+							if diff := cmp.Diff(map[string]string{v1.AnnotationResolvedDigest: imageDigest}, o.GetAnnotations()); diff != "" {
+								t.Errorf("Apply(...): -want revision annotations, +got revision annotations:\n%s", diff)
+							}
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision:  NewMockRevisionFn("test-1234567", nil),
+						MockImageInfo: func() *ImageInfo { return &ImageInfo{Digest: imageDigest} },
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:            testLog,
+					record:         event.NewNopRecorder(),
+					conditions:     conditions.ObservedGenerationPropagationManager{},
+					metrics:        &controller.NopMetrics{},
+					audit:          NewNopAuditSink(),
+					annotateDigest: true,
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"ResolvedDigestAnnotationRemoved": {
+			reason: "We should remove the annotation from the package if its digest is unknown.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								p.SetAnnotations(map[string]string{v1.AnnotationResolvedDigest: imageDigest})
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil),
+							MockUpdate: test.NewMockUpdateFn(nil, func(o client.Object) error {
+								if diff := cmp.Diff(map[string]string{}, o.GetAnnotations()); diff != "" {
+									t.Errorf("Update(...): -want package annotations, +got package annotations:\n%s", diff)
+								}
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, o client.Object, _ ...resource.ApplyOption) error {
+							if diff := cmp.Diff(map[string]string(nil), o.GetAnnotations()); diff != "" {
+								t.Errorf("Apply(...): -want revision annotations, +got revision annotations:\n%s", diff)
+							}
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision:  NewMockRevisionFn("test-1234567", nil),
+						MockImageInfo: func() *ImageInfo { return nil },
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:            testLog,
+					record:         event.NewNopRecorder(),
+					conditions:     conditions.ObservedGenerationPropagationManager{},
+					metrics:        &controller.NopMetrics{},
+					audit:          NewNopAuditSink(),
+					annotateDigest: true,
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"UnknownHealthGCRetain": {
+			reason: "We shouldn't garbage collect a revision whose health is unknown, even if it's outside the revision history limit.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								p.SetRevisionHistoryLimit(&revHistory)
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+								l := o.(*v1.ConfigurationRevisionList)
+								cur := v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: "test-1234567"}}
+								cur.SetRevision(3)
+								cur.SetConditions(v1.RevisionHealthy())
+								cur.SetDesiredState(v1.PackageRevisionActive)
+								healthy := v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: "test-old-2"}}
+								healthy.SetRevision(2)
+								healthy.SetConditions(v1.RevisionHealthy())
+								healthy.SetDesiredState(v1.PackageRevisionInactive)
+								unknown := v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: "test-old-1"}}
+								unknown.SetRevision(1)
+								unknown.SetConditions(v1.RevisionUnknownHealth())
+								unknown.SetDesiredState(v1.PackageRevisionInactive)
+								*l = v1.ConfigurationRevisionList{Items: []v1.ConfigurationRevision{cur, healthy, unknown}}
+								return nil
+							}),
+							MockDelete: func(_ context.Context, obj client.Object, _ ...client.DeleteOption) error {
+								if obj.GetName() != "test-old-2" {
+									t.Errorf("Delete(...): we shouldn't garbage collect revision %q", obj.GetName())
+								}
+								return nil
+							},
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:                   testLog,
+					record:                event.NewNopRecorder(),
+					conditions:            conditions.ObservedGenerationPropagationManager{},
+					metrics:               &controller.NopMetrics{},
+					clock:                 testingclock.NewFakePassiveClock(now),
+					audit:                 NewNopAuditSink(),
+					maxConcurrentDeletes:  1,
+					unknownHealthGCPolicy: UnknownHealthGCPolicyRetain,
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"UnknownHealthGCCollect": {
+			reason: "We should garbage collect the oldest revisions regardless of their health.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								p.SetRevisionHistoryLimit(&revHistory)
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+								l := o.(*v1.ConfigurationRevisionList)
+								cur := v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: "test-1234567"}}
+								cur.SetRevision(3)
+								cur.SetConditions(v1.RevisionHealthy())
+								cur.SetDesiredState(v1.PackageRevisionActive)
+								healthy := v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: "test-old-2"}}
+								healthy.SetRevision(2)
+								healthy.SetConditions(v1.RevisionHealthy())
+								healthy.SetDesiredState(v1.PackageRevisionInactive)
+								unknown := v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: "test-old-1"}}
+								unknown.SetRevision(1)
+								unknown.SetConditions(v1.RevisionUnknownHealth())
+								unknown.SetDesiredState(v1.PackageRevisionInactive)
+								*l = v1.ConfigurationRevisionList{Items: []v1.ConfigurationRevision{cur, healthy, unknown}}
+								return nil
+							}),
+							MockDelete: func(_ context.Context, obj client.Object, _ ...client.DeleteOption) error {
+								if obj.GetName() != "test-old-1" {
+									t.Errorf("Delete(...): we shouldn't garbage collect revision %q", obj.GetName())
+								}
+								return nil
+							},
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:                   testLog,
+					record:                event.NewNopRecorder(),
+					conditions:            conditions.ObservedGenerationPropagationManager{},
+					metrics:               &controller.NopMetrics{},
+					audit:                 NewNopAuditSink(),
+					clock:                 testingclock.NewFakePassiveClock(now),
+					maxConcurrentDeletes:  1,
+					unknownHealthGCPolicy: UnknownHealthGCPolicyCollect,
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"ContentPolicyViolation": {
+			reason: "We shouldn't create a revision of a package whose contents violate policy.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								p, ok := o.(*v1.Configuration)
+								if !ok {
+									return nil
+								}
+								if diff := cmp.Diff(v1.ContentPolicyViolation(errors.Wrapf(errBoom, errFmtContentPolicyViolation, "test-1234567").Error()), p.GetCondition(v1.TypeInstalled), test.EquateConditions()); diff != "" {
+									t.Errorf("StatusUpdate(...): -want installed condition, +got installed condition:\n%s", diff)
+								}
+								if diff := cmp.Diff("", p.GetCurrentRevision()); diff != "" {
+									t.Errorf("StatusUpdate(...): -want current revision, +got current revision:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, o client.Object, _ ...resource.ApplyOption) error {
+							t.Errorf("Apply(...): we shouldn't create revision %q", o.GetName())
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision:  NewMockRevisionFn("test-1234567", nil),
+						MockImageInfo: func() *ImageInfo { return &ImageInfo{Contents: emptyContents} },
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:        testLog,
+					record:     event.NewNopRecorder(),
+					conditions: conditions.ObservedGenerationPropagationManager{},
+					metrics:    &controller.NopMetrics{},
+					audit:      NewNopAuditSink(),
+					validator: &MockContentValidator{MockValidate: func(_ *parser.Package) error {
+						return errBoom
+					}},
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"ContentPolicyValid": {
+			reason: "We should create a revision of a package whose contents are valid.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								p, ok := o.(*v1.Configuration)
+								if !ok {
+									return nil
+								}
+								if diff := cmp.Diff(v1.Active(), p.GetCondition(v1.TypeInstalled), test.EquateConditions()); diff != "" {
+									t.Errorf("StatusUpdate(...): -want installed condition, +got installed condition:\n%s", diff)
+								}
+								if diff := cmp.Diff("test-1234567", p.GetCurrentRevision()); diff != "" {
+									t.Errorf("StatusUpdate(...): -want current revision, +got current revision:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision:  NewMockRevisionFn("test-1234567", nil),
+						MockImageInfo: func() *ImageInfo { return &ImageInfo{Contents: emptyContents} },
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:        testLog,
+					record:     event.NewNopRecorder(),
+					conditions: conditions.ObservedGenerationPropagationManager{},
+					metrics:    &controller.NopMetrics{},
+					audit:      NewNopAuditSink(),
+					validator: &MockContentValidator{MockValidate: func(c *parser.Package) error {
+						if c != emptyContents {
+							t.Errorf("Validate(...): want the contents parsed by the revisioner, got %v", c)
+						}
+						return nil
+					}},
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"NilActivationPolicyUnconfigured": {
+			reason: "Without a configured default we should implicitly activate the revision of a package that doesn't specify an activation policy.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								p, ok := o.(*v1.Configuration)
+								if !ok {
+									return nil
+								}
+								if diff := cmp.Diff(v1.Active(), p.GetCondition(v1.TypeInstalled), test.EquateConditions()); diff != "" {
+									t.Errorf("StatusUpdate(...): -want installed condition, +got installed condition:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, o client.Object, _ ...resource.ApplyOption) error {
+							if diff := cmp.Diff(v1.PackageRevisionActive, o.(*v1.ConfigurationRevision).GetDesiredState()); diff != "" {
+								t.Errorf("Apply(...): -want desired state, +got desired state:\n%s", diff)
+							}
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:        testLog,
+					record:     event.NewNopRecorder(),
+					conditions: conditions.ObservedGenerationPropagationManager{},
+					metrics:    &controller.NopMetrics{},
+					audit:      NewNopAuditSink(),
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"NilActivationPolicyDefaultAutomatic": {
+			reason: "We should activate the revision of a package that doesn't specify an activation policy, and say why, when the default policy is Automatic.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								p, ok := o.(*v1.Configuration)
+								if !ok {
+									return nil
+								}
+								if diff := cmp.Diff(v1.Active().WithMessage("Package doesn't specify a revision activation policy, so the default Automatic policy took effect"), p.GetCondition(v1.TypeInstalled), test.EquateConditions()); diff != "" {
+									t.Errorf("StatusUpdate(...): -want installed condition, +got installed condition:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, o client.Object, _ ...resource.ApplyOption) error {
+							if diff := cmp.Diff(v1.PackageRevisionActive, o.(*v1.ConfigurationRevision).GetDesiredState()); diff != "" {
+								t.Errorf("Apply(...): -want desired state, +got desired state:\n%s", diff)
+							}
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:                     testLog,
+					record:                  event.NewNopRecorder(),
+					conditions:              conditions.ObservedGenerationPropagationManager{},
+					metrics:                 &controller.NopMetrics{},
+					audit:                   NewNopAuditSink(),
+					defaultActivationPolicy: v1.AutomaticActivation,
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"NilActivationPolicyDefaultManual": {
+			reason: "We shouldn't activate the revision of a package that doesn't specify an activation policy, and say why, when the default policy is Manual.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								p, ok := o.(*v1.Configuration)
+								if !ok {
+									return nil
+								}
+								if diff := cmp.Diff(v1.Inactive().WithMessage("Package is inactive. Package doesn't specify a revision activation policy, so the default Manual policy took effect"), p.GetCondition(v1.TypeInstalled), test.EquateConditions()); diff != "" {
+									t.Errorf("StatusUpdate(...): -want installed condition, +got installed condition:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, o client.Object, _ ...resource.ApplyOption) error {
+							if diff := cmp.Diff(v1.PackageRevisionInactive, o.(*v1.ConfigurationRevision).GetDesiredState()); diff != "" {
+								t.Errorf("Apply(...): -want desired state, +got desired state:\n%s", diff)
+							}
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:                     testLog,
+					record:                  event.NewNopRecorder(),
+					conditions:              conditions.ObservedGenerationPropagationManager{},
+					metrics:                 &controller.NopMetrics{},
+					audit:                   NewNopAuditSink(),
+					defaultActivationPolicy: v1.ManualActivation,
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"NilActivationPolicyDefaultHighestHealthy": {
+			reason: "We should use the default HighestHealthy policy, keeping the highest healthy revision active while the newest revision is unhealthy, when a package doesn't specify an activation policy.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+								l := o.(*v1.ConfigurationRevisionList)
+								cur := v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: "test-1234567"}}
+								cur.SetRevision(2)
+								cur.SetConditions(v1.RevisionUnhealthy())
+								cur.SetDesiredState(v1.PackageRevisionActive)
+								good := v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: "test-good"}}
+								good.SetRevision(1)
+								good.SetConditions(v1.RevisionHealthy())
+								good.SetDesiredState(v1.PackageRevisionInactive)
+								*l = v1.ConfigurationRevisionList{Items: []v1.ConfigurationRevision{cur, good}}
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								if diff := cmp.Diff(v1.Active().WithMessage("Current revision \"test-1234567\" is unhealthy, so the last healthy revision \"test-good\" remains active"), p.GetCondition(v1.TypeInstalled), test.EquateConditions()); diff != "" {
+									t.Errorf("StatusUpdate(...): -want installed condition, +got installed condition:\n%s", diff)
+								}
+								if diff := cmp.Diff(v1.Healthy(), p.GetCondition(v1.TypeHealthy), test.EquateConditions()); diff != "" {
+									t.Errorf("StatusUpdate(...): -want healthy condition, +got healthy condition:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, o client.Object, _ ...resource.ApplyOption) error {
+							want := map[string]v1.PackageRevisionDesiredState{
+								"test-good":    v1.PackageRevisionActive,
+								"test-1234567": v1.PackageRevisionInactive,
+							}
+							if diff := cmp.Diff(want[o.GetName()], o.(*v1.ConfigurationRevision).GetDesiredState()); diff != "" {
+								t.Errorf("Apply(...): -want revision %q desired state, +got desired state:\n%s", o.GetName(), diff)
+							}
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:                     testLog,
+					record:                  event.NewNopRecorder(),
+					conditions:              conditions.ObservedGenerationPropagationManager{},
+					metrics:                 &controller.NopMetrics{},
+					audit:                   NewNopAuditSink(),
+					defaultActivationPolicy: v1.HighestHealthyActivation,
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"AutomaticActivationNewestUnhealthy": {
+			reason: "Under the Automatic policy we should keep the newest revision active, even though it's unhealthy.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								p.SetActivationPolicy(ptr.To(v1.AutomaticActivation))
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+								l := o.(*v1.ConfigurationRevisionList)
+								cur := v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: "test-1234567"}}
+								cur.SetRevision(2)
+								cur.SetConditions(v1.RevisionUnhealthy())
+								cur.SetDesiredState(v1.PackageRevisionActive)
+								good := v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: "test-good"}}
+								good.SetRevision(1)
+								good.SetConditions(v1.RevisionHealthy())
+								good.SetDesiredState(v1.PackageRevisionInactive)
+								*l = v1.ConfigurationRevisionList{Items: []v1.ConfigurationRevision{cur, good}}
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								if diff := cmp.Diff(v1.Active(), p.GetCondition(v1.TypeInstalled), test.EquateConditions()); diff != "" {
+									t.Errorf("StatusUpdate(...): -want installed condition, +got installed condition:\n%s", diff)
+								}
+								if diff := cmp.Diff(v1.Unhealthy().WithMessage("Package revision health is \"False\""), p.GetCondition(v1.TypeHealthy), test.EquateConditions()); diff != "" {
+									t.Errorf("StatusUpdate(...): -want healthy condition, +got healthy condition:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, o client.Object, _ ...resource.ApplyOption) error {
+							want := map[string]v1.PackageRevisionDesiredState{
+								"test-1234567": v1.PackageRevisionActive,
+							}
+							if diff := cmp.Diff(want[o.GetName()], o.(*v1.ConfigurationRevision).GetDesiredState()); diff != "" {
+								t.Errorf("Apply(...): -want revision %q desired state, +got desired state:\n%s", o.GetName(), diff)
+							}
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:        testLog,
+					record:     event.NewNopRecorder(),
+					conditions: conditions.ObservedGenerationPropagationManager{},
+					metrics:    &controller.NopMetrics{},
+					audit:      NewNopAuditSink(),
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"HighestHealthyActivationNewestUnhealthy": {
+			reason: "Under the HighestHealthy policy we should keep the highest healthy revision active while the newest revision is unhealthy.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								p.SetActivationPolicy(ptr.To(v1.HighestHealthyActivation))
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+								l := o.(*v1.ConfigurationRevisionList)
+								cur := v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: "test-1234567"}}
+								cur.SetRevision(2)
+								cur.SetConditions(v1.RevisionUnhealthy())
+								cur.SetDesiredState(v1.PackageRevisionActive)
+								good := v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: "test-good"}}
+								good.SetRevision(1)
+								good.SetConditions(v1.RevisionHealthy())
+								good.SetDesiredState(v1.PackageRevisionInactive)
+								*l = v1.ConfigurationRevisionList{Items: []v1.ConfigurationRevision{cur, good}}
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								if diff := cmp.Diff(v1.Active().WithMessage("Current revision \"test-1234567\" is unhealthy, so the last healthy revision \"test-good\" remains active"), p.GetCondition(v1.TypeInstalled), test.EquateConditions()); diff != "" {
+									t.Errorf("StatusUpdate(...): -want installed condition, +got installed condition:\n%s", diff)
+								}
+								if diff := cmp.Diff(v1.Healthy(), p.GetCondition(v1.TypeHealthy), test.EquateConditions()); diff != "" {
+									t.Errorf("StatusUpdate(...): -want healthy condition, +got healthy condition:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, o client.Object, _ ...resource.ApplyOption) error {
+							want := map[string]v1.PackageRevisionDesiredState{
+								"test-good":    v1.PackageRevisionActive,
+								"test-1234567": v1.PackageRevisionInactive,
+							}
+							if diff := cmp.Diff(want[o.GetName()], o.(*v1.ConfigurationRevision).GetDesiredState()); diff != "" {
+								t.Errorf("Apply(...): -want revision %q desired state, +got desired state:\n%s", o.GetName(), diff)
+							}
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:        testLog,
+					record:     event.NewNopRecorder(),
+					conditions: conditions.ObservedGenerationPropagationManager{},
+					metrics:    &controller.NopMetrics{},
+					audit:      NewNopAuditSink(),
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"HighestHealthyActivationNewestUnknown": {
+			reason: "Under the HighestHealthy policy we should activate the newest revision while its health is unknown, so that it can become healthy.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								p.SetActivationPolicy(ptr.To(v1.HighestHealthyActivation))
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+								l := o.(*v1.ConfigurationRevisionList)
+								cur := v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: "test-1234567"}}
+								cur.SetRevision(2)
+								cur.SetConditions(v1.RevisionUnknownHealth())
+								cur.SetDesiredState(v1.PackageRevisionActive)
+								good := v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: "test-good"}}
+								good.SetRevision(1)
+								good.SetConditions(v1.RevisionHealthy())
+								good.SetDesiredState(v1.PackageRevisionInactive)
+								*l = v1.ConfigurationRevisionList{Items: []v1.ConfigurationRevision{cur, good}}
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								if diff := cmp.Diff(v1.Active(), p.GetCondition(v1.TypeInstalled), test.EquateConditions()); diff != "" {
+									t.Errorf("StatusUpdate(...): -want installed condition, +got installed condition:\n%s", diff)
+								}
+								if diff := cmp.Diff(v1.Unhealthy().WithMessage("Package revision health is \"Unknown\""), p.GetCondition(v1.TypeHealthy), test.EquateConditions()); diff != "" {
+									t.Errorf("StatusUpdate(...): -want healthy condition, +got healthy condition:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, o client.Object, _ ...resource.ApplyOption) error {
+							want := map[string]v1.PackageRevisionDesiredState{
+								"test-1234567": v1.PackageRevisionActive,
+							}
+							if diff := cmp.Diff(want[o.GetName()], o.(*v1.ConfigurationRevision).GetDesiredState()); diff != "" {
+								t.Errorf("Apply(...): -want revision %q desired state, +got desired state:\n%s", o.GetName(), diff)
+							}
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:        testLog,
+					record:     event.NewNopRecorder(),
+					conditions: conditions.ObservedGenerationPropagationManager{},
+					metrics:    &controller.NopMetrics{},
+					audit:      NewNopAuditSink(),
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"ReconciledByAnnotationNoInstanceID": {
+			reason: "We shouldn't annotate the package if we don't know our instance id.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil),
+							MockUpdate: test.NewMockUpdateFn(nil, func(o client.Object) error {
+								t.Errorf("Update(...): we shouldn't update package %q", o.GetName())
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:        testLog,
+					record:     event.NewNopRecorder(),
+					conditions: conditions.ObservedGenerationPropagationManager{},
+					metrics:    &controller.NopMetrics{},
+					audit:      NewNopAuditSink(),
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"ReconciledByAnnotation": {
+			reason: "We should annotate the package with the id of the instance that reconciled it.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil),
+							MockUpdate: test.NewMockUpdateFn(nil, func(o client.Object) error {
+								if diff := cmp.Diff(map[string]string{v1.AnnotationReconciledBy: "crossplane-7d9f8b6c4-x2x9z"}, o.GetAnnotations()); diff != "" {
+									t.Errorf("Update(...): -want package annotations, +got package annotations:\n%s", diff)
+								}
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:        testLog,
+					record:     event.NewNopRecorder(),
+					conditions: conditions.ObservedGenerationPropagationManager{},
+					metrics:    &controller.NopMetrics{},
+					audit:      NewNopAuditSink(),
+					instanceID: "crossplane-7d9f8b6c4-x2x9z",
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"ReconciledByAnnotationReplaceInstanceID": {
+			reason: "We should replace the id of the instance that previously reconciled the package.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								p.SetAnnotations(map[string]string{v1.AnnotationReconciledBy: "crossplane-7d9f8b6c4-a1b2c"})
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil),
+							MockUpdate: test.NewMockUpdateFn(nil, func(o client.Object) error {
+								if diff := cmp.Diff(map[string]string{v1.AnnotationReconciledBy: "crossplane-7d9f8b6c4-x2x9z"}, o.GetAnnotations()); diff != "" {
+									t.Errorf("Update(...): -want package annotations, +got package annotations:\n%s", diff)
+								}
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:        testLog,
+					record:     event.NewNopRecorder(),
+					conditions: conditions.ObservedGenerationPropagationManager{},
+					metrics:    &controller.NopMetrics{},
+					audit:      NewNopAuditSink(),
+					instanceID: "crossplane-7d9f8b6c4-x2x9z",
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"ReconciledByAnnotationAlreadyAnnotated": {
+			reason: "We shouldn't update the package if it's already annotated with our instance id.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								p.SetAnnotations(map[string]string{v1.AnnotationReconciledBy: "crossplane-7d9f8b6c4-x2x9z"})
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil),
+							MockUpdate: test.NewMockUpdateFn(nil, func(o client.Object) error {
+								t.Errorf("Update(...): we shouldn't update package %q", o.GetName())
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:        testLog,
+					record:     event.NewNopRecorder(),
+					conditions: conditions.ObservedGenerationPropagationManager{},
+					metrics:    &controller.NopMetrics{},
+					audit:      NewNopAuditSink(),
+					instanceID: "crossplane-7d9f8b6c4-x2x9z",
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"DeferredGCNotDeferred": {
+			reason: "We should garbage collect old revisions while the current revision's health is unknown if GC isn't deferred.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								p.SetRevisionHistoryLimit(&revHistory)
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+								l := o.(*v1.ConfigurationRevisionList)
+								cur := v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: "test-1234567"}}
+								cur.SetRevision(3)
+								cur.SetConditions(v1.RevisionUnknownHealth())
+								cur.SetDesiredState(v1.PackageRevisionActive)
+								old2 := v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: "test-old-2"}}
+								old2.SetRevision(2)
+								old2.SetConditions(v1.RevisionHealthy())
+								old2.SetDesiredState(v1.PackageRevisionInactive)
+								old1 := v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: "test-old-1"}}
+								old1.SetRevision(1)
+								old1.SetConditions(v1.RevisionHealthy())
+								old1.SetDesiredState(v1.PackageRevisionInactive)
+								*l = v1.ConfigurationRevisionList{Items: []v1.ConfigurationRevision{cur, old2, old1}}
+								return nil
+							}),
+							MockDelete: func(_ context.Context, obj client.Object, _ ...client.DeleteOption) error {
+								if obj.GetName() != "test-old-1" {
+									t.Errorf("Delete(...): we shouldn't garbage collect revision %q", obj.GetName())
+								}
+								return nil
+							},
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:                  testLog,
+					record:               event.NewNopRecorder(),
+					conditions:           conditions.ObservedGenerationPropagationManager{},
+					metrics:              &controller.NopMetrics{},
+					clock:                testingclock.NewFakePassiveClock(now),
+					audit:                NewNopAuditSink(),
+					maxConcurrentDeletes: 1,
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"DeferredGCWhileUnknown": {
+			reason: "We shouldn't garbage collect old revisions while the current revision's health is unknown if GC is deferred.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								p.SetRevisionHistoryLimit(&revHistory)
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+								l := o.(*v1.ConfigurationRevisionList)
+								cur := v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: "test-1234567"}}
+								cur.SetRevision(3)
+								cur.SetConditions(v1.RevisionUnknownHealth())
+								cur.SetDesiredState(v1.PackageRevisionActive)
+								old2 := v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: "test-old-2"}}
+								old2.SetRevision(2)
+								old2.SetConditions(v1.RevisionHealthy())
+								old2.SetDesiredState(v1.PackageRevisionInactive)
+								old1 := v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: "test-old-1"}}
+								old1.SetRevision(1)
+								old1.SetConditions(v1.RevisionHealthy())
+								old1.SetDesiredState(v1.PackageRevisionInactive)
+								*l = v1.ConfigurationRevisionList{Items: []v1.ConfigurationRevision{cur, old2, old1}}
+								return nil
+							}),
+							MockDelete: func(_ context.Context, obj client.Object, _ ...client.DeleteOption) error {
+								t.Errorf("Delete(...): we shouldn't garbage collect revision %q", obj.GetName())
+								return nil
+							},
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:                  testLog,
+					record:               event.NewNopRecorder(),
+					conditions:           conditions.ObservedGenerationPropagationManager{},
+					metrics:              &controller.NopMetrics{},
+					audit:                NewNopAuditSink(),
+					maxConcurrentDeletes: 1,
+					deferGC:              true,
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"DeferredGCHealthy": {
+			reason: "We should garbage collect old revisions once the current revision is healthy, even if GC is deferred.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								p.SetRevisionHistoryLimit(&revHistory)
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+								l := o.(*v1.ConfigurationRevisionList)
+								cur := v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: "test-1234567"}}
+								cur.SetRevision(3)
+								cur.SetConditions(v1.RevisionHealthy())
+								cur.SetDesiredState(v1.PackageRevisionActive)
+								old2 := v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: "test-old-2"}}
+								old2.SetRevision(2)
+								old2.SetConditions(v1.RevisionHealthy())
+								old2.SetDesiredState(v1.PackageRevisionInactive)
+								old1 := v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: "test-old-1"}}
+								old1.SetRevision(1)
+								old1.SetConditions(v1.RevisionHealthy())
+								old1.SetDesiredState(v1.PackageRevisionInactive)
+								*l = v1.ConfigurationRevisionList{Items: []v1.ConfigurationRevision{cur, old2, old1}}
+								return nil
+							}),
+							MockDelete: func(_ context.Context, obj client.Object, _ ...client.DeleteOption) error {
+								if obj.GetName() != "test-old-1" {
+									t.Errorf("Delete(...): we shouldn't garbage collect revision %q", obj.GetName())
+								}
+								return nil
+							},
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:                  testLog,
+					record:               event.NewNopRecorder(),
+					conditions:           conditions.ObservedGenerationPropagationManager{},
+					metrics:              &controller.NopMetrics{},
+					audit:                NewNopAuditSink(),
+					clock:                testingclock.NewFakePassiveClock(now),
+					maxConcurrentDeletes: 1,
+					deferGC:              true,
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"EstablishingCRDs": {
+			reason: "We should report that a package is establishing CRDs while its healthy revision's CRDs aren't established.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: func(_ context.Context, key client.ObjectKey, o client.Object) error {
+								switch o := o.(type) {
+								case *v1.Configuration:
+									o.SetName("test")
+									o.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								case *extv1.CustomResourceDefinition:
+									if !map[string]bool{"widgets.example.org": true}[key.Name] {
+										return kerrors.NewNotFound(schema.GroupResource{}, key.Name)
+									}
+									o.Status = extv1.CustomResourceDefinitionStatus{
+										Conditions: []extv1.CustomResourceDefinitionCondition{{Type: extv1.Established, Status: extv1.ConditionTrue}},
+									}
+								}
+								return nil
+							},
+							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+								l := o.(*v1.ConfigurationRevisionList)
+								cur := v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: "test-1234567"}}
+								cur.SetRevision(1)
+								cur.SetConditions(v1.RevisionHealthy())
+								cur.SetDesiredState(v1.PackageRevisionActive)
+								cur.SetObjects([]commonv1.TypedReference{
+									{APIVersion: extv1.SchemeGroupVersion.String(), Kind: "CustomResourceDefinition", Name: "widgets.example.org"},
+									{APIVersion: extv1.SchemeGroupVersion.String(), Kind: "CustomResourceDefinition", Name: "gadgets.example.org"},
+									{APIVersion: extv1.SchemeGroupVersion.String(), Kind: "CustomResourceDefinition", Name: "gizmos.example.org"},
+									{APIVersion: "apiextensions.crossplane.io/v1", Kind: "Composition", Name: "widgets"},
+								})
+								*l = v1.ConfigurationRevisionList{Items: []v1.ConfigurationRevision{cur}}
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								if diff := cmp.Diff(v1.EstablishingCRDs("gadgets.example.org", "gizmos.example.org"), o.(*v1.Configuration).GetCondition(v1.TypeHealthy), test.EquateConditions()); diff != "" {
+									t.Errorf("StatusUpdate(...): -want Healthy condition, +got Healthy condition:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:        testLog,
+					record:     event.NewNopRecorder(),
+					conditions: conditions.ObservedGenerationPropagationManager{},
+					metrics:    &controller.NopMetrics{},
+					audit:      NewNopAuditSink(),
+					clock:      testingclock.NewFakePassiveClock(now),
+				},
+			},
+			want: want{
+				r: reconcile.Result{RequeueAfter: establishingRecheckInterval},
+			},
+		},
+		"EstablishedCRDs": {
+			reason: "We should report that a package is healthy once its healthy revision's CRDs are established.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: func(_ context.Context, key client.ObjectKey, o client.Object) error {
+								switch o := o.(type) {
+								case *v1.Configuration:
+									o.SetName("test")
+									o.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								case *extv1.CustomResourceDefinition:
+									if !map[string]bool{"widgets.example.org": true, "gadgets.example.org": true, "gizmos.example.org": true}[key.Name] {
+										return kerrors.NewNotFound(schema.GroupResource{}, key.Name)
+									}
+									o.Status = extv1.CustomResourceDefinitionStatus{
+										Conditions: []extv1.CustomResourceDefinitionCondition{{Type: extv1.Established, Status: extv1.ConditionTrue}},
+									}
+								}
+								return nil
+							},
+							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+								l := o.(*v1.ConfigurationRevisionList)
+								cur := v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: "test-1234567"}}
+								cur.SetRevision(1)
+								cur.SetConditions(v1.RevisionHealthy())
+								cur.SetDesiredState(v1.PackageRevisionActive)
+								cur.SetObjects([]commonv1.TypedReference{
+									{APIVersion: extv1.SchemeGroupVersion.String(), Kind: "CustomResourceDefinition", Name: "widgets.example.org"},
+									{APIVersion: extv1.SchemeGroupVersion.String(), Kind: "CustomResourceDefinition", Name: "gadgets.example.org"},
+									{APIVersion: extv1.SchemeGroupVersion.String(), Kind: "CustomResourceDefinition", Name: "gizmos.example.org"},
+									{APIVersion: "apiextensions.crossplane.io/v1", Kind: "Composition", Name: "widgets"},
+								})
+								*l = v1.ConfigurationRevisionList{Items: []v1.ConfigurationRevision{cur}}
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								if diff := cmp.Diff(v1.Healthy(), o.(*v1.Configuration).GetCondition(v1.TypeHealthy), test.EquateConditions()); diff != "" {
+									t.Errorf("StatusUpdate(...): -want Healthy condition, +got Healthy condition:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:        testLog,
+					record:     event.NewNopRecorder(),
+					conditions: conditions.ObservedGenerationPropagationManager{},
+					metrics:    &controller.NopMetrics{},
+					audit:      NewNopAuditSink(),
+					clock:      testingclock.NewFakePassiveClock(now),
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"ErrGetCRD": {
+			reason: "We should return an error if we can't get a CRD.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: func(_ context.Context, key client.ObjectKey, o client.Object) error {
+								switch o := o.(type) {
+								case *v1.Configuration:
+									o.SetName("test")
+									o.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								case *extv1.CustomResourceDefinition:
+									return errBoom
+								}
+								return nil
+							},
+							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+								l := o.(*v1.ConfigurationRevisionList)
+								cur := v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: "test-1234567"}}
+								cur.SetRevision(1)
+								cur.SetConditions(v1.RevisionHealthy())
+								cur.SetDesiredState(v1.PackageRevisionActive)
+								cur.SetObjects([]commonv1.TypedReference{
+									{APIVersion: extv1.SchemeGroupVersion.String(), Kind: "CustomResourceDefinition", Name: "widgets.example.org"},
+									{APIVersion: extv1.SchemeGroupVersion.String(), Kind: "CustomResourceDefinition", Name: "gadgets.example.org"},
+									{APIVersion: extv1.SchemeGroupVersion.String(), Kind: "CustomResourceDefinition", Name: "gizmos.example.org"},
+									{APIVersion: "apiextensions.crossplane.io/v1", Kind: "Composition", Name: "widgets"},
+								})
+								*l = v1.ConfigurationRevisionList{Items: []v1.ConfigurationRevision{cur}}
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:        testLog,
+					record:     event.NewNopRecorder(),
+					conditions: conditions.ObservedGenerationPropagationManager{},
+					metrics:    &controller.NopMetrics{},
+					audit:      NewNopAuditSink(),
+					clock:      testingclock.NewFakePassiveClock(now),
+				},
+			},
+			want: want{
+				err: errors.Wrapf(errBoom, errFmtGetCRD, "widgets.example.org"),
+			},
+		},
+		"DefaultRevisionHistoryLimitNoDefault": {
+			reason: "We shouldn't garbage collect revisions of a package that doesn't specify a revision history limit if there's no default.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+								l := o.(*v1.ConfigurationRevisionList)
+								for i, name := range []string{"test-old-1", "test-old-2", "test-1234567"} {
+									rev := v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: name}}
+									rev.SetRevision(int64(i + 1))
+									rev.SetConditions(v1.RevisionHealthy())
+									rev.SetDesiredState(v1.PackageRevisionInactive)
+									l.Items = append(l.Items, rev)
+								}
+								l.Items[2].SetDesiredState(v1.PackageRevisionActive)
+								return nil
+							}),
+							MockDelete: func(_ context.Context, obj client.Object, _ ...client.DeleteOption) error {
+								t.Errorf("Delete(...): we shouldn't garbage collect revision %q", obj.GetName())
+								return nil
+							},
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								if diff := cmp.Diff((*int64)(nil), o.(*v1.Configuration).GetEffectiveRevisionHistoryLimit()); diff != "" {
+									t.Errorf("StatusUpdate(...): -want effective revision history limit, +got effective revision history limit:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:                  testLog,
+					record:               event.NewNopRecorder(),
+					conditions:           conditions.ObservedGenerationPropagationManager{},
+					metrics:              &controller.NopMetrics{},
+					audit:                NewNopAuditSink(),
+					maxConcurrentDeletes: 1,
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"DefaultRevisionHistoryLimitProvider": {
+			reason: "We should use the default revision history limit for a Provider that doesn't specify one.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Provider{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ProviderRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ProviderRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Provider)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ProviderGroupVersionKind)
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+								l := o.(*v1.ProviderRevisionList)
+								for i, name := range []string{"test-old-1", "test-old-2", "test-1234567"} {
+									rev := v1.ProviderRevision{ObjectMeta: metav1.ObjectMeta{Name: name}}
+									rev.SetRevision(int64(i + 1))
+									rev.SetConditions(v1.RevisionHealthy())
+									rev.SetDesiredState(v1.PackageRevisionInactive)
+									l.Items = append(l.Items, rev)
+								}
+								l.Items[2].SetDesiredState(v1.PackageRevisionActive)
+								return nil
+							}),
+							MockDelete: func(_ context.Context, obj client.Object, _ ...client.DeleteOption) error {
+								if obj.GetName() != "test-old-1" {
+									t.Errorf("Delete(...): we shouldn't garbage collect revision %q", obj.GetName())
+								}
+								return nil
+							},
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								if diff := cmp.Diff(ptr.To[int64](1), o.(*v1.Provider).GetEffectiveRevisionHistoryLimit()); diff != "" {
+									t.Errorf("StatusUpdate(...): -want effective revision history limit, +got effective revision history limit:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:                         testLog,
+					record:                      event.NewNopRecorder(),
+					conditions:                  conditions.ObservedGenerationPropagationManager{},
+					metrics:                     &controller.NopMetrics{},
+					audit:                       NewNopAuditSink(),
+					clock:                       testingclock.NewFakePassiveClock(now),
+					maxConcurrentDeletes:        1,
+					defaultRevisionHistoryLimit: ptr.To[int64](1),
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"DefaultRevisionHistoryLimitConfiguration": {
+			reason: "We should use the default revision history limit for a Configuration that doesn't specify one.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+								l := o.(*v1.ConfigurationRevisionList)
+								for i, name := range []string{"test-old-1", "test-old-2", "test-1234567"} {
+									rev := v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: name}}
+									rev.SetRevision(int64(i + 1))
+									rev.SetConditions(v1.RevisionHealthy())
+									rev.SetDesiredState(v1.PackageRevisionInactive)
+									l.Items = append(l.Items, rev)
+								}
+								l.Items[2].SetDesiredState(v1.PackageRevisionActive)
+								return nil
+							}),
+							MockDelete: func(_ context.Context, obj client.Object, _ ...client.DeleteOption) error {
+								t.Errorf("Delete(...): we shouldn't garbage collect revision %q", obj.GetName())
+								return nil
+							},
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								if diff := cmp.Diff(ptr.To[int64](2), o.(*v1.Configuration).GetEffectiveRevisionHistoryLimit()); diff != "" {
+									t.Errorf("StatusUpdate(...): -want effective revision history limit, +got effective revision history limit:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:                         testLog,
+					record:                      event.NewNopRecorder(),
+					conditions:                  conditions.ObservedGenerationPropagationManager{},
+					metrics:                     &controller.NopMetrics{},
+					audit:                       NewNopAuditSink(),
+					maxConcurrentDeletes:        1,
+					defaultRevisionHistoryLimit: ptr.To[int64](2),
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"DefaultRevisionHistoryLimitFunction": {
+			reason: "We should use the default revision history limit for a Function that doesn't specify one.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Function{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.FunctionRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.FunctionRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Function)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.FunctionGroupVersionKind)
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+								l := o.(*v1.FunctionRevisionList)
+								for i, name := range []string{"test-old-1", "test-old-2", "test-1234567"} {
+									rev := v1.FunctionRevision{ObjectMeta: metav1.ObjectMeta{Name: name}}
+									rev.SetRevision(int64(i + 1))
+									rev.SetConditions(v1.RevisionHealthy())
+									rev.SetDesiredState(v1.PackageRevisionInactive)
+									l.Items = append(l.Items, rev)
+								}
+								l.Items[2].SetDesiredState(v1.PackageRevisionActive)
+								return nil
+							}),
+							MockDelete: func(_ context.Context, obj client.Object, _ ...client.DeleteOption) error {
+								if obj.GetName() != "test-old-1" {
+									t.Errorf("Delete(...): we shouldn't garbage collect revision %q", obj.GetName())
+								}
+								return nil
+							},
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								if diff := cmp.Diff(ptr.To[int64](1), o.(*v1.Function).GetEffectiveRevisionHistoryLimit()); diff != "" {
+									t.Errorf("StatusUpdate(...): -want effective revision history limit, +got effective revision history limit:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:                         testLog,
+					record:                      event.NewNopRecorder(),
+					conditions:                  conditions.ObservedGenerationPropagationManager{},
+					metrics:                     &controller.NopMetrics{},
+					audit:                       NewNopAuditSink(),
+					clock:                       testingclock.NewFakePassiveClock(now),
+					maxConcurrentDeletes:        1,
+					defaultRevisionHistoryLimit: ptr.To[int64](1),
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"DefaultRevisionHistoryLimitSpecOverridesDefault": {
+			reason: "A revision history limit specified by the package should take precedence over the default.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								p.SetRevisionHistoryLimit(ptr.To[int64](1))
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+								l := o.(*v1.ConfigurationRevisionList)
+								for i, name := range []string{"test-old-1", "test-old-2", "test-1234567"} {
+									rev := v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: name}}
+									rev.SetRevision(int64(i + 1))
+									rev.SetConditions(v1.RevisionHealthy())
+									rev.SetDesiredState(v1.PackageRevisionInactive)
+									l.Items = append(l.Items, rev)
+								}
+								l.Items[2].SetDesiredState(v1.PackageRevisionActive)
+								return nil
+							}),
+							MockDelete: func(_ context.Context, obj client.Object, _ ...client.DeleteOption) error {
+								if obj.GetName() != "test-old-1" {
+									t.Errorf("Delete(...): we shouldn't garbage collect revision %q", obj.GetName())
+								}
+								return nil
+							},
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								if diff := cmp.Diff(ptr.To[int64](1), o.(*v1.Configuration).GetEffectiveRevisionHistoryLimit()); diff != "" {
+									t.Errorf("StatusUpdate(...): -want effective revision history limit, +got effective revision history limit:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:                         testLog,
+					record:                      event.NewNopRecorder(),
+					conditions:                  conditions.ObservedGenerationPropagationManager{},
+					metrics:                     &controller.NopMetrics{},
+					clock:                       testingclock.NewFakePassiveClock(now),
+					audit:                       NewNopAuditSink(),
+					maxConcurrentDeletes:        1,
+					defaultRevisionHistoryLimit: ptr.To[int64](2),
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"Held": {
+			reason: "We should neither create nor activate the newly resolved revision while a package is held.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								p.SetAnnotations(map[string]string{v1.AnnotationHold: "test-old"})
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+								l := o.(*v1.ConfigurationRevisionList)
+								old := v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: "test-old"}}
+								old.SetRevision(1)
+								old.SetConditions(v1.RevisionHealthy())
+								old.SetDesiredState(v1.PackageRevisionActive)
+								*l = v1.ConfigurationRevisionList{Items: []v1.ConfigurationRevision{old}}
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								if diff := cmp.Diff(v1.Held("test-old"), p.GetCondition(v1.TypeHeld), test.EquateConditions()); diff != "" {
+									t.Errorf("StatusUpdate(...): -want held condition, +got held condition:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, o client.Object, _ ...resource.ApplyOption) error {
+							t.Errorf("Apply(...): we shouldn't apply revision %q", o.GetName())
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-new", nil),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:        testLog,
+					record:     event.NewNopRecorder(),
+					conditions: conditions.ObservedGenerationPropagationManager{},
+					metrics:    &controller.NopMetrics{},
+					audit:      NewNopAuditSink(),
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"HeldRevisionNotFound": {
+			reason: "We should neither create nor activate any revision if the held revision doesn't exist.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								p.SetAnnotations(map[string]string{v1.AnnotationHold: "test-missing"})
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+								l := o.(*v1.ConfigurationRevisionList)
+								old := v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: "test-old"}}
+								old.SetRevision(1)
+								old.SetConditions(v1.RevisionHealthy())
+								old.SetDesiredState(v1.PackageRevisionActive)
+								*l = v1.ConfigurationRevisionList{Items: []v1.ConfigurationRevision{old}}
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								if diff := cmp.Diff(v1.HeldRevisionNotFound("test-missing"), p.GetCondition(v1.TypeHeld), test.EquateConditions()); diff != "" {
+									t.Errorf("StatusUpdate(...): -want held condition, +got held condition:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, o client.Object, _ ...resource.ApplyOption) error {
+							t.Errorf("Apply(...): we shouldn't apply revision %q", o.GetName())
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-new", nil),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:        testLog,
+					record:     event.NewNopRecorder(),
+					conditions: conditions.ObservedGenerationPropagationManager{},
+					metrics:    &controller.NopMetrics{},
+					audit:      NewNopAuditSink(),
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"HoldReleased": {
+			reason: "We should create and activate the newly resolved revision once a package's hold is released.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								p.SetConditions(v1.Held("test-old"))
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+								l := o.(*v1.ConfigurationRevisionList)
+								old := v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: "test-old"}}
+								old.SetRevision(1)
+								old.SetConditions(v1.RevisionHealthy())
+								old.SetDesiredState(v1.PackageRevisionActive)
+								*l = v1.ConfigurationRevisionList{Items: []v1.ConfigurationRevision{old}}
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								if diff := cmp.Diff(v1.HoldReleased(), p.GetCondition(v1.TypeHeld), test.EquateConditions()); diff != "" {
+									t.Errorf("StatusUpdate(...): -want held condition, +got held condition:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, o client.Object, _ ...resource.ApplyOption) error {
+							want := map[string]v1.PackageRevisionDesiredState{
+								"test-old": v1.PackageRevisionInactive,
+								"test-new": v1.PackageRevisionActive,
+							}
+							if diff := cmp.Diff(want[o.GetName()], o.(*v1.ConfigurationRevision).GetDesiredState()); diff != "" {
+								t.Errorf("Apply(...): -want revision %q desired state, +got desired state:\n%s", o.GetName(), diff)
+							}
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-new", nil),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:        testLog,
+					record:     event.NewNopRecorder(),
+					conditions: conditions.ObservedGenerationPropagationManager{},
+					metrics:    &controller.NopMetrics{},
+					audit:      NewNopAuditSink(),
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"MinHealthyDurationDisabled": {
+			reason: "We should report the package healthy as soon as its revision is healthy when no minimum duration is configured.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+								l := o.(*v1.ConfigurationRevisionList)
+								cur := v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: "test-1234567"}}
+								cur.SetRevision(1)
+								c := v1.RevisionHealthy()
+								c.LastTransitionTime = metav1.NewTime(now)
+								cur.SetConditions(c)
+								cur.SetDesiredState(v1.PackageRevisionActive)
+								*l = v1.ConfigurationRevisionList{Items: []v1.ConfigurationRevision{cur}}
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								if diff := cmp.Diff(v1.Healthy(), p.GetCondition(v1.TypeHealthy), test.EquateConditions()); diff != "" {
+									t.Errorf("StatusUpdate(...): -want healthy condition, +got healthy condition:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:        testLog,
+					record:     event.NewNopRecorder(),
+					conditions: conditions.ObservedGenerationPropagationManager{},
+					metrics:    &controller.NopMetrics{},
+					audit:      NewNopAuditSink(),
+					clock:      testingclock.NewFakePassiveClock(now.Add(time.Second)),
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"RecordTimeToHealthy": {
+			reason: "We should record how long a package took to become healthy when its Healthy condition transitions to true.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								p.SetConditions(v1.UnknownHealth())
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+								l := o.(*v1.ConfigurationRevisionList)
+								cur := v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: "test-1234567"}}
+								cur.SetRevision(1)
+								c := v1.RevisionHealthy()
+								c.LastTransitionTime = metav1.NewTime(now)
+								cur.SetConditions(c)
+								cur.SetDesiredState(v1.PackageRevisionActive)
+								*l = v1.ConfigurationRevisionList{Items: []v1.ConfigurationRevision{cur}}
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								if diff := cmp.Diff(v1.Healthy(), p.GetCondition(v1.TypeHealthy), test.EquateConditions()); diff != "" {
+									t.Errorf("StatusUpdate(...): -want healthy condition, +got healthy condition:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:        testLog,
+					record:     event.NewNopRecorder(),
+					conditions: conditions.ObservedGenerationPropagationManager{},
+					metrics: &MockMetrics{
+						MockRecordFirstTimeHealthy: func(p v1.Package) {
+							if diff := cmp.Diff(v1.Healthy(), p.GetCondition(v1.TypeHealthy), test.EquateConditions()); diff != "" {
+								t.Errorf("RecordFirstTimeHealthy(...): -want healthy condition, +got healthy condition:\n%s", diff)
+							}
+						},
+					},
+					audit: NewNopAuditSink(),
+					clock: testingclock.NewFakePassiveClock(now.Add(time.Second)),
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"AlreadyHealthy": {
+			reason: "We shouldn't record how long a package took to become healthy again when it's already healthy.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								p.SetConditions(v1.Healthy())
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+								l := o.(*v1.ConfigurationRevisionList)
+								cur := v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: "test-1234567"}}
+								cur.SetRevision(1)
+								c := v1.RevisionHealthy()
+								c.LastTransitionTime = metav1.NewTime(now)
+								cur.SetConditions(c)
+								cur.SetDesiredState(v1.PackageRevisionActive)
+								*l = v1.ConfigurationRevisionList{Items: []v1.ConfigurationRevision{cur}}
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								if diff := cmp.Diff(v1.Healthy(), p.GetCondition(v1.TypeHealthy), test.EquateConditions()); diff != "" {
+									t.Errorf("StatusUpdate(...): -want healthy condition, +got healthy condition:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:        testLog,
+					record:     event.NewNopRecorder(),
+					conditions: conditions.ObservedGenerationPropagationManager{},
+					metrics: &MockMetrics{
+						MockRecordFirstTimeHealthy: func(p v1.Package) {
+							t.Errorf("RecordFirstTimeHealthy(...): we shouldn't record the time to healthy of package %q again", p.GetName())
+						},
+					},
+					audit: NewNopAuditSink(),
+					clock: testingclock.NewFakePassiveClock(now.Add(time.Second)),
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"MinHealthyDurationStabilizing": {
+			reason: "We shouldn't report the package healthy until its revision has been healthy for the minimum duration, and should come back when it has.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+								l := o.(*v1.ConfigurationRevisionList)
+								cur := v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: "test-1234567"}}
+								cur.SetRevision(1)
+								c := v1.RevisionHealthy()
+								c.LastTransitionTime = metav1.NewTime(now)
+								cur.SetConditions(c)
+								cur.SetDesiredState(v1.PackageRevisionActive)
+								*l = v1.ConfigurationRevisionList{Items: []v1.ConfigurationRevision{cur}}
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								if diff := cmp.Diff(v1.Stabilizing(6*time.Minute), p.GetCondition(v1.TypeHealthy), test.EquateConditions()); diff != "" {
+									t.Errorf("StatusUpdate(...): -want healthy condition, +got healthy condition:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:                testLog,
+					record:             event.NewNopRecorder(),
+					conditions:         conditions.ObservedGenerationPropagationManager{},
+					metrics:            &controller.NopMetrics{},
+					audit:              NewNopAuditSink(),
+					clock:              testingclock.NewFakePassiveClock(now.Add(4 * time.Minute)),
+					minHealthyDuration: 10 * time.Minute,
+				},
+			},
+			want: want{
+				r: reconcile.Result{RequeueAfter: 6 * time.Minute},
+			},
+		},
+		"MinHealthyDurationStable": {
+			reason: "We should report the package healthy once its revision has been healthy for the minimum duration.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+								l := o.(*v1.ConfigurationRevisionList)
+								cur := v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: "test-1234567"}}
+								cur.SetRevision(1)
+								c := v1.RevisionHealthy()
+								c.LastTransitionTime = metav1.NewTime(now)
+								cur.SetConditions(c)
+								cur.SetDesiredState(v1.PackageRevisionActive)
+								*l = v1.ConfigurationRevisionList{Items: []v1.ConfigurationRevision{cur}}
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								if diff := cmp.Diff(v1.Healthy(), p.GetCondition(v1.TypeHealthy), test.EquateConditions()); diff != "" {
+									t.Errorf("StatusUpdate(...): -want healthy condition, +got healthy condition:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:                testLog,
+					record:             event.NewNopRecorder(),
+					conditions:         conditions.ObservedGenerationPropagationManager{},
+					metrics:            &controller.NopMetrics{},
+					audit:              NewNopAuditSink(),
+					clock:              testingclock.NewFakePassiveClock(now.Add(11 * time.Minute)),
+					minHealthyDuration: 10 * time.Minute,
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"RevisionSpecDriftReused": {
+			reason: "We should reuse an existing revision's pull secrets and runtime config as-is by default.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Provider{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ProviderRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ProviderRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Provider)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ProviderGroupVersionKind)
+								p.SetPackagePullSecrets([]corev1.LocalObjectReference{{Name: "new-secret"}})
+								p.SetRuntimeConfigRef(&v1.RuntimeConfigReference{Name: "new-config"})
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+								l := o.(*v1.ProviderRevisionList)
+								cur := v1.ProviderRevision{ObjectMeta: metav1.ObjectMeta{Name: "test-1234567", UID: "rev-uid"}}
+								cur.SetRevision(1)
+								cur.SetConditions(v1.RevisionHealthy(), v1.RuntimeHealthy())
+								cur.SetDesiredState(v1.PackageRevisionActive)
+								cur.SetPackagePullSecrets([]corev1.LocalObjectReference{{Name: "old-secret"}})
+								cur.SetRuntimeConfigRef(&v1.RuntimeConfigReference{Name: "old-config"})
+								*l = v1.ProviderRevisionList{Items: []v1.ProviderRevision{cur}}
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, o client.Object, _ ...resource.ApplyOption) error {
+							pr := o.(*v1.ProviderRevision)
+							if diff := cmp.Diff([]corev1.LocalObjectReference{{Name: "old-secret"}}, pr.GetPackagePullSecrets()); diff != "" {
+								t.Errorf("Apply(...): -want revision pull secrets, +got revision pull secrets:\n%s", diff)
+							}
+							if diff := cmp.Diff(&v1.RuntimeConfigReference{Name: "old-config"}, pr.GetRuntimeConfigRef()); diff != "" {
+								t.Errorf("Apply(...): -want revision runtime config, +got revision runtime config:\n%s", diff)
+							}
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:        testLog,
+					record:     event.NewNopRecorder(),
+					conditions: conditions.ObservedGenerationPropagationManager{},
+					metrics:    &controller.NopMetrics{},
+					audit:      NewNopAuditSink(),
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"RevisionSpecDriftReconciled": {
+			reason: "We should update an existing revision's pull secrets and runtime config to match its package's when configured to reconcile its spec.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Provider{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ProviderRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ProviderRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Provider)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ProviderGroupVersionKind)
+								p.SetPackagePullSecrets([]corev1.LocalObjectReference{{Name: "new-secret"}})
+								p.SetRuntimeConfigRef(&v1.RuntimeConfigReference{Name: "new-config"})
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+								l := o.(*v1.ProviderRevisionList)
+								cur := v1.ProviderRevision{ObjectMeta: metav1.ObjectMeta{Name: "test-1234567", UID: "rev-uid"}}
+								cur.SetRevision(1)
+								cur.SetConditions(v1.RevisionHealthy(), v1.RuntimeHealthy())
+								cur.SetDesiredState(v1.PackageRevisionActive)
+								cur.SetPackagePullSecrets([]corev1.LocalObjectReference{{Name: "old-secret"}})
+								cur.SetRuntimeConfigRef(&v1.RuntimeConfigReference{Name: "old-config"})
+								*l = v1.ProviderRevisionList{Items: []v1.ProviderRevision{cur}}
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, o client.Object, _ ...resource.ApplyOption) error {
+							pr := o.(*v1.ProviderRevision)
+							if diff := cmp.Diff([]corev1.LocalObjectReference{{Name: "new-secret"}}, pr.GetPackagePullSecrets()); diff != "" {
+								t.Errorf("Apply(...): -want revision pull secrets, +got revision pull secrets:\n%s", diff)
+							}
+							if diff := cmp.Diff(&v1.RuntimeConfigReference{Name: "new-config"}, pr.GetRuntimeConfigRef()); diff != "" {
+								t.Errorf("Apply(...): -want revision runtime config, +got revision runtime config:\n%s", diff)
+							}
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:        testLog,
+					record:     event.NewNopRecorder(),
+					conditions: conditions.ObservedGenerationPropagationManager{},
+					metrics:    &controller.NopMetrics{},
+					audit:      NewNopAuditSink(),
+
+					reconcileRevisionSpec: true,
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"ProviderFamilyHealthDisabled": {
+			reason: "We should derive a family member's health only from its own revision when family health is disabled.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Provider{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ProviderRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ProviderRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Provider)
+								p.SetName("provider-aws-s3")
+								p.SetGroupVersionKind(v1.ProviderGroupVersionKind)
+								return nil
+							}),
+							MockList: func(_ context.Context, o client.ObjectList, opts ...client.ListOption) error {
+								l := o.(*v1.ProviderRevisionList)
+								lo := &client.ListOptions{}
+								lo.ApplyOptions(opts)
+								if lo.LabelSelector.Matches(labels.Set{v1.LabelProviderFamily: "family-aws"}) {
+									*l = v1.ProviderRevisionList{Items: []v1.ProviderRevision{
+										familyMember("provider-aws-ec2", v1.PackageRevisionActive, v1.RevisionUnhealthy(), v1.RuntimeHealthy()),
+									}}
+									return nil
+								}
+								cur := familyMember("provider-aws-s3", v1.PackageRevisionActive, v1.RevisionHealthy(), v1.RuntimeHealthy())
+								cur.SetName("test-1234567")
+								*l = v1.ProviderRevisionList{Items: []v1.ProviderRevision{cur}}
+								return nil
+							},
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								p, ok := o.(*v1.Provider)
+								if !ok {
+									return nil
+								}
+								if diff := cmp.Diff(v1.Healthy(), p.GetCondition(v1.TypeHealthy), test.EquateConditions()); diff != "" {
+									t.Errorf("StatusUpdate(...): -want healthy condition, +got healthy condition:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:        testLog,
+					record:     event.NewNopRecorder(),
+					conditions: conditions.ObservedGenerationPropagationManager{},
+					metrics:    &controller.NopMetrics{},
+					audit:      NewNopAuditSink(),
+					clock:      testingclock.NewFakePassiveClock(now),
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"ProviderFamilyAllMembersHealthy": {
+			reason: "We should report a family member healthy when every member of its family is healthy.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Provider{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ProviderRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ProviderRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Provider)
+								p.SetName("provider-aws-s3")
+								p.SetGroupVersionKind(v1.ProviderGroupVersionKind)
+								return nil
+							}),
+							MockList: func(_ context.Context, o client.ObjectList, opts ...client.ListOption) error {
+								l := o.(*v1.ProviderRevisionList)
+								lo := &client.ListOptions{}
+								lo.ApplyOptions(opts)
+								if lo.LabelSelector.Matches(labels.Set{v1.LabelProviderFamily: "family-aws"}) {
+									*l = v1.ProviderRevisionList{Items: []v1.ProviderRevision{
+										familyMember("provider-aws-ec2", v1.PackageRevisionActive, v1.RevisionHealthy(), v1.RuntimeHealthy()),
+										familyMember("provider-family-aws", v1.PackageRevisionActive, v1.RevisionHealthy(), v1.RuntimeHealthy()),
+									}}
+									return nil
+								}
+								cur := familyMember("provider-aws-s3", v1.PackageRevisionActive, v1.RevisionHealthy(), v1.RuntimeHealthy())
+								cur.SetName("test-1234567")
+								*l = v1.ProviderRevisionList{Items: []v1.ProviderRevision{cur}}
+								return nil
+							},
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								p, ok := o.(*v1.Provider)
+								if !ok {
+									return nil
+								}
+								if diff := cmp.Diff(v1.Healthy(), p.GetCondition(v1.TypeHealthy), test.EquateConditions()); diff != "" {
+									t.Errorf("StatusUpdate(...): -want healthy condition, +got healthy condition:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:          testLog,
+					record:       event.NewNopRecorder(),
+					conditions:   conditions.ObservedGenerationPropagationManager{},
+					metrics:      &controller.NopMetrics{},
+					audit:        NewNopAuditSink(),
+					clock:        testingclock.NewFakePassiveClock(now),
+					familyHealth: true,
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"ProviderFamilyMixedMemberHealth": {
+			reason: "We should report a family member partially healthy, listing its unhealthy members, when some members of its family are unhealthy.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Provider{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ProviderRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ProviderRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Provider)
+								p.SetName("provider-aws-s3")
+								p.SetGroupVersionKind(v1.ProviderGroupVersionKind)
+								return nil
+							}),
+							MockList: func(_ context.Context, o client.ObjectList, opts ...client.ListOption) error {
+								l := o.(*v1.ProviderRevisionList)
+								lo := &client.ListOptions{}
+								lo.ApplyOptions(opts)
+								if lo.LabelSelector.Matches(labels.Set{v1.LabelProviderFamily: "family-aws"}) {
+									*l = v1.ProviderRevisionList{Items: []v1.ProviderRevision{
+										familyMember("provider-aws-rds", v1.PackageRevisionActive, v1.RevisionHealthy(), v1.RuntimeUnhealthy()),
+										familyMember("provider-family-aws", v1.PackageRevisionActive, v1.RevisionHealthy(), v1.RuntimeHealthy()),
+										familyMember("provider-aws-ec2", v1.PackageRevisionActive, v1.RevisionUnhealthy()),
+										familyMember("provider-aws-iam", v1.PackageRevisionInactive, v1.RevisionUnhealthy()),
+									}}
+									return nil
+								}
+								cur := familyMember("provider-aws-s3", v1.PackageRevisionActive, v1.RevisionHealthy(), v1.RuntimeHealthy())
+								cur.SetName("test-1234567")
+								*l = v1.ProviderRevisionList{Items: []v1.ProviderRevision{cur}}
+								return nil
+							},
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								p, ok := o.(*v1.Provider)
+								if !ok {
+									return nil
+								}
+								if diff := cmp.Diff(v1.PartiallyHealthy("provider-aws-ec2", "provider-aws-rds"), p.GetCondition(v1.TypeHealthy), test.EquateConditions()); diff != "" {
+									t.Errorf("StatusUpdate(...): -want healthy condition, +got healthy condition:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:          testLog,
+					record:       event.NewNopRecorder(),
+					conditions:   conditions.ObservedGenerationPropagationManager{},
+					metrics:      &controller.NopMetrics{},
+					audit:        NewNopAuditSink(),
+					clock:        testingclock.NewFakePassiveClock(now),
+					familyHealth: true,
+				},
+			},
+			want: want{
+				r: reconcile.Result{RequeueAfter: familyHealthRecheckInterval},
+			},
+		},
+		"LastActionAnnotationDisabled": {
+			reason: "We shouldn't annotate the package with its last action unless configured to.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil),
+							MockUpdate: test.NewMockUpdateFn(nil, func(o client.Object) error {
+								t.Errorf("Update(...): we shouldn't update package %q", o.GetName())
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:        testLog,
+					record:     event.NewNopRecorder(),
+					conditions: conditions.ObservedGenerationPropagationManager{},
+					metrics:    &controller.NopMetrics{},
+					audit:      NewNopAuditSink(),
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"LastActionAnnotationActivate": {
+			reason: "We should record that we activated a revision, and under which activation policy.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil),
+							MockUpdate: test.NewMockUpdateFn(nil, func(o client.Object) error {
+								if diff := cmp.Diff(map[string]string{v1.AnnotationLastAction: `{"action":"Activate","revision":"test-1234567","reason":"AutomaticActivationPolicy"}`}, o.GetAnnotations()); diff != "" {
+									t.Errorf("Update(...): -want package annotations, +got package annotations:\n%s", diff)
+								}
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:                testLog,
+					record:             event.NewNopRecorder(),
+					conditions:         conditions.ObservedGenerationPropagationManager{},
+					metrics:            &controller.NopMetrics{},
+					audit:              NewNopAuditSink(),
+					annotateLastAction: true,
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"LastActionAnnotationCreate": {
+			reason: "We should record that we created a revision when we don't activate it.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								p.SetActivationPolicy(ptr.To(v1.ManualActivation))
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil),
+							MockUpdate: test.NewMockUpdateFn(nil, func(o client.Object) error {
+								if diff := cmp.Diff(map[string]string{v1.AnnotationLastAction: `{"action":"Create","revision":"test-1234567","reason":"NewRevision"}`}, o.GetAnnotations()); diff != "" {
+									t.Errorf("Update(...): -want package annotations, +got package annotations:\n%s", diff)
+								}
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:                testLog,
+					record:             event.NewNopRecorder(),
+					conditions:         conditions.ObservedGenerationPropagationManager{},
+					metrics:            &controller.NopMetrics{},
+					audit:              NewNopAuditSink(),
+					annotateLastAction: true,
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"LastActionAnnotationNoAction": {
+			reason: "We shouldn't update the package if we took no action on its revisions.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+								l := o.(*v1.ConfigurationRevisionList)
+								cur := v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: "test-1234567", UID: "rev-uid"}}
+								cur.SetRevision(1)
+								cur.SetConditions(v1.RevisionHealthy())
+								cur.SetDesiredState(v1.PackageRevisionActive)
+								*l = v1.ConfigurationRevisionList{Items: []v1.ConfigurationRevision{cur}}
+								return nil
+							}),
+							MockUpdate: test.NewMockUpdateFn(nil, func(o client.Object) error {
+								t.Errorf("Update(...): we shouldn't update package %q", o.GetName())
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:                testLog,
+					record:             event.NewNopRecorder(),
+					conditions:         conditions.ObservedGenerationPropagationManager{},
+					metrics:            &controller.NopMetrics{},
+					audit:              NewNopAuditSink(),
+					annotateLastAction: true,
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"PausedUntilBeforeResumeTime": {
+			reason: "We should pause reconciliation, and wake up when the pause expires, if the paused-until time hasn't passed.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								p.SetAnnotations(map[string]string{v1.AnnotationPausedUntil: now.Add(10 * time.Minute).Format(time.RFC3339)})
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil),
+							MockUpdate: test.NewMockUpdateFn(nil, func(_ client.Object) error {
+								t.Errorf("Update(...): we shouldn't remove the paused-until annotation before its time has passed")
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								if c := o.(*v1.Configuration).GetCondition(commonv1.ReconcilePaused().Type); c.Reason != commonv1.ReconcilePaused().Reason {
+									t.Errorf("StatusUpdate(...): want package paused, got condition reason %q", c.Reason)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							t.Errorf("Apply(...): we shouldn't apply a revision of a paused package")
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: func() (string, error) {
+							t.Errorf("Revision(...): we shouldn't resolve a paused package's revision")
+							return "test-1234567", nil
+						},
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:        testLog,
+					record:     event.NewNopRecorder(),
+					conditions: conditions.ObservedGenerationPropagationManager{},
+					metrics:    &controller.NopMetrics{},
+					audit:      NewNopAuditSink(),
+					clock:      testingclock.NewFakePassiveClock(now),
+				},
+			},
+			want: want{
+				r: reconcile.Result{RequeueAfter: 10 * time.Minute},
+			},
+		},
+		"PausedUntilAfterResumeTime": {
+			reason: "We should remove the paused-until annotation once its time has passed.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								p.SetAnnotations(map[string]string{v1.AnnotationPausedUntil: now.Add(-time.Minute).Format(time.RFC3339)})
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil),
+							MockUpdate: test.NewMockUpdateFn(nil, func(o client.Object) error {
+								if diff := cmp.Diff(map[string]string{}, o.GetAnnotations()); diff != "" {
+									t.Errorf("Update(...): -want annotations, +got annotations:\n%s", diff)
+								}
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								if c := o.(*v1.Configuration).GetCondition(commonv1.ReconcilePaused().Type); c.Reason == commonv1.ReconcilePaused().Reason {
+									t.Errorf("StatusUpdate(...): want package not paused once its paused-until time has passed")
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							t.Errorf("Apply(...): we shouldn't apply a revision of a paused package")
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: func() (string, error) {
+							t.Errorf("Revision(...): we shouldn't resolve a revision while removing the paused-until annotation")
+							return "test-1234567", nil
+						},
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:        testLog,
+					record:     event.NewNopRecorder(),
+					conditions: conditions.ObservedGenerationPropagationManager{},
+					metrics:    &controller.NopMetrics{},
+					audit:      NewNopAuditSink(),
+					clock:      testingclock.NewFakePassiveClock(now),
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"PausedUntilInvalidResumeTime": {
+			reason: "We should ignore a paused-until annotation that isn't an RFC3339 timestamp.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								p.SetAnnotations(map[string]string{v1.AnnotationPausedUntil: "tomorrow"})
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil),
+							MockUpdate: test.NewMockUpdateFn(nil, func(_ client.Object) error {
+								t.Errorf("Update(...): we shouldn't remove an invalid paused-until annotation")
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								if c := p.GetCondition(commonv1.ReconcilePaused().Type); c.Reason == commonv1.ReconcilePaused().Reason {
+									t.Errorf("StatusUpdate(...): want package not paused by an invalid paused-until annotation")
+								}
+								if diff := cmp.Diff("test-1234567", p.GetCurrentRevision()); diff != "" {
+									t.Errorf("StatusUpdate(...): -want current revision, +got current revision:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:        testLog,
+					record:     event.NewNopRecorder(),
+					conditions: conditions.ObservedGenerationPropagationManager{},
+					metrics:    &controller.NopMetrics{},
+					audit:      NewNopAuditSink(),
+					clock:      testingclock.NewFakePassiveClock(now),
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"DeprecatedAPIVersion": {
+			reason: "We should report, but still activate, a package whose contents define a deprecated API version.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								p, ok := o.(*v1.Configuration)
+								if !ok {
+									return nil
+								}
+								if diff := cmp.Diff("test-1234567", p.GetCurrentRevision()); diff != "" {
+									t.Errorf("StatusUpdate(...): -want current revision, +got current revision:\n%s", diff)
+								}
+								if diff := cmp.Diff(v1.Active(), p.GetCondition(v1.TypeInstalled), test.EquateConditions()); diff != "" {
+									t.Errorf("StatusUpdate(...): -want installed condition, +got installed condition:\n%s", diff)
+								}
+								if diff := cmp.Diff(v1.UsesDeprecatedAPIs("example.org/v1alpha1, Kind=Widget"), p.GetCondition(v1.TypeDeprecatedAPIs), test.EquateConditions()); diff != "" {
+									t.Errorf("StatusUpdate(...): -want DeprecatedAPIs condition, +got DeprecatedAPIs condition:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision:  NewMockRevisionFn("test-1234567", nil),
+						MockImageInfo: func() *ImageInfo { return &ImageInfo{Contents: deprecatedWidgets} },
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:        testLog,
+					record:     event.NewNopRecorder(),
+					conditions: conditions.ObservedGenerationPropagationManager{},
+					metrics:    &controller.NopMetrics{},
+					audit:      NewNopAuditSink(),
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"NoLongerDeprecatedAPIs": {
+			reason: "We should report that a package no longer uses deprecated APIs if it previously did.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								p.SetConditions(v1.UsesDeprecatedAPIs("example.org/v1alpha1, Kind=Widget"))
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								p, ok := o.(*v1.Configuration)
+								if !ok {
+									return nil
+								}
+								if diff := cmp.Diff("test-1234567", p.GetCurrentRevision()); diff != "" {
+									t.Errorf("StatusUpdate(...): -want current revision, +got current revision:\n%s", diff)
+								}
+								if diff := cmp.Diff(v1.Active(), p.GetCondition(v1.TypeInstalled), test.EquateConditions()); diff != "" {
+									t.Errorf("StatusUpdate(...): -want installed condition, +got installed condition:\n%s", diff)
+								}
+								if diff := cmp.Diff(v1.NoDeprecatedAPIs(), p.GetCondition(v1.TypeDeprecatedAPIs), test.EquateConditions()); diff != "" {
+									t.Errorf("StatusUpdate(...): -want DeprecatedAPIs condition, +got DeprecatedAPIs condition:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision:  NewMockRevisionFn("test-1234567", nil),
+						MockImageInfo: func() *ImageInfo { return &ImageInfo{Contents: clusterWidgets} },
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:        testLog,
+					record:     event.NewNopRecorder(),
+					conditions: conditions.ObservedGenerationPropagationManager{},
+					metrics:    &controller.NopMetrics{},
+					audit:      NewNopAuditSink(),
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"NeverDeprecatedAPIs": {
+			reason: "We shouldn't add a DeprecatedAPIs condition to a package that has never used deprecated APIs.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								p, ok := o.(*v1.Configuration)
+								if !ok {
+									return nil
+								}
+								if diff := cmp.Diff("test-1234567", p.GetCurrentRevision()); diff != "" {
+									t.Errorf("StatusUpdate(...): -want current revision, +got current revision:\n%s", diff)
+								}
+								if diff := cmp.Diff(v1.Active(), p.GetCondition(v1.TypeInstalled), test.EquateConditions()); diff != "" {
+									t.Errorf("StatusUpdate(...): -want installed condition, +got installed condition:\n%s", diff)
+								}
+								if diff := cmp.Diff(commonv1.Condition{Type: v1.TypeDeprecatedAPIs, Status: corev1.ConditionUnknown}, p.GetCondition(v1.TypeDeprecatedAPIs), test.EquateConditions()); diff != "" {
+									t.Errorf("StatusUpdate(...): -want DeprecatedAPIs condition, +got DeprecatedAPIs condition:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision:  NewMockRevisionFn("test-1234567", nil),
+						MockImageInfo: func() *ImageInfo { return &ImageInfo{Contents: clusterWidgets} },
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:        testLog,
+					record:     event.NewNopRecorder(),
+					conditions: conditions.ObservedGenerationPropagationManager{},
+					metrics:    &controller.NopMetrics{},
+					audit:      NewNopAuditSink(),
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"StrictOfflineNoRevisions": {
+			reason: "In strict offline mode we shouldn't fetch an IfNotPresent package that has no revisions.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								p.SetSource("xpkg.crossplane.io/crossplane/configuration-test:v1.0.0")
+								p.SetPackagePullPolicy(ptr.To(corev1.PullIfNotPresent))
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								p, ok := o.(*v1.Configuration)
+								if !ok {
+									return nil
+								}
+								if diff := cmp.Diff(v1.NoCachedRevision(), p.GetCondition(v1.TypeInstalled), test.EquateConditions()); diff != "" {
+									t.Errorf("StatusUpdate(...): -want installed condition, +got installed condition:\n%s", diff)
+								}
+								if diff := cmp.Diff("", p.GetCurrentRevision()); diff != "" {
+									t.Errorf("StatusUpdate(...): -want current revision, +got current revision:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: func() (string, error) {
+							t.Errorf("Revision(...): we shouldn't fetch the package")
+							return "", errBoom
+						},
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:           testLog,
+					record:        event.NewNopRecorder(),
+					conditions:    conditions.ObservedGenerationPropagationManager{},
+					metrics:       &controller.NopMetrics{},
+					audit:         NewNopAuditSink(),
+					strictOffline: true,
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"StrictOfflinePullAlways": {
+			reason: "In strict offline mode we should still fetch a package whose pull policy is Always.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								p.SetSource("xpkg.crossplane.io/crossplane/configuration-test:v1.0.0")
+								p.SetPackagePullPolicy(ptr.To(corev1.PullAlways))
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								p, ok := o.(*v1.Configuration)
+								if !ok {
+									return nil
+								}
+								if diff := cmp.Diff(v1.Unpacking().WithMessage(errors.Wrap(errBoom, errUnpack).Error()), p.GetCondition(v1.TypeInstalled), test.EquateConditions()); diff != "" {
+									t.Errorf("StatusUpdate(...): -want installed condition, +got installed condition:\n%s", diff)
+								}
+								if diff := cmp.Diff("", p.GetCurrentRevision()); diff != "" {
+									t.Errorf("StatusUpdate(...): -want current revision, +got current revision:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("", errBoom),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:           testLog,
+					record:        event.NewNopRecorder(),
+					conditions:    conditions.ObservedGenerationPropagationManager{},
+					metrics:       &controller.NopMetrics{},
+					audit:         NewNopAuditSink(),
+					strictOffline: true,
+				},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errUnpack),
+			},
+		},
+		"NotStrictOffline": {
+			reason: "Outside strict offline mode we should fetch an IfNotPresent package that has no revisions.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								p.SetSource("xpkg.crossplane.io/crossplane/configuration-test:v1.0.0")
+								p.SetPackagePullPolicy(ptr.To(corev1.PullIfNotPresent))
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								p, ok := o.(*v1.Configuration)
+								if !ok {
+									return nil
+								}
+								if diff := cmp.Diff(v1.Unpacking().WithMessage(errors.Wrap(errBoom, errUnpack).Error()), p.GetCondition(v1.TypeInstalled), test.EquateConditions()); diff != "" {
+									t.Errorf("StatusUpdate(...): -want installed condition, +got installed condition:\n%s", diff)
+								}
+								if diff := cmp.Diff("", p.GetCurrentRevision()); diff != "" {
+									t.Errorf("StatusUpdate(...): -want current revision, +got current revision:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("", errBoom),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:        testLog,
+					record:     event.NewNopRecorder(),
+					conditions: conditions.ObservedGenerationPropagationManager{},
+					metrics:    &controller.NopMetrics{},
+					audit:      NewNopAuditSink(),
+				},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errUnpack),
+			},
+		},
+		"FinalizerNameAddCustom": {
+			reason: "We should add the configured finalizer to a package whose revisions must be orphaned.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								p.SetDeletionPolicy(commonv1.DeletionOrphan)
+								return nil
+							}),
+							MockList:         test.NewMockListFn(nil),
+							MockUpdate:       test.NewMockUpdateFn(nil),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					finalizer: resource.NewAPIFinalizer(&test.MockClient{
+						MockUpdate: test.NewMockUpdateFn(nil, func(o client.Object) error {
+							if diff := cmp.Diff([]string{customFinalizer}, o.GetFinalizers()); diff != "" {
+								t.Errorf("Update(...): -want finalizers, +got finalizers:\n%s", diff)
+							}
+							return nil
+						}),
+					}, customFinalizer),
+					finalizerName: customFinalizer,
+					log:           testLog,
+					record:        event.NewNopRecorder(),
+					conditions:    conditions.ObservedGenerationPropagationManager{},
+					metrics:       &controller.NopMetrics{},
+					audit:         NewNopAuditSink(),
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"FinalizerNameRemoveCustom": {
+			reason: "We should only remove the configured finalizer from a deleted package.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								p.SetDeletionPolicy(commonv1.DeletionDelete)
+								p.SetFinalizers([]string{finalizer, customFinalizer})
+								p.SetDeletionTimestamp(&deletedAt)
+								return nil
+							}),
+							MockList:         test.NewMockListFn(nil),
+							MockUpdate:       test.NewMockUpdateFn(nil),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					finalizer: resource.NewAPIFinalizer(&test.MockClient{
+						MockUpdate: test.NewMockUpdateFn(nil, func(o client.Object) error {
+							if diff := cmp.Diff([]string{finalizer}, o.GetFinalizers()); diff != "" {
+								t.Errorf("Update(...): -want finalizers, +got finalizers:\n%s", diff)
+							}
+							return nil
+						}),
+					}, customFinalizer),
+					finalizerName: customFinalizer,
+					log:           testLog,
+					record:        event.NewNopRecorder(),
+					conditions:    conditions.ObservedGenerationPropagationManager{},
+					metrics:       &controller.NopMetrics{},
+					audit:         NewNopAuditSink(),
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"FinalizerNameIgnoreDefault": {
+			reason: "We shouldn't remove the default finalizer when we're configured to use another.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								p.SetDeletionPolicy(commonv1.DeletionDelete)
+								p.SetFinalizers([]string{finalizer})
+								return nil
+							}),
+							MockList:         test.NewMockListFn(nil),
+							MockUpdate:       test.NewMockUpdateFn(nil),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					finalizer: resource.NewAPIFinalizer(&test.MockClient{
+						MockUpdate: test.NewMockUpdateFn(nil, func(o client.Object) error {
+							t.Errorf("Update(...): we shouldn't remove the default finalizer when we're configured to use another")
+							return nil
+						}),
+					}, customFinalizer),
+					finalizerName: customFinalizer,
+					log:           testLog,
+					record:        event.NewNopRecorder(),
+					conditions:    conditions.ObservedGenerationPropagationManager{},
+					metrics:       &controller.NopMetrics{},
+					audit:         NewNopAuditSink(),
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"ProvenanceRewritten": {
+			reason: "We should record that a rewritten package's source was rewritten before it resolved to a digest.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								p.SetSource("xpkg.crossplane.io/crossplane/configuration-test:v1.0.0")
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								pr, ok := o.(*v1.ConfigurationRevision)
+								if !ok {
+									return nil
+								}
+								want := []v1.ProvenanceStep{
+									{Type: v1.ProvenanceStepRequested, Reference: "xpkg.crossplane.io/crossplane/configuration-test:v1.0.0"},
+									{Type: v1.ProvenanceStepRewritten, Reference: "registry.example.org/crossplane/configuration-test:v1.0.0", Via: "private-mirror"},
+									{Type: v1.ProvenanceStepDigestResolved, Reference: imageDigest},
+								}
+								if diff := cmp.Diff(want, pr.GetProvenance()); diff != "" {
+									t.Errorf("StatusUpdate(...): -want provenance, +got provenance:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision:  NewMockRevisionFn("test-1234567", nil),
+						MockImageInfo: func() *ImageInfo { return &ImageInfo{Digest: imageDigest} },
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("private-mirror", "registry.example.org/crossplane/configuration-test:v1.0.0", nil),
+					},
+					log:        testLog,
+					record:     event.NewNopRecorder(),
+					conditions: conditions.ObservedGenerationPropagationManager{},
+					metrics:    &controller.NopMetrics{},
+					audit:      NewNopAuditSink(),
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"ProvenanceAliasedAndRewritten": {
+			reason: "We should record that an aliased package's source was expanded, then rewritten, before it resolved to a digest.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								p.SetSource("alias://configuration-test:v1.0.0")
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								pr, ok := o.(*v1.ConfigurationRevision)
+								if !ok {
+									return nil
+								}
+								want := []v1.ProvenanceStep{
+									{Type: v1.ProvenanceStepRequested, Reference: "alias://configuration-test:v1.0.0"},
+									{Type: v1.ProvenanceStepAliasExpanded, Reference: "xpkg.crossplane.io/crossplane/configuration-test:v1.0.0", Via: "configuration-test"},
+									{Type: v1.ProvenanceStepRewritten, Reference: "registry.example.org/crossplane/configuration-test:v1.0.0", Via: "private-mirror"},
+									{Type: v1.ProvenanceStepDigestResolved, Reference: imageDigest},
+								}
+								if diff := cmp.Diff(want, pr.GetProvenance()); diff != "" {
+									t.Errorf("StatusUpdate(...): -want provenance, +got provenance:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision:  NewMockRevisionFn("test-1234567", nil),
+						MockImageInfo: func() *ImageInfo { return &ImageInfo{Digest: imageDigest} },
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("private-mirror", "registry.example.org/crossplane/configuration-test:v1.0.0", nil),
+					},
+					log:        testLog,
+					record:     event.NewNopRecorder(),
+					conditions: conditions.ObservedGenerationPropagationManager{},
+					metrics:    &controller.NopMetrics{},
+					audit:      NewNopAuditSink(),
+					aliases: AliasResolverFn(func(_ context.Context, _ string) (string, string, error) {
+						return "configuration-test", "xpkg.crossplane.io/crossplane/configuration-test:v1.0.0", nil
+					}),
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"MaxConditionMessageLengthTruncated": {
+			reason: "We should truncate an oversized condition message, but keep the full error in the package's status.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								p, ok := o.(*v1.Configuration)
+								if !ok {
+									return nil
+								}
+								if diff := cmp.Diff(errors.Wrap(errHuge, errUnpack).Error()[:125]+"...", p.GetCondition(v1.TypeInstalled).Message); diff != "" {
+									t.Errorf("StatusUpdate(...): -want installed condition message, +got installed condition message:\n%s", diff)
+								}
+								if diff := cmp.Diff(errors.Wrap(errHuge, errUnpack).Error(), p.GetLastResolverError()); diff != "" {
+									t.Errorf("StatusUpdate(...): -want last resolver error, +got last resolver error:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("", errHuge),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:                       testLog,
+					record:                    event.NewNopRecorder(),
+					conditions:                conditions.ObservedGenerationPropagationManager{},
+					metrics:                   &controller.NopMetrics{},
+					audit:                     NewNopAuditSink(),
+					maxConditionMessageLength: 128,
+				},
+			},
+			want: want{
+				err: errors.Wrap(errHuge, errUnpack),
+			},
+		},
+		"MaxConditionMessageLengthUnlimited": {
+			reason: "We shouldn't truncate condition messages if no maximum length is configured.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								p, ok := o.(*v1.Configuration)
+								if !ok {
+									return nil
+								}
+								if diff := cmp.Diff(errors.Wrap(errHuge, errUnpack).Error(), p.GetCondition(v1.TypeInstalled).Message); diff != "" {
+									t.Errorf("StatusUpdate(...): -want installed condition message, +got installed condition message:\n%s", diff)
+								}
+								if diff := cmp.Diff(errors.Wrap(errHuge, errUnpack).Error(), p.GetLastResolverError()); diff != "" {
+									t.Errorf("StatusUpdate(...): -want last resolver error, +got last resolver error:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("", errHuge),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:        testLog,
+					record:     event.NewNopRecorder(),
+					conditions: conditions.ObservedGenerationPropagationManager{},
+					metrics:    &controller.NopMetrics{},
+					audit:      NewNopAuditSink(),
+				},
+			},
+			want: want{
+				err: errors.Wrap(errHuge, errUnpack),
+			},
+		},
+		"LockRecorderSkipsDependencyResolution": {
+			reason: "We should record an active revision that skips dependency resolution in the Lock.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								p.SetSkipDependencyResolution(ptr.To(true))
+								return nil
+							}),
+							MockList:         test.NewMockListFn(nil),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:        testLog,
+					record:     event.NewNopRecorder(),
+					conditions: conditions.ObservedGenerationPropagationManager{},
+					metrics:    &controller.NopMetrics{},
+					audit:      NewNopAuditSink(),
+					lock: LockRecorderFn(func(_ context.Context, pr v1.PackageRevision) error {
+						if pr.GetName() != "test-1234567" {
+							t.Errorf("RecordRevision(...): want revision %q, got %q", "test-1234567", pr.GetName())
+						}
+						return nil
+					}),
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"LockRecorderResolvesDependencies": {
+			reason: "We should leave recording an active revision that resolves its dependencies to the revision reconciler.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								p.SetSkipDependencyResolution(ptr.To(false))
+								return nil
+							}),
+							MockList:         test.NewMockListFn(nil),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:        testLog,
+					record:     event.NewNopRecorder(),
+					conditions: conditions.ObservedGenerationPropagationManager{},
+					metrics:    &controller.NopMetrics{},
+					audit:      NewNopAuditSink(),
+					lock: LockRecorderFn(func(_ context.Context, pr v1.PackageRevision) error {
+						t.Errorf("RecordRevision(...): we shouldn't record revision %q, which resolves its dependencies", pr.GetName())
+						return nil
+					}),
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"RuntimeConfigPresent": {
+			reason: "We should activate a revision, and deactivate the previously active revision, if the runtime config its package references exists.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Provider{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ProviderRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ProviderRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: func(_ context.Context, _ client.ObjectKey, o client.Object) error {
+								switch p := o.(type) {
+								case *v1.Provider:
+									p.SetName("test")
+									p.SetGroupVersionKind(v1.ProviderGroupVersionKind)
+									p.SetRuntimeConfigRef(&v1.RuntimeConfigReference{Name: "missing"})
+								case *v1beta1.DeploymentRuntimeConfig:
+									return nil
+								}
+								return nil
+							},
+							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+								old := v1.ProviderRevision{ObjectMeta: metav1.ObjectMeta{Name: "test-old"}}
+								old.SetRevision(1)
+								old.SetDesiredState(v1.PackageRevisionActive)
+								*o.(*v1.ProviderRevisionList) = v1.ProviderRevisionList{Items: []v1.ProviderRevision{old}}
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								p, ok := o.(*v1.Provider)
+								if !ok {
+									return nil
+								}
+								if diff := cmp.Diff(v1.Active(), p.GetCondition(v1.TypeInstalled), test.EquateConditions()); diff != "" {
+									t.Errorf("StatusUpdate(...): -want installed condition, +got installed condition:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, o client.Object, _ ...resource.ApplyOption) error {
+							want := map[string]v1.PackageRevisionDesiredState{
+								"test-old":     v1.PackageRevisionInactive,
+								"test-1234567": v1.PackageRevisionActive,
+							}
+							if diff := cmp.Diff(want[o.GetName()], o.(*v1.ProviderRevision).GetDesiredState()); diff != "" {
+								t.Errorf("Apply(...): -want revision %q desired state, +got desired state:\n%s", o.GetName(), diff)
+							}
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:        testLog,
+					record:     event.NewNopRecorder(),
+					conditions: conditions.ObservedGenerationPropagationManager{},
+					metrics:    &controller.NopMetrics{},
+					audit:      NewNopAuditSink(),
+					features:   runtimeConfigs,
+					clock:      testingclock.NewFakePassiveClock(now),
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"RuntimeConfigMissing": {
+			reason: "We should not activate a revision, or deactivate the previously active revision, if the runtime config its package references doesn't exist.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Provider{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ProviderRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ProviderRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: func(_ context.Context, _ client.ObjectKey, o client.Object) error {
+								switch p := o.(type) {
+								case *v1.Provider:
+									p.SetName("test")
+									p.SetGroupVersionKind(v1.ProviderGroupVersionKind)
+									p.SetRuntimeConfigRef(&v1.RuntimeConfigReference{Name: "missing"})
+								case *v1beta1.DeploymentRuntimeConfig:
+									return kerrors.NewNotFound(schema.GroupResource{}, "missing")
+								}
+								return nil
+							},
+							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+								old := v1.ProviderRevision{ObjectMeta: metav1.ObjectMeta{Name: "test-old"}}
+								old.SetRevision(1)
+								old.SetDesiredState(v1.PackageRevisionActive)
+								*o.(*v1.ProviderRevisionList) = v1.ProviderRevisionList{Items: []v1.ProviderRevision{old}}
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								p, ok := o.(*v1.Provider)
+								if !ok {
+									return nil
+								}
+								if diff := cmp.Diff(v1.RuntimeConfigMissing("missing"), p.GetCondition(v1.TypeInstalled), test.EquateConditions()); diff != "" {
+									t.Errorf("StatusUpdate(...): -want installed condition, +got installed condition:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, o client.Object, _ ...resource.ApplyOption) error {
+							want := map[string]v1.PackageRevisionDesiredState{
+								"test-1234567": v1.PackageRevisionInactive,
+							}
+							if diff := cmp.Diff(want[o.GetName()], o.(*v1.ProviderRevision).GetDesiredState()); diff != "" {
+								t.Errorf("Apply(...): -want revision %q desired state, +got desired state:\n%s", o.GetName(), diff)
+							}
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:        testLog,
+					record:     event.NewNopRecorder(),
+					conditions: conditions.ObservedGenerationPropagationManager{},
+					metrics:    &controller.NopMetrics{},
+					audit:      NewNopAuditSink(),
+					features:   runtimeConfigs,
+					clock:      testingclock.NewFakePassiveClock(now),
+				},
+			},
+			want: want{
+				r: reconcile.Result{RequeueAfter: runtimeConfigRecheckInterval},
+			},
+		},
+		"ErrGetRuntimeConfig": {
+			reason: "We should return an error if we can't get the runtime config a package references.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Provider{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ProviderRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ProviderRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: func(_ context.Context, _ client.ObjectKey, o client.Object) error {
+								switch p := o.(type) {
+								case *v1.Provider:
+									p.SetName("test")
+									p.SetGroupVersionKind(v1.ProviderGroupVersionKind)
+									p.SetRuntimeConfigRef(&v1.RuntimeConfigReference{Name: "missing"})
+								case *v1beta1.DeploymentRuntimeConfig:
+									return errBoom
+								}
+								return nil
+							},
+							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+								old := v1.ProviderRevision{ObjectMeta: metav1.ObjectMeta{Name: "test-old"}}
+								old.SetRevision(1)
+								old.SetDesiredState(v1.PackageRevisionActive)
+								*o.(*v1.ProviderRevisionList) = v1.ProviderRevisionList{Items: []v1.ProviderRevision{old}}
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, o client.Object, _ ...resource.ApplyOption) error {
+							want := map[string]v1.PackageRevisionDesiredState{}
+							if diff := cmp.Diff(want[o.GetName()], o.(*v1.ProviderRevision).GetDesiredState()); diff != "" {
+								t.Errorf("Apply(...): -want revision %q desired state, +got desired state:\n%s", o.GetName(), diff)
+							}
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:        testLog,
+					record:     event.NewNopRecorder(),
+					conditions: conditions.ObservedGenerationPropagationManager{},
+					metrics:    &controller.NopMetrics{},
+					audit:      NewNopAuditSink(),
+					features:   runtimeConfigs,
+					clock:      testingclock.NewFakePassiveClock(now),
+				},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errGetRuntimeConfig),
+			},
+		},
+		"ReconcileObserver": {
+			reason: "We should report what we did to the reconcile observer.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								p.SetSource("xpkg.crossplane.io/crossplane/configuration-test:v1.0.0")
+								return nil
+							}),
+							MockList:         test.NewMockListFn(nil),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:        testLog,
+					record:     event.NewNopRecorder(),
+					conditions: conditions.ObservedGenerationPropagationManager{},
+					metrics:    &controller.NopMetrics{},
+					audit:      NewNopAuditSink(),
+					observer: controller.ReconcileObserverFn(func(_ context.Context, report controller.ReconcileReport) {
+						want := controller.ReconcileReport{
+							Request:        reconcile.Request{},
+							Source:         "xpkg.crossplane.io/crossplane/configuration-test:v1.0.0",
+							ResolvedSource: "xpkg.crossplane.io/crossplane/configuration-test:v1.0.0",
+							Revision:       "test-1234567",
+							Created:        true,
+							Activated:      true,
+						}
+						if diff := cmp.Diff(want, report, test.EquateErrors()); diff != "" {
+							t.Errorf("Observe(...): -want report, +got report:\n%s", diff)
+						}
+					}),
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"SourceUnavailableCurrentHealthy": {
+			reason: "We should keep a healthy current revision active and warn that the package's source is unavailable if its tag was deleted.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								p.SetCurrentRevision("test-1234567")
+								p.SetConditions(v1.Active())
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+								cur := v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{
+									Name:   "test-1234567",
+									Labels: map[string]string{v1.LabelParentPackage: "test"},
+								}}
+								cur.SetDesiredState(v1.PackageRevisionActive)
+								cur.SetConditions(v1.RevisionHealthy())
+								*o.(*v1.ConfigurationRevisionList) = v1.ConfigurationRevisionList{Items: []v1.ConfigurationRevision{cur}}
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								p, ok := o.(*v1.Configuration)
+								if !ok {
+									return nil
+								}
+								if diff := cmp.Diff(v1.Active(), p.GetCondition(v1.TypeInstalled), test.EquateConditions()); diff != "" {
+									t.Errorf("StatusUpdate(...): -want installed condition, +got installed condition:\n%s", diff)
+								}
+								if diff := cmp.Diff(v1.SourceUnavailable("test-1234567", errors.Wrap(errManifestUnknown, errUnpack)), p.GetCondition(v1.TypeSourceUnavailable), test.EquateConditions()); diff != "" {
+									t.Errorf("StatusUpdate(...): -want source unavailable condition, +got source unavailable condition:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("", errManifestUnknown),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:        testLog,
+					record:     event.NewNopRecorder(),
+					conditions: conditions.ObservedGenerationPropagationManager{},
+					metrics:    &controller.NopMetrics{},
+					audit:      NewNopAuditSink(),
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"SourceUnavailableCurrentUnhealthy": {
+			reason: "We should fail if a package's tag was deleted and it has no healthy revision to fall back to.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								p.SetCurrentRevision("test-1234567")
+								p.SetConditions(v1.Active())
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+								cur := v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{
+									Name:   "test-1234567",
+									Labels: map[string]string{v1.LabelParentPackage: "test"},
+								}}
+								cur.SetDesiredState(v1.PackageRevisionActive)
+								cur.SetConditions(v1.RevisionUnhealthy())
+								*o.(*v1.ConfigurationRevisionList) = v1.ConfigurationRevisionList{Items: []v1.ConfigurationRevision{cur}}
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								p, ok := o.(*v1.Configuration)
+								if !ok {
+									return nil
+								}
+								if diff := cmp.Diff(v1.Unpacking().WithMessage(errors.Wrap(errManifestUnknown, errUnpack).Error()), p.GetCondition(v1.TypeInstalled), test.EquateConditions()); diff != "" {
+									t.Errorf("StatusUpdate(...): -want installed condition, +got installed condition:\n%s", diff)
+								}
+								if diff := cmp.Diff(commonv1.Condition{Type: v1.TypeSourceUnavailable, Status: corev1.ConditionUnknown}, p.GetCondition(v1.TypeSourceUnavailable), test.EquateConditions()); diff != "" {
+									t.Errorf("StatusUpdate(...): -want source unavailable condition, +got source unavailable condition:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("", errManifestUnknown),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:        testLog,
+					record:     event.NewNopRecorder(),
+					conditions: conditions.ObservedGenerationPropagationManager{},
+					metrics:    &controller.NopMetrics{},
+					audit:      NewNopAuditSink(),
+				},
+			},
+			want: want{
+				err: errors.Wrap(errManifestUnknown, errUnpack),
+			},
+		},
+		"ServiceAccountPullSecrets": {
+			reason: "We should fetch a package using the image pull secrets of the ServiceAccount it names.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: func(_ context.Context, key client.ObjectKey, o client.Object) error {
+								switch o := o.(type) {
+								case *v1.Configuration:
+									o.SetName("test")
+									o.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+									o.SetServiceAccountName("puller")
+								case *corev1.ServiceAccount:
+									if diff := cmp.Diff(types.NamespacedName{Namespace: "crossplane-system", Name: "puller"}, key); diff != "" {
+										t.Errorf("Get(...): -want key, +got key:\n%s", diff)
+									}
+									o.ImagePullSecrets = []corev1.LocalObjectReference{{Name: "secret-a"}, {Name: "secret-b"}}
+								}
+								return nil
+							},
+							MockList: test.NewMockListFn(nil),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								p, ok := o.(*v1.Configuration)
+								if !ok {
+									return nil
+								}
+								if diff := cmp.Diff(v1.Unpacking().WithMessage(errors.Wrap(errBoom, errUnpack).Error()), p.GetCondition(v1.TypeInstalled), test.EquateConditions()); diff != "" {
+									t.Errorf("StatusUpdate(...): -want installed condition, +got installed condition:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("", errBoom),
+						MockPullSecrets: func(extra []string) {
+							if diff := cmp.Diff([]string{"secret-a", "secret-b"}, extra); diff != "" {
+								t.Errorf("Revision(...): -want pull secrets, +got pull secrets:\n%s", diff)
+							}
+						},
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:        testLog,
+					record:     event.NewNopRecorder(),
+					conditions: conditions.ObservedGenerationPropagationManager{},
+					metrics:    &controller.NopMetrics{},
+					audit:      NewNopAuditSink(),
+					namespace:  "crossplane-system",
+				},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errUnpack),
+			},
+		},
+		"ServiceAccountMissing": {
+			reason: "We should wait for the ServiceAccount a package names to exist before we fetch the package.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: func(_ context.Context, key client.ObjectKey, o client.Object) error {
+								switch o := o.(type) {
+								case *v1.Configuration:
+									o.SetName("test")
+									o.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+									o.SetServiceAccountName("puller")
+								case *corev1.ServiceAccount:
+									if diff := cmp.Diff(types.NamespacedName{Namespace: "crossplane-system", Name: "puller"}, key); diff != "" {
+										t.Errorf("Get(...): -want key, +got key:\n%s", diff)
+									}
+									return kerrors.NewNotFound(schema.GroupResource{}, "puller")
+								}
+								return nil
+							},
+							MockList: test.NewMockListFn(nil),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								p, ok := o.(*v1.Configuration)
+								if !ok {
+									return nil
+								}
+								if diff := cmp.Diff(v1.ServiceAccountPending("puller"), p.GetCondition(v1.TypeInstalled), test.EquateConditions()); diff != "" {
+									t.Errorf("StatusUpdate(...): -want installed condition, +got installed condition:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: func() (string, error) {
+							t.Errorf("Revision(...): we shouldn't fetch the package before its ServiceAccount exists")
+							return "", errBoom
+						},
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:        testLog,
+					record:     event.NewNopRecorder(),
+					conditions: conditions.ObservedGenerationPropagationManager{},
+					metrics:    &controller.NopMetrics{},
+					audit:      NewNopAuditSink(),
+					namespace:  "crossplane-system",
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: true},
+			},
+		},
+		"ExpectedDigestMatch": {
+			reason: "We should create a revision of a package that resolved to its expected digest.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								p.SetExpectedDigest(imageDigest)
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								p, ok := o.(*v1.Configuration)
+								if !ok {
+									return nil
+								}
+								if diff := cmp.Diff(v1.Active(), p.GetCondition(v1.TypeInstalled), test.EquateConditions()); diff != "" {
+									t.Errorf("StatusUpdate(...): -want installed condition, +got installed condition:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision:  NewMockRevisionFn("test-1234567", nil),
+						MockImageInfo: func() *ImageInfo { return &ImageInfo{Digest: imageDigest} },
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:        testLog,
+					record:     event.NewNopRecorder(),
+					conditions: conditions.ObservedGenerationPropagationManager{},
+					metrics:    &controller.NopMetrics{},
+					audit:      NewNopAuditSink(),
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"ExpectedDigestMismatch": {
+			reason: "We should not create a revision of a package that resolved to a different digest than expected.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								p.SetExpectedDigest(imageDigest)
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								p, ok := o.(*v1.Configuration)
+								if !ok {
+									return nil
+								}
+								if diff := cmp.Diff(v1.DigestMismatch(imageDigest, otherDigest), p.GetCondition(v1.TypeInstalled), test.EquateConditions()); diff != "" {
+									t.Errorf("StatusUpdate(...): -want installed condition, +got installed condition:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, o client.Object, _ ...resource.ApplyOption) error {
+							t.Errorf("Apply(...): we shouldn't create revision %q", o.GetName())
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision:  NewMockRevisionFn("test-1234567", nil),
+						MockImageInfo: func() *ImageInfo { return &ImageInfo{Digest: otherDigest} },
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:        testLog,
+					record:     event.NewNopRecorder(),
+					conditions: conditions.ObservedGenerationPropagationManager{},
+					metrics:    &controller.NopMetrics{},
+					audit:      NewNopAuditSink(),
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"ExpectedDigestPinned": {
+			reason: "We should create a revision of a package whose source is pinned to its expected digest, even if the Revisioner didn't report the digest.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								p.SetSource("xpkg.crossplane.io/crossplane/configuration-test@" + imageDigest)
+								p.SetExpectedDigest(imageDigest)
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								p, ok := o.(*v1.Configuration)
+								if !ok {
+									return nil
+								}
+								if diff := cmp.Diff(v1.Active(), p.GetCondition(v1.TypeInstalled), test.EquateConditions()); diff != "" {
+									t.Errorf("StatusUpdate(...): -want installed condition, +got installed condition:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision:  NewMockRevisionFn("test-1234567", nil),
+						MockImageInfo: func() *ImageInfo { return nil },
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:        testLog,
+					record:     event.NewNopRecorder(),
+					conditions: conditions.ObservedGenerationPropagationManager{},
+					metrics:    &controller.NopMetrics{},
+					audit:      NewNopAuditSink(),
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"ExpectedDigestUnknown": {
+			reason: "We should not create a revision of a package whose resolved digest we can't verify.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								p.SetExpectedDigest(imageDigest)
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								p, ok := o.(*v1.Configuration)
+								if !ok {
+									return nil
+								}
+								if diff := cmp.Diff(v1.DigestMismatch(imageDigest, ""), p.GetCondition(v1.TypeInstalled), test.EquateConditions()); diff != "" {
+									t.Errorf("StatusUpdate(...): -want installed condition, +got installed condition:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, o client.Object, _ ...resource.ApplyOption) error {
+							t.Errorf("Apply(...): we shouldn't create revision %q", o.GetName())
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision:  NewMockRevisionFn("test-1234567", nil),
+						MockImageInfo: func() *ImageInfo { return nil },
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:        testLog,
+					record:     event.NewNopRecorder(),
+					conditions: conditions.ObservedGenerationPropagationManager{},
+					metrics:    &controller.NopMetrics{},
+					audit:      NewNopAuditSink(),
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"GarbageCollectionModeDefaultLimit": {
+			reason: "We should report that a package that doesn't specify a revision history limit uses the default limit.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								p, ok := o.(*v1.Configuration)
+								if !ok {
+									return nil
+								}
+								if diff := cmp.Diff(v1.GarbageCollectionDefaultLimit(2), p.GetCondition(v1.TypeRevisionGarbageCollectionMode), test.EquateConditions()); diff != "" {
+									t.Errorf("StatusUpdate(...): -want garbage collection mode condition, +got garbage collection mode condition:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:                         testLog,
+					record:                      event.NewNopRecorder(),
+					conditions:                  conditions.ObservedGenerationPropagationManager{},
+					metrics:                     &controller.NopMetrics{},
+					audit:                       NewNopAuditSink(),
+					reportGCMode:                true,
+					defaultRevisionHistoryLimit: ptr.To[int64](2),
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"GarbageCollectionModeExplicitLimit": {
+			reason: "We should report that a package that specifies a revision history limit uses it, even if there's a default.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								p.SetRevisionHistoryLimit(ptr.To[int64](1))
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								p, ok := o.(*v1.Configuration)
+								if !ok {
+									return nil
+								}
+								if diff := cmp.Diff(v1.GarbageCollectionExplicitLimit(1), p.GetCondition(v1.TypeRevisionGarbageCollectionMode), test.EquateConditions()); diff != "" {
+									t.Errorf("StatusUpdate(...): -want garbage collection mode condition, +got garbage collection mode condition:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:                         testLog,
+					record:                      event.NewNopRecorder(),
+					conditions:                  conditions.ObservedGenerationPropagationManager{},
+					metrics:                     &controller.NopMetrics{},
+					audit:                       NewNopAuditSink(),
+					reportGCMode:                true,
+					defaultRevisionHistoryLimit: ptr.To[int64](2),
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"GarbageCollectionModeDisabledNoLimit": {
+			reason: "We should report that garbage collection is disabled for a package with no revision history limit and no default.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								p, ok := o.(*v1.Configuration)
+								if !ok {
+									return nil
+								}
+								if diff := cmp.Diff(v1.GarbageCollectionDisabled(), p.GetCondition(v1.TypeRevisionGarbageCollectionMode), test.EquateConditions()); diff != "" {
+									t.Errorf("StatusUpdate(...): -want garbage collection mode condition, +got garbage collection mode condition:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:          testLog,
+					record:       event.NewNopRecorder(),
+					conditions:   conditions.ObservedGenerationPropagationManager{},
+					metrics:      &controller.NopMetrics{},
+					audit:        NewNopAuditSink(),
+					reportGCMode: true,
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"GarbageCollectionModeDisabledZeroLimit": {
+			reason: "We should report that garbage collection is disabled for a package whose revision history limit is zero.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								p.SetRevisionHistoryLimit(ptr.To[int64](0))
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								p, ok := o.(*v1.Configuration)
+								if !ok {
+									return nil
+								}
+								if diff := cmp.Diff(v1.GarbageCollectionDisabled(), p.GetCondition(v1.TypeRevisionGarbageCollectionMode), test.EquateConditions()); diff != "" {
+									t.Errorf("StatusUpdate(...): -want garbage collection mode condition, +got garbage collection mode condition:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:                         testLog,
+					record:                      event.NewNopRecorder(),
+					conditions:                  conditions.ObservedGenerationPropagationManager{},
+					metrics:                     &controller.NopMetrics{},
+					audit:                       NewNopAuditSink(),
+					reportGCMode:                true,
+					defaultRevisionHistoryLimit: ptr.To[int64](2),
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"RevisionCreationCountFirst": {
+			reason: "We should count the first revision we create.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								if diff := cmp.Diff(int64(1), o.(*v1.Configuration).GetTotalRevisionsCreated()); diff != "" {
+									t.Errorf("StatusUpdate(...): -want total revisions created, +got total revisions created:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1111111", nil),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:          testLog,
+					record:       event.NewNopRecorder(),
+					conditions:   conditions.ObservedGenerationPropagationManager{},
+					metrics:      &controller.NopMetrics{},
+					audit:        NewNopAuditSink(),
+					countCreated: true,
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"RevisionCreationCountNew": {
+			reason: "We should count each new revision we create.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								p.SetTotalRevisionsCreated(1)
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+								l := o.(*v1.ConfigurationRevisionList)
+								for n, name := range []string{"test-1111111"} {
+									rev := v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: name, UID: types.UID(name)}}
+									rev.SetRevision(int64(n + 1))
+									rev.SetConditions(v1.RevisionHealthy())
+									l.Items = append(l.Items, rev)
+								}
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								if diff := cmp.Diff(int64(2), o.(*v1.Configuration).GetTotalRevisionsCreated()); diff != "" {
+									t.Errorf("StatusUpdate(...): -want total revisions created, +got total revisions created:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-2222222", nil),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:          testLog,
+					record:       event.NewNopRecorder(),
+					conditions:   conditions.ObservedGenerationPropagationManager{},
+					metrics:      &controller.NopMetrics{},
+					audit:        NewNopAuditSink(),
+					countCreated: true,
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"RevisionCreationCountExisting": {
+			reason: "We shouldn't count a revision that already exists.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								p.SetTotalRevisionsCreated(2)
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+								l := o.(*v1.ConfigurationRevisionList)
+								for n, name := range []string{"test-1111111", "test-2222222"} {
+									rev := v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: name, UID: types.UID(name)}}
+									rev.SetRevision(int64(n + 1))
+									rev.SetConditions(v1.RevisionHealthy())
+									l.Items = append(l.Items, rev)
+								}
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								if diff := cmp.Diff(int64(2), o.(*v1.Configuration).GetTotalRevisionsCreated()); diff != "" {
+									t.Errorf("StatusUpdate(...): -want total revisions created, +got total revisions created:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-2222222", nil),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:          testLog,
+					record:       event.NewNopRecorder(),
+					conditions:   conditions.ObservedGenerationPropagationManager{},
+					metrics:      &controller.NopMetrics{},
+					audit:        NewNopAuditSink(),
+					countCreated: true,
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"ProvenanceMissing": {
+			reason: "We shouldn't create a revision of a package whose image lacks a required provenance attestation.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								p, ok := o.(*v1.Configuration)
+								if !ok {
+									return nil
+								}
+								if diff := cmp.Diff(v1.ProvenanceMissing(errors.Errorf(errFmtProvenanceMissing, "test-1234567").Error()), p.GetCondition(v1.TypeInstalled), test.EquateConditions()); diff != "" {
+									t.Errorf("StatusUpdate(...): -want installed condition, +got installed condition:\n%s", diff)
+								}
+								if diff := cmp.Diff("", p.GetCurrentRevision()); diff != "" {
+									t.Errorf("StatusUpdate(...): -want current revision, +got current revision:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, o client.Object, _ ...resource.ApplyOption) error {
+							t.Errorf("Apply(...): we shouldn't create revision %q", o.GetName())
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision:  NewMockRevisionFn("test-1234567", nil),
+						MockImageInfo: func() *ImageInfo { return &ImageInfo{Digest: imageDigest} },
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:        testLog,
+					record:     event.NewNopRecorder(),
+					conditions: conditions.ObservedGenerationPropagationManager{},
+					metrics:    &controller.NopMetrics{},
+					audit:      NewNopAuditSink(),
+					provenance: &MockProvenanceVerifier{MockVerifyProvenance: func(d string) (bool, error) {
+						return false, nil
+					}},
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"ErrVerifyProvenance": {
+			reason: "We should return an error if we can't determine whether a package's image has a provenance attestation.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								p, ok := o.(*v1.Configuration)
+								if !ok {
+									return nil
+								}
+								if diff := cmp.Diff(commonv1.Condition{Type: v1.TypeInstalled, Status: corev1.ConditionUnknown}, p.GetCondition(v1.TypeInstalled), test.EquateConditions()); diff != "" {
+									t.Errorf("StatusUpdate(...): -want installed condition, +got installed condition:\n%s", diff)
+								}
+								if diff := cmp.Diff("", p.GetCurrentRevision()); diff != "" {
+									t.Errorf("StatusUpdate(...): -want current revision, +got current revision:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, o client.Object, _ ...resource.ApplyOption) error {
+							t.Errorf("Apply(...): we shouldn't create revision %q", o.GetName())
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision:  NewMockRevisionFn("test-1234567", nil),
+						MockImageInfo: func() *ImageInfo { return &ImageInfo{Digest: imageDigest} },
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:        testLog,
+					record:     event.NewNopRecorder(),
+					conditions: conditions.ObservedGenerationPropagationManager{},
+					metrics:    &controller.NopMetrics{},
+					audit:      NewNopAuditSink(),
+					provenance: &MockProvenanceVerifier{MockVerifyProvenance: func(d string) (bool, error) {
+						return false, errBoom
+					}},
+				},
+			},
+			want: want{
+				err: errors.Wrapf(errBoom, errFmtVerifyProvenance, "test-1234567"),
+			},
+		},
+		"ProvenanceVerified": {
+			reason: "We should create a revision of a package whose image has a required provenance attestation.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								p, ok := o.(*v1.Configuration)
+								if !ok {
+									return nil
+								}
+								if diff := cmp.Diff(v1.Active(), p.GetCondition(v1.TypeInstalled), test.EquateConditions()); diff != "" {
+									t.Errorf("StatusUpdate(...): -want installed condition, +got installed condition:\n%s", diff)
+								}
+								if diff := cmp.Diff("test-1234567", p.GetCurrentRevision()); diff != "" {
+									t.Errorf("StatusUpdate(...): -want current revision, +got current revision:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision:  NewMockRevisionFn("test-1234567", nil),
+						MockImageInfo: func() *ImageInfo { return &ImageInfo{Digest: imageDigest} },
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:        testLog,
+					record:     event.NewNopRecorder(),
+					conditions: conditions.ObservedGenerationPropagationManager{},
+					metrics:    &controller.NopMetrics{},
+					audit:      NewNopAuditSink(),
+					provenance: &MockProvenanceVerifier{MockVerifyProvenance: func(d string) (bool, error) {
+						if d != imageDigest {
+							t.Errorf("VerifyProvenance(...): want the digest resolved by the revisioner, got %q", d)
+						}
+						return true, nil
+					}},
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"ProvenancePinnedDigest": {
+			reason: "We should verify the provenance of a package whose source is pinned to a digest, even if the Revisioner didn't report the digest.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								p.SetSource("xpkg.crossplane.io/crossplane/configuration-test@" + imageDigest)
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								p, ok := o.(*v1.Configuration)
+								if !ok {
+									return nil
+								}
+								if diff := cmp.Diff(v1.Active(), p.GetCondition(v1.TypeInstalled), test.EquateConditions()); diff != "" {
+									t.Errorf("StatusUpdate(...): -want installed condition, +got installed condition:\n%s", diff)
+								}
+								if diff := cmp.Diff("test-1234567", p.GetCurrentRevision()); diff != "" {
+									t.Errorf("StatusUpdate(...): -want current revision, +got current revision:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:        testLog,
+					record:     event.NewNopRecorder(),
+					conditions: conditions.ObservedGenerationPropagationManager{},
+					metrics:    &controller.NopMetrics{},
+					audit:      NewNopAuditSink(),
+					provenance: &MockProvenanceVerifier{MockVerifyProvenance: func(d string) (bool, error) {
+						if d != imageDigest {
+							t.Errorf("VerifyProvenance(...): want the digest the source is pinned to, got %q", d)
+						}
+						return true, nil
+					}},
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"DependenciesSettled": {
+			reason: "We should activate a revision whose dependencies aren't garbage collecting, and deactivate the previously active revision.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+								old := v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: "test-old"}}
+								old.SetRevision(1)
+								old.SetDesiredState(v1.PackageRevisionActive)
+								*o.(*v1.ConfigurationRevisionList) = v1.ConfigurationRevisionList{Items: []v1.ConfigurationRevision{old}}
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								p, ok := o.(*v1.Configuration)
+								if !ok {
+									return nil
+								}
+								if diff := cmp.Diff(v1.Active(), p.GetCondition(v1.TypeInstalled), test.EquateConditions()); diff != "" {
+									t.Errorf("StatusUpdate(...): -want installed condition, +got installed condition:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, o client.Object, _ ...resource.ApplyOption) error {
+							want := map[string]v1.PackageRevisionDesiredState{
+								"test-old":     v1.PackageRevisionInactive,
+								"test-1234567": v1.PackageRevisionActive,
+							}
+							if diff := cmp.Diff(want[o.GetName()], o.(*v1.ConfigurationRevision).GetDesiredState()); diff != "" {
+								t.Errorf("Apply(...): -want revision %q desired state, +got desired state:\n%s", o.GetName(), diff)
+							}
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:        testLog,
+					record:     event.NewNopRecorder(),
+					conditions: conditions.ObservedGenerationPropagationManager{},
+					metrics:    &controller.NopMetrics{},
+					audit:      NewNopAuditSink(),
+					gcDependencies: GarbageCollectingDependencyListerFn(func(_ context.Context, _ v1.PackageRevision) ([]string, error) {
+						return nil, nil
+					}),
+					clock: testingclock.NewFakePassiveClock(now),
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"DependencyGarbageCollecting": {
+			reason: "We should defer activating a revision, and keep the previously active revision active, while one of its dependencies is garbage collecting, and check again shortly.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+								old := v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: "test-old"}}
+								old.SetRevision(1)
+								old.SetDesiredState(v1.PackageRevisionActive)
+								*o.(*v1.ConfigurationRevisionList) = v1.ConfigurationRevisionList{Items: []v1.ConfigurationRevision{old}}
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								p, ok := o.(*v1.Configuration)
+								if !ok {
+									return nil
+								}
+								if diff := cmp.Diff(v1.DependencyGarbageCollecting("xpkg.crossplane.io/crossplane/provider-test"), p.GetCondition(v1.TypeInstalled), test.EquateConditions()); diff != "" {
+									t.Errorf("StatusUpdate(...): -want installed condition, +got installed condition:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, o client.Object, _ ...resource.ApplyOption) error {
+							want := map[string]v1.PackageRevisionDesiredState{
+								"test-1234567": v1.PackageRevisionInactive,
+							}
+							if diff := cmp.Diff(want[o.GetName()], o.(*v1.ConfigurationRevision).GetDesiredState()); diff != "" {
+								t.Errorf("Apply(...): -want revision %q desired state, +got desired state:\n%s", o.GetName(), diff)
+							}
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:        testLog,
+					record:     event.NewNopRecorder(),
+					conditions: conditions.ObservedGenerationPropagationManager{},
+					metrics:    &controller.NopMetrics{},
+					audit:      NewNopAuditSink(),
+					gcDependencies: GarbageCollectingDependencyListerFn(func(_ context.Context, _ v1.PackageRevision) ([]string, error) {
+						return []string{"xpkg.crossplane.io/crossplane/provider-test"}, nil
+					}),
+					clock: testingclock.NewFakePassiveClock(now),
+				},
+			},
+			want: want{
+				r: reconcile.Result{RequeueAfter: dependencyGCRecheckInterval},
+			},
+		},
+		"ErrListGCDependencies": {
+			reason: "We should return an error if we can't tell whether a revision's dependencies are garbage collecting.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+								old := v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: "test-old"}}
+								old.SetRevision(1)
+								old.SetDesiredState(v1.PackageRevisionActive)
+								*o.(*v1.ConfigurationRevisionList) = v1.ConfigurationRevisionList{Items: []v1.ConfigurationRevision{old}}
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, o client.Object, _ ...resource.ApplyOption) error {
+							want := map[string]v1.PackageRevisionDesiredState{}
+							if diff := cmp.Diff(want[o.GetName()], o.(*v1.ConfigurationRevision).GetDesiredState()); diff != "" {
+								t.Errorf("Apply(...): -want revision %q desired state, +got desired state:\n%s", o.GetName(), diff)
+							}
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:        testLog,
+					record:     event.NewNopRecorder(),
+					conditions: conditions.ObservedGenerationPropagationManager{},
+					metrics:    &controller.NopMetrics{},
+					audit:      NewNopAuditSink(),
+					gcDependencies: GarbageCollectingDependencyListerFn(func(_ context.Context, _ v1.PackageRevision) ([]string, error) {
+						return nil, errBoom
+					}),
+					clock: testingclock.NewFakePassiveClock(now),
+				},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errListGCDependencies),
+			},
+		},
+		"MaxInformationalConditionsCapped": {
+			reason: "We should drop the informational conditions that transitioned least recently, but keep the core conditions.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								p.SetConditions(informational...)
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								p, ok := o.(*v1.Configuration)
+								if !ok {
+									return nil
+								}
+								types := make([]commonv1.ConditionType, 0, len(p.GetConditions()))
+								for _, c := range p.GetConditions() {
+									types = append(types, c.Type)
+								}
+								if diff := cmp.Diff([]commonv1.ConditionType{v1.TypeHealthy, "Informational4", "Informational3", v1.TypeInstalled}, types); diff != "" {
+									t.Errorf("StatusUpdate(...): -want condition types, +got condition types:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:                        testLog,
+					record:                     event.NewNopRecorder(),
+					conditions:                 conditions.ObservedGenerationPropagationManager{},
+					metrics:                    &controller.NopMetrics{},
+					audit:                      NewNopAuditSink(),
+					maxInformationalConditions: 2,
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"MaxInformationalConditionsUncapped": {
+			reason: "We shouldn't drop informational conditions if no maximum is configured.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								p.SetConditions(informational...)
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								p, ok := o.(*v1.Configuration)
+								if !ok {
+									return nil
+								}
+								types := make([]commonv1.ConditionType, 0, len(p.GetConditions()))
+								for _, c := range p.GetConditions() {
+									types = append(types, c.Type)
+								}
+								if diff := cmp.Diff([]commonv1.ConditionType{"Informational0", "Informational1", "Informational2", "Informational3", "Informational4", v1.TypeHealthy, v1.TypeInstalled}, types); diff != "" {
+									t.Errorf("StatusUpdate(...): -want condition types, +got condition types:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:        testLog,
+					record:     event.NewNopRecorder(),
+					conditions: conditions.ObservedGenerationPropagationManager{},
+					metrics:    &controller.NopMetrics{},
+					audit:      NewNopAuditSink(),
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"PackageTypeMismatch": {
+			reason: "We shouldn't create a revision of a Configuration whose image contains a Provider.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								p, ok := o.(*v1.Configuration)
+								if !ok {
+									return nil
+								}
+								if diff := cmp.Diff("", p.GetCurrentRevision()); diff != "" {
+									t.Errorf("StatusUpdate(...): -want current revision, +got current revision:\n%s", diff)
+								}
+								if diff := cmp.Diff(v1.PackageTypeMismatch(v1.ConfigurationKind, v1.ProviderKind), p.GetCondition(v1.TypeInstalled), test.EquateConditions()); diff != "" {
+									t.Errorf("StatusUpdate(...): -want installed condition, +got installed condition:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, o client.Object, _ ...resource.ApplyOption) error {
+							t.Errorf("Apply(...): we shouldn't create revision %q", o.GetName())
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision:  NewMockRevisionFn("test-1234567", nil),
+						MockImageInfo: func() *ImageInfo { return &ImageInfo{Contents: providerMeta} },
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:        testLog,
+					record:     event.NewNopRecorder(),
+					conditions: conditions.ObservedGenerationPropagationManager{},
+					metrics:    &controller.NopMetrics{},
+					audit:      NewNopAuditSink(),
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"PackageTypeMatch": {
+			reason: "We should create a revision of a Configuration whose image contains a Configuration.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								p, ok := o.(*v1.Configuration)
+								if !ok {
+									return nil
+								}
+								if diff := cmp.Diff("test-1234567", p.GetCurrentRevision()); diff != "" {
+									t.Errorf("StatusUpdate(...): -want current revision, +got current revision:\n%s", diff)
+								}
+								if diff := cmp.Diff(v1.Active(), p.GetCondition(v1.TypeInstalled), test.EquateConditions()); diff != "" {
+									t.Errorf("StatusUpdate(...): -want installed condition, +got installed condition:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision:  NewMockRevisionFn("test-1234567", nil),
+						MockImageInfo: func() *ImageInfo { return &ImageInfo{Contents: configurationMeta} },
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:        testLog,
+					record:     event.NewNopRecorder(),
+					conditions: conditions.ObservedGenerationPropagationManager{},
+					metrics:    &controller.NopMetrics{},
+					audit:      NewNopAuditSink(),
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"PackageTypeUnparsed": {
+			reason: "We should create a revision of a package whose contents the Revisioner didn't parse, since we can't tell what type it declares.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								p, ok := o.(*v1.Configuration)
+								if !ok {
+									return nil
+								}
+								if diff := cmp.Diff("test-1234567", p.GetCurrentRevision()); diff != "" {
+									t.Errorf("StatusUpdate(...): -want current revision, +got current revision:\n%s", diff)
+								}
+								if diff := cmp.Diff(v1.Active(), p.GetCondition(v1.TypeInstalled), test.EquateConditions()); diff != "" {
+									t.Errorf("StatusUpdate(...): -want installed condition, +got installed condition:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision:  NewMockRevisionFn("test-1234567", nil),
+						MockImageInfo: func() *ImageInfo { return &ImageInfo{Contents: nil} },
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:        testLog,
+					record:     event.NewNopRecorder(),
+					conditions: conditions.ObservedGenerationPropagationManager{},
+					metrics:    &controller.NopMetrics{},
+					audit:      NewNopAuditSink(),
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"ActivationNotified": {
+			reason: "We should notify the webhook which revision a package activated, and which revision it replaced.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+								l := o.(*v1.ConfigurationRevisionList)
+								prev := v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: "test-previous"}}
+								prev.SetDesiredState(v1.PackageRevisionActive)
+								prev.SetRevision(1)
+								l.Items = []v1.ConfigurationRevision{prev}
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								p, ok := o.(*v1.Configuration)
+								if !ok {
+									return nil
+								}
+								if diff := cmp.Diff(v1.Active(), p.GetCondition(v1.TypeInstalled), test.EquateConditions()); diff != "" {
+									t.Errorf("StatusUpdate(...): -want installed condition, +got installed condition:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision:  NewMockRevisionFn("test-1234567", nil),
+						MockImageInfo: func() *ImageInfo { return &ImageInfo{Digest: imageDigest} },
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:        testLog,
+					record:     event.NewNopRecorder(),
+					conditions: conditions.ObservedGenerationPropagationManager{},
+					metrics:    &controller.NopMetrics{},
+					audit:      NewNopAuditSink(),
+					notifier: ActivationNotifierFn(func(_ context.Context, n ActivationNotification) error {
+						want := ActivationNotification{
+							Package:          commonv1.TypedReference{APIVersion: v1.ConfigurationGroupVersionKind.GroupVersion().String(), Kind: v1.ConfigurationKind, Name: "test"},
+							PreviousRevision: "test-previous",
+							Revision:         "test-1234567",
+							Digest:           imageDigest,
+						}
+						if diff := cmp.Diff(want, n); diff != "" {
+							t.Errorf("Notify(...): -want notification, +got notification:\n%s", diff)
+						}
+						return nil
+					}),
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"ActivationNotificationFails": {
+			reason: "We should still activate the revision if we can't notify the webhook.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+								l := o.(*v1.ConfigurationRevisionList)
+								prev := v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: "test-previous"}}
+								prev.SetDesiredState(v1.PackageRevisionActive)
+								prev.SetRevision(1)
+								l.Items = []v1.ConfigurationRevision{prev}
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								p, ok := o.(*v1.Configuration)
+								if !ok {
+									return nil
+								}
+								if diff := cmp.Diff(v1.Active(), p.GetCondition(v1.TypeInstalled), test.EquateConditions()); diff != "" {
+									t.Errorf("StatusUpdate(...): -want installed condition, +got installed condition:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision:  NewMockRevisionFn("test-1234567", nil),
+						MockImageInfo: func() *ImageInfo { return &ImageInfo{Digest: imageDigest} },
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:        testLog,
+					record:     event.NewNopRecorder(),
+					conditions: conditions.ObservedGenerationPropagationManager{},
+					metrics:    &controller.NopMetrics{},
+					audit:      NewNopAuditSink(),
+					notifier: ActivationNotifierFn(func(_ context.Context, n ActivationNotification) error {
+						want := ActivationNotification{
+							Package:          commonv1.TypedReference{APIVersion: v1.ConfigurationGroupVersionKind.GroupVersion().String(), Kind: v1.ConfigurationKind, Name: "test"},
+							PreviousRevision: "test-previous",
+							Revision:         "test-1234567",
+							Digest:           imageDigest,
+						}
+						if diff := cmp.Diff(want, n); diff != "" {
+							t.Errorf("Notify(...): -want notification, +got notification:\n%s", diff)
+						}
+						return errBoom
+					}),
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"GlobalFreeze": {
+			reason: "We shouldn't activate a revision while the freeze is enabled, but should still report its health.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								p.SetCurrentRevision("test-1234567")
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+								l := o.(*v1.ConfigurationRevisionList)
+								rev := v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: "test-1234567"}}
+								rev.SetConditions(v1.RevisionHealthy())
+								rev.SetDesiredState(v1.PackageRevisionInactive)
+								rev.SetRevision(1)
+								l.Items = []v1.ConfigurationRevision{rev}
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								p, ok := o.(*v1.Configuration)
+								if !ok {
+									return nil
+								}
+								if diff := cmp.Diff(v1.Healthy(), p.GetCondition(v1.TypeHealthy), test.EquateConditions()); diff != "" {
+									t.Errorf("StatusUpdate(...): -want healthy condition, +got healthy condition:\n%s", diff)
+								}
+								if diff := cmp.Diff(commonv1.Condition{Type: v1.TypeInstalled, Status: corev1.ConditionUnknown}, p.GetCondition(v1.TypeInstalled), test.EquateConditions()); diff != "" {
+									t.Errorf("StatusUpdate(...): -want installed condition, +got installed condition:\n%s", diff)
+								}
+								if diff := cmp.Diff(v1.GlobalFreeze("Incident in progress"), p.GetCondition(v1.TypeGlobalFreeze), test.EquateConditions()); diff != "" {
+									t.Errorf("StatusUpdate(...): -want GlobalFreeze condition, +got GlobalFreeze condition:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, o client.Object, _ ...resource.ApplyOption) error {
+							t.Errorf("Apply(...): we shouldn't apply revision %q", o.GetName())
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:        testLog,
+					record:     event.NewNopRecorder(),
+					conditions: conditions.ObservedGenerationPropagationManager{},
+					metrics:    &controller.NopMetrics{},
+					audit:      NewNopAuditSink(),
+					freeze: NewConfigMapFreezeSource(&test.MockClient{
+						MockGet: func(_ context.Context, _ client.ObjectKey, o client.Object) error {
+							o.(*corev1.ConfigMap).Data = map[string]string{FreezeKeyEnabled: "true", FreezeKeyReason: "Incident in progress"}
+							return nil
+						},
+					}, "crossplane-system", "freeze"),
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"GlobalFreezeSkipsLegacyLabelMigration": {
+			reason: "We shouldn't migrate revisions' legacy labels while the freeze is enabled.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetUID("test-uid")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								p.SetCurrentRevision("test-1234567")
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+								l := o.(*v1.ConfigurationRevisionList)
+								rev := v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{
+									Name:            "test-1234567",
+									Labels:          map[string]string{"pkg.example.org/parent": "test"},
+									OwnerReferences: []metav1.OwnerReference{{UID: "test-uid", Controller: ptr.To(true)}},
+								}}
+								rev.SetConditions(v1.RevisionHealthy())
+								rev.SetDesiredState(v1.PackageRevisionActive)
+								rev.SetRevision(1)
+								l.Items = []v1.ConfigurationRevision{rev}
+								return nil
+							}),
+							MockUpdate: test.NewMockUpdateFn(nil, func(o client.Object) error {
+								t.Errorf("Update(...): we shouldn't update revision %q", o.GetName())
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								p, ok := o.(*v1.Configuration)
+								if !ok {
+									return nil
+								}
+								if diff := cmp.Diff(v1.GlobalFreeze("Incident in progress"), p.GetCondition(v1.TypeGlobalFreeze), test.EquateConditions()); diff != "" {
+									t.Errorf("StatusUpdate(...): -want GlobalFreeze condition, +got GlobalFreeze condition:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, o client.Object, _ ...resource.ApplyOption) error {
+							t.Errorf("Apply(...): we shouldn't apply revision %q", o.GetName())
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:                 testLog,
+					record:              event.NewNopRecorder(),
+					conditions:          conditions.ObservedGenerationPropagationManager{},
+					metrics:             &controller.NopMetrics{},
+					audit:               NewNopAuditSink(),
+					legacyRevisionLabel: "pkg.example.org/parent",
+					freeze: FreezeSourceFn(func(_ context.Context) (bool, string, error) {
+						return true, "Incident in progress", nil
+					}),
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"GlobalFreezeLifted": {
+			reason: "We should activate a revision once the freeze is lifted, and report that it was.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								p.SetCurrentRevision("test-1234567")
+								p.SetConditions(v1.GlobalFreeze("Incident in progress"))
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+								l := o.(*v1.ConfigurationRevisionList)
+								rev := v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: "test-1234567"}}
+								rev.SetConditions(v1.RevisionHealthy())
+								rev.SetDesiredState(v1.PackageRevisionInactive)
+								rev.SetRevision(1)
+								l.Items = []v1.ConfigurationRevision{rev}
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								p, ok := o.(*v1.Configuration)
+								if !ok {
+									return nil
+								}
+								if diff := cmp.Diff(v1.Healthy(), p.GetCondition(v1.TypeHealthy), test.EquateConditions()); diff != "" {
+									t.Errorf("StatusUpdate(...): -want healthy condition, +got healthy condition:\n%s", diff)
+								}
+								if diff := cmp.Diff(v1.Active(), p.GetCondition(v1.TypeInstalled), test.EquateConditions()); diff != "" {
+									t.Errorf("StatusUpdate(...): -want installed condition, +got installed condition:\n%s", diff)
+								}
+								if diff := cmp.Diff(v1.GlobalFreezeLifted(), p.GetCondition(v1.TypeGlobalFreeze), test.EquateConditions()); diff != "" {
+									t.Errorf("StatusUpdate(...): -want GlobalFreeze condition, +got GlobalFreeze condition:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, o client.Object, _ ...resource.ApplyOption) error {
+							want := map[string]v1.PackageRevisionDesiredState{
+								"test-1234567": v1.PackageRevisionActive,
+							}
+							if diff := cmp.Diff(want[o.GetName()], o.(*v1.ConfigurationRevision).GetDesiredState()); diff != "" {
+								t.Errorf("Apply(...): -want revision %q desired state, +got desired state:\n%s", o.GetName(), diff)
+							}
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:        testLog,
+					record:     event.NewNopRecorder(),
+					conditions: conditions.ObservedGenerationPropagationManager{},
+					metrics:    &controller.NopMetrics{},
+					audit:      NewNopAuditSink(),
+					freeze: NewConfigMapFreezeSource(&test.MockClient{
+						MockGet: func(_ context.Context, _ client.ObjectKey, o client.Object) error {
+							o.(*corev1.ConfigMap).Data = map[string]string{FreezeKeyEnabled: "false"}
+							return nil
+						},
+					}, "crossplane-system", "freeze"),
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"GlobalFreezeNeverEnabled": {
+			reason: "We shouldn't add a GlobalFreeze condition to a package whose revisions were never frozen.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								p.SetCurrentRevision("test-1234567")
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+								l := o.(*v1.ConfigurationRevisionList)
+								rev := v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: "test-1234567"}}
+								rev.SetConditions(v1.RevisionHealthy())
+								rev.SetDesiredState(v1.PackageRevisionInactive)
+								rev.SetRevision(1)
+								l.Items = []v1.ConfigurationRevision{rev}
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								p, ok := o.(*v1.Configuration)
+								if !ok {
+									return nil
+								}
+								if diff := cmp.Diff(v1.Healthy(), p.GetCondition(v1.TypeHealthy), test.EquateConditions()); diff != "" {
+									t.Errorf("StatusUpdate(...): -want healthy condition, +got healthy condition:\n%s", diff)
+								}
+								if diff := cmp.Diff(v1.Active(), p.GetCondition(v1.TypeInstalled), test.EquateConditions()); diff != "" {
+									t.Errorf("StatusUpdate(...): -want installed condition, +got installed condition:\n%s", diff)
+								}
+								if diff := cmp.Diff(commonv1.Condition{Type: v1.TypeGlobalFreeze, Status: corev1.ConditionUnknown}, p.GetCondition(v1.TypeGlobalFreeze), test.EquateConditions()); diff != "" {
+									t.Errorf("StatusUpdate(...): -want GlobalFreeze condition, +got GlobalFreeze condition:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, o client.Object, _ ...resource.ApplyOption) error {
+							want := map[string]v1.PackageRevisionDesiredState{
+								"test-1234567": v1.PackageRevisionActive,
+							}
+							if diff := cmp.Diff(want[o.GetName()], o.(*v1.ConfigurationRevision).GetDesiredState()); diff != "" {
+								t.Errorf("Apply(...): -want revision %q desired state, +got desired state:\n%s", o.GetName(), diff)
+							}
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:        testLog,
+					record:     event.NewNopRecorder(),
+					conditions: conditions.ObservedGenerationPropagationManager{},
+					metrics:    &controller.NopMetrics{},
+					audit:      NewNopAuditSink(),
+					freeze: NewConfigMapFreezeSource(&test.MockClient{
+						MockGet: func(_ context.Context, _ client.ObjectKey, o client.Object) error {
+							return kerrors.NewNotFound(schema.GroupResource{Resource: "configmaps"}, "freeze")
+						},
+					}, "crossplane-system", "freeze"),
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := tc.args.rec.Reconcile(context.Background(), reconcile.Request{})
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.r, got, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestReconcileStatusUpdateFailed(t *testing.T) {
+	errBoom := errors.New("boom")
+	testLog := logging.NewLogrLogger(zap.New(zap.UseDevMode(true), zap.WriteTo(io.Discard)).WithName("testlog"))
+
+	resolved := 0
+	applied := 0
+	statusErr := errBoom
+	var applyErr error
+
+	r := &Reconciler{
+		newPackage:             func() v1.Package { return &v1.Configuration{} },
+		newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+		newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+		client: resource.ClientApplicator{
+			Client: &test.MockClient{
+				MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+					p := o.(*v1.Configuration)
+					p.SetName("test")
+					p.SetUID("test-uid")
+					p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+					return nil
+				}),
+				MockList: test.NewMockListFn(kerrors.NewNotFound(schema.GroupResource{}, "")),
+				MockStatusUpdate: func(_ context.Context, _ client.Object, _ ...client.SubResourceUpdateOption) error {
+					return statusErr
+				},
+			},
+			Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+				applied++
+				return applyErr
+			}),
+		},
+		pkg: &MockRevisioner{
+			MockRevision: func() (string, error) {
+				resolved++
+				return "test-1234567", nil
+			},
+		},
+		config: &fake.MockConfigStore{
+			MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+			MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+		},
+		log:        testLog,
+		record:     event.NewNopRecorder(),
+		conditions: conditions.ObservedGenerationPropagationManager{},
+		metrics:    &controller.NopMetrics{},
+		audit:      NewNopAuditSink(),
+	}
+
+	// The first reconcile resolves and applies the revision, but fails to
+	// persist the package's status.
+	_, err := r.Reconcile(context.Background(), reconcile.Request{})
+	if diff := cmp.Diff(errors.Wrap(errBoom, errUpdateStatus), err, test.EquateErrors()); diff != "" {
+		t.Errorf("\nr.Reconcile(...): -want error, +got error:\n%s", diff)
+	}
+	if applied != 1 {
+		t.Errorf("\nr.Reconcile(...): want 1 revision applied, got %d", applied)
+	}
+
+	// The second reconcile should persist the package's status without
+	// resolving the revision again.
+	statusErr = nil
+	if _, err := r.Reconcile(context.Background(), reconcile.Request{}); err != nil {
+		t.Errorf("\nr.Reconcile(...): want no error, got %v", err)
+	}
+	if resolved != 1 {
+		t.Errorf("\nr.Reconcile(...): want revision resolved once, got %d", resolved)
+	}
+	if _, ok := r.unpersisted.Load(types.UID("test-uid")); ok {
+		t.Errorf("\nr.Reconcile(...): want unpersisted revision to be forgotten after status update")
+	}
+
+	// The third reconcile resolves the revision again, but fails to persist
+	// the package's status. The fourth reuses the revision, but fails before
+	// it persists the package's status.
+	statusErr = errBoom
+	if _, err := r.Reconcile(context.Background(), reconcile.Request{}); err == nil {
+		t.Errorf("\nr.Reconcile(...): want error, got nil")
+	}
+	applyErr = errBoom
+	if _, err := r.Reconcile(context.Background(), reconcile.Request{}); err == nil {
+		t.Errorf("\nr.Reconcile(...): want error, got nil")
+	}
+	if resolved != 2 {
+		t.Errorf("\nr.Reconcile(...): want revision resolved twice, got %d", resolved)
+	}
+
+	// We should only reuse an unpersisted revision once, so the fifth
+	// reconcile should resolve the revision again.
+	statusErr, applyErr = nil, nil
+	if _, err := r.Reconcile(context.Background(), reconcile.Request{}); err != nil {
+		t.Errorf("\nr.Reconcile(...): want no error, got %v", err)
+	}
+	if resolved != 3 {
+		t.Errorf("\nr.Reconcile(...): want revision resolved three times, got %d", resolved)
+	}
+}
+
+func TestReconcileDeletedDuringReconcile(t *testing.T) {
+	testLog := logging.NewLogrLogger(zap.New(zap.UseDevMode(true), zap.WriteTo(io.Discard)).WithName("testlog"))
+
+	r := &Reconciler{
+		newPackage:             func() v1.Package { return &v1.Configuration{} },
+		newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+		newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+		client: resource.ClientApplicator{
+			Client: &test.MockClient{
+				MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+					p := o.(*v1.Configuration)
+					p.SetName("test")
+					p.SetUID("test-uid")
+					p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+					return nil
+				}),
+				MockList: test.NewMockListFn(kerrors.NewNotFound(schema.GroupResource{}, "")),
+				// The package is deleted after we get it, but before we
+				// update its status.
+				MockStatusUpdate: test.NewMockSubResourceUpdateFn(kerrors.NewNotFound(schema.GroupResource{Group: v1.Group, Resource: "configurations"}, "test")),
+			},
+			Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+				return nil
+			}),
+		},
+		pkg: &MockRevisioner{
+			MockRevision: NewMockRevisionFn("test-1234567", nil),
+		},
+		config: &fake.MockConfigStore{
+			MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+			MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+		},
+		log:        testLog,
+		record:     event.NewNopRecorder(),
+		conditions: conditions.ObservedGenerationPropagationManager{},
+		metrics:    &controller.NopMetrics{},
+		audit:      NewNopAuditSink(),
+	}
+
+	res, err := r.Reconcile(context.Background(), reconcile.Request{})
+	if err != nil {
+		t.Errorf("\nr.Reconcile(...): want no error, got %v", err)
+	}
+	if diff := cmp.Diff(reconcile.Result{}, res); diff != "" {
+		t.Errorf("\nr.Reconcile(...): -want result, +got result:\n%s", diff)
+	}
+	if _, ok := r.unpersisted.Load(types.UID("test-uid")); ok {
+		t.Errorf("\nr.Reconcile(...): want no unpersisted revision to be remembered for a deleted package")
+	}
+}
+
+func TestReconcileConcurrentGC(t *testing.T) {
+	errBoom := errors.New("boom")
+	testLog := logging.NewLogrLogger(zap.New(zap.UseDevMode(true), zap.WriteTo(io.Discard)).WithName("testlog"))
+	revHistory := int64(1)
+
+	deletes := sync.Map{}
+	r := &Reconciler{
+		newPackage:             func() v1.Package { return &v1.Configuration{} },
+		newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+		newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+		client: resource.ClientApplicator{
+			Client: &test.MockClient{
+				MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+					p := o.(*v1.Configuration)
+					p.SetName("test")
+					p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+					p.SetRevisionHistoryLimit(&revHistory)
+					return nil
+				}),
+				MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+					l := o.(*v1.ConfigurationRevisionList)
+					*l = v1.ConfigurationRevisionList{Items: []v1.ConfigurationRevision{
+						{ObjectMeta: metav1.ObjectMeta{Name: "test-1234567"}, Spec: v1.PackageRevisionSpec{Revision: 5, DesiredState: v1.PackageRevisionActive}},
+						{ObjectMeta: metav1.ObjectMeta{Name: "test-old-4"}, Spec: v1.PackageRevisionSpec{Revision: 4, DesiredState: v1.PackageRevisionInactive}},
+						{ObjectMeta: metav1.ObjectMeta{Name: "test-old-3"}, Spec: v1.PackageRevisionSpec{Revision: 3, DesiredState: v1.PackageRevisionInactive}},
+						{ObjectMeta: metav1.ObjectMeta{Name: "test-old-2"}, Spec: v1.PackageRevisionSpec{Revision: 2, DesiredState: v1.PackageRevisionInactive}},
+						{ObjectMeta: metav1.ObjectMeta{Name: "test-old-1"}, Spec: v1.PackageRevisionSpec{Revision: 1, DesiredState: v1.PackageRevisionInactive}},
+					}}
+					return nil
+				}),
+				MockDelete: func(_ context.Context, obj client.Object, _ ...client.DeleteOption) error {
+					deletes.Store(obj.GetName(), true)
+					if obj.GetName() == "test-old-2" {
+						return errBoom
+					}
+					return nil
+				},
+				MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
+			},
+			Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+				return nil
+			}),
+		},
+		pkg: &MockRevisioner{
+			MockRevision: NewMockRevisionFn("test-1234567", nil),
+		},
+		config: &fake.MockConfigStore{
+			MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+			MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+		},
+		log:        testLog,
+		record:     event.NewNopRecorder(),
+		conditions: conditions.ObservedGenerationPropagationManager{},
+		metrics:    &controller.NopMetrics{},
+		audit:      NewNopAuditSink(),
+		clock:      testingclock.NewFakePassiveClock(time.Now()),
+
+		maxConcurrentDeletes: 2,
+	}
+
+	// The three oldest revisions are outside range. We should try to delete
+	// all of them, even though deleting one of them fails.
+	_, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}})
+	if diff := cmp.Diff(errors.Wrap(errors.Join(errors.Wrapf(errBoom, errFmtDeleteRevision, "test-old-2")), errGCPackageRevision), err, test.EquateErrors()); diff != "" {
+		t.Errorf("\nr.Reconcile(...): -want error, +got error:\n%s", diff)
+	}
+
+	got := []string{}
+	deletes.Range(func(k, _ any) bool {
+		got = append(got, k.(string))
+		return true
+	})
+	slices.Sort(got)
+	want := []string{"test-old-1", "test-old-2", "test-old-3"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("\nr.Reconcile(...): -want deleted revisions, +got deleted revisions:\n%s", diff)
+	}
+}
+
+func TestReconcileFastPath(t *testing.T) {
+	testLog := logging.NewLogrLogger(zap.New(zap.UseDevMode(true), zap.WriteTo(io.Discard)).WithName("testlog"))
+
+	listed := 0
+	revisionVersion := "1"
+
+	r := &Reconciler{
+		newPackage:             func() v1.Package { return &v1.Configuration{} },
+		newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+		newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+		client: resource.ClientApplicator{
+			Client: &test.MockClient{
+				MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+					switch o := o.(type) {
+					case *v1.Configuration:
+						o.SetName("test")
+						o.SetUID("test-uid")
+						o.SetResourceVersion("1")
+						o.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+						o.SetCurrentRevision("test-1234567")
+						o.SetConditions(v1.Healthy(), v1.Active())
+					case *v1.ConfigurationRevision:
+						o.SetName("test-1234567")
+						o.SetResourceVersion(revisionVersion)
+					}
+					return nil
+				}),
+				MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+					listed++
+					l := o.(*v1.ConfigurationRevisionList)
+					cr := v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: "test-1234567", ResourceVersion: revisionVersion}}
+					cr.SetRevision(1)
+					cr.SetConditions(v1.RevisionHealthy())
+					cr.SetDesiredState(v1.PackageRevisionActive)
+					*l = v1.ConfigurationRevisionList{Items: []v1.ConfigurationRevision{cr}}
+					return nil
+				}),
+				MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
+			},
+			Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+				return nil
+			}),
+		},
+		pkg: &MockRevisioner{
+			MockRevision: NewMockRevisionFn("test-1234567", nil),
+		},
+		config: &fake.MockConfigStore{
+			MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+			MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+		},
+		log:        testLog,
+		record:     event.NewNopRecorder(),
+		conditions: conditions.ObservedGenerationPropagationManager{},
+		metrics:    &controller.NopMetrics{},
+		audit:      NewNopAuditSink(),
+	}
+
+	// The first reconcile has nothing to compare against, so it should list
+	// the package's revisions.
+	if _, err := r.Reconcile(context.Background(), reconcile.Request{}); err != nil {
+		t.Errorf("\nr.Reconcile(...): want no error, got %v", err)
+	}
+	if listed != 1 {
+		t.Errorf("\nr.Reconcile(...): want revisions listed once, got %d", listed)
+	}
+
+	// Nothing changed, so the second reconcile should skip listing revisions.
+	if _, err := r.Reconcile(context.Background(), reconcile.Request{}); err != nil {
+		t.Errorf("\nr.Reconcile(...): want no error, got %v", err)
+	}
+	if listed != 1 {
+		t.Errorf("\nr.Reconcile(...): want unchanged package to skip listing revisions, got %d lists", listed)
+	}
+
+	// The revision changed, so the third reconcile should list revisions.
+	revisionVersion = "2"
+	if _, err := r.Reconcile(context.Background(), reconcile.Request{}); err != nil {
+		t.Errorf("\nr.Reconcile(...): want no error, got %v", err)
+	}
+	if listed != 2 {
+		t.Errorf("\nr.Reconcile(...): want changed revision to trigger listing revisions, got %d lists", listed)
+	}
+}
+
+func TestReconcileMetadataOnly(t *testing.T) {
+	testLog := logging.NewLogrLogger(zap.New(zap.UseDevMode(true), zap.WriteTo(io.Discard)).WithName("testlog"))
+
+	listed := 0
+	packageVersion := "1"
+	revisionVersion := "1"
+	labels := map[string]string{"team": "a"}
+	var applied, updated map[string]string
+
+	owner := metav1.OwnerReference{
+		APIVersion:         v1.SchemeGroupVersion.String(),
+		Kind:               v1.ConfigurationKind,
+		Name:               "test",
+		UID:                "test-uid",
+		Controller:         ptr.To(true),
+		BlockOwnerDeletion: ptr.To(true),
+	}
+
+	r := &Reconciler{
+		newPackage:             func() v1.Package { return &v1.Configuration{} },
+		newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+		newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+		client: resource.ClientApplicator{
+			Client: &test.MockClient{
+				MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+					switch o := o.(type) {
+					case *v1.Configuration:
+						o.SetName("test")
+						o.SetUID("test-uid")
+						o.SetResourceVersion(packageVersion)
+						o.SetLabels(labels)
+						o.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+						o.SetCurrentRevision("test-1234567")
+						o.SetConditions(v1.Healthy(), v1.Active())
+					case *v1.ConfigurationRevision:
+						o.SetName("test-1234567")
+						o.SetResourceVersion(revisionVersion)
+						o.SetLabels(map[string]string{v1.LabelParentPackage: "test", "team": "a"})
+						o.SetOwnerReferences([]metav1.OwnerReference{owner})
+					}
+					return nil
+				}),
+				MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+					listed++
+					l := o.(*v1.ConfigurationRevisionList)
+					cr := v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: "test-1234567", ResourceVersion: revisionVersion}}
+					cr.SetOwnerReferences([]metav1.OwnerReference{owner})
+					cr.SetRevision(1)
+					cr.SetConditions(v1.RevisionHealthy())
+					cr.SetDesiredState(v1.PackageRevisionActive)
+					*l = v1.ConfigurationRevisionList{Items: []v1.ConfigurationRevision{cr}}
+					return nil
+				}),
+				MockUpdate: test.NewMockUpdateFn(nil, func(o client.Object) error {
+					updated = o.GetLabels()
+					revisionVersion = "2"
+					o.SetResourceVersion(revisionVersion)
+					return nil
+				}),
+				MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
+			},
+			Applicator: resource.ApplyFn(func(_ context.Context, o client.Object, _ ...resource.ApplyOption) error {
+				applied = o.GetLabels()
+				return nil
+			}),
+		},
+		pkg: &MockRevisioner{
+			MockRevision: NewMockRevisionFn("test-1234567", nil),
+		},
+		config: &fake.MockConfigStore{
+			MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+			MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+		},
+		log:        testLog,
+		record:     event.NewNopRecorder(),
+		conditions: conditions.ObservedGenerationPropagationManager{},
+		metrics:    &controller.NopMetrics{},
+		audit:      NewNopAuditSink(),
+
+		propagateLabels: []string{"team"},
+	}
+
+	// The first reconcile is a full reconcile, which should propagate the
+	// package's labels to its current revision.
+	if _, err := r.Reconcile(context.Background(), reconcile.Request{}); err != nil {
+		t.Errorf("\nr.Reconcile(...): want no error, got %v", err)
+	}
+	if listed != 1 {
+		t.Errorf("\nr.Reconcile(...): want revisions listed once, got %d", listed)
+	}
+	if diff := cmp.Diff(map[string]string{v1.LabelParentPackage: "test", "team": "a"}, applied); diff != "" {
+		t.Errorf("\nr.Reconcile(...): -want applied revision labels, +got applied revision labels:\n%s", diff)
+	}
+
+	// Only a propagated label changed, so the second reconcile should
+	// propagate it to the revision without listing revisions.
+	packageVersion = "2"
+	labels = map[string]string{"team": "b"}
+	if _, err := r.Reconcile(context.Background(), reconcile.Request{}); err != nil {
+		t.Errorf("\nr.Reconcile(...): want no error, got %v", err)
+	}
+	if listed != 1 {
+		t.Errorf("\nr.Reconcile(...): want metadata-only change to skip listing revisions, got %d lists", listed)
+	}
+	if diff := cmp.Diff(map[string]string{v1.LabelParentPackage: "test", "team": "b"}, updated); diff != "" {
+		t.Errorf("\nr.Reconcile(...): -want updated revision labels, +got updated revision labels:\n%s", diff)
+	}
+
+	// A label we don't propagate changed, so the third reconcile should be a
+	// full reconcile.
+	packageVersion = "3"
+	labels = map[string]string{"team": "b", "cost-center": "42"}
+	if _, err := r.Reconcile(context.Background(), reconcile.Request{}); err != nil {
+		t.Errorf("\nr.Reconcile(...): want no error, got %v", err)
+	}
+	if listed != 2 {
+		t.Errorf("\nr.Reconcile(...): want other metadata change to trigger listing revisions, got %d lists", listed)
+	}
+}
+
+// An infoRecorder is a logger that records the messages it logs at info level.
+type infoRecorder struct {
+	infos *[]string
+}
+
+func (l infoRecorder) Info(msg string, _ ...any)          { *l.infos = append(*l.infos, msg) }
+func (l infoRecorder) Debug(_ string, _ ...any)           {}
+func (l infoRecorder) WithValues(_ ...any) logging.Logger { return l }
+
+func TestReconcileDebugTrace(t *testing.T) {
+	cases := map[string]struct {
+		reason      string
+		annotations map[string]string
+		want        bool
+	}{
+		"Annotated": {
+			reason:      "We should trace reconciling a package annotated for debugging.",
+			annotations: map[string]string{v1.AnnotationDebug: "true"},
+			want:        true,
+		},
+		"NotAnnotated": {
+			reason: "We should not trace reconciling a package that isn't annotated for debugging.",
+			want:   false,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var infos []string
+
+			r := &Reconciler{
+				newPackage:             func() v1.Package { return &v1.Configuration{} },
+				newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+				newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+				client: resource.ClientApplicator{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+							p := o.(*v1.Configuration)
+							p.SetName("test")
+							p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+							p.SetAnnotations(tc.annotations)
+							return nil
+						}),
+						MockList:         test.NewMockListFn(kerrors.NewNotFound(schema.GroupResource{}, "")),
+						MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
+					},
+					Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+						return nil
+					}),
+				},
+				pkg: &MockRevisioner{
+					MockRevision: NewMockRevisionFn("test-1234567", nil),
+				},
+				config: &fake.MockConfigStore{
+					MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+					MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+				},
+				log:        infoRecorder{infos: &infos},
+				record:     event.NewNopRecorder(),
+				conditions: conditions.ObservedGenerationPropagationManager{},
+				metrics:    &controller.NopMetrics{},
+				audit:      NewNopAuditSink(),
+			}
+
+			if _, err := r.Reconcile(context.Background(), reconcile.Request{}); err != nil {
+				t.Errorf("\n%s\nr.Reconcile(...): want no error, got %v", tc.reason, err)
+			}
+			if got := len(infos) > 0; got != tc.want {
+				t.Errorf("\n%s\nr.Reconcile(...): want traced %t, got messages %v", tc.reason, tc.want, infos)
+			}
+		})
+	}
+}
+
+func TestReconcileConcurrentUnpack(t *testing.T) {
+	testLog := logging.NewLogrLogger(zap.New(zap.UseDevMode(true), zap.WriteTo(io.Discard)).WithName("testlog"))
+
+	unpacking := make(chan struct{})
+	release := make(chan struct{})
+	unpacks := 0
+
+	r := &Reconciler{
+		newPackage:             func() v1.Package { return &v1.Configuration{} },
+		newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+		newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+		client: resource.ClientApplicator{
+			Client: &test.MockClient{
+				MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+					p := o.(*v1.Configuration)
+					p.SetName("test")
+					p.SetUID("pkg-uid")
+					p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+					return nil
+				}),
+				MockList:         test.NewMockListFn(nil),
+				MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
+			},
+			Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+				return nil
+			}),
+		},
+		pkg: &MockRevisioner{
+			MockRevision: func() (string, error) {
+				// Only the first reconcile should get here. Block it
+				// until the second reconcile is done.
+				unpacks++
+				close(unpacking)
+				<-release
+				return "test-1234567", nil
+			},
+		},
+		config: &fake.MockConfigStore{
+			MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+			MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+		},
+		log:        testLog,
+		record:     event.NewNopRecorder(),
+		conditions: conditions.ObservedGenerationPropagationManager{},
+		metrics:    &controller.NopMetrics{},
+		audit:      NewNopAuditSink(),
+
+		resolutions: semaphore.NewWeighted(1),
+	}
+
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}}
+
+	done := make(chan error)
+	go func() {
+		_, err := r.Reconcile(context.Background(), req)
+		done <- err
+	}()
+	<-unpacking
+
+	// The first reconcile is unpacking the package, so the second should
+	// requeue without unpacking it.
+	got, err := r.Reconcile(context.Background(), req)
+	if err != nil {
+		t.Errorf("\nr.Reconcile(...): want no error, got %v", err)
+	}
+	if diff := cmp.Diff(reconcile.Result{RequeueAfter: resolutionsSaturatedRequeue}, got); diff != "" {
+		t.Errorf("\nr.Reconcile(...): -want result, +got result:\n%s", diff)
+	}
+
+	close(release)
+	if err := <-done; err != nil {
+		t.Errorf("\nr.Reconcile(...): want no error, got %v", err)
+	}
+	if diff := cmp.Diff(1, unpacks); diff != "" {
+		t.Errorf("\nr.Reconcile(...): -want unpacks, +got unpacks:\n%s", diff)
+	}
+
+	// Once the first reconcile is done, we should be able to unpack the
+	// package again.
+	if !r.resolutions.TryAcquire(1) {
+		t.Errorf("\nr.Reconcile(...): resolution should be released after the unpack finished")
+	}
+}
+
+func TestReconcileRetrySBOMAnnotation(t *testing.T) {
+	testLog := logging.NewLogrLogger(zap.New(zap.UseDevMode(true), zap.WriteTo(io.Discard)).WithName("testlog"))
+	errBoom := errors.New("boom")
+	updates := 0
+
+	r := &Reconciler{
+		newPackage:             func() v1.Package { return &v1.Configuration{} },
+		newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+		newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+		client: resource.ClientApplicator{
+			Client: &test.MockClient{
+				MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+					// The package is healthy and unchanged, so we'd skip
+					// reconciling it if we thought it was synced.
+					p, ok := o.(*v1.Configuration)
+					if !ok {
+						return nil
+					}
+					p.SetName("test")
+					p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+					p.SetAnnotations(map[string]string{v1.AnnotationSBOMReferences: "xpkg.example.com/test@sha256:abc"})
+					p.SetCurrentRevision("test-1234567")
+					p.SetConditions(v1.Healthy(), v1.Active())
+					return nil
+				}),
+				MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+					l := o.(*v1.ConfigurationRevisionList)
+					cr := v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: "test-1234567", UID: "existing"}}
+					cr.SetConditions(v1.RevisionHealthy())
+					l.Items = []v1.ConfigurationRevision{cr}
+					return nil
+				}),
+				MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
+				MockUpdate: func(_ context.Context, _ client.Object, _ ...client.UpdateOption) error {
+					// Fail to annotate the package the first time.
+					updates++
+					if updates == 1 {
+						return errBoom
+					}
+					return nil
+				},
+			},
+			Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+				return nil
+			}),
+		},
+		pkg: &MockRevisioner{
+			MockRevision: NewMockRevisionFn("test-1234567", nil),
+		},
+		config: &fake.MockConfigStore{
+			MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+			MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+		},
+		sboms: SBOMListerFn(func(_ context.Context, _ v1.Package, _ ...string) ([]string, error) {
+			return nil, nil
+		}),
+		log:        testLog,
+		record:     event.NewNopRecorder(),
+		conditions: conditions.ObservedGenerationPropagationManager{},
+		metrics:    &controller.NopMetrics{},
+		audit:      NewNopAuditSink(),
+	}
+
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}}
+	if _, err := r.Reconcile(context.Background(), req); !errors.Is(err, errBoom) {
+		t.Fatalf("\nr.Reconcile(...): want error %v, got %v", errBoom, err)
+	}
+
+	// The package didn't change, but we failed to annotate it, so we
+	// shouldn't skip the reconcile that retries.
+	if _, err := r.Reconcile(context.Background(), req); err != nil {
+		t.Errorf("\nr.Reconcile(...): want no error, got %v", err)
+	}
+	if diff := cmp.Diff(2, updates); diff != "" {
+		t.Errorf("\nr.Reconcile(...): -want updates, +got updates:\n%s", diff)
+	}
+}
+
+func TestUnpackPanic(t *testing.T) {
+	p := &v1.Configuration{}
+	p.SetUID("pkg-uid")
+
+	r := &Reconciler{
+		pkg: &MockRevisioner{
+			MockRevision: func() (string, error) {
+				panic("boom")
+			},
+		},
+		resolutions: semaphore.NewWeighted(1),
+	}
+	if !r.resolutions.TryAcquire(1) {
+		t.Fatalf("TryAcquire(1): want resolution acquired")
+	}
+
+	func() {
+		defer func() { _ = recover() }()
+		_, _, _ = r.unpack(context.Background(), p)
+	}()
+
+	if !r.resolutions.TryAcquire(1) {
+		t.Errorf("\nr.unpack(...): resolution should be released after resolving the revision panicked")
+	}
+}
+
+func TestReconcileGCOrder(t *testing.T) {
+	testLog := logging.NewLogrLogger(zap.New(zap.UseDevMode(true), zap.WriteTo(io.Discard)).WithName("testlog"))
+
+	cases := map[string]struct {
+		reason  string
+		order   GCOrder
+		deferGC bool
+		health  commonv1.Condition
+		want    []string
+	}{
+		"ActivateFirst": {
+			reason: "We should garbage collect old revisions after we activate the current revision by default.",
+			order:  GCOrderActivateFirst,
+			health: v1.RevisionHealthy(),
+			want:   []string{"Apply test-1234567", "Delete test-old-1"},
+		},
+		"CollectFirst": {
+			reason: "We should garbage collect old revisions before we activate the current revision if configured to.",
+			order:  GCOrderCollectFirst,
+			health: v1.RevisionHealthy(),
+			want:   []string{"Delete test-old-1", "Apply test-1234567"},
+		},
+		"CollectFirstDeferred": {
+			reason:  "We shouldn't garbage collect old revisions before we activate the current revision if GC is deferred until it's healthy.",
+			order:   GCOrderCollectFirst,
+			deferGC: true,
+			health:  v1.RevisionUnknownHealth(),
+			want:    []string{"Apply test-1234567"},
+		},
+		"CollectFirstDeferredHealthy": {
+			reason:  "We should garbage collect old revisions before we activate the current revision once it's healthy, even if GC is deferred.",
+			order:   GCOrderCollectFirst,
+			deferGC: true,
+			health:  v1.RevisionHealthy(),
+			want:    []string{"Delete test-old-1", "Apply test-1234567"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			revHistory := int64(1)
+			var calls []string
+
+			r := &Reconciler{
+				newPackage:             func() v1.Package { return &v1.Configuration{} },
+				newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+				newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+				client: resource.ClientApplicator{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+							p := o.(*v1.Configuration)
+							p.SetName("test")
+							p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+							p.SetRevisionHistoryLimit(&revHistory)
+							return nil
+						}),
+						MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+							l := o.(*v1.ConfigurationRevisionList)
+							cur := v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: "test-1234567"}}
+							cur.SetRevision(3)
+							cur.SetConditions(tc.health)
+							cur.SetDesiredState(v1.PackageRevisionInactive)
+							old2 := v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: "test-old-2"}}
+							old2.SetRevision(2)
+							old2.SetConditions(v1.RevisionHealthy())
+							old2.SetDesiredState(v1.PackageRevisionInactive)
+							old1 := v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: "test-old-1"}}
+							old1.SetRevision(1)
+							old1.SetConditions(v1.RevisionHealthy())
+							old1.SetDesiredState(v1.PackageRevisionInactive)
+							*l = v1.ConfigurationRevisionList{Items: []v1.ConfigurationRevision{cur, old2, old1}}
+							return nil
+						}),
+						MockDelete: func(_ context.Context, obj client.Object, _ ...client.DeleteOption) error {
+							calls = append(calls, "Delete "+obj.GetName())
+							return nil
+						},
+						MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
+					},
+					Applicator: resource.ApplyFn(func(_ context.Context, o client.Object, _ ...resource.ApplyOption) error {
+						calls = append(calls, "Apply "+o.GetName())
+						return nil
+					}),
+				},
+				pkg: &MockRevisioner{
+					MockRevision: NewMockRevisionFn("test-1234567", nil),
+				},
+				config: &fake.MockConfigStore{
+					MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+					MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+				},
+				log:        testLog,
+				record:     event.NewNopRecorder(),
+				conditions: conditions.ObservedGenerationPropagationManager{},
+				metrics:    &controller.NopMetrics{},
+				audit:      NewNopAuditSink(),
+				clock:      testingclock.NewFakePassiveClock(time.Now()),
+
+				maxConcurrentDeletes: 1,
+				deferGC:              tc.deferGC,
+				gcOrder:              tc.order,
+			}
+
+			if _, err := r.Reconcile(context.Background(), reconcile.Request{}); err != nil {
+				t.Errorf("\n%s\nr.Reconcile(...): want no error, got %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want, calls); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want calls, +got calls:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+type eventRecorder struct {
+	events []event.Event
+}
+
+func (r *eventRecorder) Event(_ runtime.Object, e event.Event) {
+	r.events = append(r.events, e)
+}
+
+func (r *eventRecorder) WithAnnotations(_ ...string) event.Recorder {
+	return r
+}
+
+func TestReconcileSlowReconcile(t *testing.T) {
+	testLog := logging.NewLogrLogger(zap.New(zap.UseDevMode(true), zap.WriteTo(io.Discard)).WithName("testlog"))
+
+	cases := map[string]struct {
+		reason    string
+		threshold float64
+		fetch     time.Duration
+		want      []event.Event
+	}{
+		"Disabled": {
+			reason: "We shouldn't warn about slow reconciles when no threshold is configured.",
+			fetch:  55 * time.Second,
+		},
+		"Fast": {
+			reason:    "We shouldn't warn about a reconcile that takes less than the threshold fraction of its deadline.",
+			threshold: 0.8,
+			fetch:     10 * time.Second,
+		},
+		"Slow": {
+			reason:    "We should warn about a reconcile that takes more than the threshold fraction of its deadline, and name the phase that dominated it.",
+			threshold: 0.8,
+			fetch:     55 * time.Second,
+			want: []event.Event{
+				event.Warning(reasonSlowReconcile, errors.Errorf(errFmtSlowReconcile, 55*time.Second, 80, reconcileTimeout, phaseFetch, 55*time.Second)),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			clk := testingclock.NewFakePassiveClock(time.Now())
+			rec := &eventRecorder{}
+
+			r := &Reconciler{
+				newPackage:             func() v1.Package { return &v1.Configuration{} },
+				newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+				newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+				client: resource.ClientApplicator{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+							p := o.(*v1.Configuration)
+							p.SetName("test")
+							p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+							return nil
+						}),
+						MockList:         test.NewMockListFn(nil),
+						MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
+					},
+					Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+						return nil
+					}),
+				},
+				pkg: &MockRevisioner{
+					MockRevision: func() (string, error) {
+						clk.SetTime(clk.Now().Add(tc.fetch))
+						return "test-1234567", nil
+					},
+				},
+				config: &fake.MockConfigStore{
+					MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+					MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+				},
+				log:        testLog,
+				record:     rec,
+				conditions: conditions.ObservedGenerationPropagationManager{},
+				metrics:    &controller.NopMetrics{},
+				audit:      NewNopAuditSink(),
+
+				clock:                  clk,
+				slowReconcileThreshold: tc.threshold,
+			}
+
+			if _, err := r.Reconcile(context.Background(), reconcile.Request{}); err != nil {
+				t.Errorf("\n%s\nr.Reconcile(...): want no error, got %v", tc.reason, err)
+			}
+
+			var got []event.Event
+			for _, e := range rec.events {
+				if e.Reason == reasonSlowReconcile {
+					got = append(got, e)
+				}
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want slow reconcile events, +got slow reconcile events:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestReconcileStuckUnpacking(t *testing.T) {
+	testLog := logging.NewLogrLogger(zap.New(zap.UseDevMode(true), zap.WriteTo(io.Discard)).WithName("testlog"))
+
+	errBoom := errors.New("boom")
+	notStuck := commonv1.Condition{Type: v1.TypeStuck, Status: corev1.ConditionUnknown}
+
+	// Each step reconciles the same package, after advancing the clock.
+	type step struct {
+		advance time.Duration
+		err     error
+		want    commonv1.Condition
+	}
+
+	cases := map[string]struct {
+		reason    string
+		threshold time.Duration
+		steps     []step
+	}{
+		"Disabled": {
+			reason: "We shouldn't mark a package Stuck when no threshold is configured.",
+			steps: []step{
+				{err: errBoom, want: notStuck},
+				{advance: time.Hour, err: errBoom, want: notStuck},
+			},
+		},
+		"StuckThenUnpacked": {
+			reason:    "We should mark a package Stuck once it has been unpacking for longer than the threshold, and mark it unstuck once it's unpacked.",
+			threshold: 10 * time.Minute,
+			steps: []step{
+				{err: errBoom, want: notStuck},
+				{advance: 5 * time.Minute, err: errBoom, want: notStuck},
+				{advance: 6 * time.Minute, err: errBoom, want: v1.StuckUnpacking(11 * time.Minute)},
+				{advance: time.Minute, want: v1.Unstuck()},
+			},
+		},
+		"Reset": {
+			reason:    "We should start timing a package afresh if it starts unpacking again after being unpacked.",
+			threshold: 10 * time.Minute,
+			steps: []step{
+				{err: errBoom, want: notStuck},
+				{advance: 9 * time.Minute},
+				{advance: 2 * time.Minute, err: errBoom, want: notStuck},
+				{advance: 9 * time.Minute, err: errBoom, want: notStuck},
+				{advance: time.Minute, err: errBoom, want: v1.StuckUnpacking(10 * time.Minute)},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			clk := testingclock.NewFakePassiveClock(time.Now())
+
+			var stored []commonv1.Condition
+			var revErr error
+
+			r := &Reconciler{
+				newPackage:             func() v1.Package { return &v1.Configuration{} },
+				newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+				newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+				client: resource.ClientApplicator{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+							p := o.(*v1.Configuration)
+							p.SetName("test")
+							p.SetUID("pkg-uid")
+							p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+							p.SetConditions(stored...)
+							return nil
+						}),
+						MockList: test.NewMockListFn(nil),
+						MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+							stored = o.(*v1.Configuration).Status.Conditions
+							return nil
+						}),
+					},
+					Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+						return nil
+					}),
+				},
+				pkg: &MockRevisioner{
+					MockRevision: func() (string, error) {
+						return "test-1234567", revErr
+					},
+				},
+				config: &fake.MockConfigStore{
+					MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+					MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+				},
+				log:        testLog,
+				record:     event.NewNopRecorder(),
+				conditions: conditions.ObservedGenerationPropagationManager{},
+				metrics:    &controller.NopMetrics{},
+				audit:      NewNopAuditSink(),
+
+				clock:          clk,
+				stuckThreshold: tc.threshold,
+			}
+
+			for i, s := range tc.steps {
+				clk.SetTime(clk.Now().Add(s.advance))
+				revErr = s.err
+
+				_, _ = r.Reconcile(context.Background(), reconcile.Request{})
+
+				if s.want.Type == "" {
+					continue
+				}
+				got := (&commonv1.ConditionedStatus{Conditions: stored}).GetCondition(v1.TypeStuck)
+				if diff := cmp.Diff(s.want, got, test.EquateConditions()); diff != "" {
+					t.Errorf("\n%s\nstep %d: r.Reconcile(...): -want Stuck condition, +got Stuck condition:\n%s", tc.reason, i, diff)
+				}
+			}
+		})
+	}
+}
+
+func TestReconcileWarmUp(t *testing.T) {
+	testLog := logging.NewLogrLogger(zap.New(zap.UseDevMode(true), zap.WriteTo(io.Discard)).WithName("testlog"))
+
+	started := time.Now()
+	source := "xpkg.example.com/test:v1"
+
+	cases := map[string]struct {
+		reason     string
+		warmUp     time.Duration
+		elapsed    time.Duration
+		healthy    commonv1.Condition
+		identifier string
+		nextPoll   *metav1.Time
+		reconciles int
+		want       int
+	}{
+		"Disabled": {
+			reason:     "We should resolve the package's revision when no warm-up is configured.",
+			healthy:    v1.Healthy(),
+			identifier: source,
+			reconciles: 1,
+			want:       1,
+		},
+		"WarmingUp": {
+			reason:     "We should skip resolving a healthy package's revision the first time we reconcile it while warming up.",
+			warmUp:     time.Minute,
+			healthy:    v1.Healthy(),
+			identifier: source,
+			reconciles: 1,
+			want:       0,
+		},
+		"OnlyFirstReconcile": {
+			reason:     "We should only skip resolving a package's revision the first time we reconcile it while warming up.",
+			warmUp:     time.Minute,
+			healthy:    v1.Healthy(),
+			identifier: source,
+			reconciles: 2,
+			want:       1,
+		},
+		"WarmedUp": {
+			reason:     "We should resolve the package's revision once the warm-up has elapsed.",
+			warmUp:     time.Minute,
+			elapsed:    2 * time.Minute,
+			healthy:    v1.Healthy(),
+			identifier: source,
+			reconciles: 1,
+			want:       1,
+		},
+		"Unhealthy": {
+			reason:     "We should resolve an unhealthy package's revision while warming up.",
+			warmUp:     time.Minute,
+			healthy:    v1.Unhealthy(),
+			identifier: source,
+			reconciles: 1,
+			want:       1,
+		},
+		"SourceChanged": {
+			reason:     "We should resolve the revision of a package whose source changed while warming up.",
+			warmUp:     time.Minute,
+			healthy:    v1.Healthy(),
+			identifier: "xpkg.example.com/test:v0",
+			reconciles: 1,
+			want:       1,
+		},
+		"PollDue": {
+			reason:     "We should resolve the revision of a package that's due to be polled while warming up.",
+			warmUp:     time.Minute,
+			healthy:    v1.Healthy(),
+			identifier: source,
+			nextPoll:   ptr.To(metav1.NewTime(started.Add(-time.Second))),
+			reconciles: 1,
+			want:       1,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			resolved := 0
+
+			r := &Reconciler{
+				newPackage:             func() v1.Package { return &v1.Configuration{} },
+				newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+				newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+				client: resource.ClientApplicator{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+							p := o.(*v1.Configuration)
+							p.SetName("test")
+							p.SetUID("pkg-uid")
+							p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+							p.SetSource(source)
+							p.SetCurrentRevision("test-1234567")
+							p.SetCurrentIdentifier(tc.identifier)
+							p.SetNextPollTime(tc.nextPoll)
+							p.SetConditions(tc.healthy)
+							return nil
+						}),
+						MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+							l := o.(*v1.ConfigurationRevisionList)
+							cur := v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: "test-1234567"}}
+							cur.SetRevision(1)
+							cur.SetConditions(v1.RevisionHealthy())
+							cur.SetDesiredState(v1.PackageRevisionActive)
+							*l = v1.ConfigurationRevisionList{Items: []v1.ConfigurationRevision{cur}}
+							return nil
+						}),
+						MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
+					},
+					Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+						return nil
+					}),
+				},
+				pkg: &MockRevisioner{
+					MockRevision: func() (string, error) {
+						resolved++
+						return "test-1234567", nil
+					},
+				},
+				config: &fake.MockConfigStore{
+					MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+					MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+				},
+				log:        testLog,
+				record:     event.NewNopRecorder(),
+				conditions: conditions.ObservedGenerationPropagationManager{},
+				metrics:    &controller.NopMetrics{},
+				audit:      NewNopAuditSink(),
+
+				clock:   testingclock.NewFakePassiveClock(started.Add(tc.elapsed)),
+				started: started,
+				warmUp:  tc.warmUp,
+			}
+
+			for range tc.reconciles {
+				if _, err := r.Reconcile(context.Background(), reconcile.Request{}); err != nil {
+					t.Errorf("\n%s\nr.Reconcile(...): want no error, got %v", tc.reason, err)
+				}
+			}
+			if diff := cmp.Diff(tc.want, resolved); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want revision resolutions, +got revision resolutions:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestReconcilePullPolicyChange(t *testing.T) {
+	testLog := logging.NewLogrLogger(zap.New(zap.UseDevMode(true), zap.WriteTo(io.Discard)).WithName("testlog"))
+
+	now := time.Now()
+
+	type want struct {
+		requeue  time.Duration
+		resolved int
+	}
+
+	cases := map[string]struct {
+		reason     string
+		pullPolicy corev1.PullPolicy
+		nextPoll   *metav1.Time
+		warmUp     time.Duration
+		want       want
+	}{
+		"SwitchedToAlways": {
+			reason:     "We should poll a package that switched to pull policy Always after pullWait, even if it had a later reconcile scheduled.",
+			pullPolicy: corev1.PullAlways,
+			nextPoll:   ptr.To(metav1.NewTime(now.Add(time.Hour))),
+			want: want{
+				requeue:  pullWait,
+				resolved: 1,
+			},
+		},
+		"SwitchedToAlwaysWhileWarmingUp": {
+			reason:     "We should resolve the revision of a package that switched to pull policy Always while warming up, and poll it after pullWait.",
+			pullPolicy: corev1.PullAlways,
+			warmUp:     time.Hour,
+			want: want{
+				requeue:  pullWait,
+				resolved: 1,
+			},
+		},
+		"SwitchedToIfNotPresent": {
+			reason:     "We should stop polling a package that switched to pull policy IfNotPresent.",
+			pullPolicy: corev1.PullIfNotPresent,
+			nextPoll:   ptr.To(metav1.NewTime(now.Add(30 * time.Second))),
+			want: want{
+				resolved: 1,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			resolved := 0
+
+			r := &Reconciler{
+				newPackage:             func() v1.Package { return &v1.Configuration{} },
+				newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+				newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+				client: resource.ClientApplicator{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+							p := o.(*v1.Configuration)
+							p.SetName("test")
+							p.SetUID("pkg-uid")
+							p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+							p.SetSource("xpkg.example.com/test:v1")
+							p.SetCurrentRevision("test-1234567")
+							p.SetCurrentIdentifier("xpkg.example.com/test:v1")
+							p.SetNextPollTime(tc.nextPoll)
+
+							// The pull policy changed since we last
+							// reconciled the package.
+							p.SetGeneration(2)
+							p.SetPackagePullPolicy(ptr.To(tc.pullPolicy))
+							healthy := v1.Healthy()
+							healthy.ObservedGeneration = 1
+							p.SetConditions(healthy)
+							return nil
+						}),
+						MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+							l := o.(*v1.ConfigurationRevisionList)
+							cur := v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: "test-1234567"}}
+							cur.SetRevision(1)
+							cur.SetConditions(v1.RevisionHealthy())
+							cur.SetDesiredState(v1.PackageRevisionActive)
+							*l = v1.ConfigurationRevisionList{Items: []v1.ConfigurationRevision{cur}}
+							return nil
+						}),
+						MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
+					},
+					Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+						return nil
+					}),
+				},
+				pkg: &MockRevisioner{
+					MockRevision: func() (string, error) {
+						resolved++
+						return "test-1234567", nil
+					},
+				},
+				config: &fake.MockConfigStore{
+					MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+					MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+				},
+				log:        testLog,
+				record:     event.NewNopRecorder(),
+				conditions: conditions.ObservedGenerationPropagationManager{},
+				metrics:    &controller.NopMetrics{},
+				audit:      NewNopAuditSink(),
+
+				clock:   testingclock.NewFakePassiveClock(now),
+				started: now,
+				warmUp:  tc.warmUp,
+			}
+
+			res, err := r.Reconcile(context.Background(), reconcile.Request{})
+			if err != nil {
+				t.Fatalf("\n%s\nr.Reconcile(...): want no error, got %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want.requeue, res.RequeueAfter); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want requeue after, +got requeue after:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.resolved, resolved); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want revision resolutions, +got revision resolutions:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestReconcileUpgradeCheck(t *testing.T) {
+	testLog := logging.NewLogrLogger(zap.New(zap.UseDevMode(true), zap.WriteTo(io.Discard)).WithName("testlog"))
+
+	now := time.Now()
+	none := commonv1.Condition{Type: v1.TypeUpgradeAvailable, Status: corev1.ConditionUnknown}
+	digest := "sha256:ecc25c121431dfc7058754427f97c034ecde26d4aafa0da16d4fc1c6d1c2d07a"
+
+	type want struct {
+		upgrade  commonv1.Condition
+		requeue  time.Duration
+		resolved int
+	}
+
+	cases := map[string]struct {
+		reason      string
+		interval    time.Duration
+		pullPolicy  corev1.PullPolicy
+		source      string
+		lastChecked *time.Time
+		upgrade     commonv1.Condition
+		latest      string
+		want        want
+	}{
+		"Disabled": {
+			reason:     "We shouldn't check for an upgrade when no upgrade check interval is configured.",
+			pullPolicy: corev1.PullIfNotPresent,
+			source:     "xpkg.example.com/test:v1",
+			latest:     "test-7654321",
+			want: want{
+				upgrade:  none,
+				resolved: 1,
+			},
+		},
+		"UpgradeAvailable": {
+			reason:     "We should report an upgrade is available when a package's source resolves to a different revision.",
+			interval:   time.Hour,
+			pullPolicy: corev1.PullIfNotPresent,
+			source:     "xpkg.example.com/test:v1",
+			latest:     "test-7654321",
+			want: want{
+				upgrade:  v1.UpgradeAvailable("test-7654321", digest),
+				requeue:  time.Hour,
+				resolved: 2,
+			},
+		},
+		"UpToDate": {
+			reason:     "We should report a package that had an upgrade available is up to date when its source resolves to its current revision.",
+			interval:   time.Hour,
+			pullPolicy: corev1.PullIfNotPresent,
+			source:     "xpkg.example.com/test:v1",
+			upgrade:    v1.UpgradeAvailable("test-7654321", digest),
+			latest:     "test-1234567",
+			want: want{
+				upgrade:  v1.UpToDate(),
+				requeue:  time.Hour,
+				resolved: 2,
+			},
+		},
+		"RecentlyChecked": {
+			reason:      "We shouldn't check for an upgrade more than once per upgrade check interval.",
+			interval:    time.Hour,
+			pullPolicy:  corev1.PullIfNotPresent,
+			source:      "xpkg.example.com/test:v1",
+			lastChecked: ptr.To(now.Add(-15 * time.Minute)),
+			latest:      "test-7654321",
+			want: want{
+				upgrade:  none,
+				requeue:  45 * time.Minute,
+				resolved: 1,
+			},
+		},
+		"PullAlways": {
+			reason:     "We shouldn't check for an upgrade when a package is polled for new content.",
+			interval:   time.Hour,
+			pullPolicy: corev1.PullAlways,
+			source:     "xpkg.example.com/test:v1",
+			latest:     "test-7654321",
+			want: want{
+				upgrade:  none,
+				requeue:  pullWait,
+				resolved: 1,
+			},
+		},
+		"PinnedToDigest": {
+			reason:     "We shouldn't check for an upgrade when a package is pinned to a digest.",
+			interval:   time.Hour,
+			pullPolicy: corev1.PullIfNotPresent,
+			source:     "xpkg.example.com/test@" + digest,
+			latest:     "test-7654321",
+			want: want{
+				upgrade:  none,
+				resolved: 1,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var got *v1.Configuration
+			resolved := 0
+
+			r := &Reconciler{
+				newPackage:             func() v1.Package { return &v1.Configuration{} },
+				newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+				newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+				client: resource.ClientApplicator{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+							p := o.(*v1.Configuration)
+							p.SetName("test")
+							p.SetUID("pkg-uid")
+							p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+							p.SetSource(tc.source)
+							p.SetPackagePullPolicy(ptr.To(tc.pullPolicy))
+							p.SetCurrentRevision("test-1234567")
+							p.SetCurrentIdentifier(tc.source)
+							if tc.upgrade.Type != "" {
+								p.SetConditions(tc.upgrade)
+							}
+							return nil
+						}),
+						MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+							l := o.(*v1.ConfigurationRevisionList)
+							cur := v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: "test-1234567"}}
+							cur.SetRevision(1)
+							cur.SetConditions(v1.RevisionHealthy())
+							cur.SetDesiredState(v1.PackageRevisionActive)
+							*l = v1.ConfigurationRevisionList{Items: []v1.ConfigurationRevision{cur}}
+							return nil
+						}),
+						MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+							if p, ok := o.(*v1.Configuration); ok {
+								got = p
+							}
+							return nil
+						}),
+					},
+					Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+						return nil
+					}),
+				},
+				pkg: &MockRevisioner{
+					// The first resolution determines the current
+					// revision. Any second resolution is the upgrade
+					// check.
+					MockRevision: func() (string, error) {
+						resolved++
+						if resolved > 1 {
+							return tc.latest, nil
+						}
+						return "test-1234567", nil
+					},
+					MockImageInfo: func() *ImageInfo {
+						return &ImageInfo{Digest: digest}
+					},
+				},
+				config: &fake.MockConfigStore{
+					MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+					MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+				},
+				log:        testLog,
+				record:     event.NewNopRecorder(),
+				conditions: conditions.ObservedGenerationPropagationManager{},
+				metrics:    &controller.NopMetrics{},
+				audit:      NewNopAuditSink(),
+
+				clock:                testingclock.NewFakePassiveClock(now),
+				upgradeCheckInterval: tc.interval,
+			}
+			if tc.lastChecked != nil {
+				r.upgradeChecked.Store(types.UID("pkg-uid"), *tc.lastChecked)
+			}
+
+			res, err := r.Reconcile(context.Background(), reconcile.Request{})
+			if err != nil {
+				t.Fatalf("\n%s\nr.Reconcile(...): want no error, got %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want.resolved, resolved); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want revision resolutions, +got revision resolutions:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.upgrade, got.GetCondition(v1.TypeUpgradeAvailable), test.EquateConditions()); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want upgrade available condition, +got upgrade available condition:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff("test-1234567", got.GetCurrentRevision()); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want current revision, +got current revision:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.requeue, res.RequeueAfter); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want requeue after, +got requeue after:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestReconcileMultipleActiveRevisions(t *testing.T) {
+	testLog := logging.NewLogrLogger(zap.New(zap.UseDevMode(true), zap.WriteTo(io.Discard)).WithName("testlog"))
+
+	type want struct {
+		applied map[string]v1.PackageRevisionDesiredState
+		events  []event.Event
+	}
+
+	cases := map[string]struct {
+		reason  string
+		policy  *v1.RevisionActivationPolicy
+		current commonv1.Condition
+		want    want
+	}{
+		"KeepCurrentRevision": {
+			reason:  "We should deactivate all but the current revision when multiple revisions are active.",
+			current: v1.RevisionHealthy(),
+			want: want{
+				applied: map[string]v1.PackageRevisionDesiredState{
+					"test-0000000": v1.PackageRevisionInactive,
+					"test-1234567": v1.PackageRevisionActive,
+				},
+				events: []event.Event{
+					event.Warning(reasonTransitionRevision, errors.Errorf(errFmtMultipleActiveRevisions, 2, "test-0000000, test-1234567", "test-1234567")),
+				},
+			},
+		},
+		"KeepLastGoodRevision": {
+			reason:  "We should deactivate all but the last good revision when multiple revisions are active under the HighestHealthy activation policy and the current revision is unhealthy.",
+			policy:  ptr.To(v1.HighestHealthyActivation),
+			current: v1.RevisionUnhealthy(),
+			want: want{
+				applied: map[string]v1.PackageRevisionDesiredState{
+					"test-1234567": v1.PackageRevisionInactive,
+				},
+				events: []event.Event{
+					event.Warning(reasonTransitionRevision, errors.Errorf(errFmtMultipleActiveRevisions, 2, "test-0000000, test-1234567", "test-0000000")),
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			applied := map[string]v1.PackageRevisionDesiredState{}
+			rec := &eventRecorder{}
+
+			r := &Reconciler{
+				newPackage:             func() v1.Package { return &v1.Configuration{} },
+				newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+				newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+				client: resource.ClientApplicator{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+							p := o.(*v1.Configuration)
+							p.SetName("test")
+							p.SetUID("pkg-uid")
+							p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+							p.SetCurrentRevision("test-1234567")
+							p.SetActivationPolicy(tc.policy)
+							return nil
+						}),
+						MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+							l := o.(*v1.ConfigurationRevisionList)
+							old := v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: "test-0000000", UID: "old-uid"}}
+							old.SetRevision(1)
+							old.SetConditions(v1.RevisionHealthy())
+							old.SetDesiredState(v1.PackageRevisionActive)
+							cur := v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: "test-1234567", UID: "cur-uid"}}
+							cur.SetRevision(2)
+							cur.SetConditions(tc.current)
+							cur.SetDesiredState(v1.PackageRevisionActive)
+							*l = v1.ConfigurationRevisionList{Items: []v1.ConfigurationRevision{old, cur}}
+							return nil
+						}),
+						MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
+					},
+					Applicator: resource.ApplyFn(func(_ context.Context, o client.Object, _ ...resource.ApplyOption) error {
+						pr := o.(*v1.ConfigurationRevision)
+						applied[pr.GetName()] = pr.GetDesiredState()
+						return nil
+					}),
+				},
+				pkg: &MockRevisioner{
+					MockRevision: NewMockRevisionFn("test-1234567", nil),
+				},
+				config: &fake.MockConfigStore{
+					MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+					MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+				},
+				log:        testLog,
+				record:     rec,
+				conditions: conditions.ObservedGenerationPropagationManager{},
+				metrics:    &controller.NopMetrics{},
+				audit:      NewNopAuditSink(),
+			}
+
+			if _, err := r.Reconcile(context.Background(), reconcile.Request{}); err != nil {
+				t.Fatalf("\n%s\nr.Reconcile(...): want no error, got %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want.applied, applied); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want applied revision states, +got applied revision states:\n%s", tc.reason, diff)
+			}
+			// We may record other events, e.g. about the package's
+			// health. Only check the ones we expect are among them.
+			for _, e := range tc.want.events {
+				if !slices.ContainsFunc(rec.events, func(got event.Event) bool { return cmp.Equal(e, got) }) {
+					t.Errorf("\n%s\nr.Reconcile(...): want event %v, got %v", tc.reason, e, rec.events)
+				}
+			}
+		})
+	}
+}
+
+func TestReconcileCurrentRevisionDisagreement(t *testing.T) {
+	testLog := logging.NewLogrLogger(zap.New(zap.UseDevMode(true), zap.WriteTo(io.Discard)).WithName("testlog"))
+	source := "xpkg.example.com/test:v1"
+	corrected := event.Warning(reasonTransitionRevision, errors.Errorf(errFmtCurrentRevisionDisagrees, "test-1234567", "test-0000000", "test-0000000"))
+
+	type want struct {
+		current   string
+		applied   map[string]v1.PackageRevisionDesiredState
+		corrected bool
+	}
+
+	cases := map[string]struct {
+		reason     string
+		policy     *v1.RevisionActivationPolicy
+		pullPolicy corev1.PullPolicy
+		source     string
+		revision   string
+		active     commonv1.Condition
+		missing    bool
+		want       want
+	}{
+		"PreferHealthyActiveRevision": {
+			reason: "We should make the healthy active revision the package's current revision when the current revision isn't active.",
+			active: v1.RevisionHealthy(),
+			want: want{
+				current: "test-0000000",
+				applied: map[string]v1.PackageRevisionDesiredState{
+					"test-0000000": v1.PackageRevisionActive,
+				},
+				corrected: true,
+			},
+		},
+		"UnhealthyActiveRevision": {
+			reason: "We shouldn't prefer an unhealthy active revision to the package's current revision.",
+			active: v1.RevisionUnhealthy(),
+			want: want{
+				current: "test-1234567",
+				applied: map[string]v1.PackageRevisionDesiredState{
+					"test-0000000": v1.PackageRevisionInactive,
+					"test-1234567": v1.PackageRevisionActive,
+				},
+			},
+		},
+		"ManualActivation": {
+			reason: "We shouldn't prefer the active revision to an inactive current revision that's waiting to be manually activated.",
+			policy: ptr.To(v1.ManualActivation),
+			active: v1.RevisionHealthy(),
+			want: want{
+				current: "test-1234567",
+				applied: map[string]v1.PackageRevisionDesiredState{
+					"test-0000000": v1.PackageRevisionInactive,
+					"test-1234567": v1.PackageRevisionInactive,
+				},
+			},
+		},
+		"ManualActivationMissingCurrentRevision": {
+			reason:  "We should make the healthy active revision the package's current revision when the current revision doesn't exist, regardless of activation policy.",
+			policy:  ptr.To(v1.ManualActivation),
+			active:  v1.RevisionHealthy(),
+			missing: true,
+			want: want{
+				current: "test-0000000",
+				applied: map[string]v1.PackageRevisionDesiredState{
+					"test-0000000": v1.PackageRevisionActive,
+				},
+				corrected: true,
+			},
+		},
+		"PullAlwaysPreferHealthyActiveRevision": {
+			reason:     "We should keep the healthy active revision as the package's current revision when polling its unchanged source resolves to the disagreeing current revision.",
+			pullPolicy: corev1.PullAlways,
+			revision:   "test-1234567",
+			active:     v1.RevisionHealthy(),
+			want: want{
+				current: "test-0000000",
+				applied: map[string]v1.PackageRevisionDesiredState{
+					"test-0000000": v1.PackageRevisionActive,
+				},
+				corrected: true,
+			},
+		},
+		"ChangedSource": {
+			reason:   "We should make the new revision the package's current revision when its source changed, even if its current revision disagreed with its active revision.",
+			source:   "xpkg.example.com/test:v2",
+			revision: "test-7654321",
+			active:   v1.RevisionHealthy(),
+			want: want{
+				current: "test-7654321",
+				applied: map[string]v1.PackageRevisionDesiredState{
+					"test-0000000": v1.PackageRevisionInactive,
+					"test-7654321": v1.PackageRevisionActive,
+				},
+				corrected: true,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			applied := map[string]v1.PackageRevisionDesiredState{}
+			rec := &eventRecorder{}
+			var current string
+
+			src, pp := source, corev1.PullIfNotPresent
+			if tc.source != "" {
+				src = tc.source
+			}
+			if tc.pullPolicy != "" {
+				pp = tc.pullPolicy
+			}
+
+			// Packages with pull policy IfNotPresent resolve to their
+			// current revision while their source is unchanged.
+			var pkg Revisioner = NewPackageRevisioner(nil)
+			if tc.revision != "" {
+				pkg = &MockRevisioner{MockRevision: NewMockRevisionFn(tc.revision, nil)}
+			}
+
+			r := &Reconciler{
+				newPackage:             func() v1.Package { return &v1.Configuration{} },
+				newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+				newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+				client: resource.ClientApplicator{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+							p := o.(*v1.Configuration)
+							p.SetName("test")
+							p.SetUID("pkg-uid")
+							p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+							p.SetSource(src)
+							p.SetPackagePullPolicy(ptr.To(pp))
+							p.SetActivationPolicy(tc.policy)
+							p.SetCurrentRevision("test-1234567")
+							p.SetCurrentIdentifier(source)
+							return nil
+						}),
+						MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+							l := o.(*v1.ConfigurationRevisionList)
+							old := v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: "test-0000000", UID: "old-uid"}}
+							old.SetRevision(1)
+							old.SetConditions(tc.active)
+							old.SetDesiredState(v1.PackageRevisionActive)
+							*l = v1.ConfigurationRevisionList{Items: []v1.ConfigurationRevision{old}}
+							if !tc.missing {
+								cur := v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: "test-1234567", UID: "cur-uid"}}
+								cur.SetRevision(2)
+								cur.SetConditions(v1.RevisionUnknownHealth())
+								cur.SetDesiredState(v1.PackageRevisionInactive)
+								l.Items = append(l.Items, cur)
+							}
+							return nil
+						}),
+						MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+							current = o.(*v1.Configuration).GetCurrentRevision()
+							return nil
+						}),
+					},
+					Applicator: resource.ApplyFn(func(_ context.Context, o client.Object, _ ...resource.ApplyOption) error {
+						pr := o.(*v1.ConfigurationRevision)
+						applied[pr.GetName()] = pr.GetDesiredState()
+						return nil
+					}),
+				},
+				pkg: pkg,
+				config: &fake.MockConfigStore{
+					MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+					MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+				},
+				log:        testLog,
+				record:     rec,
+				conditions: conditions.ObservedGenerationPropagationManager{},
+				metrics:    &controller.NopMetrics{},
+				audit:      NewNopAuditSink(),
+				clock:      testingclock.NewFakePassiveClock(time.Now()),
+			}
+
+			if _, err := r.Reconcile(context.Background(), reconcile.Request{}); err != nil {
+				t.Fatalf("\n%s\nr.Reconcile(...): want no error, got %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want.current, current); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want current revision, +got current revision:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.applied, applied); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want applied revision states, +got applied revision states:\n%s", tc.reason, diff)
+			}
+			got := slices.ContainsFunc(rec.events, func(e event.Event) bool { return cmp.Equal(corrected, e) })
+			if got != tc.want.corrected {
+				t.Errorf("\n%s\nr.Reconcile(...): want correction event %t, got events %v", tc.reason, tc.want.corrected, rec.events)
+			}
+		})
+	}
+}
+
+func TestReconcileResolutionLimiter(t *testing.T) {
+	testLog := logging.NewLogrLogger(zap.New(zap.UseDevMode(true), zap.WriteTo(io.Discard)).WithName("testlog"))
+
+	type want struct {
+		result   reconcile.Result
+		resolved bool
+	}
+
+	cases := map[string]struct {
+		reason   string
+		acquired int64
+		want     want
+	}{
+		"Saturated": {
+			reason:   "We should requeue quickly without resolving the package if too many packages are already being resolved.",
+			acquired: 1,
+			want: want{
+				result: reconcile.Result{RequeueAfter: resolutionsSaturatedRequeue},
+			},
+		},
+		"Available": {
+			reason: "We should resolve the package if not too many packages are being resolved.",
+			want: want{
+				result:   reconcile.Result{Requeue: true},
+				resolved: true,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			resolved := false
+			s := semaphore.NewWeighted(1)
+			if tc.acquired > 0 && !s.TryAcquire(tc.acquired) {
+				t.Fatalf("cannot acquire semaphore")
+			}
+
+			r := &Reconciler{
+				newPackage:             func() v1.Package { return &v1.Configuration{} },
+				newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+				newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+				client: resource.ClientApplicator{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+							p := o.(*v1.Configuration)
+							p.SetName("test")
+							p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+							return nil
+						}),
+						MockList:         test.NewMockListFn(nil),
+						MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
+					},
+				},
+				pkg: &MockRevisioner{
+					MockRevision: func() (string, error) {
+						resolved = true
+						// The revisioner hasn't finished unpacking yet.
+						return "", nil
+					},
+				},
+				config: &fake.MockConfigStore{
+					MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+					MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+				},
+				resolutions: s,
+				log:         testLog,
+				record:      event.NewNopRecorder(),
+				conditions:  conditions.ObservedGenerationPropagationManager{},
+				metrics:     &controller.NopMetrics{},
+				audit:       NewNopAuditSink(),
+			}
+
+			res, err := r.Reconcile(context.Background(), reconcile.Request{})
+			if err != nil {
+				t.Errorf("\n%s\nr.Reconcile(...): want no error, got %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want.result, res); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want result, +got result:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.resolved, resolved); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want resolved, +got resolved:\n%s", tc.reason, diff)
+			}
+
+			// The reconcile should release any weight it acquired.
+			if tc.acquired == 0 && !s.TryAcquire(1) {
+				t.Errorf("\n%s\nr.Reconcile(...): did not release the resolution semaphore", tc.reason)
+			}
+		})
+	}
+}
+
+func TestForget(t *testing.T) {
+	r := &Reconciler{pullSecrets: NewPullSecretIndex()}
+	r.uids.Store("test", types.UID("test-uid"))
+	r.synced.Store("test", syncedPackage{})
+	r.pullSecrets.Set("test", "secret")
+	for _, m := range []*sync.Map{&r.warmed, &r.upgradeChecked, &r.unpackingSince, &r.unpersisted, &r.warnedPullAlways, &r.relabeled} {
+		m.Store(types.UID("test-uid"), true)
+		m.Store(types.UID("other-uid"), true)
+	}
+
+	r.forget("test")
+
+	if _, ok := r.uids.Load("test"); ok {
+		t.Errorf("r.forget(...): want package's UID forgotten")
+	}
+	if _, ok := r.synced.Load("test"); ok {
+		t.Errorf("r.forget(...): want package's synced state forgotten")
+	}
+	if diff := cmp.Diff([]string{}, r.pullSecrets.Packages("secret")); diff != "" {
+		t.Errorf("r.forget(...): -want packages using pull secret, +got packages using pull secret:\n%s", diff)
+	}
+	for i, m := range []*sync.Map{&r.warmed, &r.upgradeChecked, &r.unpackingSince, &r.unpersisted, &r.warnedPullAlways, &r.relabeled} {
+		if _, ok := m.Load(types.UID("test-uid")); ok {
+			t.Errorf("r.forget(...): want package's UID forgotten from map %d", i)
+		}
+		if _, ok := m.Load(types.UID("other-uid")); !ok {
+			t.Errorf("r.forget(...): want other package's UID kept in map %d", i)
+		}
 	}
 }
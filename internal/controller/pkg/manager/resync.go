@@ -0,0 +1,83 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"context"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+)
+
+// A ResyncSource periodically enqueues a reconcile for every package of a
+// kind, regardless of whether the package changed. It's a safety net for
+// environments where watch events are unreliable.
+type ResyncSource struct {
+	client   client.Reader
+	kind     PackageKind
+	interval time.Duration
+	log      logging.Logger
+}
+
+// NewResyncSource returns a source that enqueues a reconcile for every package
+// of the supplied kind once per the supplied interval.
+func NewResyncSource(c client.Reader, k PackageKind, interval time.Duration, log logging.Logger) *ResyncSource {
+	return &ResyncSource{client: c, kind: k, interval: interval, log: log}
+}
+
+// Start periodically enqueueing packages until the supplied context is done.
+func (s *ResyncSource) Start(ctx context.Context, q workqueue.TypedRateLimitingInterface[reconcile.Request]) error {
+	go func() {
+		t := time.NewTicker(s.interval)
+		defer t.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-t.C:
+				s.Enqueue(ctx, q)
+			}
+		}
+	}()
+	return nil
+}
+
+// Enqueue a reconcile for every package. Reconciles are spread evenly across
+// the resync interval, so that resyncing many packages doesn't cause a burst
+// of reconciles.
+func (s *ResyncSource) Enqueue(ctx context.Context, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+	// We only need the packages' names, so we only list their metadata.
+	l := &metav1.PartialObjectMetadataList{}
+	l.SetGroupVersionKind(s.kind.Package.GroupVersion().WithKind(s.kind.Package.Kind + "List"))
+	if err := s.client.List(ctx, l); err != nil {
+		// Nothing we can do, except logging, if we can't list packages.
+		// We'll try again at the next interval.
+		s.log.Debug("Cannot list packages to resync", "error", err)
+		return
+	}
+
+	for i, pkg := range l.Items {
+		delay := s.interval * time.Duration(i) / time.Duration(len(l.Items))
+		q.AddAfter(reconcile.Request{NamespacedName: types.NamespacedName{Name: pkg.GetName()}}, delay)
+	}
+}
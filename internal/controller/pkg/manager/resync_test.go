@@ -0,0 +1,118 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+)
+
+type delayedRequest struct {
+	Request reconcile.Request
+	Delay   time.Duration
+}
+
+type addAfterQueueMock struct {
+	workqueue.TypedRateLimitingInterface[reconcile.Request]
+
+	added []delayedRequest
+}
+
+func (q *addAfterQueueMock) AddAfter(item reconcile.Request, d time.Duration) {
+	q.added = append(q.added, delayedRequest{Request: item, Delay: d})
+}
+
+func TestResyncSourceEnqueue(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	type args struct {
+		c        client.Reader
+		interval time.Duration
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   []delayedRequest
+	}{
+		"ListError": {
+			reason: "We should not enqueue anything if we can't list packages.",
+			args: args{
+				c: &test.MockClient{
+					MockList: test.NewMockListFn(errBoom),
+				},
+				interval: time.Minute,
+			},
+		},
+		"SpreadAcrossInterval": {
+			reason: "We should enqueue every package, spread evenly across the resync interval.",
+			args: args{
+				c: &test.MockClient{
+					MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+						l, ok := o.(*metav1.PartialObjectMetadataList)
+						if !ok {
+							t.Errorf("List(...): want *metav1.PartialObjectMetadataList, got %T", o)
+							return nil
+						}
+						if diff := cmp.Diff(v1.ConfigurationGroupVersionKind.GroupVersion().WithKind("ConfigurationList"), l.GroupVersionKind()); diff != "" {
+							t.Errorf("List(...): -want GVK, +got GVK:\n%s", diff)
+						}
+						l.Items = []metav1.PartialObjectMetadata{
+							{ObjectMeta: metav1.ObjectMeta{Name: "a"}},
+							{ObjectMeta: metav1.ObjectMeta{Name: "b"}},
+							{ObjectMeta: metav1.ObjectMeta{Name: "c"}},
+							{ObjectMeta: metav1.ObjectMeta{Name: "d"}},
+						}
+						return nil
+					}),
+				},
+				interval: time.Minute,
+			},
+			want: []delayedRequest{
+				{Request: reconcile.Request{NamespacedName: types.NamespacedName{Name: "a"}}, Delay: 0},
+				{Request: reconcile.Request{NamespacedName: types.NamespacedName{Name: "b"}}, Delay: 15 * time.Second},
+				{Request: reconcile.Request{NamespacedName: types.NamespacedName{Name: "c"}}, Delay: 30 * time.Second},
+				{Request: reconcile.Request{NamespacedName: types.NamespacedName{Name: "d"}}, Delay: 45 * time.Second},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			q := &addAfterQueueMock{}
+			s := NewResyncSource(tc.args.c, ConfigurationPackageKind, tc.args.interval, logging.NewNopLogger())
+			s.Enqueue(context.Background(), q)
+
+			if diff := cmp.Diff(tc.want, q.added); diff != "" {
+				t.Errorf("\n%s\nEnqueue(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -17,31 +17,85 @@ limitations under the License.
 package manager
 
 import (
+	"archive/tar"
 	"context"
+	"path/filepath"
 
 	"github.com/google/go-containerregistry/pkg/name"
+	conregv1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
 	corev1 "k8s.io/api/core/v1"
 
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/parser"
 
 	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+	"github.com/crossplane/crossplane/internal/controller/pkg/controller"
 	"github.com/crossplane/crossplane/internal/xpkg"
 )
 
 const (
 	errBadReference = "package tag is not a valid reference"
 	errFetchPackage = "failed to fetch package digest from remote"
+	errLoadPackage  = "failed to load package digest from local package"
+)
+
+const (
+	errFetchIndex     = "failed to fetch package index from remote"
+	errSelectPlatform = "failed to select platform-specific package from package index"
+)
+
+const (
+	errFetchContents = "failed to fetch package image to parse its contents"
+	errFindStream    = "cannot find " + xpkg.StreamFile + " in package image"
+	errParseContents = "cannot parse package contents"
 )
 
 // Revisioner extracts a revision name for a package source.
 type Revisioner interface {
-	Revision(ctx context.Context, p v1.Package, extraPullSecrets ...string) (string, error)
+	// Revision returns the revision name for the supplied package's source,
+	// and what it could determine about the package image the source
+	// resolves to. It returns nil image info if it didn't inspect the image.
+	Revision(ctx context.Context, p v1.Package, extraPullSecrets ...string) (string, *ImageInfo, error)
+}
+
+// A RevisionContentsFetcher fetches the contents of an existing package
+// revision.
+type RevisionContentsFetcher interface {
+	// RevisionContents returns the parsed contents of the package image the
+	// supplied revision resolved to. It returns nil contents if it doesn't
+	// parse package contents.
+	RevisionContents(ctx context.Context, pr v1.PackageRevision, extraPullSecrets ...string) (*parser.Package, error)
+}
+
+// ImageInfo describes the package image a revision was resolved to.
+type ImageInfo struct {
+	// Digest of the image. When the package is an OCI index of
+	// platform-specific packages this is the digest of the package selected
+	// for the platform.
+	Digest string
+
+	// Platform of the image, for example linux/amd64. Empty if the image's
+	// platform is unknown.
+	Platform string
+
+	// Size of the image in bytes, including its config and layers. Nil if
+	// the image's size is unknown.
+	Size *int64
+
+	// Contents of the package. Nil unless the revisioner was configured to
+	// parse package contents.
+	Contents *parser.Package
 }
 
 // PackageRevisioner extracts a revision name for a package source.
 type PackageRevisioner struct {
 	fetcher  xpkg.Fetcher
 	registry string
+	platform *conregv1.Platform
+	parser   parser.Parser
+	namer    controller.RevisionNamer
+	localDir string
 }
 
 // A PackageRevisionerOption sets configuration for a package revisioner.
@@ -54,10 +108,46 @@ func WithDefaultRegistry(registry string) PackageRevisionerOption {
 	}
 }
 
+// WithPlatform sets the platform a package revisioner selects when a package
+// is an OCI index of platform-specific packages. Without a platform the
+// revisioner treats an index like any other package image.
+func WithPlatform(p *conregv1.Platform) PackageRevisionerOption {
+	return func(r *PackageRevisioner) {
+		r.platform = p
+	}
+}
+
+// WithContentParser sets the parser a package revisioner uses to parse the
+// contents of packages that resolve to a new revision. Without a parser the
+// revisioner doesn't inspect package contents.
+func WithContentParser(p parser.Parser) PackageRevisionerOption {
+	return func(r *PackageRevisioner) {
+		r.parser = p
+	}
+}
+
+// WithRevisionNamer sets how a package revisioner derives revision names. By
+// default revisions are named after their package and a truncated hash.
+func WithRevisionNamer(n controller.RevisionNamer) PackageRevisionerOption {
+	return func(r *PackageRevisioner) {
+		r.namer = n
+	}
+}
+
+// WithLocalPackageDir sets the directory a package revisioner loads local
+// packages - those with a file:// source - from. Without a directory the
+// revisioner refuses to load local packages.
+func WithLocalPackageDir(dir string) PackageRevisionerOption {
+	return func(r *PackageRevisioner) {
+		r.localDir = dir
+	}
+}
+
 // NewPackageRevisioner returns a new PackageRevisioner.
 func NewPackageRevisioner(fetcher xpkg.Fetcher, opts ...PackageRevisionerOption) *PackageRevisioner {
 	r := &PackageRevisioner{
 		fetcher: fetcher,
+		namer:   &controller.FriendlyRevisionNamer{},
 	}
 	for _, opt := range opts {
 		opt(r)
@@ -66,21 +156,34 @@ func NewPackageRevisioner(fetcher xpkg.Fetcher, opts ...PackageRevisionerOption)
 }
 
 // Revision extracts a revision name for a package source.
-func (r *PackageRevisioner) Revision(ctx context.Context, p v1.Package, extraPullSecrets ...string) (string, error) {
+func (r *PackageRevisioner) Revision(ctx context.Context, p v1.Package, extraPullSecrets ...string) (string, *ImageInfo, error) {
 	pullPolicy := p.GetPackagePullPolicy()
 	if pullPolicy != nil && *pullPolicy == corev1.PullNever {
-		return xpkg.FriendlyID(p.GetName(), p.GetSource()), nil
+		return r.namer.RevisionName(p, p.GetSource()), nil, nil
 	}
 	if pullPolicy != nil && *pullPolicy == corev1.PullIfNotPresent {
 		if p.GetCurrentIdentifier() == p.GetSource() {
-			return p.GetCurrentRevision(), nil
+			return p.GetCurrentRevision(), nil, nil
+		}
+	}
+	// Local packages are loaded from disk rather than fetched from a
+	// registry. Their digest is derived from the contents of the image.
+	if path, ok := xpkg.LocalPackagePath(p.GetResolvedSource()); ok {
+		img, err := xpkg.LocalImage(r.localDir, path)
+		if err != nil {
+			return "", nil, errors.Wrap(err, errLoadPackage)
+		}
+		d, err := img.Digest()
+		if err != nil {
+			return "", nil, errors.Wrap(err, errLoadPackage)
 		}
+		return r.namer.RevisionName(p, d.Hex), imageInfo(img), nil
 	}
 	// Use the package recorded in the status rather than the one in the spec,
 	// since it may have been rewritten by image config.
 	ref, err := name.ParseReference(p.GetResolvedSource(), name.WithDefaultRegistry(r.registry))
 	if err != nil {
-		return "", errors.Wrap(err, errBadReference)
+		return "", nil, errors.Wrap(err, errBadReference)
 	}
 
 	ps := v1.RefNames(p.GetPackagePullSecrets())
@@ -89,9 +192,129 @@ func (r *PackageRevisioner) Revision(ctx context.Context, p v1.Package, extraPul
 	}
 	d, err := r.fetcher.Head(ctx, ref, ps...)
 	if err != nil || d == nil {
-		return "", errors.Wrap(err, errFetchPackage)
+		return "", nil, errors.Wrap(err, errFetchPackage)
+	}
+
+	// Each entry of an index of packages is itself a full package. Resolve
+	// the package for our platform, and derive the revision from its digest
+	// rather than the index's.
+	if r.platform != nil && d.MediaType.IsIndex() {
+		idx, err := r.fetcher.Index(ctx, ref, ps...)
+		if err != nil {
+			return "", nil, errors.Wrap(err, errFetchIndex)
+		}
+		if d, err = xpkg.PlatformPackage(idx, *r.platform); err != nil {
+			return "", nil, errors.Wrap(err, errSelectPlatform)
+		}
+		ref = ref.Context().Digest(d.Digest.String())
+	}
+	id := r.namer.RevisionName(p, d.Digest.Hex)
+
+	// Only inspect the image when it's not the package's current revision,
+	// to avoid fetching its manifest and config every time we poll.
+	if id == p.GetCurrentRevision() {
+		return id, nil, nil
+	}
+
+	// The image's size and platform are informational. Don't fail to
+	// resolve a revision just because we can't fetch them. We still know
+	// its digest.
+	img, err := r.fetcher.Fetch(ctx, ref, ps...)
+	if err != nil && r.parser == nil {
+		return id, &ImageInfo{Digest: d.Digest.String()}, nil //nolint:nilerr // The image's size and platform are optional.
+	}
+	if err != nil {
+		// The package's contents aren't optional when we're configured to
+		// parse them, e.g. so that they can be validated.
+		return "", nil, errors.Wrap(err, errFetchContents)
+	}
+	info := imageInfo(img)
+	if r.parser != nil {
+		if info.Contents, err = r.contents(ctx, img); err != nil {
+			return "", nil, err
+		}
+	}
+	return id, info, nil
+}
+
+// RevisionContents returns the parsed contents of the package image the
+// supplied revision resolved to. It returns nil contents if the revisioner
+// wasn't configured to parse package contents.
+func (r *PackageRevisioner) RevisionContents(ctx context.Context, pr v1.PackageRevision, extraPullSecrets ...string) (*parser.Package, error) {
+	if r.parser == nil {
+		return nil, nil
+	}
+	src := pr.GetResolvedSource()
+	if src == "" {
+		src = pr.GetSource()
+	}
+	if path, ok := xpkg.LocalPackagePath(src); ok {
+		img, err := xpkg.LocalImage(r.localDir, path)
+		if err != nil {
+			return nil, errors.Wrap(err, errLoadPackage)
+		}
+		return r.contents(ctx, img)
+	}
+	ref, err := name.ParseReference(src, name.WithDefaultRegistry(r.registry))
+	if err != nil {
+		return nil, errors.Wrap(err, errBadReference)
+	}
+	// Fetch the image the revision resolved to, not whatever image its tag
+	// refers to now.
+	if d := pr.GetResolvedDigest(); d != "" {
+		ref = ref.Context().Digest(d)
+	}
+
+	ps := v1.RefNames(pr.GetPackagePullSecrets())
+	if len(extraPullSecrets) > 0 {
+		ps = append(ps, extraPullSecrets...)
+	}
+	img, err := r.fetcher.Fetch(ctx, ref, ps...)
+	if err != nil {
+		return nil, errors.Wrap(err, errFetchContents)
+	}
+	return r.contents(ctx, img)
+}
+
+// contents parses the package stream contained in the supplied image.
+func (r *PackageRevisioner) contents(ctx context.Context, img conregv1.Image) (*parser.Package, error) {
+	// Flatten the image's filesystem, then look for the package stream.
+	rc := mutate.Extract(img)
+	t := tar.NewReader(rc)
+	for {
+		h, err := t.Next()
+		if err != nil {
+			_ = rc.Close()
+			return nil, errors.Wrap(err, errFindStream)
+		}
+		if filepath.Base(h.Name) == xpkg.StreamFile {
+			break
+		}
+	}
+	pkg, err := r.parser.Parse(ctx, xpkg.JoinedReadCloser(t, rc))
+	if err != nil {
+		return nil, errors.Wrap(err, errParseContents)
+	}
+	return pkg, nil
+}
+
+// imageInfo returns what it can determine about the supplied image.
+func imageInfo(img conregv1.Image) *ImageInfo {
+	info := &ImageInfo{}
+	if d, err := img.Digest(); err == nil {
+		info.Digest = d.String()
+	}
+	if m, err := img.Manifest(); err == nil {
+		size := m.Config.Size
+		for _, l := range m.Layers {
+			size += l.Size
+		}
+		info.Size = &size
+	}
+	if cf, err := img.ConfigFile(); err == nil && cf.Platform() != nil {
+		info.Platform = cf.Platform().String()
 	}
-	return xpkg.FriendlyID(p.GetName(), d.Digest.Hex), nil
+	return info
 }
 
 // NopRevisioner returns an empty revision name.
@@ -103,6 +326,6 @@ func NewNopRevisioner() *NopRevisioner {
 }
 
 // Revision returns an empty revision name and no error.
-func (d *NopRevisioner) Revision(context.Context, v1.Package, ...string) (string, error) {
-	return "", nil
+func (d *NopRevisioner) Revision(context.Context, v1.Package, ...string) (string, *ImageInfo, error) {
+	return "", nil, nil
 }
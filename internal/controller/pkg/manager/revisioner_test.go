@@ -23,6 +23,9 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-containerregistry/pkg/name"
 	conregv1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/types"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
@@ -30,6 +33,7 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/test"
 
 	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+	"github.com/crossplane/crossplane/internal/controller/pkg/controller"
 	"github.com/crossplane/crossplane/internal/xpkg"
 	"github.com/crossplane/crossplane/internal/xpkg/fake"
 )
@@ -39,8 +43,19 @@ func TestPackageRevisioner(t *testing.T) {
 	pullNever := corev1.PullNever
 	pullIfNotPresent := corev1.PullIfNotPresent
 
+	img, _ := mutate.ConfigFile(empty.Image, &conregv1.ConfigFile{OS: "linux", Architecture: "arm64"})
+	m, _ := img.Manifest()
+	size := m.Config.Size
+	dg, _ := img.Digest()
+
+	arm64 := &conregv1.Platform{OS: "linux", Architecture: "arm64"}
+	idx := mutate.AppendManifests(empty.Index, mutate.IndexAddendum{Add: img, Descriptor: conregv1.Descriptor{Platform: arm64}})
+	idxDigest, _ := idx.Digest()
+
 	type args struct {
 		f                    xpkg.Fetcher
+		platform             *conregv1.Platform
+		namer                controller.RevisionNamer
 		pkg                  v1.Package
 		pullSecretFromConfig string
 	}
@@ -48,6 +63,7 @@ func TestPackageRevisioner(t *testing.T) {
 	type want struct {
 		err    error
 		digest string
+		image  *ImageInfo
 	}
 
 	cases := map[string]struct {
@@ -120,6 +136,7 @@ func TestPackageRevisioner(t *testing.T) {
 							},
 						}, nil
 					},
+					MockFetch: fake.NewMockFetchFn(nil, errBoom),
 				},
 				pkg: &v1.Provider{
 					ObjectMeta: metav1.ObjectMeta{
@@ -139,7 +156,48 @@ func TestPackageRevisioner(t *testing.T) {
 				},
 			},
 			want: want{
+				// Failing to fetch the image's size and platform
+				// shouldn't fail to resolve its revision.
 				digest: "provider-aws-ecc25c121431",
+				image:  &ImageInfo{Digest: "sha256:ecc25c121431dfc7058754427f97c034ecde26d4aafa0da16d258090e0443904"},
+			},
+		},
+		"SuccessfulCustomRevisionNamer": {
+			reason: "Should name the revision using the supplied revision namer.",
+			args: args{
+				f: &fake.MockFetcher{
+					MockHead: func(_ name.Reference) (*conregv1.Descriptor, error) {
+						return &conregv1.Descriptor{
+							Digest: conregv1.Hash{
+								Algorithm: "sha256",
+								Hex:       "ecc25c121431dfc7058754427f97c034ecde26d4aafa0da16d258090e0443904",
+							},
+						}, nil
+					},
+					MockFetch: fake.NewMockFetchFn(nil, errBoom),
+				},
+				namer: controller.RevisionNamerFn(func(p v1.Package, hash string) string {
+					return p.GetName() + "-build-42-" + hash[:6]
+				}),
+				pkg: &v1.Provider{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "provider-aws",
+					},
+					Spec: v1.ProviderSpec{
+						PackageSpec: v1.PackageSpec{
+							Package: "crossplane/provider-aws:latest",
+						},
+					},
+					Status: v1.ProviderStatus{
+						PackageStatus: v1.PackageStatus{
+							ResolvedPackage: "crossplane/provider-aws:latest",
+						},
+					},
+				},
+			},
+			want: want{
+				digest: "provider-aws-build-42-ecc25c",
+				image:  &ImageInfo{Digest: "sha256:ecc25c121431dfc7058754427f97c034ecde26d4aafa0da16d258090e0443904"},
 			},
 		},
 		"SuccessfulDigest": {
@@ -168,10 +226,67 @@ func TestPackageRevisioner(t *testing.T) {
 							Hex:       "ecc25c121431dfc7058754427f97c034ecde26d4aafa0da16d258090e0443904",
 						},
 					}, nil),
+					MockFetch: fake.NewMockFetchFn(img, nil),
 				},
 			},
 			want: want{
 				digest: "provider-nop-ecc25c121431",
+				image:  &ImageInfo{Digest: dg.String(), Platform: "linux/arm64", Size: &size},
+			},
+		},
+		"SuccessfulIndex": {
+			reason: "Should return the digest of the package for our platform if the package source is an index of packages.",
+			args: args{
+				pkg: &v1.Provider{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "provider-nop",
+					},
+					Spec: v1.ProviderSpec{
+						PackageSpec: v1.PackageSpec{
+							Package: "crossplane-contrib/provider-nop:v1.0.0",
+						},
+					},
+					Status: v1.ProviderStatus{
+						PackageStatus: v1.PackageStatus{
+							ResolvedPackage: "crossplane-contrib/provider-nop:v1.0.0",
+						},
+					},
+				},
+				platform: arm64,
+				f: &fake.MockFetcher{
+					MockHead:  fake.NewMockHeadFn(&conregv1.Descriptor{MediaType: types.OCIImageIndex, Digest: idxDigest}, nil),
+					MockIndex: fake.NewMockIndexFn(idx, nil),
+					MockFetch: fake.NewMockFetchFn(img, nil),
+				},
+			},
+			want: want{
+				digest: xpkg.FriendlyID("provider-nop", dg.Hex),
+				image:  &ImageInfo{Digest: dg.String(), Platform: "linux/arm64", Size: &size},
+			},
+		},
+		"ErrNoPlatformPackage": {
+			reason: "Should return an error if the package source is an index of packages with no package for our platform.",
+			args: args{
+				pkg: &v1.Provider{
+					Spec: v1.ProviderSpec{
+						PackageSpec: v1.PackageSpec{
+							Package: "crossplane-contrib/provider-nop:v1.0.0",
+						},
+					},
+					Status: v1.ProviderStatus{
+						PackageStatus: v1.PackageStatus{
+							ResolvedPackage: "crossplane-contrib/provider-nop:v1.0.0",
+						},
+					},
+				},
+				platform: &conregv1.Platform{OS: "linux", Architecture: "amd64"},
+				f: &fake.MockFetcher{
+					MockHead:  fake.NewMockHeadFn(&conregv1.Descriptor{MediaType: types.OCIImageIndex, Digest: idxDigest}, nil),
+					MockIndex: fake.NewMockIndexFn(idx, nil),
+				},
+			},
+			want: want{
+				err: errors.Wrap(errors.New("index contains no package for platform linux/amd64"), errSelectPlatform),
 			},
 		},
 		"ErrParseRef": {
@@ -221,8 +336,12 @@ func TestPackageRevisioner(t *testing.T) {
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			r := NewPackageRevisioner(tc.args.f)
-			h, err := r.Revision(context.TODO(), tc.args.pkg, tc.args.pullSecretFromConfig)
+			opts := []PackageRevisionerOption{WithPlatform(tc.args.platform)}
+			if tc.args.namer != nil {
+				opts = append(opts, WithRevisionNamer(tc.args.namer))
+			}
+			r := NewPackageRevisioner(tc.args.f, opts...)
+			h, image, err := r.Revision(context.TODO(), tc.args.pkg, tc.args.pullSecretFromConfig)
 
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
 				t.Errorf("\n%s\nr.Name(...): -want error, +got error:\n%s", tc.reason, diff)
@@ -230,6 +349,9 @@ func TestPackageRevisioner(t *testing.T) {
 			if diff := cmp.Diff(tc.want.digest, h, test.EquateErrors()); diff != "" {
 				t.Errorf("\n%s\nr.Name(...): -want, +got:\n%s", tc.reason, diff)
 			}
+			if diff := cmp.Diff(tc.want.image, image); diff != "" {
+				t.Errorf("\n%s\nr.Name(...): -want image info, +got image info:\n%s", tc.reason, diff)
+			}
 		})
 	}
 }
@@ -0,0 +1,94 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"context"
+
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+
+	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+	"github.com/crossplane/crossplane/apis/pkg/v1beta1"
+)
+
+const (
+	errGetRuntimeConfig = "cannot get runtime config"
+)
+
+// missingRuntimeConfig returns the name of the runtime config the supplied
+// package references, and whether that runtime config doesn't exist. Packages
+// that don't have a runtime, or that don't reference a runtime config, are
+// never missing one.
+func missingRuntimeConfig(ctx context.Context, c client.Reader, p v1.Package) (string, bool, error) {
+	pwr, ok := p.(v1.PackageWithRuntime)
+	if !ok {
+		return "", false, nil
+	}
+	ref := pwr.GetRuntimeConfigRef()
+	if ref == nil {
+		return "", false, nil
+	}
+	err := c.Get(ctx, types.NamespacedName{Name: ref.Name}, &v1beta1.DeploymentRuntimeConfig{})
+	if kerrors.IsNotFound(err) {
+		return ref.Name, true, nil
+	}
+	return ref.Name, false, errors.Wrap(err, errGetRuntimeConfig)
+}
+
+// enqueuePackagesForRuntimeConfig returns an event handler that enqueues
+// packages of the supplied kind that reference a runtime config when it
+// changes, for example because it was created.
+func enqueuePackagesForRuntimeConfig(kube client.Client, k PackageKind, log logging.Logger) handler.EventHandler {
+	return handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, o client.Object) []reconcile.Request {
+		rc, ok := o.(*v1beta1.DeploymentRuntimeConfig)
+		if !ok {
+			return nil
+		}
+		l := k.NewPackageList()
+		if err := kube.List(ctx, l); err != nil {
+			// Nothing we can do, except logging, if we can't list packages.
+			log.Debug("Cannot list packages while attempting to enqueue from runtime config", "error", err)
+			return nil
+		}
+		items, err := apimeta.ExtractList(l)
+		if err != nil {
+			log.Debug("Cannot extract packages while attempting to enqueue from runtime config", "error", err)
+			return nil
+		}
+
+		var matches []reconcile.Request
+		for _, i := range items {
+			p, ok := i.(v1.PackageWithRuntime)
+			if !ok {
+				continue
+			}
+			if ref := p.GetRuntimeConfigRef(); ref != nil && ref.Name == rc.GetName() {
+				log.Debug("Enqueuing package for runtime config", "package", p.GetName(), "runtimeConfig", rc.GetName())
+				matches = append(matches, reconcile.Request{NamespacedName: types.NamespacedName{Name: p.GetName()}})
+			}
+		}
+		return matches
+	})
+}
@@ -0,0 +1,141 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+
+	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+	"github.com/crossplane/crossplane/internal/xpkg"
+)
+
+// Artifact types of common software bill of materials (SBOM) formats.
+const (
+	// SBOMArtifactTypeSPDX is the artifact type of an SPDX SBOM.
+	SBOMArtifactTypeSPDX = "application/spdx+json"
+
+	// SBOMArtifactTypeCycloneDX is the artifact type of a CycloneDX SBOM.
+	SBOMArtifactTypeCycloneDX = "application/vnd.cyclonedx+json"
+)
+
+// maxAnnotatedSBOMReferences is the maximum number of SBOM references
+// annotated on a package, to bound the size of its annotations.
+const maxAnnotatedSBOMReferences = 10
+
+const (
+	errParseSBOMRef   = "cannot parse package source"
+	errFetchSBOMImage = "cannot fetch package image descriptor"
+	errFetchReferrers = "cannot fetch artifacts that refer to package image"
+	errReadReferrers  = "cannot read artifacts that refer to package image"
+)
+
+// An SBOMLister discovers the software bill of materials (SBOM) artifacts that
+// refer to a package image.
+type SBOMLister interface {
+	// ListSBOMs returns references to the SBOM artifacts that refer to the
+	// image of the supplied package. It returns an empty slice if none do.
+	ListSBOMs(ctx context.Context, p v1.Package, secrets ...string) ([]string, error)
+}
+
+// An SBOMListerFn discovers the SBOM artifacts that refer to a package image.
+type SBOMListerFn func(ctx context.Context, p v1.Package, secrets ...string) ([]string, error)
+
+// ListSBOMs that refer to the supplied package's image.
+func (fn SBOMListerFn) ListSBOMs(ctx context.Context, p v1.Package, secrets ...string) ([]string, error) {
+	return fn(ctx, p, secrets...)
+}
+
+// A ReferrersSBOMLister discovers the artifacts of an SBOM type that refer to
+// a package image, using the OCI referrers API.
+type ReferrersSBOMLister struct {
+	fetcher  xpkg.Fetcher
+	registry string
+	types    []string
+}
+
+// NewReferrersSBOMLister returns an SBOMLister that discovers the artifacts of
+// the supplied types that refer to a package image. It discovers SPDX and
+// CycloneDX SBOMs if no types are supplied. Package sources that don't
+// specify a registry are fetched from the supplied one.
+func NewReferrersSBOMLister(f xpkg.Fetcher, registry string, artifactTypes ...string) *ReferrersSBOMLister {
+	if len(artifactTypes) == 0 {
+		artifactTypes = []string{SBOMArtifactTypeSPDX, SBOMArtifactTypeCycloneDX}
+	}
+	return &ReferrersSBOMLister{fetcher: f, registry: registry, types: artifactTypes}
+}
+
+// ListSBOMs returns digest references to the artifacts of an SBOM type that
+// refer to the supplied package's image. It returns no references for
+// packages that are never pulled from a registry.
+func (l *ReferrersSBOMLister) ListSBOMs(ctx context.Context, p v1.Package, secrets ...string) ([]string, error) {
+	if pp := p.GetPackagePullPolicy(); pp != nil && *pp == corev1.PullNever {
+		return nil, nil
+	}
+	if _, ok := xpkg.LocalPackagePath(p.GetResolvedSource()); ok {
+		return nil, nil
+	}
+	ref, err := name.ParseReference(p.GetResolvedSource(), name.WithDefaultRegistry(l.registry))
+	if err != nil {
+		return nil, errors.Wrap(err, errParseSBOMRef)
+	}
+	secrets = append(v1.RefNames(p.GetPackagePullSecrets()), secrets...)
+
+	// Artifacts refer to an image by digest, so we need to resolve the
+	// package's source to one before we can look them up.
+	d, err := l.fetcher.Head(ctx, ref, secrets...)
+	if err != nil || d == nil {
+		return nil, errors.Wrap(err, errFetchSBOMImage)
+	}
+	idx, err := l.fetcher.Referrers(ctx, ref.Context().Digest(d.Digest.String()), secrets...)
+	if err != nil {
+		return nil, errors.Wrap(err, errFetchReferrers)
+	}
+	m, err := idx.IndexManifest()
+	if err != nil {
+		return nil, errors.Wrap(err, errReadReferrers)
+	}
+	var refs []string
+	for _, d := range m.Manifests {
+		if slices.Contains(l.types, d.ArtifactType) {
+			refs = append(refs, ref.Context().Digest(d.Digest.String()).String())
+		}
+	}
+	return refs, nil
+}
+
+// annotateSBOMs annotates the supplied package with up to
+// maxAnnotatedSBOMReferences of the supplied SBOM references, removing the
+// annotation if there are none. It returns true if the package's annotations
+// changed.
+func annotateSBOMs(p v1.Package, refs []string) bool {
+	current, ok := p.GetAnnotations()[v1.AnnotationSBOMReferences]
+	if len(refs) == 0 {
+		meta.RemoveAnnotations(p, v1.AnnotationSBOMReferences)
+		return ok
+	}
+	want := strings.Join(refs[:min(len(refs), maxAnnotatedSBOMReferences)], ",")
+	meta.AddAnnotations(p, map[string]string{v1.AnnotationSBOMReferences: want})
+	return !ok || current != want
+}
@@ -0,0 +1,158 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-containerregistry/pkg/name"
+	conregv1 "github.com/google/go-containerregistry/pkg/v1"
+	conregfake "github.com/google/go-containerregistry/pkg/v1/fake"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+	"github.com/crossplane/crossplane/internal/xpkg/fake"
+)
+
+func TestReferrersSBOMLister(t *testing.T) {
+	errBoom := errors.New("boom")
+	digest := conregv1.Hash{Algorithm: "sha256", Hex: "ecc25c121431dfc7058754427f97c034ecde26d4aafa0da16d4fc1c6d1c2d07a"}
+	spdx := conregv1.Hash{Algorithm: "sha256", Hex: "1111111111111111111111111111111111111111111111111111111111111111"}
+	cyclonedx := conregv1.Hash{Algorithm: "sha256", Hex: "2222222222222222222222222222222222222222222222222222222222222222"}
+	sig := conregv1.Hash{Algorithm: "sha256", Hex: "3333333333333333333333333333333333333333333333333333333333333333"}
+
+	head := func(_ name.Reference) (*conregv1.Descriptor, error) {
+		return &conregv1.Descriptor{Digest: digest}, nil
+	}
+	referrers := func(ds ...conregv1.Descriptor) conregv1.ImageIndex {
+		idx := &conregfake.FakeImageIndex{}
+		idx.IndexManifestReturns(&conregv1.IndexManifest{Manifests: ds}, nil)
+		return idx
+	}
+
+	type args struct {
+		types      []string
+		pullPolicy *corev1.PullPolicy
+	}
+	type want struct {
+		refs []string
+		err  error
+	}
+	cases := map[string]struct {
+		reason    string
+		head      func(name.Reference) (*conregv1.Descriptor, error)
+		referrers func(name.Digest) (conregv1.ImageIndex, error)
+		args      args
+		want      want
+	}{
+		"NeverPulled": {
+			reason: "We shouldn't look up the referrers of a package that is never pulled from a registry.",
+			args: args{
+				pullPolicy: ptr.To(corev1.PullNever),
+			},
+			want: want{},
+		},
+		"HeadError": {
+			reason: "We should return an error if we can't resolve the image's digest.",
+			head: func(_ name.Reference) (*conregv1.Descriptor, error) {
+				return nil, errBoom
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errFetchSBOMImage),
+			},
+		},
+		"FetchError": {
+			reason: "We should return an error if we can't fetch the image's referrers.",
+			head:   head,
+			referrers: func(_ name.Digest) (conregv1.ImageIndex, error) {
+				return nil, errBoom
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errFetchReferrers),
+			},
+		},
+		"NoSBOMs": {
+			reason: "An image that isn't referred to by an artifact of an SBOM type has no SBOMs.",
+			head:   head,
+			referrers: func(_ name.Digest) (conregv1.ImageIndex, error) {
+				return referrers(conregv1.Descriptor{ArtifactType: "application/vnd.dev.cosign.artifact.sig.v1+json", Digest: sig}), nil
+			},
+			want: want{},
+		},
+		"DefaultSBOMs": {
+			reason: "We should return references to the SPDX and CycloneDX SBOMs that refer to an image by default.",
+			head:   head,
+			referrers: func(d name.Digest) (conregv1.ImageIndex, error) {
+				if d.String() != "xpkg.crossplane.io/crossplane/test@"+digest.String() {
+					t.Errorf("Referrers(...): want the resolved image's digest, got %q", d.String())
+				}
+				return referrers(
+					conregv1.Descriptor{ArtifactType: SBOMArtifactTypeSPDX, Digest: spdx},
+					conregv1.Descriptor{ArtifactType: "application/vnd.dev.cosign.artifact.sig.v1+json", Digest: sig},
+					conregv1.Descriptor{ArtifactType: SBOMArtifactTypeCycloneDX, Digest: cyclonedx},
+				), nil
+			},
+			want: want{
+				refs: []string{
+					"xpkg.crossplane.io/crossplane/test@" + spdx.String(),
+					"xpkg.crossplane.io/crossplane/test@" + cyclonedx.String(),
+				},
+			},
+		},
+		"CustomSBOMs": {
+			reason: "We should only return references to the SBOMs of the supplied types.",
+			head:   head,
+			referrers: func(_ name.Digest) (conregv1.ImageIndex, error) {
+				return referrers(
+					conregv1.Descriptor{ArtifactType: SBOMArtifactTypeSPDX, Digest: spdx},
+					conregv1.Descriptor{ArtifactType: SBOMArtifactTypeCycloneDX, Digest: cyclonedx},
+				), nil
+			},
+			args: args{
+				types: []string{SBOMArtifactTypeCycloneDX},
+			},
+			want: want{
+				refs: []string{"xpkg.crossplane.io/crossplane/test@" + cyclonedx.String()},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			p := &v1.Configuration{}
+			p.SetSource("crossplane/test:v1.0.0")
+			p.SetResolvedSource("crossplane/test:v1.0.0")
+			p.SetPackagePullPolicy(tc.args.pullPolicy)
+
+			l := NewReferrersSBOMLister(&fake.MockFetcher{MockHead: tc.head, MockReferrers: tc.referrers}, "xpkg.crossplane.io", tc.args.types...)
+			refs, err := l.ListSBOMs(context.Background(), p)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nListSBOMs(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.refs, refs); diff != "" {
+				t.Errorf("\n%s\nListSBOMs(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"context"
+
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+const (
+	errSSAGetObject   = "cannot get object"
+	errSSAGetGVK      = "cannot determine object's kind"
+	errSSAApplyObject = "cannot apply object"
+)
+
+// A ServerSideApplicator applies changes to an object using server-side apply,
+// as the supplied field manager. This lets the package manager coexist with
+// other controllers that edit the same objects, since the API server tracks
+// which fields each of them manages.
+type ServerSideApplicator struct {
+	client       client.Client
+	fieldManager string
+}
+
+// NewServerSideApplicator returns an Applicator that applies changes to an
+// object using server-side apply, as the supplied field manager.
+func NewServerSideApplicator(c client.Client, fieldManager string) *ServerSideApplicator {
+	return &ServerSideApplicator{client: c, fieldManager: fieldManager}
+}
+
+// Apply changes to the supplied object. The object is created if it doesn't
+// exist. The supplied ApplyOptions are only evaluated if it does. Fields the
+// field manager previously applied but that are no longer set are removed, and
+// conflicts with other field managers are forced in the field manager's favor.
+func (a *ServerSideApplicator) Apply(ctx context.Context, o client.Object, ao ...resource.ApplyOption) error {
+	current := o.DeepCopyObject().(client.Object) //nolint:forcetypeassert // Will always be a client.Object.
+	err := a.client.Get(ctx, types.NamespacedName{Name: o.GetName(), Namespace: o.GetNamespace()}, current)
+	if err != nil && !kerrors.IsNotFound(err) {
+		return errors.Wrap(err, errSSAGetObject)
+	}
+	if err == nil {
+		for _, fn := range ao {
+			if err := fn(ctx, current, o); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Objects read using a typed client don't know their kind, but a
+	// server-side apply must specify it.
+	if o.GetObjectKind().GroupVersionKind().Empty() {
+		gvk, err := apiutil.GVKForObject(o, a.client.Scheme())
+		if err != nil {
+			return errors.Wrap(err, errSSAGetGVK)
+		}
+		o.GetObjectKind().SetGroupVersionKind(gvk)
+	}
+
+	// A server-side apply may not specify managed fields, and shouldn't
+	// be rejected because the object changed since we read it.
+	o.SetManagedFields(nil)
+	o.SetResourceVersion("")
+
+	return errors.Wrap(a.client.Patch(ctx, o, client.Apply, client.ForceOwnership, client.FieldOwner(a.fieldManager)), errSSAApplyObject)
+}
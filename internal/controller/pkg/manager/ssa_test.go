@@ -0,0 +1,138 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+)
+
+func TestServerSideApplicator(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	s := runtime.NewScheme()
+	_ = v1.AddToScheme(s)
+
+	type patched struct {
+		patched      bool
+		patchType    types.PatchType
+		fieldManager string
+		force        *bool
+		gvk          schema.GroupVersionKind
+	}
+	type want struct {
+		err   error
+		patch patched
+	}
+	cases := map[string]struct {
+		reason string
+		get    error
+		opts   []resource.ApplyOption
+		want   want
+	}{
+		"GetError": {
+			reason: "We should return an error if we can't get the current object.",
+			get:    errBoom,
+			want: want{
+				err: errors.Wrap(errBoom, errSSAGetObject),
+			},
+		},
+		"ApplyOptionError": {
+			reason: "We shouldn't apply an object that an ApplyOption rejects.",
+			opts: []resource.ApplyOption{func(_ context.Context, _, _ runtime.Object) error {
+				return errBoom
+			}},
+			want: want{
+				err: errBoom,
+			},
+		},
+		"CreateWithFieldManager": {
+			reason: "We should create an object that doesn't exist using server-side apply as our field manager.",
+			get:    kerrors.NewNotFound(schema.GroupResource{}, "test"),
+			want: want{
+				patch: patched{
+					patched:      true,
+					patchType:    types.ApplyPatchType,
+					fieldManager: "crossplane-test",
+					force:        ptr.To(true),
+					gvk:          v1.ConfigurationRevisionGroupVersionKind,
+				},
+			},
+		},
+		"UpdateWithFieldManager": {
+			reason: "We should update an object that exists using server-side apply as our field manager.",
+			want: want{
+				patch: patched{
+					patched:      true,
+					patchType:    types.ApplyPatchType,
+					fieldManager: "crossplane-test",
+					force:        ptr.To(true),
+					gvk:          v1.ConfigurationRevisionGroupVersionKind,
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := patched{}
+			c := &test.MockClient{
+				MockGet: test.NewMockGetFn(tc.get),
+				MockPatch: func(_ context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+					po := &client.PatchOptions{}
+					po.ApplyOptions(opts)
+					got.patched = true
+					got.patchType = patch.Type()
+					got.fieldManager = po.FieldManager
+					got.force = po.Force
+					got.gvk = obj.GetObjectKind().GroupVersionKind()
+					return nil
+				},
+				MockScheme: test.NewMockSchemeFn(s),
+			}
+
+			pr := &v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{
+				Name:            "test",
+				ResourceVersion: "42",
+				ManagedFields:   []metav1.ManagedFieldsEntry{{Manager: "someone-else"}},
+			}}
+			err := NewServerSideApplicator(c, "crossplane-test").Apply(context.Background(), pr, tc.opts...)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nApply(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.patch, got, cmp.AllowUnexported(patched{})); diff != "" {
+				t.Errorf("\n%s\nApply(...): -want patch, +got patch:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
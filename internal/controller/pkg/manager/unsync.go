@@ -0,0 +1,154 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// An unsyncingQueue forgets the synced state of the packages it enqueues, so
+// that they're fully reconciled even though neither they nor their revisions
+// changed. Packages are enqueued this way when something else they depend on
+// changes, like an ImageConfig or a pull secret, or periodically.
+type unsyncingQueue struct {
+	workqueue.TypedRateLimitingInterface[reconcile.Request]
+
+	synced *sync.Map
+}
+
+// Add the supplied request to the queue.
+func (q unsyncingQueue) Add(req reconcile.Request) {
+	q.synced.Delete(req.Name)
+	q.TypedRateLimitingInterface.Add(req)
+}
+
+// AddAfter adds the supplied request to the queue after the supplied delay.
+func (q unsyncingQueue) AddAfter(req reconcile.Request, d time.Duration) {
+	q.synced.Delete(req.Name)
+	q.TypedRateLimitingInterface.AddAfter(req, d)
+}
+
+// AddRateLimited adds the supplied request to the queue once the rate limiter
+// allows it.
+func (q unsyncingQueue) AddRateLimited(req reconcile.Request) {
+	q.synced.Delete(req.Name)
+	q.TypedRateLimitingInterface.AddRateLimited(req)
+}
+
+// An unsyncingEventHandler wraps an event handler so that the packages it
+// enqueues are fully reconciled.
+type unsyncingEventHandler struct {
+	handler handler.EventHandler
+	synced  *sync.Map
+}
+
+// Create handles a create event.
+func (h unsyncingEventHandler) Create(ctx context.Context, e event.CreateEvent, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+	h.handler.Create(ctx, e, unsyncingQueue{TypedRateLimitingInterface: q, synced: h.synced})
+}
+
+// Update handles an update event.
+func (h unsyncingEventHandler) Update(ctx context.Context, e event.UpdateEvent, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+	h.handler.Update(ctx, e, unsyncingQueue{TypedRateLimitingInterface: q, synced: h.synced})
+}
+
+// Delete handles a delete event.
+func (h unsyncingEventHandler) Delete(ctx context.Context, e event.DeleteEvent, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+	h.handler.Delete(ctx, e, unsyncingQueue{TypedRateLimitingInterface: q, synced: h.synced})
+}
+
+// Generic handles a generic event.
+func (h unsyncingEventHandler) Generic(ctx context.Context, e event.GenericEvent, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+	h.handler.Generic(ctx, e, unsyncingQueue{TypedRateLimitingInterface: q, synced: h.synced})
+}
+
+// A resyncUnsyncingEventHandler wraps an event handler so that the packages it
+// enqueues because the cache was periodically resynced are fully reconciled.
+// A resync delivers an update event for an object that didn't change. We rely
+// on resyncs to notice changes to things we don't watch, like the health of
+// other members of a provider family or whether CRDs are established.
+type resyncUnsyncingEventHandler struct {
+	handler handler.EventHandler
+	synced  *sync.Map
+}
+
+// Create handles a create event.
+func (h resyncUnsyncingEventHandler) Create(ctx context.Context, e event.CreateEvent, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+	h.handler.Create(ctx, e, q)
+}
+
+// Update handles an update event. Update events for objects whose resource
+// version didn't change are resyncs.
+func (h resyncUnsyncingEventHandler) Update(ctx context.Context, e event.UpdateEvent, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+	if e.ObjectOld != nil && e.ObjectNew != nil && e.ObjectOld.GetResourceVersion() == e.ObjectNew.GetResourceVersion() {
+		q = unsyncingQueue{TypedRateLimitingInterface: q, synced: h.synced}
+	}
+	h.handler.Update(ctx, e, q)
+}
+
+// Delete handles a delete event.
+func (h resyncUnsyncingEventHandler) Delete(ctx context.Context, e event.DeleteEvent, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+	h.handler.Delete(ctx, e, q)
+}
+
+// Generic handles a generic event.
+func (h resyncUnsyncingEventHandler) Generic(ctx context.Context, e event.GenericEvent, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+	h.handler.Generic(ctx, e, q)
+}
+
+// An unsyncingRawSource wraps a source so that the packages it enqueues are
+// fully reconciled.
+type unsyncingRawSource struct {
+	source source.Source
+	synced *sync.Map
+}
+
+// Start the wrapped source.
+func (s unsyncingRawSource) Start(ctx context.Context, q workqueue.TypedRateLimitingInterface[reconcile.Request]) error {
+	return s.source.Start(ctx, unsyncingQueue{TypedRateLimitingInterface: q, synced: s.synced})
+}
+
+// unsyncing returns the supplied event handler, wrapped so that the packages
+// it enqueues are fully reconciled rather than skipped because they and their
+// revisions are unchanged.
+func (r *Reconciler) unsyncing(h handler.EventHandler) handler.EventHandler {
+	return unsyncingEventHandler{handler: h, synced: &r.synced}
+}
+
+// unsyncingSource returns the supplied source, wrapped so that the packages it
+// enqueues are fully reconciled rather than skipped because they and their
+// revisions are unchanged.
+func (r *Reconciler) unsyncingSource(s source.Source) source.Source {
+	return unsyncingRawSource{source: s, synced: &r.synced}
+}
+
+// unsyncingResyncs returns the supplied event handler, wrapped so that the
+// packages it enqueues because the cache was periodically resynced are fully
+// reconciled rather than skipped because they and their revisions are
+// unchanged. Packages it enqueues because they or their revisions changed may
+// still be skipped.
+func (r *Reconciler) unsyncingResyncs(h handler.EventHandler) handler.EventHandler {
+	return resyncUnsyncingEventHandler{handler: h, synced: &r.synced}
+}
@@ -0,0 +1,136 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"context"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+)
+
+func TestUnsyncing(t *testing.T) {
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}}
+
+	cases := map[string]struct {
+		reason  string
+		enqueue func(r *Reconciler, q workqueue.TypedRateLimitingInterface[reconcile.Request])
+	}{
+		"EventHandler": {
+			reason: "Packages enqueued by a wrapped event handler should be fully reconciled.",
+			enqueue: func(r *Reconciler, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+				h := r.unsyncing(handler.EnqueueRequestsFromMapFunc(func(_ context.Context, _ client.Object) []reconcile.Request {
+					return []reconcile.Request{req}
+				}))
+				h.Generic(context.Background(), event.GenericEvent{Object: &v1.Configuration{}}, q)
+			},
+		},
+		"Source": {
+			reason: "Packages enqueued by a wrapped source should be fully reconciled.",
+			enqueue: func(r *Reconciler, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+				s := r.unsyncingSource(source.Func(func(_ context.Context, q workqueue.TypedRateLimitingInterface[reconcile.Request]) error {
+					q.Add(req)
+					return nil
+				}))
+				if err := s.Start(context.Background(), q); err != nil {
+					t.Errorf("Start(...): want no error, got %v", err)
+				}
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			q := workqueue.NewTypedRateLimitingQueue(workqueue.DefaultTypedControllerRateLimiter[reconcile.Request]())
+			defer q.ShutDown()
+
+			r := &Reconciler{}
+			r.synced.Store("test", syncedPackage{})
+			r.synced.Store("other", syncedPackage{})
+
+			tc.enqueue(r, q)
+
+			if q.Len() != 1 {
+				t.Errorf("\n%s\nwant package enqueued once, got queue length %d", tc.reason, q.Len())
+			}
+			if _, ok := r.synced.Load("test"); ok {
+				t.Errorf("\n%s\nwant enqueued package's synced state forgotten", tc.reason)
+			}
+			if _, ok := r.synced.Load("other"); !ok {
+				t.Errorf("\n%s\nwant other package's synced state kept", tc.reason)
+			}
+		})
+	}
+}
+
+func TestUnsyncingResyncs(t *testing.T) {
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}}
+
+	withResourceVersion := func(rv string) *v1.Configuration {
+		p := &v1.Configuration{}
+		p.SetName("test")
+		p.SetResourceVersion(rv)
+		return p
+	}
+
+	cases := map[string]struct {
+		reason string
+		e      event.UpdateEvent
+		synced bool
+	}{
+		"Resync": {
+			reason: "Packages enqueued because the cache was resynced should be fully reconciled.",
+			e:      event.UpdateEvent{ObjectOld: withResourceVersion("1"), ObjectNew: withResourceVersion("1")},
+			synced: false,
+		},
+		"Changed": {
+			reason: "Packages enqueued because they changed may skip a full reconcile.",
+			e:      event.UpdateEvent{ObjectOld: withResourceVersion("1"), ObjectNew: withResourceVersion("2")},
+			synced: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			q := workqueue.NewTypedRateLimitingQueue(workqueue.DefaultTypedControllerRateLimiter[reconcile.Request]())
+			defer q.ShutDown()
+
+			r := &Reconciler{}
+			r.synced.Store("test", syncedPackage{})
+
+			h := r.unsyncingResyncs(handler.EnqueueRequestsFromMapFunc(func(_ context.Context, _ client.Object) []reconcile.Request {
+				return []reconcile.Request{req}
+			}))
+			h.Update(context.Background(), tc.e, q)
+
+			if q.Len() != 1 {
+				t.Errorf("\n%s\nwant package enqueued once, got queue length %d", tc.reason, q.Len())
+			}
+			if _, ok := r.synced.Load("test"); ok != tc.synced {
+				t.Errorf("\n%s\nwant package's synced state kept %t, got %t", tc.reason, tc.synced, ok)
+			}
+		})
+	}
+}
@@ -23,6 +23,7 @@ import (
 	"path/filepath"
 
 	"github.com/google/go-containerregistry/pkg/name"
+	conregv1 "github.com/google/go-containerregistry/pkg/v1"
 	"github.com/google/go-containerregistry/pkg/v1/mutate"
 	"github.com/google/go-containerregistry/pkg/v1/validate"
 
@@ -36,6 +37,7 @@ import (
 const (
 	errBadReference            = "package tag is not a valid reference"
 	errFetchPackage            = "failed to fetch package from remote"
+	errLoadPackage             = "failed to load local package"
 	errGetManifest             = "failed to get package image manifest from remote"
 	errFetchLayer              = "failed to fetch annotated base layer from remote"
 	errGetUncompressed         = "failed to get uncompressed contents from layer"
@@ -46,6 +48,11 @@ const (
 	errValidateImage           = "invalid package image"
 )
 
+const (
+	errFetchIndex     = "failed to fetch package index from remote"
+	errSelectPlatform = "failed to select platform-specific package from package index"
+)
+
 const (
 	layerAnnotation     = "io.crossplane.xpkg"
 	baseAnnotationValue = "base"
@@ -57,6 +64,8 @@ const (
 type ImageBackend struct {
 	registry string
 	fetcher  xpkg.Fetcher
+	platform *conregv1.Platform
+	localDir string
 }
 
 // An ImageBackendOption sets configuration for an image backend.
@@ -69,6 +78,24 @@ func WithDefaultRegistry(registry string) ImageBackendOption {
 	}
 }
 
+// WithPlatform sets the platform an image backend selects when a package is an
+// OCI index of platform-specific packages. Without a platform the backend
+// fetches whatever image the registry resolves the package to.
+func WithPlatform(p *conregv1.Platform) ImageBackendOption {
+	return func(i *ImageBackend) {
+		i.platform = p
+	}
+}
+
+// WithLocalPackageDir sets the directory an image backend loads local packages
+// - those with a file:// source - from. Without a directory the backend
+// refuses to load local packages.
+func WithLocalPackageDir(dir string) ImageBackendOption {
+	return func(i *ImageBackend) {
+		i.localDir = dir
+	}
+}
+
 // NewImageBackend creates a new image backend.
 func NewImageBackend(fetcher xpkg.Fetcher, opts ...ImageBackendOption) *ImageBackend {
 	i := &ImageBackend{
@@ -94,20 +121,9 @@ func (i *ImageBackend) Init(ctx context.Context, bo ...parser.BackendOption) (io
 	for _, o := range bo {
 		o(n)
 	}
-	// Use the package recorded in the status rather than the one from the spec,
-	// since it may have been rewritten by an image config.
-	ref, err := name.ParseReference(n.pr.GetResolvedSource(), name.WithDefaultRegistry(i.registry))
-	if err != nil {
-		return nil, errors.Wrap(err, errBadReference)
-	}
-	// Fetch image from registry.
-	ps := v1.RefNames(n.pr.GetPackagePullSecrets())
-	if n.pullSecretFromConfig != "" {
-		ps = append(ps, n.pullSecretFromConfig)
-	}
-	img, err := i.fetcher.Fetch(ctx, ref, ps...)
+	img, err := i.image(ctx, n)
 	if err != nil {
-		return nil, errors.Wrap(err, errFetchPackage)
+		return nil, err
 	}
 	// Get image manifest.
 	manifest, err := img.Manifest()
@@ -180,6 +196,64 @@ func (i *ImageBackend) Init(ctx context.Context, bo ...parser.BackendOption) (io
 	return xpkg.JoinedReadCloser(t, tarc), nil
 }
 
+// image returns the package image for the revision held by the supplied nested
+// backend.
+func (i *ImageBackend) image(ctx context.Context, n *nestedBackend) (conregv1.Image, error) {
+	// Use the package recorded in the status rather than the one from the spec,
+	// since it may have been rewritten by an image config.
+	src := n.pr.GetResolvedSource()
+
+	// Load local packages from disk.
+	if path, ok := xpkg.LocalPackagePath(src); ok {
+		img, err := xpkg.LocalImage(i.localDir, path)
+		return img, errors.Wrap(err, errLoadPackage)
+	}
+
+	ref, err := name.ParseReference(src, name.WithDefaultRegistry(i.registry))
+	if err != nil {
+		return nil, errors.Wrap(err, errBadReference)
+	}
+	// Fetch image from registry.
+	ps := v1.RefNames(n.pr.GetPackagePullSecrets())
+	if n.pullSecretFromConfig != "" {
+		ps = append(ps, n.pullSecretFromConfig)
+	}
+	if i.platform != nil {
+		if ref, err = i.platformPackage(ctx, n.pr, ref, ps...); err != nil {
+			return nil, err
+		}
+	}
+	img, err := i.fetcher.Fetch(ctx, ref, ps...)
+	return img, errors.Wrap(err, errFetchPackage)
+}
+
+// platformPackage returns a reference to the package for our platform if the
+// supplied reference is an OCI index of platform-specific packages. Otherwise
+// it returns the supplied reference.
+func (i *ImageBackend) platformPackage(ctx context.Context, pr v1.PackageRevision, ref name.Reference, secrets ...string) (name.Reference, error) {
+	// Use the package the package manager resolved the revision to, if it
+	// recorded one, in case the index changed since.
+	if d := pr.GetResolvedDigest(); d != "" {
+		return ref.Context().Digest(d), nil
+	}
+	d, err := i.fetcher.Head(ctx, ref, secrets...)
+	if err != nil || d == nil {
+		return nil, errors.Wrap(err, errFetchPackage)
+	}
+	if !d.MediaType.IsIndex() {
+		return ref, nil
+	}
+	idx, err := i.fetcher.Index(ctx, ref, secrets...)
+	if err != nil {
+		return nil, errors.Wrap(err, errFetchIndex)
+	}
+	pd, err := xpkg.PlatformPackage(idx, *i.platform)
+	if err != nil {
+		return nil, errors.Wrap(err, errSelectPlatform)
+	}
+	return ref.Context().Digest(pd.Digest.String()), nil
+}
+
 // nestedBackend is a nop parser backend that conforms to the parser backend
 // interface to allow holding intermediate data passed via parser backend
 // options.
@@ -22,6 +22,7 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	conregv1 "github.com/google/go-containerregistry/pkg/v1"
 	"github.com/google/go-containerregistry/pkg/v1/empty"
 	"github.com/google/go-containerregistry/pkg/v1/mutate"
 	"github.com/google/go-containerregistry/pkg/v1/random"
@@ -53,6 +54,9 @@ func TestImageBackend(t *testing.T) {
 		},
 	})
 
+	arm64 := &conregv1.Platform{OS: "linux", Architecture: "arm64"}
+	randIdx := mutate.AppendManifests(empty.Index, mutate.IndexAddendum{Add: randImg, Descriptor: conregv1.Descriptor{Platform: arm64}})
+
 	// TODO(phisco): uncomment when https://github.com/google/go-containerregistry/pull/1758 is merged
 	// streamCont := "somestreamofyaml"
 	// tarBuf := new(bytes.Buffer)
@@ -73,8 +77,9 @@ func TestImageBackend(t *testing.T) {
 	// packImg, _ := mutate.AppendLayers(empty.Image, packLayer)
 
 	type args struct {
-		f    xpkg.Fetcher
-		opts []parser.BackendOption
+		f        xpkg.Fetcher
+		platform *conregv1.Platform
+		opts     []parser.BackendOption
 	}
 
 	cases := map[string]struct {
@@ -174,6 +179,69 @@ func TestImageBackend(t *testing.T) {
 			},
 			want: errors.Wrap(errBoom, errFetchPackage),
 		},
+		"ErrNoPlatformPackage": {
+			reason: "Should return error if the package is an index of packages with no package for our platform.",
+			args: args{
+				f: &fake.MockFetcher{
+					MockHead:  fake.NewMockHeadFn(&conregv1.Descriptor{MediaType: types.OCIImageIndex}, nil),
+					MockIndex: fake.NewMockIndexFn(randIdx, nil),
+				},
+				platform: &conregv1.Platform{OS: "linux", Architecture: "amd64"},
+				opts: []parser.BackendOption{PackageRevision(&v1.ProviderRevision{
+					Spec: v1.ProviderRevisionSpec{
+						PackageRevisionSpec: v1.PackageRevisionSpec{
+							Package: "test/test:latest",
+						},
+					},
+					Status: v1.PackageRevisionStatus{
+						ResolvedPackage: "test/test:latest",
+					},
+				})},
+			},
+			want: errors.Wrap(errors.New("index contains no package for platform linux/amd64"), errSelectPlatform),
+		},
+		"ErrFetchIndex": {
+			reason: "Should return error if the package is an index of packages that we fail to fetch.",
+			args: args{
+				f: &fake.MockFetcher{
+					MockHead:  fake.NewMockHeadFn(&conregv1.Descriptor{MediaType: types.OCIImageIndex}, nil),
+					MockIndex: fake.NewMockIndexFn(nil, errBoom),
+				},
+				platform: arm64,
+				opts: []parser.BackendOption{PackageRevision(&v1.ProviderRevision{
+					Spec: v1.ProviderRevisionSpec{
+						PackageRevisionSpec: v1.PackageRevisionSpec{
+							Package: "test/test:latest",
+						},
+					},
+					Status: v1.PackageRevisionStatus{
+						ResolvedPackage: "test/test:latest",
+					},
+				})},
+			},
+			want: errors.Wrap(errBoom, errFetchIndex),
+		},
+		"ResolvedDigest": {
+			reason: "Should fetch the package the revision was resolved to without inspecting the index again.",
+			args: args{
+				f: &fake.MockFetcher{
+					MockFetch: fake.NewMockFetchFn(randImg, nil),
+				},
+				platform: arm64,
+				opts: []parser.BackendOption{PackageRevision(&v1.ProviderRevision{
+					Spec: v1.ProviderRevisionSpec{
+						PackageRevisionSpec: v1.PackageRevisionSpec{
+							Package: "test/test:latest",
+						},
+					},
+					Status: v1.PackageRevisionStatus{
+						ResolvedPackage: "test/test:latest",
+						ResolvedDigest:  "sha256:ecc25c121431dfc7058754427f97c034ecde26d4aafa0da16d258090e0443904",
+					},
+				})},
+			},
+			want: errors.Wrapf(io.EOF, errFmtNoPackageFileFound, 1, true),
+		},
 		// TODO(phisco): uncomment when https://github.com/google/go-containerregistry/pull/1758 is merged
 		// "SuccessFetchPackage": {
 		// 	reason: "Should not return error is package is not in cache but is fetched successfully.",
@@ -192,7 +260,7 @@ func TestImageBackend(t *testing.T) {
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			b := NewImageBackend(tc.args.f)
+			b := NewImageBackend(tc.args.f, WithPlatform(tc.args.platform))
 			rc, err := b.Init(context.TODO(), tc.args.opts...)
 			if err == nil && rc != nil {
 				_, err = io.ReadAll(rc)
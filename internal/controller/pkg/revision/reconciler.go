@@ -284,6 +284,9 @@ func SetupProviderRevision(mgr ctrl.Manager, o controller.Options) error {
 		For(&v1.ProviderRevision{}).
 		Owns(&corev1.Secret{}). // Watch secret changes to react if pull or cert secrets change.
 		Watches(&v1beta1.Lock{}, EnqueuePackageRevisionsForLock(mgr.GetClient(), &v1.ProviderRevisionList{}, log)).
+		Watches(&v1.Provider{}, EnqueueDependentPackageRevisionsForPackage(mgr.GetClient(), &v1.ProviderRevisionList{}, log)).
+		Watches(&v1.Configuration{}, EnqueueDependentPackageRevisionsForPackage(mgr.GetClient(), &v1.ProviderRevisionList{}, log)).
+		Watches(&v1.Function{}, EnqueueDependentPackageRevisionsForPackage(mgr.GetClient(), &v1.ProviderRevisionList{}, log)).
 		Watches(&v1beta1.ImageConfig{}, EnqueuePackageRevisionsForImageConfig(mgr.GetClient(), &v1.ProviderRevisionList{}, log))
 
 	ro := []ReconcilerOption{
@@ -292,7 +295,7 @@ func SetupProviderRevision(mgr ctrl.Manager, o controller.Options) error {
 		WithEstablisher(NewAPIEstablisher(mgr.GetClient(), o.Namespace, o.MaxConcurrentPackageEstablishers)),
 		WithNewPackageRevisionFn(nr),
 		WithParser(parser.New(metaScheme, objScheme)),
-		WithParserBackend(NewImageBackend(fetcher, WithDefaultRegistry(o.DefaultRegistry))),
+		WithParserBackend(NewImageBackend(fetcher, WithDefaultRegistry(o.DefaultRegistry), WithPlatform(o.Platform), WithLocalPackageDir(o.LocalPackageDir))),
 		WithConfigStore(xpkg.NewImageConfigStore(mgr.GetClient(), o.Namespace)),
 		WithLinter(xpkg.NewProviderLinter()),
 		WithLogger(log),
@@ -336,7 +339,7 @@ func SetupConfigurationRevision(mgr ctrl.Manager, o controller.Options) error {
 		WithNewPackageRevisionFn(nr),
 		WithEstablisher(NewAPIEstablisher(mgr.GetClient(), o.Namespace, o.MaxConcurrentPackageEstablishers)),
 		WithParser(parser.New(metaScheme, objScheme)),
-		WithParserBackend(NewImageBackend(f, WithDefaultRegistry(o.DefaultRegistry))),
+		WithParserBackend(NewImageBackend(f, WithDefaultRegistry(o.DefaultRegistry), WithPlatform(o.Platform), WithLocalPackageDir(o.LocalPackageDir))),
 		WithConfigStore(xpkg.NewImageConfigStore(mgr.GetClient(), o.Namespace)),
 		WithLinter(xpkg.NewConfigurationLinter()),
 		WithLogger(log),
@@ -350,6 +353,9 @@ func SetupConfigurationRevision(mgr ctrl.Manager, o controller.Options) error {
 		Named(name).
 		For(&v1.ConfigurationRevision{}).
 		Watches(&v1beta1.Lock{}, EnqueuePackageRevisionsForLock(mgr.GetClient(), &v1.ConfigurationRevisionList{}, log)).
+		Watches(&v1.Provider{}, EnqueueDependentPackageRevisionsForPackage(mgr.GetClient(), &v1.ConfigurationRevisionList{}, log)).
+		Watches(&v1.Configuration{}, EnqueueDependentPackageRevisionsForPackage(mgr.GetClient(), &v1.ConfigurationRevisionList{}, log)).
+		Watches(&v1.Function{}, EnqueueDependentPackageRevisionsForPackage(mgr.GetClient(), &v1.ConfigurationRevisionList{}, log)).
 		Watches(&v1beta1.ImageConfig{}, EnqueuePackageRevisionsForImageConfig(mgr.GetClient(), &v1.ConfigurationRevisionList{}, log)).
 		WithOptions(o.ForControllerRuntime()).
 		Complete(ratelimiter.NewReconciler(name, errors.WithSilentRequeueOnConflict(r), o.GlobalRateLimiter))
@@ -384,6 +390,9 @@ func SetupFunctionRevision(mgr ctrl.Manager, o controller.Options) error {
 		For(&v1.FunctionRevision{}).
 		Owns(&corev1.Secret{}). // Watch secret changes to react if pull or cert secrets change.
 		Watches(&v1beta1.Lock{}, EnqueuePackageRevisionsForLock(mgr.GetClient(), &v1.FunctionRevisionList{}, log)).
+		Watches(&v1.Provider{}, EnqueueDependentPackageRevisionsForPackage(mgr.GetClient(), &v1.FunctionRevisionList{}, log)).
+		Watches(&v1.Configuration{}, EnqueueDependentPackageRevisionsForPackage(mgr.GetClient(), &v1.FunctionRevisionList{}, log)).
+		Watches(&v1.Function{}, EnqueueDependentPackageRevisionsForPackage(mgr.GetClient(), &v1.FunctionRevisionList{}, log)).
 		Watches(&v1beta1.ImageConfig{}, EnqueuePackageRevisionsForImageConfig(mgr.GetClient(), &v1.FunctionRevisionList{}, log))
 
 	ro := []ReconcilerOption{
@@ -392,7 +401,7 @@ func SetupFunctionRevision(mgr ctrl.Manager, o controller.Options) error {
 		WithEstablisher(NewAPIEstablisher(mgr.GetClient(), o.Namespace, o.MaxConcurrentPackageEstablishers)),
 		WithNewPackageRevisionFn(nr),
 		WithParser(parser.New(metaScheme, objScheme)),
-		WithParserBackend(NewImageBackend(fetcher, WithDefaultRegistry(o.DefaultRegistry))),
+		WithParserBackend(NewImageBackend(fetcher, WithDefaultRegistry(o.DefaultRegistry), WithPlatform(o.Platform), WithLocalPackageDir(o.LocalPackageDir))),
 		WithConfigStore(xpkg.NewImageConfigStore(mgr.GetClient(), o.Namespace)),
 		WithLinter(xpkg.NewFunctionLinter()),
 		WithLogger(log),
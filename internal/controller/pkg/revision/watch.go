@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"strings"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
@@ -91,3 +92,74 @@ func EnqueuePackageRevisionsForLock(kube client.Client, l v1.PackageRevisionList
 		return matches
 	})
 }
+
+// EnqueueDependentPackageRevisionsForPackage enqueues a reconcile for all
+// package revisions that depend on a package when that package is healthy.
+// This allows dependents that are waiting on the package to converge without
+// waiting for their next poll.
+func EnqueueDependentPackageRevisionsForPackage(kube client.Client, l v1.PackageRevisionList, log logging.Logger) handler.EventHandler {
+	return handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, o client.Object) []reconcile.Request {
+		p, ok := o.(v1.Package)
+		if !ok {
+			return nil
+		}
+		// We only care about packages that have become healthy.
+		if p.GetCondition(v1.TypeHealthy).Status != corev1.ConditionTrue || p.GetCurrentRevision() == "" {
+			return nil
+		}
+
+		lock := &v1beta1.Lock{}
+		if err := kube.Get(ctx, types.NamespacedName{Name: lockName}, lock); err != nil {
+			// Nothing we can do, except logging, if we can't get the Lock.
+			log.Debug("Cannot get Lock while attempting to enqueue dependents of package", "error", err)
+			return nil
+		}
+
+		// The package's current revision is in the Lock under its own name.
+		// We use its source to find the packages that depend on it.
+		source := ""
+		for _, lp := range lock.Packages {
+			if lp.Name == p.GetCurrentRevision() {
+				source = lp.Source
+				break
+			}
+		}
+		if source == "" {
+			return nil
+		}
+
+		dependents := map[string]bool{}
+		for _, lp := range lock.Packages {
+			for _, dep := range lp.Dependencies {
+				if dep.Identifier() == source {
+					dependents[lp.Name] = true
+				}
+			}
+		}
+		if len(dependents) == 0 {
+			return nil
+		}
+
+		rl := l.DeepCopyObject().(v1.PackageRevisionList) //nolint:forcetypeassert // Guaranteed to be PackageRevisionList.
+		if err := kube.List(ctx, rl); err != nil {
+			// Nothing we can do, except logging, if we can't list
+			// package revisions.
+			log.Debug("Cannot list package revisions while attempting to enqueue dependents of package", "error", err)
+			return nil
+		}
+
+		var matches []reconcile.Request
+		for _, rev := range rl.GetRevisions() {
+			if !dependents[rev.GetName()] {
+				continue
+			}
+			log.Debug("Enqueuing for healthy dependency",
+				"revision-type", fmt.Sprintf("%T", rev),
+				"revision-name", rev.GetName(),
+				"dependency-type", fmt.Sprintf("%T", p),
+				"dependency-name", p.GetName())
+			matches = append(matches, reconcile.Request{NamespacedName: types.NamespacedName{Name: rev.GetName()}})
+		}
+		return matches
+	})
+}
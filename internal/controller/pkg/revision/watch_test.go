@@ -0,0 +1,149 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package revision
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	kevent "sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+	"github.com/crossplane/crossplane/apis/pkg/v1beta1"
+)
+
+func TestEnqueueDependentPackageRevisionsForPackage(t *testing.T) {
+	type args struct {
+		kube  client.Client
+		event kevent.UpdateEvent
+	}
+	type want struct {
+		added []any
+	}
+
+	healthy := &v1.Provider{}
+	healthy.SetName("provider-nop")
+	healthy.SetCurrentRevision("provider-nop-1234")
+	healthy.SetConditions(v1.Healthy())
+
+	unhealthy := healthy.DeepCopy()
+	unhealthy.SetConditions(v1.Unhealthy())
+
+	lock := func(o client.Object) error {
+		l := o.(*v1beta1.Lock) //nolint:forcetypeassert // Guaranteed to be a Lock.
+		l.Packages = []v1beta1.LockPackage{
+			{
+				Name:   "provider-nop-1234",
+				Source: "xpkg.crossplane.io/crossplane/provider-nop",
+			},
+			{
+				Name:   "config-nop-a-5678",
+				Source: "xpkg.crossplane.io/crossplane/config-nop-a",
+				Dependencies: []v1beta1.Dependency{
+					{Package: "xpkg.crossplane.io/crossplane/provider-nop"},
+				},
+			},
+			{
+				Name:   "config-nop-b-5678",
+				Source: "xpkg.crossplane.io/crossplane/config-nop-b",
+			},
+		}
+		return nil
+	}
+
+	revisions := func(o client.ObjectList) error {
+		l := o.(*v1.ConfigurationRevisionList) //nolint:forcetypeassert // Guaranteed to be a ConfigurationRevisionList.
+		a := v1.ConfigurationRevision{}
+		a.SetName("config-nop-a-5678")
+		b := v1.ConfigurationRevision{}
+		b.SetName("config-nop-b-5678")
+		l.Items = []v1.ConfigurationRevision{a, b}
+		return nil
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NotHealthy": {
+			reason: "We should not enqueue anything when the package is not healthy.",
+			args: args{
+				kube: &test.MockClient{
+					MockGet:  test.NewMockGetFn(nil, lock),
+					MockList: test.NewMockListFn(nil, revisions),
+				},
+				event: kevent.UpdateEvent{ObjectOld: unhealthy, ObjectNew: unhealthy},
+			},
+			want: want{},
+		},
+		"GetLockError": {
+			reason: "We should not enqueue anything when we can't get the Lock.",
+			args: args{
+				kube: &test.MockClient{
+					MockGet:  test.NewMockGetFn(errors.New("boom")),
+					MockList: test.NewMockListFn(nil, revisions),
+				},
+				event: kevent.UpdateEvent{ObjectOld: unhealthy, ObjectNew: healthy},
+			},
+			want: want{},
+		},
+		"EnqueueDependents": {
+			reason: "We should enqueue only the revisions that depend on the healthy package.",
+			args: args{
+				kube: &test.MockClient{
+					MockGet:  test.NewMockGetFn(nil, lock),
+					MockList: test.NewMockListFn(nil, revisions),
+				},
+				event: kevent.UpdateEvent{ObjectOld: unhealthy, ObjectNew: healthy},
+			},
+			want: want{
+				added: []any{reconcile.Request{NamespacedName: types.NamespacedName{Name: "config-nop-a-5678"}}},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			h := EnqueueDependentPackageRevisionsForPackage(tc.args.kube, &v1.ConfigurationRevisionList{}, logging.NewNopLogger())
+			q := rateLimitingQueueMock{}
+			h.Update(context.Background(), tc.args.event, &q)
+
+			if diff := cmp.Diff(tc.want.added, q.added); diff != "" {
+				t.Errorf("\n%s\nh.Update(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+type rateLimitingQueueMock struct {
+	workqueue.TypedRateLimitingInterface[reconcile.Request]
+	added []any
+}
+
+func (f *rateLimitingQueueMock) Add(item reconcile.Request) {
+	f.added = append(f.added, item)
+}
@@ -83,7 +83,10 @@ var _ xpkg.Fetcher = &MockFetcher{}
 type MockFetcher struct {
 	MockFetch func() (v1.Image, error)
 	MockHead  func(name.Reference) (*v1.Descriptor, error)
+	MockIndex func(name.Reference) (v1.ImageIndex, error)
 	MockTags  func(name.Reference) ([]string, error)
+
+	MockReferrers func(name.Digest) (v1.ImageIndex, error)
 }
 
 // NewMockFetchFn creates a new MockFetch function for MockFetcher.
@@ -106,6 +109,16 @@ func (m *MockFetcher) Head(_ context.Context, ref name.Reference, _ ...string) (
 	return m.MockHead(ref)
 }
 
+// NewMockIndexFn creates a new MockIndex function for MockFetcher.
+func NewMockIndexFn(idx v1.ImageIndex, err error) func(name.Reference) (v1.ImageIndex, error) {
+	return func(_ name.Reference) (v1.ImageIndex, error) { return idx, err }
+}
+
+// Index calls the underlying MockIndex.
+func (m *MockFetcher) Index(_ context.Context, ref name.Reference, _ ...string) (v1.ImageIndex, error) {
+	return m.MockIndex(ref)
+}
+
 // NewMockTagsFn creates a new MockTags function for MockFetcher.
 func NewMockTagsFn(tags []string, err error) func(name.Reference) ([]string, error) {
 	return func(_ name.Reference) ([]string, error) { return tags, err }
@@ -115,3 +128,13 @@ func NewMockTagsFn(tags []string, err error) func(name.Reference) ([]string, err
 func (m *MockFetcher) Tags(_ context.Context, ref name.Reference, _ ...string) ([]string, error) {
 	return m.MockTags(ref)
 }
+
+// NewMockReferrersFn creates a new MockReferrers function for MockFetcher.
+func NewMockReferrersFn(idx v1.ImageIndex, err error) func(name.Digest) (v1.ImageIndex, error) {
+	return func(_ name.Digest) (v1.ImageIndex, error) { return idx, err }
+}
+
+// Referrers calls the underlying MockReferrers.
+func (m *MockFetcher) Referrers(_ context.Context, ref name.Digest, _ ...string) (v1.ImageIndex, error) {
+	return m.MockReferrers(ref)
+}
@@ -21,7 +21,10 @@ import (
 	"crypto/tls"
 	"crypto/x509"
 	"io"
+	"net"
 	"net/http"
+	"net/url"
+	"strings"
 
 	"github.com/google/go-containerregistry/pkg/authn/k8schain"
 	"github.com/google/go-containerregistry/pkg/name"
@@ -29,6 +32,7 @@ import (
 	"github.com/google/go-containerregistry/pkg/v1/empty"
 	"github.com/google/go-containerregistry/pkg/v1/remote"
 	"github.com/sirupsen/logrus"
+	"golang.org/x/net/http/httpproxy"
 	"k8s.io/client-go/kubernetes"
 
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
@@ -47,7 +51,9 @@ func init() { //nolint:gochecknoinits // See comment below.
 type Fetcher interface {
 	Fetch(ctx context.Context, ref name.Reference, secrets ...string) (v1.Image, error)
 	Head(ctx context.Context, ref name.Reference, secrets ...string) (*v1.Descriptor, error)
+	Index(ctx context.Context, ref name.Reference, secrets ...string) (v1.ImageIndex, error)
 	Tags(ctx context.Context, ref name.Reference, secrets ...string) ([]string, error)
+	Referrers(ctx context.Context, ref name.Digest, secrets ...string) (v1.ImageIndex, error)
 }
 
 // K8sFetcher uses kubernetes credentials to fetch package images.
@@ -57,6 +63,8 @@ type K8sFetcher struct {
 	serviceAccount string
 	transport      http.RoundTripper
 	userAgent      string
+	proxy          *url.URL
+	retry          *RetryPolicy
 }
 
 // FetcherOpt can be used to add optional parameters to NewK8sFetcher.
@@ -75,6 +83,40 @@ func WithCustomCA(rootCAs *x509.CertPool) FetcherOpt {
 	}
 }
 
+// WithProxy is a FetcherOpt that can be used to fetch packages through the
+// supplied HTTP(S) proxy, regardless of the proxy environment variables. The
+// proxy URL may include credentials used to authenticate to the proxy. Hosts
+// matching the supplied no-proxy list are fetched from directly. Entries in the
+// no-proxy list use the same format as the NO_PROXY environment variable.
+func WithProxy(proxyURL string, noProxy ...string) FetcherOpt {
+	return func(k *K8sFetcher) error {
+		t, ok := k.transport.(*http.Transport)
+		if !ok {
+			return errors.New("Fetcher transport is not an HTTP transport")
+		}
+
+		u, err := url.Parse(proxyURL)
+		if err != nil {
+			return errors.Wrap(err, "cannot parse proxy URL")
+		}
+		if u.Scheme != "http" && u.Scheme != "https" {
+			return errors.Errorf("proxy URL scheme must be http or https, not %q", u.Scheme)
+		}
+
+		cfg := &httpproxy.Config{
+			HTTPProxy:  proxyURL,
+			HTTPSProxy: proxyURL,
+			NoProxy:    strings.Join(noProxy, ","),
+		}
+		pf := cfg.ProxyFunc()
+		t.Proxy = func(r *http.Request) (*url.URL, error) {
+			return pf(r.URL)
+		}
+		k.proxy = u
+		return nil
+	}
+}
+
 // WithUserAgent is a FetcherOpt that can be used to set the user agent on all HTTP requests.
 func WithUserAgent(userAgent string) FetcherOpt {
 	return func(k *K8sFetcher) error {
@@ -88,6 +130,16 @@ func WithUserAgent(userAgent string) FetcherOpt {
 	}
 }
 
+// WithRetryPolicy is a FetcherOpt that retries registry requests that fail
+// transiently according to the supplied policy, so that brief network blips
+// don't fail a fetch. Retries stop when the fetch's context is done.
+func WithRetryPolicy(p RetryPolicy) FetcherOpt {
+	return func(k *K8sFetcher) error {
+		k.retry = &p
+		return nil
+	}
+}
+
 // WithNamespace is a FetcherOpt that sets the Namespace for fetching package
 // pull secrets.
 func WithNamespace(ns string) FetcherOpt {
@@ -126,6 +178,15 @@ func NewK8sFetcher(client kubernetes.Interface, opts ...FetcherOpt) (*K8sFetcher
 	return k, nil
 }
 
+// roundTripper returns the transport used for registry requests.
+func (i *K8sFetcher) roundTripper() http.RoundTripper {
+	var rt http.RoundTripper = i.transport
+	if i.retry != nil && i.retry.MaxAttempts > 1 {
+		rt = &retryTransport{RoundTripper: rt, policy: *i.retry}
+	}
+	return &headerRecordingTransport{rt}
+}
+
 // Fetch fetches a package image.
 func (i *K8sFetcher) Fetch(ctx context.Context, ref name.Reference, secrets ...string) (v1.Image, error) {
 	auth, err := k8schain.New(ctx, i.client, k8schain.Options{
@@ -136,12 +197,13 @@ func (i *K8sFetcher) Fetch(ctx context.Context, ref name.Reference, secrets ...s
 	if err != nil {
 		return nil, err
 	}
-	return remote.Image(ref,
+	img, err := remote.Image(ref,
 		remote.WithAuthFromKeychain(auth),
-		remote.WithTransport(i.transport),
+		remote.WithTransport(i.roundTripper()),
 		remote.WithContext(ctx),
 		remote.WithUserAgent(i.userAgent),
 	)
+	return img, i.proxyError(err)
 }
 
 // Head fetches a package descriptor.
@@ -156,25 +218,45 @@ func (i *K8sFetcher) Head(ctx context.Context, ref name.Reference, secrets ...st
 	}
 	d, err := remote.Head(ref,
 		remote.WithAuthFromKeychain(auth),
-		remote.WithTransport(i.transport),
+		remote.WithTransport(i.roundTripper()),
 		remote.WithContext(ctx),
 		remote.WithUserAgent(i.userAgent),
 	)
 	if err != nil || d == nil {
 		rd, gErr := remote.Get(ref,
 			remote.WithAuthFromKeychain(auth),
-			remote.WithTransport(i.transport),
+			remote.WithTransport(i.roundTripper()),
 			remote.WithContext(ctx),
 			remote.WithUserAgent(i.userAgent),
 		)
 		if gErr != nil {
-			return nil, errors.Wrapf(gErr, "failed to fetch package descriptor with a GET request after a previous HEAD request failure: %v", err)
+			return nil, i.proxyError(errors.Wrapf(gErr, "failed to fetch package descriptor with a GET request after a previous HEAD request failure: %v", err))
 		}
 		return &rd.Descriptor, nil
 	}
 	return d, nil
 }
 
+// Index fetches a package index, i.e. an OCI index of platform-specific
+// packages.
+func (i *K8sFetcher) Index(ctx context.Context, ref name.Reference, secrets ...string) (v1.ImageIndex, error) {
+	auth, err := k8schain.New(ctx, i.client, k8schain.Options{
+		Namespace:          i.namespace,
+		ServiceAccountName: i.serviceAccount,
+		ImagePullSecrets:   secrets,
+	})
+	if err != nil {
+		return nil, err
+	}
+	idx, err := remote.Index(ref,
+		remote.WithAuthFromKeychain(auth),
+		remote.WithTransport(i.roundTripper()),
+		remote.WithContext(ctx),
+		remote.WithUserAgent(i.userAgent),
+	)
+	return idx, i.proxyError(err)
+}
+
 // Tags fetches a package's tags.
 func (i *K8sFetcher) Tags(ctx context.Context, ref name.Reference, secrets ...string) ([]string, error) {
 	auth, err := k8schain.New(ctx, i.client, k8schain.Options{
@@ -185,12 +267,43 @@ func (i *K8sFetcher) Tags(ctx context.Context, ref name.Reference, secrets ...st
 	if err != nil {
 		return nil, err
 	}
-	return remote.List(ref.Context(),
+	tags, err := remote.List(ref.Context(),
 		remote.WithAuthFromKeychain(auth),
-		remote.WithTransport(i.transport),
+		remote.WithTransport(i.roundTripper()),
 		remote.WithContext(ctx),
 		remote.WithUserAgent(i.userAgent),
 	)
+	return tags, i.proxyError(err)
+}
+
+// Referrers fetches an index of the artifacts that refer to a package image,
+// for example its signatures and attestations.
+func (i *K8sFetcher) Referrers(ctx context.Context, ref name.Digest, secrets ...string) (v1.ImageIndex, error) {
+	auth, err := k8schain.New(ctx, i.client, k8schain.Options{
+		Namespace:          i.namespace,
+		ServiceAccountName: i.serviceAccount,
+		ImagePullSecrets:   secrets,
+	})
+	if err != nil {
+		return nil, err
+	}
+	idx, err := remote.Referrers(ref,
+		remote.WithAuthFromKeychain(auth),
+		remote.WithTransport(i.roundTripper()),
+		remote.WithContext(ctx),
+		remote.WithUserAgent(i.userAgent),
+	)
+	return idx, i.proxyError(err)
+}
+
+// proxyError makes it clear that the supplied error was caused by failing to
+// connect to the configured proxy, if that's the case.
+func (i *K8sFetcher) proxyError(err error) error {
+	oe := &net.OpError{}
+	if i.proxy != nil && errors.As(err, &oe) && oe.Op == "proxyconnect" {
+		return errors.Wrapf(err, "cannot connect to proxy %s", i.proxy.Redacted())
+	}
+	return err
 }
 
 // NopFetcher always returns an empty image and never returns error.
@@ -211,7 +324,17 @@ func (n *NopFetcher) Head(_ context.Context, _ name.Reference, _ ...string) (*v1
 	return nil, nil
 }
 
+// Index returns an empty index and does not return error.
+func (n *NopFetcher) Index(_ context.Context, _ name.Reference, _ ...string) (v1.ImageIndex, error) {
+	return empty.Index, nil
+}
+
 // Tags returns a nil slice and does not return error.
 func (n *NopFetcher) Tags(_ context.Context, _ name.Reference, _ ...string) ([]string, error) {
 	return nil, nil
 }
+
+// Referrers returns an empty index and does not return error.
+func (n *NopFetcher) Referrers(_ context.Context, _ name.Digest, _ ...string) (v1.ImageIndex, error) {
+	return empty.Index, nil
+}
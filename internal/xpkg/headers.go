@@ -0,0 +1,122 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xpkg
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+)
+
+const (
+	// maxResponseHeaders is the maximum number of headers recorded from a
+	// registry response.
+	maxResponseHeaders = 32
+
+	// maxResponseHeaderValue is the maximum length of a recorded header
+	// value. Longer values are truncated.
+	maxResponseHeaderValue = 256
+
+	// redacted replaces the values of sensitive headers.
+	redacted = "REDACTED"
+)
+
+// Headers whose names contain any of these strings are redacted, since they
+// may contain credentials.
+var sensitiveHeaders = []string{"authorization", "cookie", "token", "secret", "key"}
+
+type responseHeadersKey struct{}
+
+// ResponseHeaders records the headers of the last registry response received
+// while fetching a package. It's intended for debugging registry issues, so
+// it redacts sensitive headers and bounds how many headers it records.
+type ResponseHeaders struct {
+	mu     sync.Mutex
+	header http.Header
+}
+
+// WithResponseHeaders returns a copy of the supplied context that records the
+// headers of registry responses received by fetchers using the context.
+func WithResponseHeaders(ctx context.Context) (context.Context, *ResponseHeaders) {
+	h := &ResponseHeaders{}
+	return context.WithValue(ctx, responseHeadersKey{}, h), h
+}
+
+// Header returns the recorded headers. It returns nil if no headers were
+// recorded.
+func (h *ResponseHeaders) Header() http.Header {
+	if h == nil {
+		return nil
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.header.Clone()
+}
+
+func (h *ResponseHeaders) record(in http.Header) {
+	keys := make([]string, 0, len(in))
+	for k := range in {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	if len(keys) > maxResponseHeaders {
+		keys = keys[:maxResponseHeaders]
+	}
+
+	out := make(http.Header, len(keys))
+	for _, k := range keys {
+		if sensitive(k) {
+			out[k] = []string{redacted}
+			continue
+		}
+		for _, v := range in[k] {
+			if len(v) > maxResponseHeaderValue {
+				v = v[:maxResponseHeaderValue]
+			}
+			out[k] = append(out[k], v)
+		}
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.header = out
+}
+
+func sensitive(header string) bool {
+	l := strings.ToLower(header)
+	return slices.ContainsFunc(sensitiveHeaders, func(s string) bool { return strings.Contains(l, s) })
+}
+
+// A headerRecordingTransport records the headers of responses to requests
+// whose context was returned by WithResponseHeaders.
+type headerRecordingTransport struct {
+	http.RoundTripper
+}
+
+// RoundTrip executes the supplied request, recording its response headers.
+func (t *headerRecordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.RoundTripper.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+	if h, ok := req.Context().Value(responseHeadersKey{}).(*ResponseHeaders); ok {
+		h.record(resp.Header)
+	}
+	return resp, nil
+}
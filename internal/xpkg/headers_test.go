@@ -0,0 +1,112 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xpkg
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+type roundTripperFn func(*http.Request) (*http.Response, error)
+
+func (fn roundTripperFn) RoundTrip(req *http.Request) (*http.Response, error) {
+	return fn(req)
+}
+
+func TestHeaderRecordingTransport(t *testing.T) {
+	many := http.Header{}
+	for i := range maxResponseHeaders + 1 {
+		many.Set(fmt.Sprintf("X-Header-%02d", i), "v")
+	}
+	manyWant := many.Clone()
+	manyWant.Del(fmt.Sprintf("X-Header-%02d", maxResponseHeaders))
+
+	cases := map[string]struct {
+		reason string
+		record bool
+		header http.Header
+		want   http.Header
+	}{
+		"NotRecording": {
+			reason: "We shouldn't record headers unless the request's context asks us to.",
+			header: http.Header{"Docker-Content-Digest": {"sha256:cafe"}},
+		},
+		"Recording": {
+			reason: "We should record the response's headers if the request's context asks us to.",
+			record: true,
+			header: http.Header{
+				"Docker-Content-Digest": {"sha256:cafe"},
+				"Ratelimit-Remaining":   {"99;w=21600"},
+			},
+			want: http.Header{
+				"Docker-Content-Digest": {"sha256:cafe"},
+				"Ratelimit-Remaining":   {"99;w=21600"},
+			},
+		},
+		"Redacted": {
+			reason: "We should redact headers that may contain credentials.",
+			record: true,
+			header: http.Header{
+				"Set-Cookie":         {"session=secret"},
+				"X-Amz-Access-Token": {"secret"},
+			},
+			want: http.Header{
+				"Set-Cookie":         {redacted},
+				"X-Amz-Access-Token": {redacted},
+			},
+		},
+		"TooManyHeaders": {
+			reason: "We should record no more than the maximum number of headers.",
+			record: true,
+			header: many,
+			want:   manyWant,
+		},
+		"LongValue": {
+			reason: "We should truncate long header values.",
+			record: true,
+			header: http.Header{"Link": {strings.Repeat("a", maxResponseHeaderValue+1)}},
+			want:   http.Header{"Link": {strings.Repeat("a", maxResponseHeaderValue)}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			var h *ResponseHeaders
+			if tc.record {
+				ctx, h = WithResponseHeaders(ctx)
+			}
+
+			rt := &headerRecordingTransport{RoundTripper: roundTripperFn(func(_ *http.Request) (*http.Response, error) {
+				return &http.Response{StatusCode: http.StatusOK, Header: tc.header}, nil
+			})}
+			req, _ := http.NewRequestWithContext(ctx, http.MethodHead, "https://registry.example.com/v2/", nil)
+			if _, err := rt.RoundTrip(req); err != nil {
+				t.Fatalf("\n%s\nRoundTrip(...): want no error, got %v", tc.reason, err)
+			}
+
+			if diff := cmp.Diff(tc.want, h.Header()); diff != "" {
+				t.Errorf("\n%s\nHeader(): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xpkg
+
+import (
+	"path/filepath"
+	"strings"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+)
+
+// LocalPackageScheme prefixes the source of a package that should be loaded
+// from an image tarball on the local filesystem, rather than from a registry.
+const LocalPackageScheme = "file://"
+
+const (
+	errFmtLocalPackagesDisabled  = "cannot load local package %q: local packages are disabled"
+	errFmtLocalPackageNotFound   = "cannot find local package %q"
+	errFmtLocalPackageOutsideDir = "local package %q is not in the local package directory %q"
+	errFmtInvalidLocalPackageDir = "cannot resolve local package directory %q"
+	errFmtInvalidLocalPackage    = "local package %q is not a valid package image tarball"
+)
+
+// LocalPackagePath returns the path to the supplied package source's image
+// tarball on the local filesystem, and true if it's a local package source.
+func LocalPackagePath(source string) (string, bool) {
+	return strings.CutPrefix(source, LocalPackageScheme)
+}
+
+// LocalImage loads a package image from the image tarball at the supplied
+// path on the local filesystem. The image tarball must be in the format
+// written by 'docker save' or 'crossplane xpkg build'. Only image tarballs in
+// the supplied directory may be loaded - relative paths are relative to it.
+// Local packages are disabled if the directory is empty.
+func LocalImage(dir, path string) (v1.Image, error) {
+	if dir == "" {
+		return nil, errors.Errorf(errFmtLocalPackagesDisabled, path)
+	}
+	root, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return nil, errors.Wrapf(err, errFmtInvalidLocalPackageDir, dir)
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(root, path)
+	}
+	// Resolve symlinks, so that a symlink in the directory can't point to
+	// an image tarball outside it.
+	resolved, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, errFmtLocalPackageNotFound, path)
+	}
+	rel, err := filepath.Rel(root, resolved)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return nil, errors.Errorf(errFmtLocalPackageOutsideDir, path, dir)
+	}
+	img, err := tarball.ImageFromPath(resolved, nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, errFmtInvalidLocalPackage, path)
+	}
+	return img, nil
+}
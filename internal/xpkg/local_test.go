@@ -0,0 +1,180 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xpkg
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+)
+
+func TestLocalPackagePath(t *testing.T) {
+	type want struct {
+		path  string
+		local bool
+	}
+
+	cases := map[string]struct {
+		reason string
+		source string
+		want   want
+	}{
+		"Local": {
+			reason: "A source with the local package scheme should be a local package.",
+			source: "file:///packages/provider-aws.xpkg",
+			want: want{
+				path:  "/packages/provider-aws.xpkg",
+				local: true,
+			},
+		},
+		"Remote": {
+			reason: "A source without the local package scheme should not be a local package.",
+			source: "xpkg.crossplane.io/crossplane/provider-aws:v1.0.0",
+			want: want{
+				path: "xpkg.crossplane.io/crossplane/provider-aws:v1.0.0",
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			path, local := LocalPackagePath(tc.source)
+			if diff := cmp.Diff(tc.want, want{path: path, local: local}, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("\n%s\nLocalPackagePath(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestLocalImage(t *testing.T) {
+	dir := t.TempDir()
+	outside := t.TempDir()
+
+	valid := filepath.Join(dir, "valid.xpkg")
+	tag, _ := name.NewTag("crossplane/provider-aws:v1.0.0")
+	if err := tarball.WriteToFile(valid, tag, empty.Image); err != nil {
+		t.Fatalf("tarball.WriteToFile(...): %v", err)
+	}
+	wantDigest, _ := empty.Image.Digest()
+
+	invalid := filepath.Join(dir, "invalid.xpkg")
+	if err := os.WriteFile(invalid, []byte("not a tarball"), 0o600); err != nil {
+		t.Fatalf("os.WriteFile(...): %v", err)
+	}
+
+	elsewhere := filepath.Join(outside, "elsewhere.xpkg")
+	if err := tarball.WriteToFile(elsewhere, tag, empty.Image); err != nil {
+		t.Fatalf("tarball.WriteToFile(...): %v", err)
+	}
+	link := filepath.Join(dir, "link.xpkg")
+	if err := os.Symlink(elsewhere, link); err != nil {
+		t.Fatalf("os.Symlink(...): %v", err)
+	}
+
+	type args struct {
+		dir  string
+		path string
+	}
+	cases := map[string]struct {
+		reason  string
+		args    args
+		wantErr bool
+	}{
+		"Disabled": {
+			reason: "We should return an error if local packages are disabled.",
+			args: args{
+				path: valid,
+			},
+			wantErr: true,
+		},
+		"NotFound": {
+			reason: "We should return an error if the local package doesn't exist.",
+			args: args{
+				dir:  dir,
+				path: filepath.Join(dir, "missing.xpkg"),
+			},
+			wantErr: true,
+		},
+		"Invalid": {
+			reason: "We should return an error if the local package isn't an image tarball.",
+			args: args{
+				dir:  dir,
+				path: invalid,
+			},
+			wantErr: true,
+		},
+		"OutsideDir": {
+			reason: "We should return an error if the local package isn't in the local package directory.",
+			args: args{
+				dir:  dir,
+				path: elsewhere,
+			},
+			wantErr: true,
+		},
+		"EscapesDir": {
+			reason: "We should return an error if a relative path escapes the local package directory.",
+			args: args{
+				dir:  dir,
+				path: filepath.Join("..", filepath.Base(outside), "elsewhere.xpkg"),
+			},
+			wantErr: true,
+		},
+		"SymlinkOutsideDir": {
+			reason: "We should return an error if the local package is a symlink to a file outside the local package directory.",
+			args: args{
+				dir:  dir,
+				path: link,
+			},
+			wantErr: true,
+		},
+		"Valid": {
+			reason: "We should load a valid local package.",
+			args: args{
+				dir:  dir,
+				path: valid,
+			},
+		},
+		"ValidRelative": {
+			reason: "We should load a valid local package relative to the local package directory.",
+			args: args{
+				dir:  dir,
+				path: "valid.xpkg",
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			img, err := LocalImage(tc.args.dir, tc.args.path)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("\n%s\nLocalImage(...): want error %t, got %v", tc.reason, tc.wantErr, err)
+			}
+			if err != nil {
+				return
+			}
+			d, _ := img.Digest()
+			if diff := cmp.Diff(wantDigest, d); diff != "" {
+				t.Errorf("\n%s\nLocalImage(...): -want digest, +got digest:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -17,6 +17,8 @@ limitations under the License.
 package xpkg
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"os"
 	"path/filepath"
 	"strings"
@@ -68,6 +70,18 @@ const (
 	// identifierDelimeters is the set of valid OCI image identifier delimeter
 	// characters.
 	identifierDelimeters string = ":@"
+
+	// maxFriendlyNameLength is the maximum length of the package name portion
+	// of a friendly ID.
+	maxFriendlyNameLength = 50
+
+	// maxFriendlyHashLength is the maximum length of the hash portion of a
+	// friendly ID.
+	maxFriendlyHashLength = 12
+
+	// truncatedNameHashLength is the length of the hash of the package name
+	// that is appended to a truncated package name.
+	truncatedNameHashLength = 6
 )
 
 func truncate(str string, num int) string {
@@ -78,10 +92,24 @@ func truncate(str string, num int) string {
 	return t
 }
 
+// truncateWithHash truncates the supplied string to the supplied length. A
+// truncated string is suffixed with a short hash of the original string, so
+// that strings sharing a long prefix remain distinct once truncated.
+func truncateWithHash(str string, num int) string {
+	if len(str) <= num {
+		return str
+	}
+	sum := sha256.Sum256([]byte(str))
+	prefix := strings.TrimRight(str[0:num-truncatedNameHashLength-1], "-.")
+	return prefix + "-" + hex.EncodeToString(sum[:])[:truncatedNameHashLength]
+}
+
 // FriendlyID builds a valid DNS label string made up of the name of a package
-// and its image digest.
+// and its image digest. Long package names are truncated and suffixed with a
+// stable hash of the full name, so that packages whose names share a long
+// prefix have distinct IDs.
 func FriendlyID(name, hash string) string {
-	return ToDNSLabel(strings.Join([]string{truncate(name, 50), truncate(hash, 12)}, "-"))
+	return ToDNSLabel(strings.Join([]string{truncateWithHash(name, maxFriendlyNameLength), truncate(hash, maxFriendlyHashLength)}, "-"))
 }
 
 // ToDNSLabel converts the string to a valid DNS label.
@@ -17,6 +17,7 @@ limitations under the License.
 package xpkg
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -43,12 +44,42 @@ func TestFriendlyID(t *testing.T) {
 			want: "provider-aws-1234567",
 		},
 		"PackageOverLimit": {
-			reason: "If package is over limit it should be truncated.",
+			reason: "If package is over limit it should be truncated and suffixed with a hash of the package name.",
 			args: args{
 				pkg:  "provider-aws-plusabunchofothernonsensethatisgoingtogetslicedoff",
 				hash: "1234567",
 			},
-			want: "provider-aws-plusabunchofothernonsensethatisgoingt-1234567",
+			want: "provider-aws-plusabunchofothernonsensethati-f78863-1234567",
+		},
+		"PackageOverLimitWithSharedPrefix": {
+			reason: "Packages that are over limit and share a prefix should have distinct IDs.",
+			args: args{
+				pkg:  "provider-aws-plusabunchofothernonsensethatisgoingtogetslicedaway",
+				hash: "1234567",
+			},
+			want: "provider-aws-plusabunchofothernonsensethati-5ab3b5-1234567",
+		},
+		"PackageFarOverLimit": {
+			reason: "A very long package name should result in a valid DNS label suffixed with a hash of the package name.",
+			args: args{
+				pkg:  strings.Repeat("a", 300),
+				hash: "1234567",
+			},
+			want: strings.Repeat("a", 43) + "-9835fa-1234567",
+		},
+		"TruncatedPackageInvalid": {
+			reason: "If truncating a package that is over limit doesn't produce a valid DNS label it should be suffixed with a hash of the package name.",
+			args: args{
+				pkg: strings.Repeat("-", 60),
+			},
+			want: "d398f8",
+		},
+		"TruncatedPackagesInvalidWithSharedPrefix": {
+			reason: "Packages that are over limit, share a prefix, and don't truncate to a valid DNS label should have distinct IDs.",
+			args: args{
+				pkg: strings.Repeat("-", 70),
+			},
+			want: "3b4c0a",
 		},
 		"HashOverLimit": {
 			reason: "If hash is over limit it should be truncated.",
@@ -64,7 +95,7 @@ func TestFriendlyID(t *testing.T) {
 				pkg:  "provider-aws-plusabunchofothernonsensethatisgoingtogetslicedoff",
 				hash: "1234567891234567",
 			},
-			want: "provider-aws-plusabunchofothernonsensethatisgoingt-123456789123",
+			want: "provider-aws-plusabunchofothernonsensethati-f78863-123456789123",
 		},
 		"ReplacePeriod": {
 			reason: "All period characters should be replaced with a dash.",
@@ -72,7 +103,7 @@ func TestFriendlyID(t *testing.T) {
 				pkg:  "provider.aws-plusabunchofothernonsensethatisgoingtogetslicedoff",
 				hash: "1234.567891234567",
 			},
-			want: "provider-aws-plusabunchofothernonsensethatisgoingt-1234-5678912",
+			want: "provider-aws-plusabunchofothernonsensethati-160fd3-1234-5678912",
 		},
 		"DigestIsName": {
 			reason: "A valid DNS label should be returned when package digest is a name.",
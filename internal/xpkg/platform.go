@@ -0,0 +1,49 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xpkg
+
+import (
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+)
+
+const (
+	errGetIndexManifest     = "cannot get index manifest"
+	errFmtNoPlatformPackage = "index contains no package for platform %s"
+)
+
+// PlatformPackage returns the descriptor of the package for the supplied
+// platform in the supplied OCI index of platform-specific packages. It returns
+// an error if the index contains no package for the platform.
+func PlatformPackage(idx v1.ImageIndex, p v1.Platform) (*v1.Descriptor, error) {
+	im, err := idx.IndexManifest()
+	if err != nil {
+		return nil, errors.Wrap(err, errGetIndexManifest)
+	}
+	for _, d := range im.Manifests {
+		// Each entry of an index of packages must itself be a package
+		// image, not a nested index.
+		if !d.MediaType.IsImage() || d.Platform == nil {
+			continue
+		}
+		if d.Platform.Satisfies(p) {
+			return &d, nil
+		}
+	}
+	return nil, errors.Errorf(errFmtNoPlatformPackage, p)
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xpkg
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestPlatformPackage(t *testing.T) {
+	amd64 := v1.Platform{OS: "linux", Architecture: "amd64"}
+	arm64 := v1.Platform{OS: "linux", Architecture: "arm64"}
+
+	amd64Img, _ := mutate.ConfigFile(empty.Image, &v1.ConfigFile{OS: amd64.OS, Architecture: amd64.Architecture})
+	arm64Img, _ := mutate.ConfigFile(empty.Image, &v1.ConfigFile{OS: arm64.OS, Architecture: arm64.Architecture})
+	amd64Digest, _ := amd64Img.Digest()
+	arm64Digest, _ := arm64Img.Digest()
+
+	idx := mutate.AppendManifests(empty.Index,
+		mutate.IndexAddendum{Add: amd64Img, Descriptor: v1.Descriptor{Platform: &amd64}},
+		mutate.IndexAddendum{Add: arm64Img, Descriptor: v1.Descriptor{Platform: &arm64}},
+	)
+
+	type want struct {
+		digest v1.Hash
+		err    error
+	}
+
+	cases := map[string]struct {
+		reason   string
+		platform v1.Platform
+		want     want
+	}{
+		"AMD64": {
+			reason:   "We should return the package for the requested platform.",
+			platform: amd64,
+			want: want{
+				digest: amd64Digest,
+			},
+		},
+		"ARM64": {
+			reason:   "We should return the package for the requested platform.",
+			platform: arm64,
+			want: want{
+				digest: arm64Digest,
+			},
+		},
+		"NoCompatiblePackage": {
+			reason:   "We should return an error if the index contains no package for the requested platform.",
+			platform: v1.Platform{OS: "windows", Architecture: "amd64"},
+			want: want{
+				err: errors.Errorf(errFmtNoPlatformPackage, "windows/amd64"),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			d, err := PlatformPackage(idx, tc.platform)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nPlatformPackage(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if err != nil {
+				return
+			}
+			if diff := cmp.Diff(tc.want.digest, d.Digest); diff != "" {
+				t.Errorf("\n%s\nPlatformPackage(...): -want digest, +got digest:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
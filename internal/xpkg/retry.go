@@ -0,0 +1,115 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xpkg
+
+import (
+	"io"
+	"net/http"
+	"slices"
+	"time"
+)
+
+// Registry responses with these status codes are retried, since they usually
+// indicate a transient problem.
+var retryStatusCodes = []int{
+	http.StatusRequestTimeout,
+	http.StatusTooManyRequests,
+	http.StatusInternalServerError,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+// A RetryPolicy determines how a Fetcher retries registry requests that fail
+// transiently, for example because of a brief network blip.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of times a request is attempted,
+	// including the first attempt. Values less than two disable retries.
+	MaxAttempts int
+
+	// BaseDelay is how long to wait before the first retry.
+	BaseDelay time.Duration
+
+	// Factor by which the delay is multiplied before each subsequent retry.
+	// Values less than one are treated as one.
+	Factor float64
+}
+
+// delay returns how long to wait before the supplied retry, starting at zero.
+func (p RetryPolicy) delay(retry int) time.Duration {
+	d := float64(p.BaseDelay)
+	for range retry {
+		d *= max(p.Factor, 1)
+	}
+	return time.Duration(d)
+}
+
+// A retryTransport retries requests that fail transiently according to a
+// RetryPolicy. It stops retrying when the request's context is done, so that
+// retries respect the overall fetch timeout.
+type retryTransport struct {
+	http.RoundTripper
+
+	policy RetryPolicy
+}
+
+// RoundTrip executes the supplied request, retrying it if it fails
+// transiently.
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	for attempt := 1; ; attempt++ {
+		resp, err := t.RoundTripper.RoundTrip(req)
+		if attempt >= t.policy.MaxAttempts || !t.retryable(req, resp, err) {
+			return resp, err
+		}
+
+		// Discard the response we're about to retry, so that its
+		// connection can be reused.
+		if resp != nil {
+			_, _ = io.Copy(io.Discard, resp.Body)
+			_ = resp.Body.Close()
+		}
+
+		timer := time.NewTimer(t.policy.delay(attempt - 1))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+
+		// A request with a body can only be retried if we can rewind it.
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+	}
+}
+
+func (t *retryTransport) retryable(req *http.Request, resp *http.Response, err error) bool {
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return false
+	}
+	if err != nil {
+		// Don't retry requests that were cancelled or timed out.
+		return req.Context().Err() == nil
+	}
+	return slices.Contains(retryStatusCodes, resp.StatusCode)
+}
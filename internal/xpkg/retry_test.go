@@ -0,0 +1,152 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xpkg
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+)
+
+func TestRetryTransport(t *testing.T) {
+	errBlip := errors.New("connection reset by peer")
+
+	type want struct {
+		status   int
+		err      error
+		attempts int
+	}
+
+	cases := map[string]struct {
+		reason    string
+		policy    RetryPolicy
+		responses []func() (*http.Response, error)
+		cancelled bool
+		want      want
+	}{
+		"SucceedsOnSecondAttempt": {
+			reason: "We should absorb a transient network error by retrying the request.",
+			policy: RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, Factor: 2},
+			responses: []func() (*http.Response, error){
+				func() (*http.Response, error) { return nil, errBlip },
+				func() (*http.Response, error) { return response(http.StatusOK), nil },
+			},
+			want: want{
+				status:   http.StatusOK,
+				attempts: 2,
+			},
+		},
+		"RetryableStatus": {
+			reason: "We should retry a request the registry responded to with a transient error status.",
+			policy: RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, Factor: 2},
+			responses: []func() (*http.Response, error){
+				func() (*http.Response, error) { return response(http.StatusServiceUnavailable), nil },
+				func() (*http.Response, error) { return response(http.StatusOK), nil },
+			},
+			want: want{
+				status:   http.StatusOK,
+				attempts: 2,
+			},
+		},
+		"NonRetryableStatus": {
+			reason: "We shouldn't retry a request the registry responded to with a permanent error status.",
+			policy: RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, Factor: 2},
+			responses: []func() (*http.Response, error){
+				func() (*http.Response, error) { return response(http.StatusNotFound), nil },
+			},
+			want: want{
+				status:   http.StatusNotFound,
+				attempts: 1,
+			},
+		},
+		"ExhaustsAttempts": {
+			reason: "We should return the last error once we've made the maximum number of attempts.",
+			policy: RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond, Factor: 2},
+			responses: []func() (*http.Response, error){
+				func() (*http.Response, error) { return nil, errBlip },
+				func() (*http.Response, error) { return nil, errBlip },
+				func() (*http.Response, error) { return response(http.StatusOK), nil },
+			},
+			want: want{
+				err:      errBlip,
+				attempts: 2,
+			},
+		},
+		"ContextDone": {
+			reason: "We should stop retrying once the request's context is done.",
+			policy: RetryPolicy{MaxAttempts: 3, BaseDelay: time.Hour, Factor: 2},
+			responses: []func() (*http.Response, error){
+				func() (*http.Response, error) { return nil, errBlip },
+				func() (*http.Response, error) { return response(http.StatusOK), nil },
+			},
+			cancelled: true,
+			want: want{
+				err:      context.Canceled,
+				attempts: 1,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			attempts := 0
+			rt := &retryTransport{
+				policy: tc.policy,
+				RoundTripper: roundTripperFn(func(_ *http.Request) (*http.Response, error) {
+					fn := tc.responses[attempts]
+					attempts++
+					if tc.cancelled {
+						// The fetch times out while we wait to retry.
+						time.AfterFunc(10*time.Millisecond, cancel)
+					}
+					return fn()
+				}),
+			}
+			req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "https://registry.example.com/v2/", nil)
+			resp, err := rt.RoundTrip(req)
+
+			if diff := cmp.Diff(tc.want.err, err, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nRoundTrip(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			status := 0
+			if resp != nil {
+				status = resp.StatusCode
+			}
+			if diff := cmp.Diff(tc.want.status, status); diff != "" {
+				t.Errorf("\n%s\nRoundTrip(...): -want status, +got status:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.attempts, attempts); diff != "" {
+				t.Errorf("\n%s\nRoundTrip(...): -want attempts, +got attempts:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func response(status int) *http.Response {
+	return &http.Response{StatusCode: status, Body: io.NopCloser(strings.NewReader(""))}
+}